@@ -0,0 +1,651 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+const (
+	// branchProtectionKindRule is the kind of classic branch protection rules
+	branchProtectionKindRule = "branch_protection_rule"
+	// branchProtectionKindRuleset is the kind of repository and organization rulesets
+	branchProtectionKindRuleset = "ruleset"
+
+	complianceStatusCompliant    = "compliant"
+	complianceStatusNonCompliant = "non_compliant"
+)
+
+// MaxBranchProtectionRepositories is the number of repositories audited when auditing the branch protection of an owner.
+// The branch protection is read with a few requests per repository, the other repositories are not audited.
+const MaxBranchProtectionRepositories = 100
+
+// BranchActor is a user, team or app that is allowed to bypass a branch protection rule or a ruleset
+type BranchActor struct {
+	TypeName string `graphql:"__typename"`
+	User     struct {
+		Login string
+	} `graphql:"... on User"`
+	Team struct {
+		CombinedSlug string
+	} `graphql:"... on Team"`
+	App struct {
+		Slug string
+	} `graphql:"... on App"`
+}
+
+// String formats the actor as "<kind>:<name>", for example "team:grafana/platform"
+func (a BranchActor) String() string {
+	switch a.TypeName {
+	case "User":
+		return "user:" + a.User.Login
+	case "Team":
+		return "team:" + a.Team.CombinedSlug
+	case "App":
+		return "app:" + a.App.Slug
+	}
+	return ""
+}
+
+// RulesetBypassActor is a team or app that is allowed to bypass a ruleset. Unlike branch protection rules, users can't be ruleset bypass actors.
+type RulesetBypassActor struct {
+	TypeName string `graphql:"__typename"`
+	Team     struct {
+		CombinedSlug string
+	} `graphql:"... on Team"`
+	App struct {
+		Slug string
+	} `graphql:"... on App"`
+}
+
+// String formats the actor as "<kind>:<name>", for example "app:dependabot"
+func (a RulesetBypassActor) String() string {
+	switch a.TypeName {
+	case "Team":
+		return "team:" + a.Team.CombinedSlug
+	case "App":
+		return "app:" + a.App.Slug
+	}
+	return ""
+}
+
+// BranchProtectionRule is a classic branch protection rule of a repository
+type BranchProtectionRule struct {
+	Pattern                      string
+	RequiresApprovingReviews     bool
+	RequiredApprovingReviewCount int64
+	RequiresCodeOwnerReviews     bool
+	RequiresStatusChecks         bool
+	RequiredStatusCheckContexts  []string
+	RequiresCommitSignatures     bool
+	RequiresLinearHistory        bool
+	IsAdminEnforced              bool
+	BypassPullRequestAllowances  struct {
+		Nodes []struct {
+			Actor BranchActor
+		}
+	} `graphql:"bypassPullRequestAllowances(first: 100)"`
+	BypassForcePushAllowances struct {
+		Nodes []struct {
+			Actor BranchActor
+		}
+	} `graphql:"bypassForcePushAllowances(first: 100)"`
+}
+
+// RepositoryRuleset is a ruleset defined on a repository or inherited from its organization
+type RepositoryRuleset struct {
+	Name        string
+	Enforcement githubv4.RuleEnforcement
+	Target      githubv4.RepositoryRulesetTarget
+	Source      struct {
+		TypeName     string `graphql:"__typename"`
+		Organization struct {
+			Login string
+		} `graphql:"... on Organization"`
+		Repository struct {
+			NameWithOwner string
+		} `graphql:"... on Repository"`
+	}
+	Conditions struct {
+		RefName struct {
+			Include []string
+			Exclude []string
+		}
+	}
+	Rules struct {
+		Nodes []struct {
+			Type       githubv4.RepositoryRuleType
+			Parameters struct {
+				PullRequest struct {
+					RequiredApprovingReviewCount int64
+					RequireCodeOwnerReview       bool
+				} `graphql:"... on PullRequestParameters"`
+				StatusChecks struct {
+					RequiredStatusChecks []struct {
+						Context string
+					}
+				} `graphql:"... on RequiredStatusChecksParameters"`
+			}
+		}
+	} `graphql:"rules(first: 100)"`
+	BypassActors struct {
+		Nodes []struct {
+			Actor              RulesetBypassActor
+			DeployKey          bool
+			OrganizationAdmin  bool
+			RepositoryRoleName string
+		}
+	} `graphql:"bypassActors(first: 100)"`
+}
+
+// QueryListBranchProtectionRules is the GraphQL query for listing the branch protection rules of a repository
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    nameWithOwner
+//	    defaultBranchRef {
+//	      name
+//	    }
+//	    branchProtectionRules(first: 100) {
+//	      nodes {
+//	        pattern
+//	        requiredApprovingReviewCount
+//	        requiredStatusCheckContexts
+//	        bypassPullRequestAllowances(first: 100) {
+//	          nodes {
+//	            actor {
+//	              ... on User {
+//	                login
+//	              }
+//	            }
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListBranchProtectionRules struct {
+	Repository struct {
+		NameWithOwner    string
+		DefaultBranchRef struct {
+			Name string
+		}
+		BranchProtectionRules struct {
+			Nodes    []BranchProtectionRule
+			PageInfo models.PageInfo
+		} `graphql:"branchProtectionRules(first: 100, after: $cursor)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// QueryListRepositoryRulesets is the GraphQL query for listing the rulesets that apply to a repository, including the ones inherited from its organization
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    rulesets(first: 100, includeParents: true) {
+//	      nodes {
+//	        name
+//	        enforcement
+//	        target
+//	        conditions {
+//	          refName {
+//	            include
+//	            exclude
+//	          }
+//	        }
+//	        rules(first: 100) {
+//	          nodes {
+//	            type
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListRepositoryRulesets struct {
+	Repository struct {
+		Rulesets struct {
+			Nodes    []RepositoryRuleset
+			PageInfo models.PageInfo
+		} `graphql:"rulesets(first: 100, after: $cursor, includeParents: true)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// branchProtectionEntry is a branch protection rule or a ruleset flattened to the settings that matter for an audit
+type branchProtectionEntry struct {
+	Kind                         string
+	Name                         string
+	Source                       string
+	Enforcement                  string
+	AppliesToDefaultBranch       bool
+	RequiredApprovingReviewCount int64
+	RequiresCodeOwnerReviews     bool
+	RequiredStatusChecks         []string
+	RequiresStatusChecks         bool
+	RequiresSignedCommits        bool
+	RequiresLinearHistory        bool
+	BypassActors                 []string
+}
+
+// RepositoryBranchProtection holds every branch protection rule and ruleset of a single repository
+type RepositoryBranchProtection struct {
+	Repository    string
+	DefaultBranch string
+	Rules         []branchProtectionEntry
+}
+
+// BranchProtection is the result of a branch protection audit, along with the policy the default branches are checked against
+type BranchProtection struct {
+	Repositories []RepositoryBranchProtection
+	Policy       models.BranchProtectionPolicy
+}
+
+// Frames converts the audit to two Grafana DataFrames: the list of rules and the compliance status of every default branch
+func (b BranchProtection) Frames() data.Frames {
+	rules := data.NewFrame(
+		"branch_protection_rules",
+		data.NewField("repository", nil, []string{}),
+		data.NewField("default_branch", nil, []string{}),
+		data.NewField("kind", nil, []string{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+		data.NewField("enforcement", nil, []string{}),
+		data.NewField("applies_to_default_branch", nil, []bool{}),
+		data.NewField("required_approving_review_count", nil, []int64{}),
+		data.NewField("requires_code_owner_reviews", nil, []bool{}),
+		data.NewField("required_status_checks", nil, []json.RawMessage{}),
+		data.NewField("requires_signed_commits", nil, []bool{}),
+		data.NewField("requires_linear_history", nil, []bool{}),
+		data.NewField("bypass_actors", nil, []json.RawMessage{}),
+	)
+
+	compliance := data.NewFrame(
+		"branch_protection_compliance",
+		data.NewField("repository", nil, []string{}),
+		data.NewField("default_branch", nil, []string{}),
+		data.NewField("protected", nil, []bool{}),
+		data.NewField("required_approving_review_count", nil, []int64{}),
+		data.NewField("requires_code_owner_reviews", nil, []bool{}),
+		data.NewField("required_status_checks", nil, []json.RawMessage{}),
+		data.NewField("requires_signed_commits", nil, []bool{}),
+		data.NewField("requires_linear_history", nil, []bool{}),
+		data.NewField("bypass_actors", nil, []json.RawMessage{}),
+		data.NewField("compliance_status", nil, []string{}),
+		data.NewField("violations", nil, []json.RawMessage{}),
+	)
+
+	for _, repo := range b.Repositories {
+		for _, r := range repo.Rules {
+			rules.AppendRow(
+				repo.Repository,
+				repo.DefaultBranch,
+				r.Kind,
+				r.Name,
+				r.Source,
+				r.Enforcement,
+				r.AppliesToDefaultBranch,
+				r.RequiredApprovingReviewCount,
+				r.RequiresCodeOwnerReviews,
+				rawJSONArray(r.RequiredStatusChecks),
+				r.RequiresSignedCommits,
+				r.RequiresLinearHistory,
+				rawJSONArray(r.BypassActors),
+			)
+		}
+
+		effective, protected := effectiveBranchProtection(repo.Rules)
+		violations := branchProtectionViolations(effective, protected, b.Policy)
+		status := complianceStatusCompliant
+		if len(violations) > 0 {
+			status = complianceStatusNonCompliant
+		}
+
+		compliance.AppendRow(
+			repo.Repository,
+			repo.DefaultBranch,
+			protected,
+			effective.RequiredApprovingReviewCount,
+			effective.RequiresCodeOwnerReviews,
+			rawJSONArray(effective.RequiredStatusChecks),
+			effective.RequiresSignedCommits,
+			effective.RequiresLinearHistory,
+			rawJSONArray(effective.BypassActors),
+			status,
+			rawJSONArray(violations),
+		)
+	}
+
+	rules.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	compliance.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{rules, compliance}
+}
+
+// rawJSONArray marshals a list of strings to a JSON array, never returning null
+func rawJSONArray(values []string) json.RawMessage {
+	if values == nil {
+		values = []string{}
+	}
+	b, _ := json.Marshal(values)
+	return json.RawMessage(b)
+}
+
+// effectiveBranchProtection combines every enforced rule that applies to the default branch.
+// Rules stack, so the strictest setting of each rule wins and the bypass actors of every rule are merged.
+func effectiveBranchProtection(rules []branchProtectionEntry) (branchProtectionEntry, bool) {
+	var (
+		effective = branchProtectionEntry{}
+		protected bool
+		checks    = map[string]struct{}{}
+		actors    = map[string]struct{}{}
+	)
+
+	for _, r := range rules {
+		if !r.AppliesToDefaultBranch || (r.Kind == branchProtectionKindRuleset && r.Enforcement != string(githubv4.RuleEnforcementActive)) {
+			continue
+		}
+		protected = true
+
+		if r.RequiredApprovingReviewCount > effective.RequiredApprovingReviewCount {
+			effective.RequiredApprovingReviewCount = r.RequiredApprovingReviewCount
+		}
+		effective.RequiresCodeOwnerReviews = effective.RequiresCodeOwnerReviews || r.RequiresCodeOwnerReviews
+		effective.RequiresStatusChecks = effective.RequiresStatusChecks || r.RequiresStatusChecks
+		effective.RequiresSignedCommits = effective.RequiresSignedCommits || r.RequiresSignedCommits
+		effective.RequiresLinearHistory = effective.RequiresLinearHistory || r.RequiresLinearHistory
+
+		for _, c := range r.RequiredStatusChecks {
+			checks[c] = struct{}{}
+		}
+		for _, a := range r.BypassActors {
+			actors[a] = struct{}{}
+		}
+	}
+
+	effective.RequiredStatusChecks = sortedKeys(checks)
+	effective.BypassActors = sortedKeys(actors)
+
+	return effective, protected
+}
+
+// branchProtectionViolations lists the reasons why the effective protection of a default branch does not satisfy the policy
+func branchProtectionViolations(effective branchProtectionEntry, protected bool, policy models.BranchProtectionPolicy) []string {
+	if !protected {
+		return []string{"default branch is not protected"}
+	}
+
+	violations := []string{}
+	if effective.RequiredApprovingReviewCount < policy.RequiredApprovingReviewCount {
+		violations = append(violations, fmt.Sprintf("requires %d approving reviews, policy requires %d", effective.RequiredApprovingReviewCount, policy.RequiredApprovingReviewCount))
+	}
+	if policy.RequireCodeOwnerReviews && !effective.RequiresCodeOwnerReviews {
+		violations = append(violations, "code owner reviews are not required")
+	}
+	if policy.RequireStatusChecks && !effective.RequiresStatusChecks {
+		violations = append(violations, "status checks are not required")
+	}
+	if policy.RequireSignedCommits && !effective.RequiresSignedCommits {
+		violations = append(violations, "signed commits are not required")
+	}
+	if policy.RequireLinearHistory && !effective.RequiresLinearHistory {
+		violations = append(violations, "linear history is not required")
+	}
+	if policy.DisallowBypassActors && len(effective.BypassActors) > 0 {
+		violations = append(violations, fmt.Sprintf("bypass actors are allowed: %s", strings.Join(effective.BypassActors, ", ")))
+	}
+
+	return violations
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// refPatternToRegexp converts a fnmatch style branch pattern, as used by branch protection rules and rulesets, to a regular expression.
+// `*` matches any character except `/`, `**` matches any character and `?` matches a single character except `/`.
+func refPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+				continue
+			}
+			sb.WriteString("[^/]*")
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// refPatternMatches returns true if the branch name matches the fnmatch style pattern
+func refPatternMatches(pattern string, branch string) bool {
+	re, err := refPatternToRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(branch)
+}
+
+// rulesetMatchesBranch checks the ref name conditions of a ruleset against a branch which is the default branch of the repository
+func rulesetMatchesBranch(include []string, exclude []string, branch string) bool {
+	matches := func(pattern string) bool {
+		switch pattern {
+		case "~ALL", "~DEFAULT_BRANCH":
+			return true
+		}
+		return refPatternMatches(strings.TrimPrefix(pattern, "refs/heads/"), branch)
+	}
+
+	for _, pattern := range exclude {
+		if matches(pattern) {
+			return false
+		}
+	}
+	for _, pattern := range include {
+		if matches(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func branchProtectionRuleEntry(rule BranchProtectionRule, repository string, defaultBranch string) branchProtectionEntry {
+	actors := map[string]struct{}{}
+	for _, v := range rule.BypassPullRequestAllowances.Nodes {
+		if actor := v.Actor.String(); actor != "" {
+			actors[actor] = struct{}{}
+		}
+	}
+	for _, v := range rule.BypassForcePushAllowances.Nodes {
+		if actor := v.Actor.String(); actor != "" {
+			actors[actor] = struct{}{}
+		}
+	}
+	// Unless the rule is enforced for administrators, they can bypass it
+	if !rule.IsAdminEnforced {
+		actors["repository_admin"] = struct{}{}
+	}
+
+	reviews := int64(0)
+	if rule.RequiresApprovingReviews {
+		reviews = rule.RequiredApprovingReviewCount
+	}
+
+	return branchProtectionEntry{
+		Kind:                         branchProtectionKindRule,
+		Name:                         rule.Pattern,
+		Source:                       repository,
+		AppliesToDefaultBranch:       defaultBranch != "" && refPatternMatches(rule.Pattern, defaultBranch),
+		RequiredApprovingReviewCount: reviews,
+		RequiresCodeOwnerReviews:     rule.RequiresCodeOwnerReviews,
+		RequiresStatusChecks:         rule.RequiresStatusChecks,
+		RequiredStatusChecks:         rule.RequiredStatusCheckContexts,
+		RequiresSignedCommits:        rule.RequiresCommitSignatures,
+		RequiresLinearHistory:        rule.RequiresLinearHistory,
+		BypassActors:                 sortedKeys(actors),
+	}
+}
+
+func rulesetEntry(ruleset RepositoryRuleset, defaultBranch string) branchProtectionEntry {
+	entry := branchProtectionEntry{
+		Kind:        branchProtectionKindRuleset,
+		Name:        ruleset.Name,
+		Enforcement: string(ruleset.Enforcement),
+		AppliesToDefaultBranch: defaultBranch != "" &&
+			ruleset.Target == githubv4.RepositoryRulesetTargetBranch &&
+			rulesetMatchesBranch(ruleset.Conditions.RefName.Include, ruleset.Conditions.RefName.Exclude, defaultBranch),
+	}
+
+	switch ruleset.Source.TypeName {
+	case "Organization":
+		entry.Source = ruleset.Source.Organization.Login
+	case "Repository":
+		entry.Source = ruleset.Source.Repository.NameWithOwner
+	}
+
+	for _, rule := range ruleset.Rules.Nodes {
+		switch rule.Type {
+		case githubv4.RepositoryRuleTypePullRequest:
+			entry.RequiredApprovingReviewCount = rule.Parameters.PullRequest.RequiredApprovingReviewCount
+			entry.RequiresCodeOwnerReviews = rule.Parameters.PullRequest.RequireCodeOwnerReview
+		case githubv4.RepositoryRuleTypeRequiredStatusChecks:
+			entry.RequiresStatusChecks = true
+			for _, check := range rule.Parameters.StatusChecks.RequiredStatusChecks {
+				entry.RequiredStatusChecks = append(entry.RequiredStatusChecks, check.Context)
+			}
+		case githubv4.RepositoryRuleTypeRequiredSignatures:
+			entry.RequiresSignedCommits = true
+		case githubv4.RepositoryRuleTypeRequiredLinearHistory:
+			entry.RequiresLinearHistory = true
+		}
+	}
+
+	actors := map[string]struct{}{}
+	for _, v := range ruleset.BypassActors.Nodes {
+		switch {
+		case v.OrganizationAdmin:
+			actors["organization_admin"] = struct{}{}
+		case v.DeployKey:
+			actors["deploy_key"] = struct{}{}
+		case v.RepositoryRoleName != "":
+			actors["role:"+v.RepositoryRoleName] = struct{}{}
+		default:
+			if actor := v.Actor.String(); actor != "" {
+				actors[actor] = struct{}{}
+			}
+		}
+	}
+	entry.BypassActors = sortedKeys(actors)
+
+	return entry
+}
+
+// GetRepositoryBranchProtection retrieves the branch protection rules and the rulesets of a single repository
+func GetRepositoryBranchProtection(ctx context.Context, client models.Client, owner string, repository string) (RepositoryBranchProtection, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"owner":  githubv4.String(owner),
+			"name":   githubv4.String(repository),
+		}
+
+		result = RepositoryBranchProtection{
+			Repository: fmt.Sprintf("%s/%s", owner, repository),
+			Rules:      []branchProtectionEntry{},
+		}
+		rules = []BranchProtectionRule{}
+	)
+
+	for {
+		q := &QueryListBranchProtectionRules{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return RepositoryBranchProtection{}, errors.WithStack(err)
+		}
+		if q.Repository.NameWithOwner != "" {
+			result.Repository = q.Repository.NameWithOwner
+		}
+		result.DefaultBranch = q.Repository.DefaultBranchRef.Name
+		rules = append(rules, q.Repository.BranchProtectionRules.Nodes...)
+
+		if !q.Repository.BranchProtectionRules.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.BranchProtectionRules.PageInfo.EndCursor
+	}
+
+	for _, rule := range rules {
+		result.Rules = append(result.Rules, branchProtectionRuleEntry(rule, result.Repository, result.DefaultBranch))
+	}
+
+	variables["cursor"] = (*githubv4.String)(nil)
+	for {
+		q := &QueryListRepositoryRulesets{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return RepositoryBranchProtection{}, errors.WithStack(err)
+		}
+		for _, ruleset := range q.Repository.Rulesets.Nodes {
+			result.Rules = append(result.Rules, rulesetEntry(ruleset, result.DefaultBranch))
+		}
+
+		if !q.Repository.Rulesets.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.Rulesets.PageInfo.EndCursor
+	}
+
+	return result, nil
+}
+
+// GetBranchProtection audits the branch protection of a repository, or of the first MaxBranchProtectionRepositories repositories of the owner when no repository is set.
+// When auditing an owner, a repository whose branch protection can't be read doesn't fail the audit, it is left out.
+func GetBranchProtection(ctx context.Context, client models.Client, opts models.ListBranchProtectionOptions) (BranchProtection, error) {
+	result := BranchProtection{
+		Repositories: []RepositoryBranchProtection{},
+		Policy:       opts.Policy,
+	}
+
+	if opts.Owner == "" {
+		return result, nil
+	}
+
+	repositories, err := listRepositoryNames(ctx, client, opts.Owner, opts.Repository)
+	if err != nil {
+		return BranchProtection{}, err
+	}
+	repositories = firstRepositoryNames(repositories, MaxBranchProtectionRepositories, opts.Owner)
+
+	for _, repository := range repositories {
+		protection, err := GetRepositoryBranchProtection(ctx, client, opts.Owner, repository)
+		if err != nil {
+			if opts.Repository != "" {
+				return BranchProtection{}, fmt.Errorf("getting branch protection of %s/%s: %w", opts.Owner, repository, err)
+			}
+			backend.Logger.Warn("could not get the branch protection of the repository", "owner", opts.Owner, "repository", repository, "error", err)
+			continue
+		}
+		result.Repositories = append(result.Repositories, protection)
+	}
+
+	return result, nil
+}
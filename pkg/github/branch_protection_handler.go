@@ -0,0 +1,24 @@
+package github
+
+import (
+	"context"
+
+	"github.com/grafana/github-datasource/pkg/dfutil"
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func (s *QueryHandler) handleBranchProtectionQuery(ctx context.Context, q backend.DataQuery) backend.DataResponse {
+	query := &models.BranchProtectionQuery{}
+	if err := UnmarshalQuery(q.JSON, query); err != nil {
+		return *err
+	}
+	return dfutil.FrameResponseWithError(s.Datasource.HandleBranchProtectionQuery(ctx, query, q))
+}
+
+// HandleBranchProtection handles the plugin query for github branch protection rules and rulesets
+func (s *QueryHandler) HandleBranchProtection(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return &backend.QueryDataResponse{
+		Responses: processQueries(ctx, req, s.handleBranchProtectionQuery),
+	}, nil
+}
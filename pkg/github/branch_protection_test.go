@@ -0,0 +1,263 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetRepositoryBranchProtection(t *testing.T) {
+	var (
+		ctx = context.Background()
+	)
+
+	testVariables := testutil.GetTestVariablesFunction("cursor", "owner", "name")
+	testQuery := func(t *testing.T, q interface{}) {
+		switch q.(type) {
+		case *QueryListBranchProtectionRules, *QueryListRepositoryRulesets:
+		default:
+			t.Errorf("unexpected query type %T", q)
+		}
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+
+	protection, err := GetRepositoryBranchProtection(ctx, client, "grafana", "grafana")
+	require.NoError(t, err)
+	assert.Equal(t, "grafana/grafana", protection.Repository)
+}
+
+// failingRepositoryClient fails the queries of a repository and lists the repositories of the owner
+type failingRepositoryClient struct {
+	*testutil.TestClient
+	repositories []string
+	failing      string
+}
+
+func (c *failingRepositoryClient) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	if query, ok := q.(*QueryListRepositories); ok {
+		for _, name := range c.repositories {
+			repo := struct {
+				Repository Repository `graphql:"... on Repository"`
+			}{}
+			repo.Repository.Name = name
+			query.Search.Nodes = append(query.Search.Nodes, repo)
+		}
+		return nil
+	}
+	if variables["name"] == githubv4.String(c.failing) {
+		return errors.New("internal error")
+	}
+	return c.TestClient.Query(ctx, q, variables)
+}
+
+func TestGetBranchProtectionSkipsFailingRepositories(t *testing.T) {
+	client := &failingRepositoryClient{
+		TestClient:   testutil.NewTestClient(t, nil, nil),
+		repositories: []string{"grafana", "loki"},
+		failing:      "grafana",
+	}
+
+	protection, err := GetBranchProtection(context.Background(), client, models.ListBranchProtectionOptions{Owner: "grafana"})
+	require.NoError(t, err)
+	require.Len(t, protection.Repositories, 1)
+	assert.Equal(t, "grafana/loki", protection.Repositories[0].Repository)
+
+	// a single repository still fails the audit
+	_, err = GetBranchProtection(context.Background(), client, models.ListBranchProtectionOptions{Owner: "grafana", Repository: "grafana"})
+	assert.Error(t, err)
+}
+
+// graphqlServerClient sends the queries to a GraphQL server returning a fixed response, so that the query built from the struct and the decoding of the response are tested
+type graphqlServerClient struct {
+	*testutil.TestClient
+	graphql *githubv4.Client
+	// queries are the GraphQL documents that were sent
+	queries []string
+}
+
+func newGraphQLServerClient(t *testing.T, response string) *graphqlServerClient {
+	client := &graphqlServerClient{TestClient: testutil.NewTestClient(t, nil, nil)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		request := struct {
+			Query string `json:"query"`
+		}{}
+		require.NoError(t, json.Unmarshal(body, &request))
+		client.queries = append(client.queries, request.Query)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	client.graphql = githubv4.NewEnterpriseClient(server.URL, server.Client())
+	return client
+}
+
+func (c *graphqlServerClient) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	return c.graphql.Query(ctx, q, variables)
+}
+
+func TestRulesetBypassActors(t *testing.T) {
+	client := newGraphQLServerClient(t, `{"data": {"repository": {"rulesets": {"nodes": [{
+		"name": "main",
+		"enforcement": "ACTIVE",
+		"target": "BRANCH",
+		"source": {"__typename": "Organization", "login": "grafana"},
+		"conditions": {"refName": {"include": ["~DEFAULT_BRANCH"], "exclude": []}},
+		"rules": {"nodes": []},
+		"bypassActors": {"nodes": [
+			{"actor": {"__typename": "Team", "combinedSlug": "grafana/release"}, "deployKey": false, "organizationAdmin": false, "repositoryRoleName": null},
+			{"actor": {"__typename": "App", "slug": "dependabot"}, "deployKey": false, "organizationAdmin": false, "repositoryRoleName": null},
+			{"actor": null, "deployKey": false, "organizationAdmin": true, "repositoryRoleName": null}
+		]}
+	}], "pageInfo": {"hasNextPage": false}}}}}`)
+
+	q := &QueryListRepositoryRulesets{}
+	require.NoError(t, client.Query(context.Background(), q, map[string]interface{}{
+		"cursor": (*githubv4.String)(nil),
+		"owner":  githubv4.String("grafana"),
+		"name":   githubv4.String("grafana"),
+	}))
+
+	// the BypassActor union only has apps and teams, a user fragment fails the validation of the query
+	require.Len(t, client.queries, 1)
+	assert.NotContains(t, client.queries[0], "... on User")
+
+	require.Len(t, q.Repository.Rulesets.Nodes, 1)
+	entry := rulesetEntry(q.Repository.Rulesets.Nodes[0], "main")
+	assert.True(t, entry.AppliesToDefaultBranch)
+	assert.Equal(t, "grafana", entry.Source)
+	assert.Equal(t, []string{"app:dependabot", "organization_admin", "team:grafana/release"}, entry.BypassActors)
+}
+
+func TestRefPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		branch  string
+		want    bool
+	}{
+		{pattern: "main", branch: "main", want: true},
+		{pattern: "main", branch: "main2", want: false},
+		{pattern: "release/*", branch: "release/1.0", want: true},
+		{pattern: "release/*", branch: "release/1.0/hotfix", want: false},
+		{pattern: "release/**", branch: "release/1.0/hotfix", want: true},
+		{pattern: "v?", branch: "v1", want: true},
+		{pattern: "*", branch: "feature/x", want: false},
+		{pattern: "ma.n", branch: "main", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.branch, func(t *testing.T) {
+			assert.Equal(t, tt.want, refPatternMatches(tt.pattern, tt.branch))
+		})
+	}
+}
+
+func TestRulesetMatchesBranch(t *testing.T) {
+	assert.True(t, rulesetMatchesBranch([]string{"~DEFAULT_BRANCH"}, nil, "main"))
+	assert.True(t, rulesetMatchesBranch([]string{"~ALL"}, nil, "main"))
+	assert.True(t, rulesetMatchesBranch([]string{"refs/heads/main"}, nil, "main"))
+	assert.False(t, rulesetMatchesBranch([]string{"~ALL"}, []string{"refs/heads/main"}, "main"))
+	assert.False(t, rulesetMatchesBranch([]string{"refs/heads/release/*"}, nil, "main"))
+	assert.False(t, rulesetMatchesBranch(nil, nil, "main"))
+}
+
+func TestBranchProtectionCompliance(t *testing.T) {
+	policy := models.BranchProtectionPolicy{
+		RequiredApprovingReviewCount: 2,
+		RequireStatusChecks:          true,
+		RequireSignedCommits:         true,
+		DisallowBypassActors:         true,
+	}
+
+	t.Run("unprotected default branch", func(t *testing.T) {
+		rules := []branchProtectionEntry{
+			{Kind: branchProtectionKindRule, Name: "release/*", RequiredApprovingReviewCount: 2},
+		}
+		effective, protected := effectiveBranchProtection(rules)
+		assert.False(t, protected)
+		assert.Equal(t, []string{"default branch is not protected"}, branchProtectionViolations(effective, protected, policy))
+	})
+
+	t.Run("rules stack", func(t *testing.T) {
+		rules := []branchProtectionEntry{
+			{Kind: branchProtectionKindRule, Name: "main", AppliesToDefaultBranch: true, RequiredApprovingReviewCount: 1, RequiresStatusChecks: true, RequiredStatusChecks: []string{"ci/test"}},
+			{Kind: branchProtectionKindRuleset, Name: "org", AppliesToDefaultBranch: true, Enforcement: string(githubv4.RuleEnforcementActive), RequiredApprovingReviewCount: 2, RequiresSignedCommits: true},
+			{Kind: branchProtectionKindRuleset, Name: "evaluate", AppliesToDefaultBranch: true, Enforcement: "EVALUATE", BypassActors: []string{"team:grafana/admins"}},
+		}
+		effective, protected := effectiveBranchProtection(rules)
+		assert.True(t, protected)
+		assert.Equal(t, int64(2), effective.RequiredApprovingReviewCount)
+		assert.Equal(t, []string{"ci/test"}, effective.RequiredStatusChecks)
+		assert.Empty(t, branchProtectionViolations(effective, protected, policy))
+	})
+
+	t.Run("bypass actors and missing settings", func(t *testing.T) {
+		rules := []branchProtectionEntry{
+			{Kind: branchProtectionKindRule, Name: "main", AppliesToDefaultBranch: true, RequiredApprovingReviewCount: 1, BypassActors: []string{"repository_admin"}},
+		}
+		effective, protected := effectiveBranchProtection(rules)
+		assert.Equal(t, []string{
+			"requires 1 approving reviews, policy requires 2",
+			"status checks are not required",
+			"signed commits are not required",
+			"bypass actors are allowed: repository_admin",
+		}, branchProtectionViolations(effective, protected, policy))
+	})
+}
+
+func TestBranchProtectionDataFrame(t *testing.T) {
+	protection := BranchProtection{
+		Policy: models.BranchProtectionPolicy{
+			RequiredApprovingReviewCount: 1,
+			RequireLinearHistory:         true,
+		},
+		Repositories: []RepositoryBranchProtection{
+			{
+				Repository:    "grafana/grafana",
+				DefaultBranch: "main",
+				Rules: []branchProtectionEntry{
+					{
+						Kind:                         branchProtectionKindRule,
+						Name:                         "main",
+						Source:                       "grafana/grafana",
+						AppliesToDefaultBranch:       true,
+						RequiredApprovingReviewCount: 1,
+						RequiresStatusChecks:         true,
+						RequiredStatusChecks:         []string{"ci/build", "ci/test"},
+						BypassActors:                 []string{"team:grafana/release"},
+					},
+					{
+						Kind:                   branchProtectionKindRuleset,
+						Name:                   "linear history",
+						Source:                 "grafana",
+						Enforcement:            "ACTIVE",
+						AppliesToDefaultBranch: true,
+						RequiresLinearHistory:  true,
+					},
+				},
+			},
+			{
+				Repository:    "grafana/loki",
+				DefaultBranch: "main",
+				Rules:         []branchProtectionEntry{},
+			},
+		},
+	}
+
+	testutil.CheckGoldenFramer(t, "branch_protection", protection)
+}
@@ -0,0 +1,411 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryListCommitChecks is the GraphQL query for listing the check suites, check runs and commit statuses of a commit.
+// Only the first 100 check runs of every check suite are listed.
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    object(expression: "main") {
+//	      ... on Commit {
+//	        oid
+//	        checkSuites(first: 100) {
+//	          nodes {
+//	            app {
+//	              name
+//	            }
+//	            conclusion
+//	            checkRuns(first: 100) {
+//	              nodes {
+//	                name
+//	                conclusion
+//	              }
+//	            }
+//	          }
+//	        }
+//	        status {
+//	          contexts {
+//	            context
+//	            state
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListCommitChecks struct {
+	Repository struct {
+		Object *struct {
+			Commit CommitChecks `graphql:"... on Commit"`
+		} `graphql:"object(expression: $ref)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// QueryListCommitChecksInRange is the GraphQL query for listing the check suites, check runs and commit statuses of every commit of a ref in a time range.
+// GraphQL limits a query to 500,000 nodes, so it pages through 25 commits and their first 20 check suites at a time.
+type QueryListCommitChecksInRange struct {
+	Repository struct {
+		Object *struct {
+			Commit struct {
+				History struct {
+					Nodes    []HistoryCommitChecks
+					PageInfo models.PageInfo
+				} `graphql:"history(first: 25, after: $cursor, since: $since, until: $until)"`
+			} `graphql:"... on Commit"`
+		} `graphql:"object(expression: $ref)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// QueryPullRequestHead is the GraphQL query for retrieving the head commit of a pull request
+type QueryPullRequestHead struct {
+	Repository struct {
+		PullRequest *struct {
+			HeadRefOid string
+		} `graphql:"pullRequest(number: $number)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// CheckRun is a single check, like a CI job, reported by a GitHub App
+type CheckRun struct {
+	Name        string
+	Status      githubv4.CheckStatusState
+	Conclusion  githubv4.CheckConclusionState
+	StartedAt   githubv4.DateTime
+	CompletedAt githubv4.DateTime
+	DetailsURL  string `graphql:"detailsUrl"`
+}
+
+// CheckSuite is the group of check runs a GitHub App reported for a commit
+type CheckSuite struct {
+	App *struct {
+		Name string
+		Slug string
+	}
+	WorkflowRun *struct {
+		Workflow struct {
+			Name string
+		}
+	}
+	Status     githubv4.CheckStatusState
+	Conclusion githubv4.CheckConclusionState
+	CreatedAt  githubv4.DateTime
+	UpdatedAt  githubv4.DateTime
+	CheckRuns  struct {
+		TotalCount int64
+		Nodes      []CheckRun
+	} `graphql:"checkRuns(first: 100)"`
+}
+
+// appName returns the name of the app that reported the check suite
+func (s CheckSuite) appName() string {
+	if s.App == nil {
+		return ""
+	}
+	return s.App.Name
+}
+
+// workflowName returns the name of the GitHub Actions workflow of the check suite, which is empty for other apps
+func (s CheckSuite) workflowName() string {
+	if s.WorkflowRun == nil {
+		return ""
+	}
+	return s.WorkflowRun.Workflow.Name
+}
+
+// CommitStatusContext is a legacy commit status, reported by integrations that don't use the checks API
+type CommitStatusContext struct {
+	Context     string
+	State       githubv4.StatusState
+	Description string
+	TargetURL   string `graphql:"targetUrl"`
+	CreatedAt   githubv4.DateTime
+	Creator     *struct {
+		Login string
+	}
+}
+
+// CheckSuites is a page of the check suites of a commit
+type CheckSuites struct {
+	Nodes    []CheckSuite
+	PageInfo models.PageInfo
+}
+
+// CommitStatus is the combined legacy status of a commit
+type CommitStatus struct {
+	Contexts []CommitStatusContext
+}
+
+// CommitChecks are the check suites and commit statuses of a commit
+type CommitChecks struct {
+	OID         string      `graphql:"oid"`
+	CheckSuites CheckSuites `graphql:"checkSuites(first: 100, after: $cursor)"`
+	Status      *CommitStatus
+}
+
+// HistoryCommitChecks are the check suites and commit statuses of a commit listed in the history of a ref.
+// The cursor of the query pages through the history, so only the first check suites of the commit are listed.
+type HistoryCommitChecks struct {
+	OID         string      `graphql:"oid"`
+	CheckSuites CheckSuites `graphql:"checkSuites(first: 20)"`
+	Status      *CommitStatus
+}
+
+// Checks is the list of checks of one or more commits
+type Checks []CommitChecks
+
+// durationSeconds returns the seconds between two times, or nil when either of them is not set
+func durationSeconds(from time.Time, to time.Time) *float64 {
+	if from.IsZero() || to.IsZero() {
+		return nil
+	}
+	seconds := to.Sub(from).Seconds()
+	return &seconds
+}
+
+// Frames converts the checks to a frame of check suites, a frame of check runs and a frame of commit statuses
+func (c Checks) Frames() data.Frames {
+	suites := data.NewFrame(
+		"check_suites",
+		data.NewField("sha", nil, []string{}),
+		data.NewField("app", nil, []string{}),
+		data.NewField("workflow", nil, []string{}),
+		data.NewField("status", nil, []string{}),
+		data.NewField("conclusion", nil, []string{}),
+		data.NewField("check_runs", nil, []int64{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("updated_at", nil, []time.Time{}),
+		data.NewField("duration", nil, []*float64{}),
+	)
+
+	runs := data.NewFrame(
+		"check_runs",
+		data.NewField("sha", nil, []string{}),
+		data.NewField("app", nil, []string{}),
+		data.NewField("workflow", nil, []string{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("status", nil, []string{}),
+		data.NewField("conclusion", nil, []string{}),
+		data.NewField("started_at", nil, []*time.Time{}),
+		data.NewField("completed_at", nil, []*time.Time{}),
+		data.NewField("duration", nil, []*float64{}),
+		data.NewField("url", nil, []string{}),
+	)
+
+	statuses := data.NewFrame(
+		"commit_statuses",
+		data.NewField("sha", nil, []string{}),
+		data.NewField("context", nil, []string{}),
+		data.NewField("state", nil, []string{}),
+		data.NewField("description", nil, []string{}),
+		data.NewField("creator", nil, []string{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("url", nil, []string{}),
+	)
+
+	for _, commit := range c {
+		for _, suite := range commit.CheckSuites.Nodes {
+			var duration *float64
+			if suite.Status == githubv4.CheckStatusStateCompleted {
+				duration = durationSeconds(suite.CreatedAt.Time, suite.UpdatedAt.Time)
+			}
+
+			suites.AppendRow(
+				commit.OID,
+				suite.appName(),
+				suite.workflowName(),
+				string(suite.Status),
+				string(suite.Conclusion),
+				suite.CheckRuns.TotalCount,
+				suite.CreatedAt.Time,
+				suite.UpdatedAt.Time,
+				duration,
+			)
+
+			for _, run := range suite.CheckRuns.Nodes {
+				runs.AppendRow(
+					commit.OID,
+					suite.appName(),
+					suite.workflowName(),
+					run.Name,
+					string(run.Status),
+					string(run.Conclusion),
+					nullableTime(run.StartedAt.Time),
+					nullableTime(run.CompletedAt.Time),
+					durationSeconds(run.StartedAt.Time, run.CompletedAt.Time),
+					run.DetailsURL,
+				)
+			}
+		}
+
+		if commit.Status == nil {
+			continue
+		}
+		for _, status := range commit.Status.Contexts {
+			var creator string
+			if status.Creator != nil {
+				creator = status.Creator.Login
+			}
+
+			statuses.AppendRow(
+				commit.OID,
+				status.Context,
+				string(status.State),
+				status.Description,
+				creator,
+				status.CreatedAt.Time,
+				status.TargetURL,
+			)
+		}
+	}
+
+	return data.Frames{suites, runs, statuses}
+}
+
+// GetCommitChecks lists the check suites, check runs and commit statuses of the commit the ref points to
+func GetCommitChecks(ctx context.Context, client models.Client, owner string, repository string, ref string) (CommitChecks, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"name":   githubv4.String(repository),
+			"owner":  githubv4.String(owner),
+			"ref":    githubv4.String(ref),
+		}
+
+		checks CommitChecks
+	)
+
+	for {
+		q := &QueryListCommitChecks{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return CommitChecks{}, errors.WithStack(err)
+		}
+		if q.Repository.Object == nil {
+			return CommitChecks{}, backend.DownstreamError(fmt.Errorf("ref %s not found in %s/%s", ref, owner, repository))
+		}
+
+		commit := q.Repository.Object.Commit
+		if checks.OID == "" {
+			checks = commit
+		} else {
+			checks.CheckSuites.Nodes = append(checks.CheckSuites.Nodes, commit.CheckSuites.Nodes...)
+		}
+
+		if !commit.CheckSuites.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = commit.CheckSuites.PageInfo.EndCursor
+	}
+
+	return checks, nil
+}
+
+// GetPullRequestHead returns the SHA of the head commit of a pull request
+func GetPullRequestHead(ctx context.Context, client models.Client, owner string, repository string, number int64) (string, error) {
+	variables := map[string]interface{}{
+		"name":   githubv4.String(repository),
+		"owner":  githubv4.String(owner),
+		"number": githubv4.Int(number),
+	}
+
+	q := &QueryPullRequestHead{}
+	if err := client.Query(ctx, q, variables); err != nil {
+		return "", errors.WithStack(err)
+	}
+	if q.Repository.PullRequest == nil {
+		return "", backend.DownstreamError(fmt.Errorf("pull request #%d not found in %s/%s", number, owner, repository))
+	}
+
+	return q.Repository.PullRequest.HeadRefOid, nil
+}
+
+// GetCommitChecksInRange lists the check suites, check runs and commit statuses of every commit of the ref in the time range.
+// The checks are fetched along with the history of the ref, the rare commits with more check suites than the history query lists are completed one by one.
+func GetCommitChecksInRange(ctx context.Context, client models.Client, owner string, repository string, ref string, from time.Time, to time.Time) (Checks, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"name":   githubv4.String(repository),
+			"owner":  githubv4.String(owner),
+			"ref":    githubv4.String(ref),
+			"since":  githubv4.GitTimestamp{Time: from},
+			"until":  githubv4.GitTimestamp{Time: to},
+		}
+
+		checks = Checks{}
+	)
+
+	for {
+		q := &QueryListCommitChecksInRange{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if q.Repository.Object == nil {
+			return nil, backend.DownstreamError(fmt.Errorf("ref %s not found in %s/%s", ref, owner, repository))
+		}
+
+		history := q.Repository.Object.Commit.History
+		for _, commit := range history.Nodes {
+			if !commit.CheckSuites.PageInfo.HasNextPage {
+				checks = append(checks, CommitChecks(commit))
+				continue
+			}
+
+			c, err := GetCommitChecks(ctx, client, owner, repository, commit.OID)
+			if err != nil {
+				return nil, err
+			}
+			checks = append(checks, c)
+		}
+
+		if !history.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = history.PageInfo.EndCursor
+	}
+
+	return checks, nil
+}
+
+// GetChecksInRange lists the check suites, check runs and commit statuses of the commits selected by the options.
+// The time range is only used by the range source, which lists the checks of every commit of the ref in the range.
+// Without a ref, the default branch of the repository is used.
+func GetChecksInRange(ctx context.Context, client models.Client, opts models.ListChecksOptions, from time.Time, to time.Time) (Checks, error) {
+	if opts.Owner == "" || opts.Repository == "" {
+		return nil, nil
+	}
+
+	ref := opts.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	switch opts.Source {
+	case models.ChecksSourcePullRequest:
+		head, err := GetPullRequestHead(ctx, client, opts.Owner, opts.Repository, opts.PullRequest)
+		if err != nil {
+			return nil, err
+		}
+		ref = head
+	case models.ChecksSourceRange:
+		return GetCommitChecksInRange(ctx, client, opts.Owner, opts.Repository, ref, from, to)
+	}
+
+	checks, err := GetCommitChecks(ctx, client, opts.Owner, opts.Repository, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return Checks{checks}, nil
+}
@@ -0,0 +1,24 @@
+package github
+
+import (
+	"context"
+
+	"github.com/grafana/github-datasource/pkg/dfutil"
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func (s *QueryHandler) handleChecksQuery(ctx context.Context, q backend.DataQuery) backend.DataResponse {
+	query := &models.ChecksQuery{}
+	if err := UnmarshalQuery(q.JSON, query); err != nil {
+		return *err
+	}
+	return dfutil.FrameResponseWithError(s.Datasource.HandleChecksQuery(ctx, query, q))
+}
+
+// HandleChecks handles the plugin query for github Checks
+func (s *QueryHandler) HandleChecks(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return &backend.QueryDataResponse{
+		Responses: processQueries(ctx, req, s.handleChecksQuery),
+	}, nil
+}
@@ -0,0 +1,182 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetChecksInRange(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		opts = models.ListChecksOptions{
+			Repository: "grafana",
+			Owner:      "grafana",
+			Ref:        "main",
+		}
+	)
+
+	testVariables := testutil.GetTestVariablesFunction("cursor", "name", "owner", "ref")
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryListCommitChecks)
+		require.True(t, ok, "unexpected query type %T", q)
+		query.Repository.Object = &struct {
+			Commit CommitChecks `graphql:"... on Commit"`
+		}{Commit: CommitChecks{OID: "abc123"}}
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+
+	checks, err := GetChecksInRange(ctx, client, opts, time.Now().Add(-30*24*time.Hour), time.Now())
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Equal(t, "abc123", checks[0].OID)
+}
+
+func TestGetChecksInRangeDefaultsToHead(t *testing.T) {
+	client := testutil.NewTestClient(t, func(t *testing.T, variables map[string]interface{}) {
+		assert.Equal(t, githubv4.String("HEAD"), variables["ref"])
+	}, func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryListCommitChecks)
+		require.True(t, ok, "unexpected query type %T", q)
+		query.Repository.Object = &struct {
+			Commit CommitChecks `graphql:"... on Commit"`
+		}{Commit: CommitChecks{OID: "abc123"}}
+	})
+
+	checks, err := GetChecksInRange(context.Background(), client, models.ListChecksOptions{Repository: "grafana", Owner: "grafana"}, time.Time{}, time.Now())
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+}
+
+func TestGetChecksInRangeWithHistory(t *testing.T) {
+	var (
+		pages   = 0
+		fetched = 0
+	)
+
+	client := testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		switch query := q.(type) {
+		case *QueryListCommitChecksInRange:
+			pages++
+			query.Repository.Object = &struct {
+				Commit struct {
+					History struct {
+						Nodes    []HistoryCommitChecks
+						PageInfo models.PageInfo
+					} `graphql:"history(first: 25, after: $cursor, since: $since, until: $until)"`
+				} `graphql:"... on Commit"`
+			}{}
+
+			history := &query.Repository.Object.Commit.History
+			if pages == 1 {
+				busy := HistoryCommitChecks{OID: "abc123"}
+				busy.CheckSuites.PageInfo.HasNextPage = true
+				history.Nodes = []HistoryCommitChecks{busy, {OID: "def456"}}
+				history.PageInfo = models.PageInfo{HasNextPage: true, EndCursor: "1"}
+				return
+			}
+			history.Nodes = []HistoryCommitChecks{{OID: "ghi789"}}
+		case *QueryListCommitChecks:
+			// only the commit with more check suites than the history lists is fetched on its own
+			fetched++
+			query.Repository.Object = &struct {
+				Commit CommitChecks `graphql:"... on Commit"`
+			}{Commit: CommitChecks{OID: "abc123"}}
+		default:
+			t.Fatalf("unexpected query %T", q)
+		}
+	})
+
+	opts := models.ListChecksOptions{Repository: "grafana", Owner: "grafana", Ref: "main", Source: models.ChecksSourceRange}
+	checks, err := GetChecksInRange(context.Background(), client, opts, time.Now().Add(-24*time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, 1, fetched)
+
+	oids := make([]string, len(checks))
+	for i, c := range checks {
+		oids[i] = c.OID
+	}
+	assert.Equal(t, []string{"abc123", "def456", "ghi789"}, oids)
+}
+
+func TestGetPullRequestHeadNotFound(t *testing.T) {
+	testVariables := testutil.GetTestVariablesFunction("name", "owner", "number")
+	client := testutil.NewTestClient(t, testVariables, testutil.GetTestQueryFunction(&QueryPullRequestHead{}))
+
+	_, err := GetPullRequestHead(context.Background(), client, "grafana", "grafana", 12)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pull request #12 not found in grafana/grafana")
+}
+
+func TestChecksDataFrame(t *testing.T) {
+	startedAt, err := time.Parse(time.RFC3339, "2024-05-01T10:00:00Z")
+	require.NoError(t, err)
+
+	actions := CheckSuite{
+		App: &struct {
+			Name string
+			Slug string
+		}{Name: "GitHub Actions", Slug: "github-actions"},
+		WorkflowRun: &struct {
+			Workflow struct {
+				Name string
+			}
+		}{},
+		Status:     githubv4.CheckStatusStateCompleted,
+		Conclusion: githubv4.CheckConclusionStateSuccess,
+		CreatedAt:  githubv4.DateTime{Time: startedAt},
+		UpdatedAt:  githubv4.DateTime{Time: startedAt.Add(10 * time.Minute)},
+	}
+	actions.WorkflowRun.Workflow.Name = "CI"
+	actions.CheckRuns.TotalCount = 2
+	actions.CheckRuns.Nodes = []CheckRun{
+		{
+			Name:        "lint",
+			Status:      githubv4.CheckStatusStateCompleted,
+			Conclusion:  githubv4.CheckConclusionStateSuccess,
+			StartedAt:   githubv4.DateTime{Time: startedAt.Add(time.Minute)},
+			CompletedAt: githubv4.DateTime{Time: startedAt.Add(3 * time.Minute)},
+			DetailsURL:  "https://github.com/grafana/grafana/actions/runs/1/job/1",
+		},
+		{
+			Name:   "test",
+			Status: githubv4.CheckStatusStateQueued,
+		},
+	}
+
+	buildkite := CheckSuite{
+		App: &struct {
+			Name string
+			Slug string
+		}{Name: "Buildkite", Slug: "buildkite"},
+		Status:    githubv4.CheckStatusStateInProgress,
+		CreatedAt: githubv4.DateTime{Time: startedAt},
+		UpdatedAt: githubv4.DateTime{Time: startedAt.Add(5 * time.Minute)},
+	}
+
+	commit := CommitChecks{OID: "abc123"}
+	commit.CheckSuites.Nodes = []CheckSuite{actions, buildkite}
+	commit.Status = &CommitStatus{
+		Contexts: []CommitStatusContext{
+			{
+				Context:     "ci/circleci: build",
+				State:       githubv4.StatusStateFailure,
+				Description: "Your tests failed on CircleCI",
+				TargetURL:   "https://circleci.com/gh/grafana/grafana/1",
+				CreatedAt:   githubv4.DateTime{Time: startedAt.Add(4 * time.Minute)},
+				Creator:     &struct{ Login string }{Login: "circleci"},
+			},
+		},
+	}
+
+	testutil.CheckGoldenFramer(t, "checks", Checks{commit, {OID: "def456"}})
+}
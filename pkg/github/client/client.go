@@ -26,6 +26,7 @@ type Client struct {
 	restClient    *googlegithub.Client
 	graphqlClient *githubv4.Client
 	authType      models.AuthType
+	registry      *containerRegistry
 }
 
 const (
@@ -82,18 +83,25 @@ func createAppClient(settings models.Settings, opts httpclient.Options) (*Client
 		return nil, backend.DownstreamError(errors.New("error creating token source"))
 	}
 
+	registry := &containerRegistry{
+		url:         containerRegistryGitHubURL,
+		httpClient:  &http.Client{Transport: httpClient.Transport},
+		credentials: itr.Token,
+	}
+
 	httpClient.Transport = itr
 	if settings.GitHubURL == "" {
 		return &Client{
 			restClient:    googlegithub.NewClient(httpClient),
 			graphqlClient: githubv4.NewClient(httpClient),
 			authType:      models.AuthTypeGithubApp,
+			registry:      registry,
 		}, nil
 	}
 
 	itr.BaseURL = fmt.Sprintf("%s/api/v3", settings.GitHubURL)
 
-	return useGitHubEnterprise(httpClient, settings, models.AuthTypeGithubApp)
+	return useGitHubEnterprise(httpClient, settings, models.AuthTypeGithubApp, registry)
 }
 
 func createAccessTokenClient(ctx context.Context, settings models.Settings, opts httpclient.Options) (*Client, error) {
@@ -116,18 +124,32 @@ func createAccessTokenClient(ctx context.Context, settings models.Settings, opts
 			Source: oauth2.ReuseTokenSource(nil, src),
 		}
 	}
+
+	registryClient, err := httpclient.New(opts)
+	if err != nil {
+		return nil, backend.DownstreamErrorf("error creating http client: %w", err)
+	}
+	registry := &containerRegistry{
+		url:        containerRegistryGitHubURL,
+		httpClient: registryClient,
+		credentials: func(context.Context) (string, error) {
+			return settings.AccessToken, nil
+		},
+	}
+
 	if settings.GitHubURL == "" {
 		return &Client{
 			restClient:    googlegithub.NewClient(httpClient),
 			graphqlClient: githubv4.NewClient(httpClient),
 			authType:      models.AuthTypePAT,
+			registry:      registry,
 		}, nil
 	}
 
-	return useGitHubEnterprise(httpClient, settings, models.AuthTypePAT)
+	return useGitHubEnterprise(httpClient, settings, models.AuthTypePAT, registry)
 }
 
-func useGitHubEnterprise(httpClient *http.Client, settings models.Settings, authType models.AuthType) (*Client, error) {
+func useGitHubEnterprise(httpClient *http.Client, settings models.Settings, authType models.AuthType, registry *containerRegistry) (*Client, error) {
 	_, err := url.Parse(settings.GitHubURL)
 	if err != nil {
 		return nil, backend.DownstreamError(errors.New("incorrect enterprise url"))
@@ -138,10 +160,16 @@ func useGitHubEnterprise(httpClient *http.Client, settings models.Settings, auth
 		return nil, backend.DownstreamError(errors.New("instantiating enterprise rest client"))
 	}
 
+	registry.url, err = containerRegistryURL(settings.GitHubURL)
+	if err != nil {
+		return nil, backend.DownstreamError(errors.New("incorrect enterprise url"))
+	}
+
 	return &Client{
 		restClient:    restClient,
 		graphqlClient: githubv4.NewEnterpriseClient(fmt.Sprintf("%s/api/graphql", settings.GitHubURL), httpClient),
 		authType:      authType,
+		registry:      registry,
 	}, nil
 }
 
@@ -244,6 +272,24 @@ func (client *Client) ListPullRequestFiles(ctx context.Context, owner, repo stri
 	return files, resp, nil
 }
 
+// CompareCommits compares two commits, branches or tags, returning the commits and the files the head has and the base doesn't.
+func (client *Client) CompareCommits(ctx context.Context, owner, repo, base, head string, opts *googlegithub.ListOptions) (*googlegithub.CommitsComparison, *googlegithub.Response, error) {
+	comparison, resp, err := client.restClient.Repositories.CompareCommits(ctx, owner, repo, base, head, opts)
+	if err != nil {
+		return nil, nil, addErrorSourceToError(err, resp)
+	}
+	return comparison, resp, nil
+}
+
+// GetTree returns the tree of a commit, branch or tag. When recursive is true, it includes every file of the repository up to the GitHub limits.
+func (client *Client) GetTree(ctx context.Context, owner, repo, sha string, recursive bool) (*googlegithub.Tree, *googlegithub.Response, error) {
+	tree, resp, err := client.restClient.Git.GetTree(ctx, owner, repo, sha, recursive)
+	if err != nil {
+		return nil, nil, addErrorSourceToError(err, resp)
+	}
+	return tree, resp, nil
+}
+
 // GetWorkflowUsage returns the workflow usage for a specific workflow.
 func (client *Client) GetWorkflowUsage(ctx context.Context, owner, repo, workflow string, timeRange backend.TimeRange) (models.WorkflowUsage, error) {
 	actors := make(map[string]struct{}, 0)
@@ -438,3 +484,76 @@ func (client *Client) getWorkflowRuns(ctx context.Context, owner, repo, workflow
 
 	return workflowRuns, response.NextPage, nil
 }
+
+// ListPackagesForOrg sends a request to the GitHub rest API to list the packages of an organization.
+func (client *Client) ListPackagesForOrg(ctx context.Context, org string, opts *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	packages, resp, err := client.restClient.Organizations.ListPackages(ctx, org, opts)
+	if err != nil {
+		return nil, nil, addErrorSourceToError(err, resp)
+	}
+	return packages, resp, nil
+}
+
+// ListPackagesForUser sends a request to the GitHub rest API to list the packages of a user.
+func (client *Client) ListPackagesForUser(ctx context.Context, user string, opts *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	packages, resp, err := client.restClient.Users.ListPackages(ctx, user, opts)
+	if err != nil {
+		return nil, nil, addErrorSourceToError(err, resp)
+	}
+	return packages, resp, nil
+}
+
+// ListPackageVersionsForOrg sends a request to the GitHub rest API to list the versions of a package of an organization.
+func (client *Client) ListPackageVersionsForOrg(ctx context.Context, org, packageType, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	versions, resp, err := client.restClient.Organizations.PackageGetAllVersions(ctx, org, packageType, packageName, opts)
+	if err != nil {
+		return nil, nil, addErrorSourceToError(err, resp)
+	}
+	return versions, resp, nil
+}
+
+// ListPackageVersionsForUser sends a request to the GitHub rest API to list the versions of a package of a user.
+// go-github does not paginate the versions of the packages of another user, so the request is built here.
+func (client *Client) ListPackageVersionsForUser(ctx context.Context, user, packageType, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	u := fmt.Sprintf("users/%s/packages/%s/%s/versions?page=%d&per_page=%d", url.PathEscape(user), url.PathEscape(packageType), url.PathEscape(packageName), opts.Page, opts.PerPage)
+	req, err := client.restClient.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var versions []*googlegithub.PackageVersion
+	resp, err := client.restClient.Do(ctx, req, &versions)
+	if err != nil {
+		return nil, nil, addErrorSourceToError(err, resp)
+	}
+	return versions, resp, nil
+}
+
+// GetSBOM sends a request to the GitHub rest API to export the software bill of materials of a repository from its dependency graph.
+// The response is returned with the error, the status tells the repositories without a dependency graph apart.
+func (client *Client) GetSBOM(ctx context.Context, owner, repo string) (*googlegithub.SBOM, *googlegithub.Response, error) {
+	sbom, resp, err := client.restClient.DependencyGraph.GetSBOM(ctx, owner, repo)
+	if err != nil {
+		return nil, resp, addErrorSourceToError(err, resp)
+	}
+	return sbom, resp, nil
+}
+
+// GetRepository sends a request to the GitHub rest API to get a repository, with the security and analysis settings the GraphQL API does not have.
+func (client *Client) GetRepository(ctx context.Context, owner, repo string) (*googlegithub.Repository, *googlegithub.Response, error) {
+	repository, resp, err := client.restClient.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return nil, nil, addErrorSourceToError(err, resp)
+	}
+	return repository, resp, nil
+}
+
+// GetCodeScanningDefaultSetup sends a request to the GitHub rest API to get the code scanning default setup of a repository.
+// The response is returned with the error, the status tells the repositories where code scanning is not available apart.
+func (client *Client) GetCodeScanningDefaultSetup(ctx context.Context, owner, repo string) (*googlegithub.DefaultSetupConfiguration, *googlegithub.Response, error) {
+	config, resp, err := client.restClient.CodeScanning.GetDefaultSetupConfiguration(ctx, owner, repo)
+	if err != nil {
+		return nil, resp, addErrorSourceToError(err, resp)
+	}
+	return config, resp, nil
+}
@@ -0,0 +1,174 @@
+package githubclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+const (
+	mediaTypeOCIIndex          = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest       = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerList        = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest    = "application/vnd.docker.distribution.manifest.v2+json"
+	containerRegistryGitHubURL = "https://ghcr.io"
+)
+
+// containerRegistry gets the manifests of the container images from the GitHub container registry.
+// The rest API lists the versions of the images and their tags, but not their platforms and sizes.
+type containerRegistry struct {
+	url        string
+	httpClient *http.Client
+	// credentials returns the token used to get a pull token from the registry
+	credentials func(ctx context.Context) (string, error)
+}
+
+// containerRegistryURL returns the URL of the container registry of a GitHub Enterprise server, which uses a subdomain
+func containerRegistryURL(githubURL string) (string, error) {
+	u, err := url.Parse(githubURL)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s://containers.%s", u.Scheme, u.Host), nil
+}
+
+// registryManifest is a manifest of the registry API, an image manifest or an index of the images of each platform
+type registryManifest struct {
+	MediaType string `json:"mediaType"`
+	Config    struct {
+		Size int64 `json:"size"`
+	} `json:"config"`
+	Layers []struct {
+		Size int64 `json:"size"`
+	} `json:"layers"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+			Variant      string `json:"variant"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+func (m registryManifest) size() int64 {
+	size := m.Config.Size
+	for _, l := range m.Layers {
+		size += l.Size
+	}
+	return size
+}
+
+// GetContainerManifest gets the manifest of a container image from the container registry. The reference is a tag or a digest.
+// The size of a multi-platform image is the sum of the sizes of its platforms, which are read from their own manifests.
+func (client *Client) GetContainerManifest(ctx context.Context, owner, name, reference string) (*models.ContainerManifest, error) {
+	if client.registry == nil {
+		return nil, backend.DownstreamError(errors.New("the container registry is not available"))
+	}
+
+	repository := strings.ToLower(owner + "/" + name)
+	token, err := client.registry.token(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	m, digest, err := client.registry.manifest(ctx, token, repository, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &models.ContainerManifest{Digest: digest, MediaType: m.MediaType, Size: m.size()}
+	if len(m.Manifests) == 0 {
+		return manifest, nil
+	}
+
+	for _, p := range m.Manifests {
+		// attestations are stored in the index as the platform unknown/unknown
+		if p.Platform.OS == "unknown" {
+			continue
+		}
+		image, _, err := client.registry.manifest(ctx, token, repository, p.Digest)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Platforms = append(manifest.Platforms, models.ContainerPlatform{
+			Digest:       p.Digest,
+			OS:           p.Platform.OS,
+			Architecture: p.Platform.Architecture,
+			Variant:      p.Platform.Variant,
+			Size:         image.size(),
+		})
+		manifest.Size += image.size()
+	}
+	return manifest, nil
+}
+
+// token exchanges the credentials of the data source for a token allowing to pull the repository
+func (r *containerRegistry) token(ctx context.Context, repository string) (string, error) {
+	credentials, err := r.credentials(ctx)
+	if err != nil {
+		return "", backend.DownstreamErrorf("getting the container registry credentials: %w", err)
+	}
+
+	u := fmt.Sprintf("%s/token?scope=%s", r.url, url.QueryEscape("repository:"+repository+":pull"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth("x-access-token", credentials)
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	if _, err := r.do(req, &body); err != nil {
+		return "", fmt.Errorf("getting a container registry token: %w", err)
+	}
+	return body.Token, nil
+}
+
+// manifest gets a manifest from the registry and returns it with its digest
+func (r *containerRegistry) manifest(ctx context.Context, token, repository, reference string) (registryManifest, string, error) {
+	m := registryManifest{}
+	u := fmt.Sprintf("%s/v2/%s/manifests/%s", r.url, repository, reference)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return m, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", strings.Join([]string{mediaTypeOCIIndex, mediaTypeOCIManifest, mediaTypeDockerList, mediaTypeDockerManifest}, ", "))
+
+	header, err := r.do(req, &m)
+	if err != nil {
+		return m, "", fmt.Errorf("getting the manifest of %s:%s: %w", repository, reference, err)
+	}
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" && strings.HasPrefix(reference, "sha256:") {
+		digest = reference
+	}
+	return m, digest, nil
+}
+
+func (r *containerRegistry) do(req *http.Request, v any) (http.Header, error) {
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("container registry returned %s", resp.Status)
+		if backend.ErrorSourceFromHTTPStatus(resp.StatusCode) == backend.ErrorSourceDownstream {
+			return nil, backend.DownstreamError(err)
+		}
+		return nil, err
+	}
+	return resp.Header, json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,96 @@
+package githubclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+func TestGetContainerManifest(t *testing.T) {
+	manifests := map[string]any{
+		"latest": map[string]any{
+			"mediaType": mediaTypeOCIIndex,
+			"manifests": []any{
+				map[string]any{"digest": "sha256:amd64", "platform": map[string]any{"os": "linux", "architecture": "amd64"}},
+				map[string]any{"digest": "sha256:arm64", "platform": map[string]any{"os": "linux", "architecture": "arm64", "variant": "v8"}},
+				map[string]any{"digest": "sha256:attestation", "platform": map[string]any{"os": "unknown", "architecture": "unknown"}},
+			},
+		},
+		"sha256:amd64": map[string]any{"mediaType": mediaTypeOCIManifest, "config": map[string]any{"size": 10}, "layers": []any{map[string]any{"size": 100}, map[string]any{"size": 200}}},
+		"sha256:arm64": map[string]any{"mediaType": mediaTypeOCIManifest, "config": map[string]any{"size": 10}, "layers": []any{map[string]any{"size": 50}}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			_, password, _ := r.BasicAuth()
+			if password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "repository:grafana/grafana:pull", r.URL.Query().Get("scope"))
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "pull-token"})
+		default:
+			assert.Equal(t, "Bearer pull-token", r.Header.Get("Authorization"))
+			reference := r.URL.Path[len("/v2/grafana/grafana/manifests/"):]
+			m, ok := manifests[reference]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if reference == "latest" {
+				w.Header().Set("Docker-Content-Digest", "sha256:index")
+			}
+			_ = json.NewEncoder(w).Encode(m)
+		}
+	}))
+	defer server.Close()
+
+	credentials := "secret"
+	client := &Client{registry: &containerRegistry{
+		url:         server.URL,
+		httpClient:  server.Client(),
+		credentials: func(context.Context) (string, error) { return credentials, nil },
+	}}
+
+	manifest, err := client.GetContainerManifest(context.Background(), "Grafana", "grafana", "latest")
+	require.NoError(t, err)
+	assert.Equal(t, &models.ContainerManifest{
+		Digest:    "sha256:index",
+		MediaType: mediaTypeOCIIndex,
+		Size:      370,
+		Platforms: []models.ContainerPlatform{
+			{Digest: "sha256:amd64", OS: "linux", Architecture: "amd64", Size: 310},
+			{Digest: "sha256:arm64", OS: "linux", Architecture: "arm64", Variant: "v8", Size: 60},
+		},
+	}, manifest)
+
+	manifest, err = client.GetContainerManifest(context.Background(), "grafana", "grafana", "sha256:amd64")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:amd64", manifest.Digest)
+	assert.Equal(t, int64(310), manifest.Size)
+	assert.Empty(t, manifest.Platforms)
+
+	_, err = client.GetContainerManifest(context.Background(), "grafana", "grafana", "missing")
+	require.Error(t, err)
+	assert.True(t, backend.IsDownstreamError(err))
+
+	credentials = "wrong"
+	_, err = client.GetContainerManifest(context.Background(), "grafana", "grafana", "latest")
+	require.Error(t, err)
+	assert.True(t, backend.IsDownstreamError(err))
+}
+
+func TestContainerRegistryURL(t *testing.T) {
+	u, err := containerRegistryURL("https://github.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "https://containers.github.example.com", u)
+}
@@ -0,0 +1,426 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// CodeownersLocations are the paths GitHub looks for a CODEOWNERS file at, in the order it looks for them
+var CodeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeownersRule is a line of a CODEOWNERS file: the files matching the pattern are owned by the owners.
+// A rule without owners means that the matching files have no owner.
+type CodeownersRule struct {
+	Line    int64
+	Pattern string
+	Owners  []string
+
+	re *regexp.Regexp
+}
+
+// Matches returns true if the file path matches the pattern of the rule
+func (r CodeownersRule) Matches(path string) bool {
+	return r.re.MatchString(path)
+}
+
+// CodeownersError is a line of a CODEOWNERS file that GitHub ignores or partially ignores
+type CodeownersError struct {
+	Line   int64
+	Text   string
+	Reason string
+}
+
+// Codeowners is a parsed CODEOWNERS file
+type Codeowners struct {
+	// Path is where the file was found, it is empty when the repository has no CODEOWNERS file
+	Path   string
+	Rules  []CodeownersRule
+	Errors []CodeownersError
+}
+
+// Match returns the rule that applies to the file: like GitHub, the last matching rule wins
+func (c Codeowners) Match(path string) (CodeownersRule, bool) {
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].Matches(path) {
+			return c.Rules[i], true
+		}
+	}
+	return CodeownersRule{}, false
+}
+
+// owners returns the owners of a file, or nothing when no rule matches it or the matching rule has no owners
+func (c Codeowners) owners(path string) []string {
+	rule, ok := c.Match(path)
+	if !ok {
+		return nil
+	}
+	return rule.Owners
+}
+
+// Owners returns every distinct owner referenced by the rules, in the order they first appear
+func (c Codeowners) Owners() []string {
+	var (
+		seen   = map[string]bool{}
+		owners = []string{}
+	)
+	for _, r := range c.Rules {
+		for _, o := range r.Owners {
+			if !seen[o] {
+				seen[o] = true
+				owners = append(owners, o)
+			}
+		}
+	}
+	return owners
+}
+
+var codeownersOwnerRegexp = regexp.MustCompile(`^(@[A-Za-z0-9-]+(/[A-Za-z0-9_.-]+)?|[^@\s]+@[^@\s]+)$`)
+
+// ParseCodeowners parses the content of a CODEOWNERS file.
+// Lines GitHub does not support, like negated patterns or character ranges, are reported as errors and skipped.
+func ParseCodeowners(path string, content string) Codeowners {
+	c := Codeowners{
+		Path:   path,
+		Rules:  []CodeownersRule{},
+		Errors: []CodeownersError{},
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		number := int64(i + 1)
+		text := strings.TrimSpace(line)
+		if idx := strings.Index(text, " #"); idx >= 0 {
+			text = strings.TrimSpace(text[:idx])
+		}
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		pattern := fields[0]
+		rule := CodeownersRule{Line: number, Pattern: pattern, Owners: []string{}}
+
+		re, err := codeownersPatternToRegexp(pattern)
+		if err != nil {
+			c.Errors = append(c.Errors, CodeownersError{Line: number, Text: text, Reason: err.Error()})
+			continue
+		}
+		rule.re = re
+
+		for _, owner := range fields[1:] {
+			if !codeownersOwnerRegexp.MatchString(owner) {
+				c.Errors = append(c.Errors, CodeownersError{Line: number, Text: text, Reason: fmt.Sprintf("invalid owner %q", owner)})
+				continue
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+
+		c.Rules = append(c.Rules, rule)
+	}
+
+	return c
+}
+
+// codeownersPatternToRegexp converts a CODEOWNERS pattern to a regular expression matching file paths.
+// It follows the gitignore rules GitHub documents for CODEOWNERS files:
+// patterns without a slash match at any depth, patterns ending with a slash match everything in the directory,
+// and patterns whose last segment has a wildcard (like docs/*) don't match the files of nested directories.
+func codeownersPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		return nil, errors.New("negated patterns are not supported")
+	case strings.ContainsAny(pattern, "[]"):
+		return nil, errors.New("character ranges are not supported")
+	case strings.HasPrefix(pattern, `\#`):
+		return nil, errors.New("escaped patterns are not supported")
+	}
+
+	var (
+		anchored  = strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+		directory = strings.HasSuffix(pattern, "/")
+		trimmed   = strings.Trim(pattern, "/")
+	)
+	if trimmed == "" {
+		return regexp.Compile("^.*$")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(.*/)?")
+	}
+
+	segments := strings.Split(trimmed, "/")
+	for i, s := range segments {
+		last := i == len(segments)-1
+		if s == "**" {
+			if last {
+				sb.WriteString(".*")
+			} else {
+				sb.WriteString("(.*/)?")
+			}
+			continue
+		}
+
+		for _, c := range s {
+			switch c {
+			case '*':
+				sb.WriteString("[^/]*")
+			case '?':
+				sb.WriteString("[^/]")
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		if !last {
+			sb.WriteString("/")
+		}
+	}
+
+	last := segments[len(segments)-1]
+	switch {
+	case directory:
+		sb.WriteString("/.*")
+	case last == "**" || strings.ContainsAny(last, "*?"):
+	default:
+		// a plain name matches a file or every file of a directory
+		sb.WriteString("(/.*)?")
+	}
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
+
+// QueryGetFileText is the GraphQL query for retrieving the content of a text file at a ref
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    object(expression: "main:.github/CODEOWNERS") {
+//	      ... on Blob {
+//	        text
+//	      }
+//	    }
+//	  }
+//	}
+type QueryGetFileText struct {
+	Repository struct {
+		Object *struct {
+			Blob struct {
+				IsBinary bool
+				Text     *string
+			} `graphql:"... on Blob"`
+		} `graphql:"object(expression: $expression)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// GetFileText returns the content of a text file at a ref, or false if the file does not exist
+func GetFileText(ctx context.Context, client models.Client, owner string, repo string, ref string, path string) (string, bool, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	q := &QueryGetFileText{}
+	variables := map[string]interface{}{
+		"owner":      githubv4.String(owner),
+		"name":       githubv4.String(repo),
+		"expression": githubv4.String(fmt.Sprintf("%s:%s", ref, path)),
+	}
+	if err := client.Query(ctx, q, variables); err != nil {
+		return "", false, errors.WithStack(err)
+	}
+
+	if q.Repository.Object == nil || q.Repository.Object.Blob.IsBinary || q.Repository.Object.Blob.Text == nil {
+		return "", false, nil
+	}
+	return *q.Repository.Object.Blob.Text, true, nil
+}
+
+// GetCodeowners fetches and parses the CODEOWNERS file of a repository at a ref.
+// It returns an empty Codeowners, without a path, when the repository has no CODEOWNERS file.
+func GetCodeowners(ctx context.Context, client models.Client, owner string, repo string, ref string) (Codeowners, error) {
+	for _, path := range CodeownersLocations {
+		content, ok, err := GetFileText(ctx, client, owner, repo, ref, path)
+		if err != nil {
+			return Codeowners{}, err
+		}
+		if ok {
+			return ParseCodeowners(path, content), nil
+		}
+	}
+
+	return ParseCodeowners("", ""), nil
+}
+
+// Codeowner kinds
+const (
+	CodeownerUser  = "user"
+	CodeownerTeam  = "team"
+	CodeownerEmail = "email"
+)
+
+// CodeownerStatus tells whether a code owner exists and has been active recently
+type CodeownerStatus struct {
+	Owner string
+	Kind  string
+	// Exists is false when the user or the team can't be found. Email owners are not checked and always exist.
+	Exists bool
+	// Active is true when the user, or at least one member of the team, contributed since the start of the window
+	Active bool
+	// Members is the number of members of a team
+	Members int64
+}
+
+// Reason explains why the owner is not valid or not active, it is empty for active owners
+func (s CodeownerStatus) Reason(since time.Time) string {
+	switch {
+	case !s.Exists:
+		return fmt.Sprintf("%s not found", s.Kind)
+	case s.Active:
+		return ""
+	case s.Kind == CodeownerTeam && s.Members == 0:
+		return "team has no members"
+	case s.Kind == CodeownerTeam:
+		return fmt.Sprintf("no team member contributed since %s", since.Format(time.DateOnly))
+	default:
+		return fmt.Sprintf("no contributions since %s", since.Format(time.DateOnly))
+	}
+}
+
+type contributions struct {
+	HasAnyContributions          bool
+	RestrictedContributionsCount int64
+}
+
+func (c contributions) hasAny() bool {
+	return c.HasAnyContributions || c.RestrictedContributionsCount > 0
+}
+
+// QueryCodeownerUser is the GraphQL query for checking whether a user exists and contributed since a date
+//
+//	{
+//	  repositoryOwner(login: "octocat") {
+//	    ... on User {
+//	      contributionsCollection(from: "2020-08-19T00:00:00Z") {
+//	        hasAnyContributions
+//	      }
+//	    }
+//	  }
+//	}
+type QueryCodeownerUser struct {
+	RepositoryOwner *struct {
+		Typename string `graphql:"__typename"`
+		User     struct {
+			ContributionsCollection contributions `graphql:"contributionsCollection(from: $from)"`
+		} `graphql:"... on User"`
+	} `graphql:"repositoryOwner(login: $login)"`
+}
+
+// QueryCodeownerTeam is the GraphQL query for checking whether a team exists and whether its members contributed since a date
+//
+//	{
+//	  repositoryOwner(login: "grafana") {
+//	    ... on Organization {
+//	      team(slug: "backend") {
+//	        members(first: 100) {
+//	          nodes {
+//	            contributionsCollection(from: "2020-08-19T00:00:00Z") {
+//	              hasAnyContributions
+//	            }
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryCodeownerTeam struct {
+	RepositoryOwner *struct {
+		Organization struct {
+			Team *struct {
+				Members struct {
+					TotalCount int64
+					Nodes      []struct {
+						ContributionsCollection contributions `graphql:"contributionsCollection(from: $from)"`
+					}
+					PageInfo models.PageInfo
+				} `graphql:"members(first: 100, after: $cursor)"`
+			} `graphql:"team(slug: $slug)"`
+		} `graphql:"... on Organization"`
+	} `graphql:"repositoryOwner(login: $login)"`
+}
+
+// GetCodeownerStatus checks whether a code owner (@user, @org/team or an email address) exists and contributed since a date
+func GetCodeownerStatus(ctx context.Context, client models.Client, owner string, since time.Time) (CodeownerStatus, error) {
+	if !strings.HasPrefix(owner, "@") {
+		return CodeownerStatus{Owner: owner, Kind: CodeownerEmail, Exists: true, Active: true}, nil
+	}
+
+	login, slug, isTeam := strings.Cut(strings.TrimPrefix(owner, "@"), "/")
+	if !isTeam {
+		status := CodeownerStatus{Owner: owner, Kind: CodeownerUser}
+		q := &QueryCodeownerUser{}
+		variables := map[string]interface{}{
+			"login": githubv4.String(login),
+			"from":  githubv4.DateTime{Time: since},
+		}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return CodeownerStatus{}, errors.WithStack(err)
+		}
+		if q.RepositoryOwner == nil || q.RepositoryOwner.Typename != "User" {
+			return status, nil
+		}
+		status.Exists = true
+		status.Active = q.RepositoryOwner.User.ContributionsCollection.hasAny()
+		return status, nil
+	}
+
+	status := CodeownerStatus{Owner: owner, Kind: CodeownerTeam}
+	variables := map[string]interface{}{
+		"cursor": (*githubv4.String)(nil),
+		"login":  githubv4.String(login),
+		"slug":   githubv4.String(slug),
+		"from":   githubv4.DateTime{Time: since},
+	}
+	for {
+		q := &QueryCodeownerTeam{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return CodeownerStatus{}, errors.WithStack(err)
+		}
+		if q.RepositoryOwner == nil || q.RepositoryOwner.Organization.Team == nil {
+			return status, nil
+		}
+
+		members := q.RepositoryOwner.Organization.Team.Members
+		status.Exists = true
+		status.Members = members.TotalCount
+		for _, m := range members.Nodes {
+			if m.ContributionsCollection.hasAny() {
+				status.Active = true
+				return status, nil
+			}
+		}
+		if !members.PageInfo.HasNextPage {
+			return status, nil
+		}
+		variables["cursor"] = members.PageInfo.EndCursor
+	}
+}
+
+// GetCodeownerStatuses checks every owner referenced by a CODEOWNERS file
+func GetCodeownerStatuses(ctx context.Context, client models.Client, codeowners Codeowners, since time.Time) (map[string]CodeownerStatus, error) {
+	statuses := map[string]CodeownerStatus{}
+	for _, owner := range codeowners.Owners() {
+		status, err := GetCodeownerStatus(ctx, client, owner, since)
+		if err != nil {
+			return nil, err
+		}
+		statuses[owner] = status
+	}
+	return statuses, nil
+}
@@ -0,0 +1,469 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// CodeownersPullRequestLimit is the number of pull requests in the time range whose files are listed, with a REST call each
+const CodeownersPullRequestLimit = 100
+
+// CodeownersCoverage is the number of files of a directory that have an owner
+type CodeownersCoverage struct {
+	Path       string
+	Files      int64
+	OwnedFiles int64
+}
+
+// Coverage returns the share of the files of the directory that have an owner
+func (c CodeownersCoverage) Coverage() float64 {
+	if c.Files == 0 {
+		return 0
+	}
+	return float64(c.OwnedFiles) / float64(c.Files)
+}
+
+// CodeownersProblem is a line of a CODEOWNERS file that GitHub ignores, or an owner that doesn't exist
+type CodeownersProblem struct {
+	Line   int64
+	Text   string
+	Owner  string
+	Reason string
+}
+
+// CodeownersPullRequest is a pull request touching owned files, along with the owners that approved it
+type CodeownersPullRequest struct {
+	Number     int64
+	Title      string
+	URL        string
+	Files      int64
+	OwnedFiles int64
+	// Owners are the owners of the files changed by the pull request
+	Owners []string
+	// ApprovingOwners are the owners that approved the pull request, directly or through a member of their team
+	ApprovingOwners []string
+	// OwnerApproved is true when every owned file was approved by at least one of its owners
+	OwnerApproved bool
+}
+
+// CodeownerApprovals is how often the pull requests touching the files of an owner were approved by that owner
+type CodeownerApprovals struct {
+	Owner        string
+	PullRequests int64
+	Approved     int64
+}
+
+// ApprovalRate returns the share of the pull requests touching the files of the owner that the owner approved
+func (a CodeownerApprovals) ApprovalRate() float64 {
+	if a.PullRequests == 0 {
+		return 0
+	}
+	return float64(a.Approved) / float64(a.PullRequests)
+}
+
+// CodeownersReport is the coverage, the problems and the review routing of a CODEOWNERS file
+type CodeownersReport struct {
+	Codeowners   Codeowners
+	Coverage     []CodeownersCoverage
+	UnownedFiles []string
+	// TreeTruncated is true when the repository has more files than GitHub returns, the coverage is then partial
+	TreeTruncated bool
+	Problems      []CodeownersProblem
+	PullRequests  []CodeownersPullRequest
+	// PullRequestsTruncated is true when the time range has more than CodeownersPullRequestLimit pull requests, only the first ones are checked
+	PullRequestsTruncated bool
+	Approvals             []CodeownerApprovals
+}
+
+// Frames converts the report to the coverage, unowned files, problems, pull requests and approvals frames
+func (r CodeownersReport) Frames() data.Frames {
+	coverage := data.NewFrame(
+		"codeowners_coverage",
+		data.NewField("directory", nil, []string{}),
+		data.NewField("files", nil, []int64{}),
+		data.NewField("owned_files", nil, []int64{}),
+		data.NewField("unowned_files", nil, []int64{}),
+		data.NewField("coverage", nil, []float64{}),
+	)
+	for _, c := range r.Coverage {
+		coverage.AppendRow(c.Path, c.Files, c.OwnedFiles, c.Files-c.OwnedFiles, c.Coverage())
+	}
+	coverage.Meta = &data.FrameMeta{Custom: map[string]interface{}{
+		"codeowners_path": r.Codeowners.Path,
+		"tree_truncated":  r.TreeTruncated,
+	}}
+
+	unowned := data.NewFrame(
+		"unowned_files",
+		data.NewField("path", nil, []string{}),
+	)
+	for _, f := range r.UnownedFiles {
+		unowned.AppendRow(f)
+	}
+
+	problems := data.NewFrame(
+		"codeowners_errors",
+		data.NewField("line", nil, []int64{}),
+		data.NewField("text", nil, []string{}),
+		data.NewField("owner", nil, []string{}),
+		data.NewField("reason", nil, []string{}),
+	)
+	for _, p := range r.Problems {
+		problems.AppendRow(p.Line, p.Text, p.Owner, p.Reason)
+	}
+
+	pullRequests := data.NewFrame(
+		"codeowners_pull_requests",
+		data.NewField("number", nil, []int64{}),
+		data.NewField("title", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("files", nil, []int64{}),
+		data.NewField("owned_files", nil, []int64{}),
+		data.NewField("owners", nil, []string{}),
+		data.NewField("approving_owners", nil, []string{}),
+		data.NewField("owner_approved", nil, []bool{}),
+	)
+	pullRequests.Meta = &data.FrameMeta{Custom: map[string]interface{}{
+		"pull_requests_truncated": r.PullRequestsTruncated,
+	}}
+	for _, pr := range r.PullRequests {
+		pullRequests.AppendRow(
+			pr.Number,
+			pr.Title,
+			pr.URL,
+			pr.Files,
+			pr.OwnedFiles,
+			strings.Join(pr.Owners, " "),
+			strings.Join(pr.ApprovingOwners, " "),
+			pr.OwnerApproved,
+		)
+	}
+
+	approvals := data.NewFrame(
+		"codeowners_approvals",
+		data.NewField("owner", nil, []string{}),
+		data.NewField("pull_requests", nil, []int64{}),
+		data.NewField("approved", nil, []int64{}),
+		data.NewField("approval_rate", nil, []float64{}),
+	)
+	for _, a := range r.Approvals {
+		approvals.AppendRow(a.Owner, a.PullRequests, a.Approved, a.ApprovalRate())
+	}
+
+	return data.Frames{coverage, unowned, problems, pullRequests, approvals}
+}
+
+// GetCodeownersCoverage rolls the files of a repository up to the path depth and counts the ones that have an owner
+func GetCodeownersCoverage(codeowners Codeowners, files []string, pathDepth int) ([]CodeownersCoverage, []string) {
+	var (
+		dirs    = map[string]*CodeownersCoverage{}
+		unowned = []string{}
+	)
+
+	for _, f := range files {
+		path := hotspotDirectory(f, pathDepth)
+		if dirs[path] == nil {
+			dirs[path] = &CodeownersCoverage{Path: path}
+		}
+		dirs[path].Files++
+
+		if len(codeowners.owners(f)) > 0 {
+			dirs[path].OwnedFiles++
+		} else {
+			unowned = append(unowned, f)
+		}
+	}
+
+	coverage := make([]CodeownersCoverage, 0, len(dirs))
+	for _, c := range dirs {
+		coverage = append(coverage, *c)
+	}
+	sort.Slice(coverage, func(i, j int) bool {
+		if coverage[i].Coverage() != coverage[j].Coverage() {
+			return coverage[i].Coverage() < coverage[j].Coverage()
+		}
+		return coverage[i].Path < coverage[j].Path
+	})
+	sort.Strings(unowned)
+
+	return coverage, unowned
+}
+
+// getCodeownersProblems lists the lines GitHub ignores and the owners that can't be found
+func getCodeownersProblems(codeowners Codeowners, statuses map[string]CodeownerStatus) []CodeownersProblem {
+	problems := []CodeownersProblem{}
+	for _, e := range codeowners.Errors {
+		problems = append(problems, CodeownersProblem{Line: e.Line, Text: e.Text, Reason: e.Reason})
+	}
+
+	for _, r := range codeowners.Rules {
+		for _, o := range r.Owners {
+			if status, ok := statuses[o]; ok && !status.Exists {
+				problems = append(problems, CodeownersProblem{
+					Line:   r.Line,
+					Text:   strings.Join(append([]string{r.Pattern}, r.Owners...), " "),
+					Owner:  o,
+					Reason: fmt.Sprintf("%s not found", status.Kind),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+	return problems
+}
+
+// codeownerApprovers maps every user and team owner to the logins that approve on its behalf: the user itself, or the members of the team
+type codeownerApprovers map[string]map[string]bool
+
+func (a codeownerApprovers) approves(owner string, logins map[string]bool) bool {
+	for login := range a[owner] {
+		if logins[login] {
+			return true
+		}
+	}
+	return false
+}
+
+// getCodeownersPullRequests checks which owners approved each pull request touching their files.
+// Email owners can't be mapped to a reviewer, so they never approve.
+func getCodeownersPullRequests(codeowners Codeowners, prs PullRequestReviews, files map[int64][]string, approvers codeownerApprovers) ([]CodeownersPullRequest, []CodeownerApprovals) {
+	var (
+		pullRequests = []CodeownersPullRequest{}
+		approvals    = map[string]*CodeownerApprovals{}
+	)
+
+	for _, pr := range prs {
+		approvedBy := map[string]bool{}
+		for _, r := range pr.Reviews {
+			if r.State == githubv4.PullRequestReviewStateApproved {
+				approvedBy[r.Author.User.Login] = true
+			}
+		}
+
+		result := CodeownersPullRequest{
+			Number:        pr.Number,
+			Title:         pr.Title,
+			URL:           pr.URL,
+			Files:         int64(len(files[pr.Number])),
+			OwnerApproved: true,
+		}
+
+		owners := map[string]bool{}
+		for _, f := range files[pr.Number] {
+			fileOwners := codeowners.owners(f)
+			if len(fileOwners) == 0 {
+				continue
+			}
+			result.OwnedFiles++
+
+			approved := false
+			for _, o := range fileOwners {
+				owners[o] = true
+				if approvers.approves(o, approvedBy) {
+					approved = true
+				}
+			}
+			if !approved {
+				result.OwnerApproved = false
+			}
+		}
+		if result.OwnedFiles == 0 {
+			continue
+		}
+
+		for o := range owners {
+			result.Owners = append(result.Owners, o)
+			if approvals[o] == nil {
+				approvals[o] = &CodeownerApprovals{Owner: o}
+			}
+			approvals[o].PullRequests++
+			if approvers.approves(o, approvedBy) {
+				result.ApprovingOwners = append(result.ApprovingOwners, o)
+				approvals[o].Approved++
+			}
+		}
+		sort.Strings(result.Owners)
+		sort.Strings(result.ApprovingOwners)
+		pullRequests = append(pullRequests, result)
+	}
+
+	list := make([]CodeownerApprovals, 0, len(approvals))
+	for _, a := range approvals {
+		list = append(list, *a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Owner < list[j].Owner
+	})
+
+	return pullRequests, list
+}
+
+// QueryCodeownerUserExists is the GraphQL query for checking whether a user exists
+//
+//	{
+//	  repositoryOwner(login: "octocat") {
+//	    __typename
+//	  }
+//	}
+type QueryCodeownerUserExists struct {
+	RepositoryOwner *struct {
+		Typename string `graphql:"__typename"`
+	} `graphql:"repositoryOwner(login: $login)"`
+}
+
+// getCodeownerApprovers checks whether every user and team owner exists, without checking their activity, and resolves the logins that
+// approve on their behalf: the user itself, or the members of the team. Team members come from the cache shared with the team searches.
+func getCodeownerApprovers(ctx context.Context, client models.Client, teams *teamMembersCache, codeowners Codeowners) (map[string]CodeownerStatus, codeownerApprovers, error) {
+	var (
+		statuses  = map[string]CodeownerStatus{}
+		approvers = codeownerApprovers{}
+	)
+
+	for _, owner := range codeowners.Owners() {
+		if !strings.HasPrefix(owner, "@") {
+			statuses[owner] = CodeownerStatus{Owner: owner, Kind: CodeownerEmail, Exists: true}
+			continue
+		}
+
+		login, slug, isTeam := strings.Cut(strings.TrimPrefix(owner, "@"), "/")
+		if !isTeam {
+			q := &QueryCodeownerUserExists{}
+			if err := client.Query(ctx, q, map[string]interface{}{"login": githubv4.String(login)}); err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			exists := q.RepositoryOwner != nil && q.RepositoryOwner.Typename == "User"
+			statuses[owner] = CodeownerStatus{Owner: owner, Kind: CodeownerUser, Exists: exists}
+			if exists {
+				approvers[owner] = map[string]bool{login: true}
+			}
+			continue
+		}
+
+		members, err := teams.get(ctx, client, login, slug)
+		if errors.As(err, &teamNotFoundError{}) {
+			statuses[owner] = CodeownerStatus{Owner: owner, Kind: CodeownerTeam}
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		statuses[owner] = CodeownerStatus{Owner: owner, Kind: CodeownerTeam, Exists: true, Members: int64(len(members))}
+		approvers[owner] = map[string]bool{}
+		for _, m := range members {
+			approvers[owner][m] = true
+		}
+	}
+
+	return statuses, approvers, nil
+}
+
+// getPullRequestFileNames lists the files changed by a pull request, up to PageNumberLimit pages of 100 files
+func getPullRequestFileNames(ctx context.Context, client models.Client, owner string, repo string, number int64) ([]string, error) {
+	var (
+		names = []string{}
+		page  = 1
+	)
+	for i := 0; i < PageNumberLimit; i++ {
+		files, resp, err := client.ListPullRequestFiles(ctx, owner, repo, int(number), &googlegithub.ListOptions{Page: page, PerPage: 100})
+		if err != nil {
+			return nil, fmt.Errorf("listing files of pull request %d: %w", number, err)
+		}
+		for _, f := range files {
+			names = append(names, f.GetFilename())
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return names, nil
+}
+
+// GetRepositoryFiles lists the path of every file of a repository at a ref.
+// It returns true when the repository has more files than GitHub returns in a single tree.
+func GetRepositoryFiles(ctx context.Context, client models.Client, owner string, repo string, ref string) ([]string, bool, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	tree, _, err := client.GetTree(ctx, owner, repo, ref, true)
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+
+	files := []string{}
+	for _, e := range tree.Entries {
+		if e.GetType() == "blob" {
+			files = append(files, e.GetPath())
+		}
+	}
+	return files, tree.GetTruncated(), nil
+}
+
+// GetCodeownersReport fetches the CODEOWNERS file of a repository and reports the files without owners, the owners that don't exist,
+// and how often the pull requests in the time range were approved by the owners of the files they changed.
+// It makes one REST call per pull request to list its files, for at most CodeownersPullRequestLimit pull requests.
+func GetCodeownersReport(ctx context.Context, client models.Client, teams *teamMembersCache, opts models.ListCodeownersOptions, from time.Time, to time.Time) (CodeownersReport, error) {
+	codeowners, err := GetCodeowners(ctx, client, opts.Owner, opts.Repository, opts.Ref)
+	if err != nil {
+		return CodeownersReport{}, err
+	}
+
+	files, truncated, err := GetRepositoryFiles(ctx, client, opts.Owner, opts.Repository, opts.Ref)
+	if err != nil {
+		return CodeownersReport{}, err
+	}
+	coverage, unowned := GetCodeownersCoverage(codeowners, files, opts.PathDepth)
+
+	statuses, approvers, err := getCodeownerApprovers(ctx, client, teams, codeowners)
+	if err != nil {
+		return CodeownersReport{}, err
+	}
+
+	prs, err := GetPullRequestReviewsInRange(ctx, client, models.ListPullRequestsOptions{
+		Owner:      opts.Owner,
+		Repository: opts.Repository,
+		TimeField:  opts.TimeField,
+	}, from, to)
+	if err != nil {
+		return CodeownersReport{}, err
+	}
+
+	prsTruncated := len(prs) > CodeownersPullRequestLimit
+	if prsTruncated {
+		prs = prs[:CodeownersPullRequestLimit]
+	}
+
+	prFiles := map[int64][]string{}
+	for _, pr := range prs {
+		if prFiles[pr.Number], err = getPullRequestFileNames(ctx, client, opts.Owner, opts.Repository, pr.Number); err != nil {
+			return CodeownersReport{}, err
+		}
+	}
+
+	pullRequests, approvals := getCodeownersPullRequests(codeowners, prs, prFiles, approvers)
+
+	return CodeownersReport{
+		Codeowners:            codeowners,
+		Coverage:              coverage,
+		UnownedFiles:          unowned,
+		TreeTruncated:         truncated,
+		Problems:              getCodeownersProblems(codeowners, statuses),
+		PullRequests:          pullRequests,
+		PullRequestsTruncated: prsTruncated,
+		Approvals:             approvals,
+	}, nil
+}
@@ -0,0 +1,247 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+const codeownersReportTestFile = `*.go      @grafana/backend
+/docs/    @grafana/docs-squad @ghost
+/pkg/models/ @octocat
+/vendor/
+!/tmp/    @octocat
+`
+
+func codeownersReportTest(t *testing.T) (Codeowners, map[string]CodeownerStatus, codeownerApprovers, PullRequestReviews, map[int64][]string) {
+	t.Helper()
+
+	codeowners := ParseCodeowners(".github/CODEOWNERS", codeownersReportTestFile)
+	statuses := map[string]CodeownerStatus{
+		"@grafana/backend":    {Owner: "@grafana/backend", Kind: CodeownerTeam, Exists: true, Active: true, Members: 2},
+		"@grafana/docs-squad": {Owner: "@grafana/docs-squad", Kind: CodeownerTeam, Exists: true, Active: true, Members: 1},
+		"@ghost":              {Owner: "@ghost", Kind: CodeownerUser},
+		"@octocat":            {Owner: "@octocat", Kind: CodeownerUser, Exists: true, Active: true},
+	}
+	approvers := codeownerApprovers{
+		"@grafana/backend":    {"first": true, "second": true},
+		"@grafana/docs-squad": {"writer": true},
+		"@octocat":            {"octocat": true},
+	}
+
+	review := func(login string, state githubv4.PullRequestReviewState) Review {
+		return Review{State: state, Author: Author{User: models.User{Login: login}}}
+	}
+	prs := PullRequestReviews{
+		{Number: 1, Title: "backend change", Reviews: []Review{review("first", githubv4.PullRequestReviewStateApproved)}},
+		{Number: 2, Title: "models and docs", Reviews: []Review{
+			review("octocat", githubv4.PullRequestReviewStateChangesRequested),
+			review("writer", githubv4.PullRequestReviewStateApproved),
+		}},
+		{Number: 3, Title: "vendored update", Reviews: []Review{review("first", githubv4.PullRequestReviewStateApproved)}},
+	}
+	files := map[int64][]string{
+		1: {"pkg/github/commits.go", "README.md"},
+		2: {"pkg/models/commits.go", "docs/index.md"},
+		3: {"vendor/lib/lib.txt"},
+	}
+
+	return codeowners, statuses, approvers, prs, files
+}
+
+func TestGetCodeownersCoverage(t *testing.T) {
+	codeowners, _, _, _, _ := codeownersReportTest(t)
+	files := []string{"README.md", "main.go", "docs/index.md", "pkg/models/commits.go", "pkg/README.md", "vendor/lib/lib.go"}
+
+	coverage, unowned := GetCodeownersCoverage(codeowners, files, 1)
+	assert.Equal(t, []CodeownersCoverage{
+		{Path: "vendor", Files: 1, OwnedFiles: 0},
+		{Path: ".", Files: 2, OwnedFiles: 1},
+		{Path: "pkg", Files: 2, OwnedFiles: 1},
+		{Path: "docs", Files: 1, OwnedFiles: 1},
+	}, coverage)
+	// the last matching rule wins, so the vendored go file has no owner
+	assert.Equal(t, []string{"README.md", "pkg/README.md", "vendor/lib/lib.go"}, unowned)
+}
+
+func TestGetCodeownersProblems(t *testing.T) {
+	codeowners, statuses, _, _, _ := codeownersReportTest(t)
+
+	assert.Equal(t, []CodeownersProblem{
+		{Line: 2, Text: "/docs/ @grafana/docs-squad @ghost", Owner: "@ghost", Reason: "user not found"},
+		{Line: 5, Text: "!/tmp/    @octocat", Reason: "negated patterns are not supported"},
+	}, getCodeownersProblems(codeowners, statuses))
+}
+
+func TestGetCodeownersPullRequests(t *testing.T) {
+	codeowners, _, approvers, prs, files := codeownersReportTest(t)
+
+	pullRequests, approvals := getCodeownersPullRequests(codeowners, prs, files, approvers)
+
+	require.Len(t, pullRequests, 2)
+	assert.Equal(t, CodeownersPullRequest{
+		Number:          1,
+		Title:           "backend change",
+		Files:           2,
+		OwnedFiles:      1,
+		Owners:          []string{"@grafana/backend"},
+		ApprovingOwners: []string{"@grafana/backend"},
+		OwnerApproved:   true,
+	}, pullRequests[0])
+	assert.Equal(t, CodeownersPullRequest{
+		Number:          2,
+		Title:           "models and docs",
+		Files:           2,
+		OwnedFiles:      2,
+		Owners:          []string{"@ghost", "@grafana/docs-squad", "@octocat"},
+		ApprovingOwners: []string{"@grafana/docs-squad"},
+		OwnerApproved:   false,
+	}, pullRequests[1])
+
+	assert.Equal(t, []CodeownerApprovals{
+		{Owner: "@ghost", PullRequests: 1, Approved: 0},
+		{Owner: "@grafana/backend", PullRequests: 1, Approved: 1},
+		{Owner: "@grafana/docs-squad", PullRequests: 1, Approved: 1},
+		{Owner: "@octocat", PullRequests: 1, Approved: 0},
+	}, approvals)
+}
+
+// codeownersMockClient answers the user and team lookups of code owners, only octocat and grafana/backend exist
+type codeownersMockClient struct {
+	*testutil.TestClient
+	teamQueries int
+}
+
+func (m *codeownersMockClient) Query(_ context.Context, q interface{}, variables map[string]interface{}) error {
+	switch q := q.(type) {
+	case *QueryCodeownerUserExists:
+		if variables["login"] == githubv4.String("octocat") {
+			q.RepositoryOwner = &struct {
+				Typename string `graphql:"__typename"`
+			}{Typename: "User"}
+		}
+	case *QueryListTeamMembers:
+		m.teamQueries++
+		if variables["slug"] != githubv4.String("backend") {
+			return nil
+		}
+		q.Organization.Team = &struct {
+			Members struct {
+				Nodes []struct {
+					Login string
+				}
+				PageInfo models.PageInfo
+			} `graphql:"members(first: 100, after: $cursor)"`
+		}{}
+		q.Organization.Team.Members.Nodes = []struct {
+			Login string
+		}{{Login: "first"}, {Login: "second"}}
+	default:
+		m.T.Errorf("unexpected query %T", q)
+	}
+	return nil
+}
+
+func TestGetCodeownerApprovers(t *testing.T) {
+	codeowners := ParseCodeowners(".github/CODEOWNERS", codeownersReportTestFile+"*.md docs@grafana.com\n")
+	client := &codeownersMockClient{TestClient: testutil.NewTestClient(t, nil, nil)}
+	teams := newTeamMembersCache()
+
+	statuses, approvers, err := getCodeownerApprovers(context.Background(), client, teams, codeowners)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]CodeownerStatus{
+		"@grafana/backend":    {Owner: "@grafana/backend", Kind: CodeownerTeam, Exists: true, Members: 2},
+		"@grafana/docs-squad": {Owner: "@grafana/docs-squad", Kind: CodeownerTeam},
+		"@ghost":              {Owner: "@ghost", Kind: CodeownerUser},
+		"@octocat":            {Owner: "@octocat", Kind: CodeownerUser, Exists: true},
+		"docs@grafana.com":    {Owner: "docs@grafana.com", Kind: CodeownerEmail, Exists: true},
+	}, statuses)
+	assert.Equal(t, codeownerApprovers{
+		"@grafana/backend": {"first": true, "second": true},
+		"@octocat":         {"octocat": true},
+	}, approvers)
+
+	// the members of the teams that exist are reused from the cache
+	_, _, err = getCodeownerApprovers(context.Background(), client, teams, codeowners)
+	require.NoError(t, err)
+	assert.Equal(t, 3, client.teamQueries)
+}
+
+// pullRequestFilesMockClient returns pages of a single file for every pull request, without an end
+type pullRequestFilesMockClient struct {
+	*testutil.TestClient
+	calls int
+}
+
+func (m *pullRequestFilesMockClient) ListPullRequestFiles(_ context.Context, _, _ string, number int, opts *googlegithub.ListOptions) ([]*googlegithub.CommitFile, *googlegithub.Response, error) {
+	m.calls++
+	file := &googlegithub.CommitFile{Filename: googlegithub.Ptr(fmt.Sprintf("pr%d/page%d.go", number, opts.Page))}
+	return []*googlegithub.CommitFile{file}, &googlegithub.Response{NextPage: opts.Page + 1}, nil
+}
+
+func TestGetPullRequestFileNames(t *testing.T) {
+	client := &pullRequestFilesMockClient{TestClient: testutil.NewTestClient(t, nil, nil)}
+
+	files, err := getPullRequestFileNames(context.Background(), client, "grafana", "grafana", 7)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr7/page1.go", "pr7/page2.go"}, files)
+	assert.Equal(t, PageNumberLimit, client.calls)
+}
+
+// treeMockClient returns the same tree for every ref
+type treeMockClient struct {
+	*testutil.TestClient
+	tree *googlegithub.Tree
+}
+
+func (m *treeMockClient) GetTree(_ context.Context, owner, repo, sha string, recursive bool) (*googlegithub.Tree, *googlegithub.Response, error) {
+	if owner != "grafana" || repo != "grafana" || sha != "HEAD" || !recursive {
+		m.T.Errorf("unexpected tree %s/%s@%s recursive=%t", owner, repo, sha, recursive)
+	}
+	return m.tree, nil, nil
+}
+
+func TestGetRepositoryFiles(t *testing.T) {
+	client := &treeMockClient{
+		TestClient: testutil.NewTestClient(t, nil, nil),
+		tree: &googlegithub.Tree{
+			Truncated: googlegithub.Ptr(true),
+			Entries: []*googlegithub.TreeEntry{
+				{Path: googlegithub.Ptr("pkg"), Type: googlegithub.Ptr("tree")},
+				{Path: googlegithub.Ptr("pkg/main.go"), Type: googlegithub.Ptr("blob")},
+				{Path: googlegithub.Ptr("vendor/lib"), Type: googlegithub.Ptr("commit")},
+			},
+		},
+	}
+
+	files, truncated, err := GetRepositoryFiles(context.Background(), client, "grafana", "grafana", "")
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, []string{"pkg/main.go"}, files)
+}
+
+func TestCodeownersReportDataframe(t *testing.T) {
+	codeowners, statuses, approvers, prs, files := codeownersReportTest(t)
+	coverage, unowned := GetCodeownersCoverage(codeowners, []string{"README.md", "main.go", "docs/index.md", "pkg/models/commits.go"}, 1)
+	pullRequests, approvals := getCodeownersPullRequests(codeowners, prs, files, approvers)
+
+	report := CodeownersReport{
+		Codeowners:   codeowners,
+		Coverage:     coverage,
+		UnownedFiles: unowned,
+		Problems:     getCodeownersProblems(codeowners, statuses),
+		PullRequests: pullRequests,
+		Approvals:    approvals,
+	}
+
+	testutil.CheckGoldenFramer(t, "codeowners", report)
+}
@@ -0,0 +1,158 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestCodeownersPatternToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{pattern: "*", matches: []string{"README.md", "pkg/github/commits.go"}},
+		{pattern: "*.js", matches: []string{"app.js", "src/app.js"}, misses: []string{"app.ts"}},
+		{pattern: "/build/logs/", matches: []string{"build/logs/out.log", "build/logs/a/b.log"}, misses: []string{"src/build/logs/out.log"}},
+		{pattern: "docs/*", matches: []string{"docs/index.md"}, misses: []string{"docs/build/index.md", "src/docs/index.md"}},
+		{pattern: "apps/", matches: []string{"apps/main.go", "src/apps/main.go"}, misses: []string{"apps.go"}},
+		{pattern: "/docs/", matches: []string{"docs/index.md", "docs/a/b.md"}, misses: []string{"src/docs/index.md"}},
+		{pattern: "**/logs", matches: []string{"logs/a.log", "build/logs/a.log", "deep/build/logs/a.log"}},
+		{pattern: "/scripts/**", matches: []string{"scripts/a.sh", "scripts/ci/b.sh"}, misses: []string{"src/scripts/a.sh"}},
+		{pattern: "pkg/**/client.go", matches: []string{"pkg/client.go", "pkg/github/client/client.go"}, misses: []string{"client.go"}},
+		{pattern: "Makefile", matches: []string{"Makefile", "tools/Makefile"}, misses: []string{"Makefile.old"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := codeownersPatternToRegexp(tt.pattern)
+			require.NoError(t, err)
+			for _, path := range tt.matches {
+				assert.True(t, re.MatchString(path), "%s should match %s", tt.pattern, path)
+			}
+			for _, path := range tt.misses {
+				assert.False(t, re.MatchString(path), "%s should not match %s", tt.pattern, path)
+			}
+		})
+	}
+}
+
+func TestParseCodeowners(t *testing.T) {
+	content := `# default owners
+*       @grafana/backend
+
+/docs/  @grafana/docs-squad docs@grafana.com # docs
+*.ts    @octocat not-an-owner
+!/vendor/ @grafana/backend
+/vendor/
+`
+
+	codeowners := ParseCodeowners(".github/CODEOWNERS", content)
+	assert.Equal(t, ".github/CODEOWNERS", codeowners.Path)
+
+	require.Len(t, codeowners.Rules, 4)
+	assert.Equal(t, int64(4), codeowners.Rules[1].Line)
+	assert.Equal(t, []string{"@grafana/docs-squad", "docs@grafana.com"}, codeowners.Rules[1].Owners)
+	assert.Equal(t, []string{"@octocat"}, codeowners.Rules[2].Owners)
+	assert.Empty(t, codeowners.Rules[3].Owners)
+
+	require.Len(t, codeowners.Errors, 2)
+	assert.Equal(t, CodeownersError{Line: 5, Text: "*.ts    @octocat not-an-owner", Reason: `invalid owner "not-an-owner"`}, codeowners.Errors[0])
+	assert.Equal(t, "negated patterns are not supported", codeowners.Errors[1].Reason)
+
+	assert.Equal(t, []string{"@grafana/backend", "@grafana/docs-squad", "docs@grafana.com", "@octocat"}, codeowners.Owners())
+
+	rule, ok := codeowners.Match("docs/index.md")
+	require.True(t, ok)
+	assert.Equal(t, "/docs/", rule.Pattern)
+
+	rule, ok = codeowners.Match("src/index.ts")
+	require.True(t, ok)
+	assert.Equal(t, "*.ts", rule.Pattern)
+
+	rule, ok = codeowners.Match("vendor/lib.go")
+	require.True(t, ok)
+	assert.Empty(t, rule.Owners)
+
+	_, ok = ParseCodeowners("", "").Match("main.go")
+	assert.False(t, ok)
+}
+
+func TestGetCodeowners(t *testing.T) {
+	var (
+		expressions []string
+		content     = githubv4.String("* @grafana/backend")
+	)
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		testutil.EnsureKeysAreSet(t, variables, "owner", "name", "expression")
+		expressions = append(expressions, string(variables["expression"].(githubv4.String)))
+	}
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryGetFileText)
+		require.True(t, ok)
+		// the file is only found at the second location
+		if len(expressions) == 2 {
+			text := string(content)
+			query.Repository.Object = &struct {
+				Blob struct {
+					IsBinary bool
+					Text     *string
+				} `graphql:"... on Blob"`
+			}{}
+			query.Repository.Object.Blob.Text = &text
+		}
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+	codeowners, err := GetCodeowners(context.Background(), client, "grafana", "grafana", "main")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"main:.github/CODEOWNERS", "main:CODEOWNERS"}, expressions)
+	assert.Equal(t, "CODEOWNERS", codeowners.Path)
+	assert.Equal(t, []string{"@grafana/backend"}, codeowners.Owners())
+}
+
+func TestGetCodeownerStatus(t *testing.T) {
+	since := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("email owners are not checked", func(t *testing.T) {
+		status, err := GetCodeownerStatus(context.Background(), testutil.NewTestClient(t, nil, nil), "docs@grafana.com", since)
+		require.NoError(t, err)
+		assert.Equal(t, CodeownerStatus{Owner: "docs@grafana.com", Kind: CodeownerEmail, Exists: true, Active: true}, status)
+	})
+
+	t.Run("users that are not found", func(t *testing.T) {
+		testVariables := testutil.GetTestVariablesFunction("login", "from")
+		client := testutil.NewTestClient(t, testVariables, testutil.GetTestQueryFunction(&QueryCodeownerUser{}))
+
+		status, err := GetCodeownerStatus(context.Background(), client, "@ghost", since)
+		require.NoError(t, err)
+		assert.False(t, status.Exists)
+		assert.Equal(t, "user not found", status.Reason(since))
+	})
+
+	t.Run("teams that are not found", func(t *testing.T) {
+		testVariables := testutil.GetTestVariablesFunction("login", "slug", "from", "cursor")
+		client := testutil.NewTestClient(t, testVariables, testutil.GetTestQueryFunction(&QueryCodeownerTeam{}))
+
+		status, err := GetCodeownerStatus(context.Background(), client, "@grafana/ghosts", since)
+		require.NoError(t, err)
+		assert.Equal(t, CodeownerTeam, status.Kind)
+		assert.Equal(t, "team not found", status.Reason(since))
+	})
+
+	t.Run("reasons", func(t *testing.T) {
+		assert.Equal(t, "", CodeownerStatus{Kind: CodeownerUser, Exists: true, Active: true}.Reason(since))
+		assert.Equal(t, "no contributions since 2020-08-01", CodeownerStatus{Kind: CodeownerUser, Exists: true}.Reason(since))
+		assert.Equal(t, "team has no members", CodeownerStatus{Kind: CodeownerTeam, Exists: true}.Reason(since))
+		assert.Equal(t, "no team member contributed since 2020-08-01", CodeownerStatus{Kind: CodeownerTeam, Exists: true, Members: 3}.Reason(since))
+	})
+}
@@ -97,6 +97,46 @@ func (m *mockClient) ListPullRequestFiles(ctx context.Context, owner, repo strin
 	return nil, nil, nil
 }
 
+func (m *mockClient) CompareCommits(_ context.Context, _, _, _, _ string, _ *googlegithub.ListOptions) (*googlegithub.CommitsComparison, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockClient) GetTree(_ context.Context, _, _, _ string, _ bool) (*googlegithub.Tree, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockClient) ListPackagesForOrg(_ context.Context, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockClient) ListPackagesForUser(_ context.Context, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockClient) ListPackageVersionsForOrg(_ context.Context, _, _, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockClient) ListPackageVersionsForUser(_ context.Context, _, _, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockClient) GetContainerManifest(_ context.Context, _, _, _ string) (*models.ContainerManifest, error) {
+	return nil, nil
+}
+
+func (m *mockClient) GetSBOM(_ context.Context, _, _ string) (*googlegithub.SBOM, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockClient) GetRepository(_ context.Context, _, _ string) (*googlegithub.Repository, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockClient) GetCodeScanningDefaultSetup(_ context.Context, _, _ string) (*googlegithub.DefaultSetupConfiguration, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
 func TestGetCodeScanningAlerts(t *testing.T) {
 	var (
 		ctx  = context.Background()
@@ -0,0 +1,146 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// MaxCollaboratorRepositories is the number of repositories whose collaborators are listed when listing the collaborators of an owner.
+// The collaborators are read with a request per repository, the collaborators of the other repositories are not listed.
+const MaxCollaboratorRepositories = 100
+
+// QueryListCollaborators is the GraphQL query for listing the collaborators of a repository and their permission
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    collaborators(affiliation: OUTSIDE, first: 100) {
+//	      edges {
+//	        permission
+//	        node {
+//	          login
+//	          name
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListCollaborators struct {
+	Repository struct {
+		NameWithOwner string
+		Collaborators struct {
+			Edges []struct {
+				Permission githubv4.RepositoryPermission
+				Node       models.User
+			}
+			PageInfo models.PageInfo
+		} `graphql:"collaborators(affiliation: $affiliation, first: 100, after: $cursor)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// Collaborator is a user with access to a repository
+type Collaborator struct {
+	Repository string
+	Permission githubv4.RepositoryPermission
+	User       models.User
+}
+
+// Collaborators is a list of repository collaborators
+type Collaborators []Collaborator
+
+// Frames converts the list of collaborators to a Grafana DataFrame
+func (c Collaborators) Frames() data.Frames {
+	frame := data.NewFrame(
+		"collaborators",
+		data.NewField("repository", nil, []string{}),
+		data.NewField("login", nil, []string{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("email", nil, []string{}),
+		data.NewField("company", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("permission", nil, []string{}),
+	)
+
+	for _, v := range c {
+		frame.AppendRow(
+			v.Repository,
+			v.User.Login,
+			v.User.Name,
+			v.User.Email,
+			v.User.Company,
+			v.User.URL,
+			string(v.Permission),
+		)
+	}
+
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{frame}
+}
+
+// GetAllCollaborators lists the collaborators of a repository, or of the first MaxCollaboratorRepositories repositories of the owner when no repository is set.
+// When listing the collaborators of an owner, a repository whose collaborators can't be read doesn't fail the query, its collaborators are not listed.
+func GetAllCollaborators(ctx context.Context, client models.Client, opts models.ListCollaboratorsOptions) (Collaborators, error) {
+	collaborators := Collaborators{}
+	if opts.Owner == "" {
+		return collaborators, nil
+	}
+
+	repositories, err := listRepositoryNames(ctx, client, opts.Owner, opts.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repositories = firstRepositoryNames(repositories, MaxCollaboratorRepositories, opts.Owner)
+
+	for _, repository := range repositories {
+		c, err := getRepositoryCollaborators(ctx, client, opts.Owner, repository, opts.Affiliation)
+		if err != nil {
+			if opts.Repository != "" {
+				return nil, fmt.Errorf("listing collaborators of %s/%s: %w", opts.Owner, repository, err)
+			}
+			backend.Logger.Warn("could not list the collaborators of the repository", "owner", opts.Owner, "repository", repository, "error", err)
+			continue
+		}
+		collaborators = append(collaborators, c...)
+	}
+
+	return collaborators, nil
+}
+
+func getRepositoryCollaborators(ctx context.Context, client models.Client, owner string, repository string, affiliation models.CollaboratorAffiliation) (Collaborators, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor":      (*githubv4.String)(nil),
+			"owner":       githubv4.String(owner),
+			"name":        githubv4.String(repository),
+			"affiliation": githubv4.CollaboratorAffiliation(affiliation),
+		}
+
+		collaborators = Collaborators{}
+	)
+
+	for {
+		q := &QueryListCollaborators{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, v := range q.Repository.Collaborators.Edges {
+			collaborators = append(collaborators, Collaborator{
+				Repository: q.Repository.NameWithOwner,
+				Permission: v.Permission,
+				User:       v.Node,
+			})
+		}
+		if !q.Repository.Collaborators.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.Collaborators.PageInfo.EndCursor
+	}
+
+	return collaborators, nil
+}
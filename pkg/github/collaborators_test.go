@@ -0,0 +1,80 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetAllCollaborators(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		opts = models.CollaboratorsOptionsWithRepo(models.ListCollaboratorsOptions{}, "grafana", "grafana")
+	)
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		testutil.EnsureKeysAreSet(t, variables, "cursor", "owner", "name", "affiliation")
+		if variables["affiliation"] != githubv4.CollaboratorAffiliationOutside {
+			t.Errorf("expected affiliation to default to OUTSIDE, got %v", variables["affiliation"])
+		}
+	}
+
+	client := testutil.NewTestClient(t,
+		testVariables,
+		testutil.GetTestQueryFunction(&QueryListCollaborators{}),
+	)
+
+	_, err := GetAllCollaborators(ctx, client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAllCollaboratorsSkipsFailingRepositories(t *testing.T) {
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryListCollaborators)
+		require.True(t, ok)
+		query.Repository.NameWithOwner = "grafana/loki"
+		query.Repository.Collaborators.Edges = make([]struct {
+			Permission githubv4.RepositoryPermission
+			Node       models.User
+		}, 1)
+	}
+	client := &failingRepositoryClient{
+		TestClient:   testutil.NewTestClient(t, nil, testQuery),
+		repositories: []string{"grafana", "loki"},
+		failing:      "grafana",
+	}
+
+	collaborators, err := GetAllCollaborators(context.Background(), client, models.ListCollaboratorsOptions{Owner: "grafana"})
+	require.NoError(t, err)
+	require.Len(t, collaborators, 1)
+	assert.Equal(t, "grafana/loki", collaborators[0].Repository)
+
+	// a single repository still fails the query
+	_, err = GetAllCollaborators(context.Background(), client, models.CollaboratorsOptionsWithRepo(models.ListCollaboratorsOptions{}, "grafana", "grafana"))
+	assert.Error(t, err)
+}
+
+func TestCollaboratorsDataFrame(t *testing.T) {
+	collaborators := Collaborators{
+		{
+			Repository: "grafana/grafana",
+			Permission: githubv4.RepositoryPermissionWrite,
+			User: models.User{
+				Login: "contractor",
+				Name:  "Outside Contractor",
+				Email: "contractor@example.com",
+				URL:   "https://github.com/contractor",
+			},
+		},
+	}
+
+	testutil.CheckGoldenFramer(t, "collaborators", collaborators)
+}
@@ -74,6 +74,46 @@ func (m *commitFilesMockClient) ListPullRequestFiles(_ context.Context, owner, r
 	return p.files, resp, nil
 }
 
+func (m *commitFilesMockClient) CompareCommits(_ context.Context, _, _, _, _ string, _ *googlegithub.ListOptions) (*googlegithub.CommitsComparison, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) GetTree(_ context.Context, _, _, _ string, _ bool) (*googlegithub.Tree, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) ListPackagesForOrg(_ context.Context, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) ListPackagesForUser(_ context.Context, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) ListPackageVersionsForOrg(_ context.Context, _, _, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) ListPackageVersionsForUser(_ context.Context, _, _, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) GetContainerManifest(_ context.Context, _, _, _ string) (*models.ContainerManifest, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) GetSBOM(_ context.Context, _, _ string) (*googlegithub.SBOM, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) GetRepository(_ context.Context, _, _ string) (*googlegithub.Repository, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitFilesMockClient) GetCodeScanningDefaultSetup(_ context.Context, _, _ string) (*googlegithub.DefaultSetupConfiguration, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
 func TestGetCommitFiles(t *testing.T) {
 	ctx := context.Background()
 	opts := models.CommitFilesOptions{
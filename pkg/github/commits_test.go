@@ -58,6 +58,46 @@ func (m *commitsWithFilesMockClient) ListPullRequestFiles(_ context.Context, _,
 	panic("unimplemented")
 }
 
+func (m *commitsWithFilesMockClient) CompareCommits(_ context.Context, _, _, _, _ string, _ *googlegithub.ListOptions) (*googlegithub.CommitsComparison, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) GetTree(_ context.Context, _, _, _ string, _ bool) (*googlegithub.Tree, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) ListPackagesForOrg(_ context.Context, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) ListPackagesForUser(_ context.Context, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) ListPackageVersionsForOrg(_ context.Context, _, _, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) ListPackageVersionsForUser(_ context.Context, _, _, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) GetContainerManifest(_ context.Context, _, _, _ string) (*models.ContainerManifest, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) GetSBOM(_ context.Context, _, _ string) (*googlegithub.SBOM, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) GetRepository(_ context.Context, _, _ string) (*googlegithub.Repository, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+func (m *commitsWithFilesMockClient) GetCodeScanningDefaultSetup(_ context.Context, _, _ string) (*googlegithub.DefaultSetupConfiguration, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
 func TestGetAllCommits(t *testing.T) {
 	var (
 		ctx  = context.Background()
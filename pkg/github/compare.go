@@ -0,0 +1,241 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryListCommitPullRequests is the GraphQL query for looking up the pull requests a list of commits were merged with
+//
+//	{
+//	  nodes(ids: ["C_kwDOAOaWjdoAKDk3ZjJmZmE5ZGU5ZGU2ZjE1ZjU5ZTk2ZjE0ZjUyZWIyZTQ2YmU1YjA"]) {
+//	    ... on Commit {
+//	      oid
+//	      associatedPullRequests(first: 1) {
+//	        nodes {
+//	          number
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListCommitPullRequests struct {
+	Nodes []struct {
+		Commit CommitPullRequests `graphql:"... on Commit"`
+	} `graphql:"nodes(ids: $ids)"`
+}
+
+// CommitPullRequests is a commit along with the pull request it was merged with
+type CommitPullRequests struct {
+	OID                    string `graphql:"oid"`
+	AssociatedPullRequests struct {
+		Nodes []struct {
+			Number int64
+			Title  string
+			URL    string
+		}
+	} `graphql:"associatedPullRequests(first: 1)"`
+}
+
+// ComparisonPullRequest is a pull request that one or more commits of a comparison were merged with
+type ComparisonPullRequest struct {
+	Number  int64
+	Title   string
+	URL     string
+	Commits int64
+}
+
+// CommitComparison is the comparison of a head commit against a base commit: what is in a deploy or a release
+type CommitComparison struct {
+	Base         string
+	Head         string
+	Status       string
+	AheadBy      int64
+	BehindBy     int64
+	TotalCommits int64
+	URL          string
+	Commits      Commits
+	PullRequests []ComparisonPullRequest
+	Files        CommitFilesWrapper
+}
+
+// Frames converts the comparison to a summary frame, followed by the commits, the pull requests, the changed files and the authors of the comparison
+func (c CommitComparison) Frames() data.Frames {
+	summary := data.NewFrame(
+		"comparison",
+		data.NewField("base", nil, []string{c.Base}),
+		data.NewField("head", nil, []string{c.Head}),
+		data.NewField("status", nil, []string{c.Status}),
+		data.NewField("ahead_by", nil, []int64{c.AheadBy}),
+		data.NewField("behind_by", nil, []int64{c.BehindBy}),
+		data.NewField("total_commits", nil, []int64{c.TotalCommits}),
+		data.NewField("pull_requests", nil, []int64{int64(len(c.PullRequests))}),
+		data.NewField("changed_files", nil, []int64{int64(len(c.Files))}),
+		data.NewField("url", nil, []string{c.URL}),
+	)
+
+	pullRequests := data.NewFrame(
+		"comparison_pull_requests",
+		data.NewField("number", nil, []int64{}),
+		data.NewField("title", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("commits", nil, []int64{}),
+	)
+	for _, pr := range c.PullRequests {
+		pullRequests.AppendRow(pr.Number, pr.Title, pr.URL, pr.Commits)
+	}
+
+	authors := data.NewFrame(
+		"comparison_authors",
+		data.NewField("author", nil, []string{}),
+		data.NewField("author_login", nil, []string{}),
+		data.NewField("author_email", nil, []string{}),
+		data.NewField("commits", nil, []int64{}),
+	)
+
+	type author struct {
+		name, login, email string
+		commits            int64
+	}
+	var (
+		index = map[string]int{}
+		list  = []author{}
+	)
+	for _, commit := range c.Commits {
+		key := commit.Author.User.Login
+		if key == "" {
+			key = commit.Author.Email
+		}
+		if i, ok := index[key]; ok {
+			list[i].commits++
+			continue
+		}
+		index[key] = len(list)
+		list = append(list, author{name: commit.Author.Name, login: commit.Author.User.Login, email: commit.Author.Email, commits: 1})
+	}
+	for _, a := range list {
+		authors.AppendRow(a.name, a.login, a.email, a.commits)
+	}
+
+	frames := data.Frames{summary}
+	frames = append(frames, c.Commits.Frames()...)
+	frames = append(frames, pullRequests)
+	frames = append(frames, c.Files.Frames()...)
+	frames = append(frames, authors)
+
+	return frames
+}
+
+// restCommit converts a commit returned by the REST API to the commit framed by the Commits query
+func restCommit(c *googlegithub.RepositoryCommit) Commit {
+	commit := Commit{
+		OID:     c.GetSHA(),
+		Message: githubv4.String(c.GetCommit().GetMessage()),
+	}
+
+	if author := c.GetCommit().GetAuthor(); author != nil {
+		commit.Author.Name = author.GetName()
+		commit.Author.Email = author.GetEmail()
+		commit.Author.Date = githubv4.GitTimestamp{Time: author.GetDate().Time}
+		commit.AuthoredDate = githubv4.DateTime{Time: author.GetDate().Time}
+	}
+	if committer := c.GetCommit().GetCommitter(); committer != nil {
+		commit.CommittedDate = githubv4.DateTime{Time: committer.GetDate().Time}
+	}
+	commit.Author.User.Login = c.GetAuthor().GetLogin()
+
+	return commit
+}
+
+// GetComparison compares the head against the base of the options, which can be branches, tags or commit SHAs.
+// The REST API returns at most 300 changed files for a comparison.
+func GetComparison(ctx context.Context, client models.Client, opts models.CompareOptions) (CommitComparison, error) {
+	comparison := CommitComparison{
+		Base:         opts.Base,
+		Head:         opts.Head,
+		Commits:      Commits{},
+		PullRequests: []ComparisonPullRequest{},
+		Files:        CommitFilesWrapper{},
+	}
+	if opts.Owner == "" || opts.Repository == "" || opts.Base == "" || opts.Head == "" {
+		return comparison, nil
+	}
+
+	var (
+		listOpts = &googlegithub.ListOptions{PerPage: 100}
+		page     = 1
+		ids      = []githubv4.ID{}
+	)
+	for page != 0 {
+		listOpts.Page = page
+		c, resp, err := client.CompareCommits(ctx, opts.Owner, opts.Repository, opts.Base, opts.Head, listOpts)
+		if err != nil {
+			return CommitComparison{}, fmt.Errorf("comparing commits: owner=%s repo=%s base=%s head=%s: %w", opts.Owner, opts.Repository, opts.Base, opts.Head, err)
+		}
+
+		// the summary and the changed files are the same on every page
+		if page == 1 {
+			comparison.Status = c.GetStatus()
+			comparison.AheadBy = int64(c.GetAheadBy())
+			comparison.BehindBy = int64(c.GetBehindBy())
+			comparison.TotalCommits = int64(c.GetTotalCommits())
+			comparison.URL = c.GetHTMLURL()
+			comparison.Files = CommitFilesWrapper(c.Files)
+		}
+		for _, commit := range c.Commits {
+			comparison.Commits = append(comparison.Commits, restCommit(commit))
+			ids = append(ids, githubv4.ID(commit.GetNodeID()))
+		}
+
+		if resp == nil || len(c.Commits) == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	prs, err := getCommitPullRequests(ctx, client, ids)
+	if err != nil {
+		return CommitComparison{}, err
+	}
+	comparison.PullRequests = prs
+
+	return comparison, nil
+}
+
+// getCommitPullRequests looks up the pull requests the commits were merged with, 100 commits at a time.
+// The REST comparison doesn't return pull requests, and the GraphQL one only compares refs, not commit SHAs.
+func getCommitPullRequests(ctx context.Context, client models.Client, ids []githubv4.ID) ([]ComparisonPullRequest, error) {
+	var (
+		index = map[int64]int{}
+		prs   = []ComparisonPullRequest{}
+	)
+
+	for start := 0; start < len(ids); start += 100 {
+		end := min(start+100, len(ids))
+
+		q := &QueryListCommitPullRequests{}
+		if err := client.Query(ctx, q, map[string]interface{}{"ids": ids[start:end]}); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, node := range q.Nodes {
+			for _, pr := range node.Commit.AssociatedPullRequests.Nodes {
+				if i, ok := index[pr.Number]; ok {
+					prs[i].Commits++
+					continue
+				}
+				index[pr.Number] = len(prs)
+				prs = append(prs, ComparisonPullRequest{Number: pr.Number, Title: pr.Title, URL: pr.URL, Commits: 1})
+			}
+		}
+	}
+
+	return prs, nil
+}
@@ -0,0 +1,153 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+// compareMockClient answers the REST comparisons with a comparison for every base...head, returned on the first page,
+// and the pull request lookups with the pull request of every commit SHA. The node ID of a commit is its SHA prefixed with "node-".
+type compareMockClient struct {
+	*testutil.TestClient
+	comparisons  map[string]*googlegithub.CommitsComparison
+	pullRequests map[string]int64
+	compared     []string
+	pages        int
+	lookups      int
+}
+
+func (m *compareMockClient) CompareCommits(_ context.Context, owner, repo, base, head string, opts *googlegithub.ListOptions) (*googlegithub.CommitsComparison, *googlegithub.Response, error) {
+	comparison, ok := m.comparisons[base+"..."+head]
+	if owner != "grafana" || repo != "grafana" || !ok {
+		m.T.Errorf("unexpected comparison %s/%s %s...%s", owner, repo, base, head)
+	}
+	m.pages++
+	if opts.Page > 1 {
+		return &googlegithub.CommitsComparison{}, &googlegithub.Response{}, nil
+	}
+	m.compared = append(m.compared, base+"..."+head)
+	return comparison, &googlegithub.Response{NextPage: 2}, nil
+}
+
+func (m *compareMockClient) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	query, ok := q.(*QueryListCommitPullRequests)
+	if !ok {
+		return m.TestClient.Query(ctx, q, variables)
+	}
+	m.lookups++
+
+	for _, id := range variables["ids"].([]githubv4.ID) {
+		sha := strings.TrimPrefix(id.(string), "node-")
+		node := struct {
+			Commit CommitPullRequests `graphql:"... on Commit"`
+		}{Commit: CommitPullRequests{OID: sha}}
+
+		if number, ok := m.pullRequests[sha]; ok {
+			node.Commit.AssociatedPullRequests.Nodes = []struct {
+				Number int64
+				Title  string
+				URL    string
+			}{{Number: number, Title: fmt.Sprintf("PR #%d", number), URL: fmt.Sprintf("https://github.com/grafana/grafana/pull/%d", number)}}
+		}
+		query.Nodes = append(query.Nodes, node)
+	}
+
+	return nil
+}
+
+// newRESTCommit returns a commit of a REST comparison
+func newRESTCommit(sha string, login string, name string, date time.Time) *googlegithub.RepositoryCommit {
+	timestamp := &googlegithub.Timestamp{Time: date}
+	return &googlegithub.RepositoryCommit{
+		SHA:    googlegithub.Ptr(sha),
+		NodeID: googlegithub.Ptr("node-" + sha),
+		Author: &googlegithub.User{Login: googlegithub.Ptr(login)},
+		Commit: &googlegithub.Commit{
+			Message:   googlegithub.Ptr("commit " + sha),
+			Author:    &googlegithub.CommitAuthor{Name: googlegithub.Ptr(name), Email: googlegithub.Ptr(login + "@example.com"), Date: timestamp},
+			Committer: &googlegithub.CommitAuthor{Date: timestamp},
+		},
+	}
+}
+
+func TestGetComparison(t *testing.T) {
+	committedAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+	client := &compareMockClient{
+		TestClient: testutil.NewTestClient(t, nil, nil),
+		comparisons: map[string]*googlegithub.CommitsComparison{
+			"v1.0.0...abc123": {
+				Status:       googlegithub.Ptr("ahead"),
+				AheadBy:      googlegithub.Ptr(2),
+				BehindBy:     googlegithub.Ptr(0),
+				TotalCommits: googlegithub.Ptr(2),
+				HTMLURL:      googlegithub.Ptr("https://github.com/grafana/grafana/compare/v1.0.0...abc123"),
+				Commits: []*googlegithub.RepositoryCommit{
+					newRESTCommit("sha1", "testUser", "Test User", committedAt),
+					newRESTCommit("sha2", "testUser", "Test User", committedAt.Add(time.Hour)),
+				},
+				Files: []*googlegithub.CommitFile{
+					{Filename: googlegithub.Ptr("pkg/main.go"), Additions: googlegithub.Ptr(10), Deletions: googlegithub.Ptr(2), Changes: googlegithub.Ptr(12), Status: googlegithub.Ptr("modified")},
+				},
+			},
+		},
+		pullRequests: map[string]int64{"sha0": 9, "sha1": 10, "sha2": 11},
+	}
+
+	comparison, err := GetComparison(context.Background(), client, models.CompareOptions{
+		Owner:      "grafana",
+		Repository: "grafana",
+		Base:       "v1.0.0",
+		Head:       "abc123",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.pages)
+	assert.Equal(t, 1, client.lookups)
+	require.Len(t, comparison.Commits, 2)
+	assert.Equal(t, []ComparisonPullRequest{
+		{Number: 10, Title: "PR #10", URL: "https://github.com/grafana/grafana/pull/10", Commits: 1},
+		{Number: 11, Title: "PR #11", URL: "https://github.com/grafana/grafana/pull/11", Commits: 1},
+	}, comparison.PullRequests)
+
+	testutil.CheckGoldenFramer(t, "compare", comparison)
+}
+
+func TestGetComparisonLooksUpEveryCommit(t *testing.T) {
+	var (
+		committedAt = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		commits     = []*googlegithub.RepositoryCommit{}
+		numbers     = map[string]int64{}
+	)
+
+	// commits deep in a long-lived branch have their pull request found whatever their place in the history of the head
+	for i := 0; i < 250; i++ {
+		sha := fmt.Sprintf("sha%d", i)
+		commits = append(commits, newRESTCommit(sha, "testUser", "Test User", committedAt.Add(time.Duration(i)*time.Minute)))
+		numbers[sha] = int64(i/2 + 1)
+	}
+
+	client := &compareMockClient{
+		TestClient:   testutil.NewTestClient(t, nil, nil),
+		comparisons:  map[string]*googlegithub.CommitsComparison{"main...feature": {Commits: commits}},
+		pullRequests: numbers,
+	}
+
+	comparison, err := GetComparison(context.Background(), client, models.CompareOptions{Owner: "grafana", Repository: "grafana", Base: "main", Head: "feature"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, client.lookups)
+	require.Len(t, comparison.PullRequests, 125)
+	for _, pr := range comparison.PullRequests {
+		assert.Equal(t, int64(2), pr.Commits)
+	}
+}
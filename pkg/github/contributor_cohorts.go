@@ -0,0 +1,321 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// Contribution kinds
+const (
+	ContributionCommit      = "commit"
+	ContributionPullRequest = "pull_request"
+	ContributionIssue       = "issue"
+)
+
+// Contribution is a commit, a pull request or an issue authored by a contributor
+type Contribution struct {
+	Contributor string
+	Kind        string
+	Time        time.Time
+}
+
+// ContributorActivity is the first and the latest contribution of a contributor along with the number of contributions of every kind
+type ContributorActivity struct {
+	Contributor  string
+	First        time.Time
+	Latest       time.Time
+	Cohort       time.Time
+	Commits      int64
+	PullRequests int64
+	Issues       int64
+}
+
+// CohortPeriod is the number of active contributors of an interval by kind:
+// first-time contributors made their first contribution during the interval, returning ones contributed before,
+// and churned ones contributed during the previous interval but not during this one.
+type CohortPeriod struct {
+	Start     time.Time
+	Active    int64
+	FirstTime int64
+	Returning int64
+	Churned   int64
+}
+
+// CohortRetention is the share of the contributors of a cohort still contributing in each interval after their first one.
+// Retention[0] is the interval of the first contribution, it is always 1.
+type CohortRetention struct {
+	Cohort    time.Time
+	Size      int64
+	Retention []*float64
+}
+
+// ContributorCohorts is the contributors of a repository grouped by the interval of their first contribution
+type ContributorCohorts struct {
+	Contributors []ContributorActivity
+	Periods      []CohortPeriod
+	Retention    []CohortRetention
+}
+
+// Frames converts the cohorts to the period, retention and contributors frames
+func (c ContributorCohorts) Frames() data.Frames {
+	periods := data.NewFrame(
+		"contributor_cohorts",
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("active", nil, []int64{}),
+		data.NewField("first_time", nil, []int64{}),
+		data.NewField("returning", nil, []int64{}),
+		data.NewField("churned", nil, []int64{}),
+	)
+	for _, p := range c.Periods {
+		periods.AppendRow(p.Start, p.Active, p.FirstTime, p.Returning, p.Churned)
+	}
+
+	fields := []*data.Field{
+		data.NewField("cohort", nil, []time.Time{}),
+		data.NewField("size", nil, []int64{}),
+	}
+	for i := range c.Periods {
+		fields = append(fields, data.NewField(fmt.Sprintf("period_%d", i), nil, []*float64{}))
+	}
+	retention := data.NewFrame("cohort_retention", fields...)
+	for _, r := range c.Retention {
+		row := []interface{}{r.Cohort, r.Size}
+		for i := range c.Periods {
+			var v *float64
+			if i < len(r.Retention) {
+				v = r.Retention[i]
+			}
+			row = append(row, v)
+		}
+		retention.AppendRow(row...)
+	}
+
+	contributors := data.NewFrame(
+		"contributors",
+		data.NewField("contributor", nil, []string{}),
+		data.NewField("first_contribution", nil, []time.Time{}),
+		data.NewField("latest_contribution", nil, []time.Time{}),
+		data.NewField("cohort", nil, []time.Time{}),
+		data.NewField("commits", nil, []int64{}),
+		data.NewField("pull_requests", nil, []int64{}),
+		data.NewField("issues", nil, []int64{}),
+	)
+	for _, a := range c.Contributors {
+		contributors.AppendRow(a.Contributor, a.First, a.Latest, a.Cohort, a.Commits, a.PullRequests, a.Issues)
+	}
+
+	return data.Frames{periods, retention, contributors}
+}
+
+// intervalStart returns the start of the interval the time is in, in UTC
+func intervalStart(t time.Time, interval models.CohortInterval) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case models.CohortWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.CohortQuarter:
+		return time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// nextInterval returns the start of the interval following the one starting at start
+func nextInterval(start time.Time, interval models.CohortInterval) time.Time {
+	switch interval {
+	case models.CohortWeek:
+		return start.AddDate(0, 0, 7)
+	case models.CohortQuarter:
+		return start.AddDate(0, 3, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// GetContributorCohorts groups the contributors by the interval of their first contribution and counts them in every interval of the time range.
+// Contributions before the time range are only used to find the first contribution of every contributor and the contributors of the interval before the range.
+func GetContributorCohorts(contributions []Contribution, interval models.CohortInterval, from time.Time, to time.Time) ContributorCohorts {
+	var (
+		activity = map[string]*ContributorActivity{}
+		// active lists the contributors of every interval
+		active = map[time.Time]map[string]bool{}
+	)
+
+	for _, c := range contributions {
+		if c.Contributor == "" || c.Time.After(to) {
+			continue
+		}
+
+		a, ok := activity[c.Contributor]
+		if !ok {
+			a = &ContributorActivity{Contributor: c.Contributor, First: c.Time, Latest: c.Time}
+			activity[c.Contributor] = a
+		}
+		if c.Time.Before(a.First) {
+			a.First = c.Time
+		}
+		if c.Time.After(a.Latest) {
+			a.Latest = c.Time
+		}
+		switch c.Kind {
+		case ContributionCommit:
+			a.Commits++
+		case ContributionPullRequest:
+			a.PullRequests++
+		case ContributionIssue:
+			a.Issues++
+		}
+
+		start := intervalStart(c.Time, interval)
+		if active[start] == nil {
+			active[start] = map[string]bool{}
+		}
+		active[start][c.Contributor] = true
+	}
+
+	cohorts := ContributorCohorts{
+		Contributors: []ContributorActivity{},
+		Periods:      []CohortPeriod{},
+		Retention:    []CohortRetention{},
+	}
+
+	members := map[time.Time][]string{}
+	for _, a := range activity {
+		a.Cohort = intervalStart(a.First, interval)
+		members[a.Cohort] = append(members[a.Cohort], a.Contributor)
+		if !a.Latest.Before(from) {
+			cohorts.Contributors = append(cohorts.Contributors, *a)
+		}
+	}
+	sort.Slice(cohorts.Contributors, func(i, j int) bool {
+		if !cohorts.Contributors[i].First.Equal(cohorts.Contributors[j].First) {
+			return cohorts.Contributors[i].First.Before(cohorts.Contributors[j].First)
+		}
+		return cohorts.Contributors[i].Contributor < cohorts.Contributors[j].Contributor
+	})
+
+	first := intervalStart(from, interval)
+	starts := []time.Time{}
+	for start := first; start.Before(to); start = nextInterval(start, interval) {
+		starts = append(starts, start)
+	}
+
+	// the contributors of the interval before the range tell who churned in the first interval
+	previous := active[intervalStart(first.Add(-time.Nanosecond), interval)]
+
+	for _, start := range starts {
+		p := CohortPeriod{Start: start}
+		for contributor := range active[start] {
+			p.Active++
+			if activity[contributor].Cohort.Equal(start) {
+				p.FirstTime++
+			} else {
+				p.Returning++
+			}
+		}
+		for contributor := range previous {
+			if !active[start][contributor] {
+				p.Churned++
+			}
+		}
+		previous = active[start]
+		cohorts.Periods = append(cohorts.Periods, p)
+	}
+
+	for i, start := range starts {
+		cohort := members[start]
+		if len(cohort) == 0 {
+			continue
+		}
+
+		r := CohortRetention{Cohort: start, Size: int64(len(cohort))}
+		for _, later := range starts[i:] {
+			var retained int64
+			for _, contributor := range cohort {
+				if active[later][contributor] {
+					retained++
+				}
+			}
+			share := float64(retained) / float64(len(cohort))
+			r.Retention = append(r.Retention, &share)
+		}
+		cohorts.Retention = append(cohorts.Retention, r)
+	}
+
+	return cohorts
+}
+
+// getContributions keys the commits, pull requests and issues by the login of their author, so a contributor is counted once across kinds.
+// Commits whose author isn't linked to a GitHub user are skipped: their email can't be matched with the authors of pull requests and issues.
+func getContributions(commits Commits, pullRequests PullRequests, issues Issues) []Contribution {
+	contributions := make([]Contribution, 0, len(commits)+len(pullRequests)+len(issues))
+	for _, c := range commits {
+		if c.Author.User.Login == "" {
+			continue
+		}
+		contributions = append(contributions, Contribution{Contributor: c.Author.User.Login, Kind: ContributionCommit, Time: c.CommittedDate.Time})
+	}
+	for _, pr := range pullRequests {
+		contributions = append(contributions, Contribution{Contributor: pr.Author.User.Login, Kind: ContributionPullRequest, Time: pr.CreatedAt.Time})
+	}
+	for _, issue := range issues {
+		contributions = append(contributions, Contribution{Contributor: issue.Author.Login, Kind: ContributionIssue, Time: issue.CreatedAt.Time})
+	}
+
+	return contributions
+}
+
+// GetContributionsInRange lists the commits of the ref and the pull requests and issues opened in a repository within a time range
+func GetContributionsInRange(ctx context.Context, client models.Client, opts models.ListContributorCohortsOptions, from time.Time, to time.Time) ([]Contribution, error) {
+	ref := opts.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	commits, err := GetCommitsInRange(ctx, client, models.ListCommitsOptions{
+		Owner:      opts.Owner,
+		Repository: opts.Repository,
+		Ref:        ref,
+	}, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests, err := GetPullRequestsInRange(ctx, client, models.ListPullRequestsOptions{
+		Owner:      opts.Owner,
+		Repository: opts.Repository,
+		TimeField:  models.PullRequestCreatedAt,
+	}, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := GetIssuesInRange(ctx, client, models.ListIssuesOptions{
+		Owner:      opts.Owner,
+		Repository: opts.Repository,
+		TimeField:  models.IssueCreatedAt,
+	}, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return getContributions(commits, pullRequests, issues), nil
+}
+
+// GetContributorCohortsInRange groups the contributors of a repository into cohorts over the time range.
+// The contributions of the lookback window before the range are fetched too, to tell first-time contributors from returning ones.
+func GetContributorCohortsInRange(ctx context.Context, client models.Client, opts models.ListContributorCohortsOptions, from time.Time, to time.Time) (ContributorCohorts, error) {
+	contributions, err := GetContributionsInRange(ctx, client, opts, from.AddDate(0, 0, -opts.LookbackDays), to)
+	if err != nil {
+		return ContributorCohorts{}, err
+	}
+
+	return GetContributorCohorts(contributions, opts.Interval, from, to), nil
+}
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestIntervalStart(t *testing.T) {
+	// a Wednesday
+	tm := time.Date(2020, 8, 19, 15, 4, 5, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2020, 8, 17, 0, 0, 0, 0, time.UTC), intervalStart(tm, models.CohortWeek))
+	assert.Equal(t, time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC), intervalStart(tm, models.CohortMonth))
+	assert.Equal(t, time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC), intervalStart(tm, models.CohortQuarter))
+
+	assert.Equal(t, time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC), nextInterval(time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC), models.CohortQuarter))
+}
+
+func contributorCohortsTestContributions() []Contribution {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2020, month, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	return []Contribution{
+		// a long time contributor, active before the range and in every month of it
+		{Contributor: "veteran", Kind: ContributionCommit, Time: day(time.May, 3)},
+		{Contributor: "veteran", Kind: ContributionCommit, Time: day(time.June, 3)},
+		{Contributor: "veteran", Kind: ContributionPullRequest, Time: day(time.July, 3)},
+		{Contributor: "veteran", Kind: ContributionIssue, Time: day(time.August, 3)},
+		// active in the month before the range only
+		{Contributor: "gone", Kind: ContributionIssue, Time: day(time.June, 20)},
+		// a first-time contributor in July who came back in August
+		{Contributor: "newcomer", Kind: ContributionPullRequest, Time: day(time.July, 10)},
+		{Contributor: "newcomer", Kind: ContributionCommit, Time: day(time.August, 10)},
+		// a first-time contributor in July who didn't come back
+		{Contributor: "drive-by", Kind: ContributionIssue, Time: day(time.July, 11)},
+		// a first-time contributor in August
+		{Contributor: "late", Kind: ContributionPullRequest, Time: day(time.August, 20)},
+		// ignored: no author, or after the range
+		{Contributor: "", Kind: ContributionPullRequest, Time: day(time.August, 21)},
+		{Contributor: "future", Kind: ContributionPullRequest, Time: day(time.September, 2)},
+	}
+}
+
+func TestGetContributorCohorts(t *testing.T) {
+	from := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 8, 31, 23, 59, 59, 0, time.UTC)
+
+	cohorts := GetContributorCohorts(contributorCohortsTestContributions(), models.CohortMonth, from, to)
+
+	assert.Equal(t, []CohortPeriod{
+		{Start: from, Active: 3, FirstTime: 2, Returning: 1, Churned: 1},
+		{Start: time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC), Active: 3, FirstTime: 1, Returning: 2, Churned: 1},
+	}, cohorts.Periods)
+
+	require.Len(t, cohorts.Retention, 2)
+	assert.Equal(t, from, cohorts.Retention[0].Cohort)
+	assert.Equal(t, int64(2), cohorts.Retention[0].Size)
+	require.Len(t, cohorts.Retention[0].Retention, 2)
+	assert.Equal(t, 1.0, *cohorts.Retention[0].Retention[0])
+	assert.Equal(t, 0.5, *cohorts.Retention[0].Retention[1])
+	require.Len(t, cohorts.Retention[1].Retention, 1)
+
+	// the contributors who stopped before the range are not listed
+	names := []string{}
+	for _, c := range cohorts.Contributors {
+		names = append(names, c.Contributor)
+	}
+	assert.Equal(t, []string{"veteran", "newcomer", "drive-by", "late"}, names)
+	assert.Equal(t, ContributorActivity{
+		Contributor:  "veteran",
+		First:        time.Date(2020, 5, 3, 12, 0, 0, 0, time.UTC),
+		Latest:       time.Date(2020, 8, 3, 12, 0, 0, 0, time.UTC),
+		Cohort:       time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+		Commits:      2,
+		PullRequests: 1,
+		Issues:       1,
+	}, cohorts.Contributors[0])
+}
+
+func TestGetContributions(t *testing.T) {
+	at := githubv4.DateTime{Time: time.Date(2020, 7, 3, 12, 0, 0, 0, time.UTC)}
+
+	commits := Commits{
+		{OID: "aaa", CommittedDate: at, Author: GitActor{Email: "newcomer@example.com", User: models.User{Login: "newcomer"}}},
+		{OID: "bbb", CommittedDate: at, Author: GitActor{Email: "unlinked@example.com"}},
+	}
+	pullRequests := PullRequests{{Number: 1, CreatedAt: at, Author: PullRequestAuthor{User: models.User{Login: "newcomer"}}}}
+	issues := Issues{{Number: 2, CreatedAt: at}}
+	issues[0].Author.Login = "reporter"
+
+	contributions := getContributions(commits, pullRequests, issues)
+	assert.Equal(t, []Contribution{
+		{Contributor: "newcomer", Kind: ContributionCommit, Time: at.Time},
+		{Contributor: "newcomer", Kind: ContributionPullRequest, Time: at.Time},
+		{Contributor: "reporter", Kind: ContributionIssue, Time: at.Time},
+	}, contributions)
+
+	// the commit and the pull request of the same person make a single first-time contributor
+	cohorts := GetContributorCohorts(contributions, models.CohortMonth, time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 7, 31, 0, 0, 0, 0, time.UTC))
+	require.Len(t, cohorts.Periods, 1)
+	assert.Equal(t, int64(2), cohorts.Periods[0].FirstTime)
+}
+
+func TestContributorCohortsDataframe(t *testing.T) {
+	from := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 8, 31, 23, 59, 59, 0, time.UTC)
+
+	cohorts := GetContributorCohorts(contributorCohortsTestContributions(), models.CohortMonth, from, to)
+	testutil.CheckGoldenFramer(t, "contributor_cohorts", cohorts)
+}
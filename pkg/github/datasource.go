@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
@@ -21,7 +22,15 @@ var (
 
 // Datasource handles requests to GitHub
 type Datasource struct {
-	client *githubclient.Client
+	client           *githubclient.Client
+	teamMembers      *teamMembersCache
+	projectSnapshots *projects.StatusSnapshotStore
+	packageDownloads *packageDownloadsStore
+	stargazers       *repositoryHistoryStore[Stargazer]
+	forks            *repositoryHistoryStore[Fork]
+
+	// stop cancels the background recording of the snapshots
+	stop context.CancelFunc
 }
 
 // HandleRepositoriesQuery is the query handler for listing GitHub Repositories
@@ -29,14 +38,25 @@ func (d *Datasource) HandleRepositoriesQuery(ctx context.Context, query *models.
 	opt := models.ListRepositoriesOptions{
 		Owner:      query.Owner,
 		Repository: query.Repository,
+		Mode:       query.Options.Mode,
+		StaleDays:  query.Options.StaleDays,
 	}
 
+	if opt.Mode == models.RepositoriesModeInventory {
+		return GetRepositoryInventory(ctx, d.client, opt)
+	}
+	if opt.Mode == models.RepositoriesModeStale {
+		return GetStaleRepositories(ctx, d.client, opt, time.Now())
+	}
 	return GetAllRepositories(ctx, d.client, opt)
 }
 
 // HandleIssuesQuery is the query handler for listing GitHub Issues
 func (d *Datasource) HandleIssuesQuery(ctx context.Context, query *models.IssuesQuery, req backend.DataQuery) (dfutil.Framer, error) {
 	opt := models.IssueOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	if err := d.teamMembers.resolveTeam(ctx, d.client, opt.Owner, &opt.TeamSearchOptions); err != nil {
+		return nil, err
+	}
 	return GetIssuesInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
 }
 
@@ -87,6 +107,11 @@ func (d *Datasource) HandleBranchesQuery(ctx context.Context, query *models.Bran
 		Repository: query.Repository,
 		Owner:      query.Owner,
 		Query:      query.Options.Query,
+		Mode:       query.Options.Mode,
+		StaleDays:  query.Options.StaleDays,
+	}
+	if opt.Mode == models.BranchesModeStale {
+		return GetStaleBranches(ctx, d.client, opt, time.Now())
 	}
 	return GetAllBranches(ctx, d.client, opt)
 }
@@ -94,8 +119,14 @@ func (d *Datasource) HandleBranchesQuery(ctx context.Context, query *models.Bran
 // HandleReleasesQuery is the query handler for listing GitHub Releases
 func (d *Datasource) HandleReleasesQuery(ctx context.Context, query *models.ReleasesQuery, req backend.DataQuery) (dfutil.Framer, error) {
 	opt := models.ListReleasesOptions{
-		Repository: query.Repository,
-		Owner:      query.Owner,
+		Repository:     query.Repository,
+		Owner:          query.Owner,
+		IncludeAssets:  query.Options.IncludeAssets,
+		IncludeCadence: query.Options.IncludeCadence,
+	}
+
+	if opt.IncludeAssets || opt.IncludeCadence {
+		return GetReleaseMetrics(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
 	}
 
 	if req.TimeRange.From.Unix() <= 0 && req.TimeRange.To.Unix() <= 0 {
@@ -107,16 +138,32 @@ func (d *Datasource) HandleReleasesQuery(ctx context.Context, query *models.Rele
 // HandlePullRequestsQuery is the query handler for listing GitHub PullRequests
 func (d *Datasource) HandlePullRequestsQuery(ctx context.Context, query *models.PullRequestsQuery, req backend.DataQuery) (dfutil.Framer, error) {
 	opt := models.PullRequestOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	if err := d.teamMembers.resolveTeam(ctx, d.client, opt.Owner, &opt.TeamSearchOptions); err != nil {
+		return nil, err
+	}
 
+	var (
+		prs PullRequests
+		err error
+	)
 	if req.TimeRange.From.Unix() <= 0 && req.TimeRange.To.Unix() <= 0 {
-		return GetAllPullRequests(ctx, d.client, opt)
+		prs, err = GetAllPullRequests(ctx, d.client, opt)
+	} else {
+		prs, err = GetPullRequestsInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
+	}
+	if err != nil || !opt.IncludeContributorExperience {
+		return prs, err
 	}
-	return GetPullRequestsInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
+
+	return GetContributorExperience(ctx, d.client, prs)
 }
 
 // HandleReviewsQuery is the query handler for listing GitHub Pull Request Reviews
 func (d *Datasource) HandleReviewsQuery(ctx context.Context, query *models.PullRequestsQuery, req backend.DataQuery) (dfutil.Framer, error) {
 	opt := models.PullRequestOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	if err := d.teamMembers.resolveTeam(ctx, d.client, opt.Owner, &opt.TeamSearchOptions); err != nil {
+		return nil, err
+	}
 
 	if req.TimeRange.From.Unix() <= 0 && req.TimeRange.To.Unix() <= 0 {
 		return GetAllPullRequestReviews(ctx, d.client, opt)
@@ -158,13 +205,35 @@ func (d *Datasource) HandleMilestonesQuery(ctx context.Context, query *models.Mi
 }
 
 // HandlePackagesQuery is the query handler for listing GitHub Packages
+// In the Downloads mode, the downloads of the packages of a repository are recorded every time they are queried, and periodically afterwards, to chart them over time.
 func (d *Datasource) HandlePackagesQuery(ctx context.Context, query *models.PackagesQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if query.Options.Mode == models.PackagesModeRegistry {
+		opt, err := models.RegistryPackagesOptions(query.Options, query.Owner)
+		if err != nil {
+			return nil, err
+		}
+		return GetRegistryPackages(ctx, d.client, opt)
+	}
+
 	opt, err := models.PackagesOptionsWithRepo(query.Options, query.Owner, query.Repository)
 	if err != nil {
 		return nil, err
 	}
 
-	return GetAllPackages(ctx, d.client, opt)
+	packages, err := GetAllPackages(ctx, d.client, opt)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Mode != models.PackagesModeDownloads {
+		return packages, nil
+	}
+
+	// queries with different filters get their own snapshots
+	key := fmt.Sprintf("%s/%s:%s:%s", opt.Owner, opt.Repository, opt.PackageType, opt.Names)
+	now := time.Now()
+	d.packageDownloads.watch(key, opt, now)
+	snapshots := d.packageDownloads.record(key, packages, now)
+	return getPackageDownloads(snapshots, req.TimeRange.From, req.TimeRange.To), nil
 }
 
 // HandleVulnerabilitiesQuery is the query handler for listing GitHub Packages
@@ -180,13 +249,26 @@ func (d *Datasource) HandleVulnerabilitiesQuery(ctx context.Context, query *mode
 // HandleProjectsQuery is the query handler for listing GitHub Projects
 func (d *Datasource) HandleProjectsQuery(ctx context.Context, query *models.ProjectsQuery, req backend.DataQuery) (dfutil.Framer, error) {
 	opt := models.ProjectOptions{
-		Organization: query.Options.Organization,
-		Number:       query.Options.Number,
-		User:         query.Options.User,
-		Kind:         query.Options.Kind,
-		Filters:      query.Options.Filters,
+		Organization:     query.Options.Organization,
+		Number:           query.Options.Number,
+		User:             query.Options.User,
+		Kind:             query.Options.Kind,
+		Repository:       query.Options.Repository,
+		Team:             query.Options.Team,
+		Filters:          query.Options.Filters,
+		FilterExpression: query.Options.FilterExpression,
+		Mode:             query.Options.Mode,
+		IterationField:   query.Options.IterationField,
+		EstimateField:    query.Options.EstimateField,
+		Iteration:        query.Options.Iteration,
 	}
 
+	if opt.Mode == models.ProjectModeBurndown {
+		return projects.GetProjectBurndown(ctx, d.client, opt, time.Now())
+	}
+	if opt.Mode == models.ProjectModeFlow {
+		return projects.GetProjectFlow(ctx, d.client, d.projectSnapshots, opt, req.TimeRange.From, req.TimeRange.To)
+	}
 	if projects.ProjectNumber(query.Options.Number) > 0 {
 		return projects.GetAllProjectItems(ctx, d.client, opt)
 	}
@@ -200,7 +282,7 @@ func (d *Datasource) HandleStargazersQuery(ctx context.Context, query *models.St
 		Owner:      query.Owner,
 	}
 
-	return GetStargazers(ctx, d.client, opt, req.TimeRange)
+	return GetStargazers(ctx, d.client, d.stargazers.history(opt.Owner, opt.Repository, time.Now()), opt, req.TimeRange)
 }
 
 // HandleWorkflowsQuery is the query handler for listing workflows of a GitHub repository
@@ -263,6 +345,131 @@ func (d *Datasource) HandleOrganizationsQuery(ctx context.Context, query *models
 	return Organizations(orgs), nil
 }
 
+// HandleBranchProtectionQuery is the query handler for auditing branch protection rules and rulesets
+func (d *Datasource) HandleBranchProtectionQuery(ctx context.Context, query *models.BranchProtectionQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.BranchProtectionOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetBranchProtection(ctx, d.client, opt)
+}
+
+// HandleMembersQuery is the query handler for listing the members of a GitHub organization
+func (d *Datasource) HandleMembersQuery(ctx context.Context, query *models.MembersQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.ListMembersOptions{
+		Owner: query.Owner,
+	}
+
+	return GetAllMembers(ctx, d.client, opt)
+}
+
+// HandleTeamsQuery is the query handler for listing the teams of a GitHub organization
+func (d *Datasource) HandleTeamsQuery(ctx context.Context, query *models.TeamsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.ListTeamsOptions{
+		Owner: query.Owner,
+		Query: query.Options.Query,
+	}
+
+	return GetAllTeams(ctx, d.client, opt)
+}
+
+// HandleCollaboratorsQuery is the query handler for listing the collaborators of a GitHub repository
+func (d *Datasource) HandleCollaboratorsQuery(ctx context.Context, query *models.CollaboratorsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.CollaboratorsOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetAllCollaborators(ctx, d.client, opt)
+}
+
+// HandleDeploymentStatusesQuery is the query handler for listing the status history of GitHub Deployments
+func (d *Datasource) HandleDeploymentStatusesQuery(ctx context.Context, query *models.DeploymentStatusesQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.ListDeploymentStatusesOptions{
+		Repository:  query.Repository,
+		Owner:       query.Owner,
+		Environment: query.Options.Environment,
+	}
+
+	from, to := req.TimeRange.From, req.TimeRange.To
+	if from.Unix() <= 0 && to.Unix() <= 0 {
+		from, to = time.Time{}, time.Now()
+	}
+	return GetDeploymentStatusesInRange(ctx, d.client, opt, from, to)
+}
+
+// HandleEnvironmentsQuery is the query handler for listing the deployment environments of a GitHub repository
+func (d *Datasource) HandleEnvironmentsQuery(ctx context.Context, query *models.EnvironmentsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.ListEnvironmentsOptions{
+		Repository: query.Repository,
+		Owner:      query.Owner,
+	}
+
+	return GetAllEnvironments(ctx, d.client, opt)
+}
+
+// HandleChecksQuery is the query handler for listing GitHub check suites, check runs and commit statuses
+func (d *Datasource) HandleChecksQuery(ctx context.Context, query *models.ChecksQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.ChecksOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetChecksInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
+}
+
+// HandleMergeQueueQuery is the query handler for listing the entries and the history of a GitHub merge queue
+func (d *Datasource) HandleMergeQueueQuery(ctx context.Context, query *models.MergeQueueQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.MergeQueueOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetMergeQueueInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
+}
+
+// HandleDiscussionsQuery is the query handler for listing GitHub Discussions
+func (d *Datasource) HandleDiscussionsQuery(ctx context.Context, query *models.DiscussionsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.DiscussionOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetDiscussionsInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
+}
+
+// HandleCompareQuery is the query handler for comparing two GitHub refs
+func (d *Datasource) HandleCompareQuery(ctx context.Context, query *models.CompareQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.CompareOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetComparison(ctx, d.client, opt)
+}
+
+// HandleHotspotsQuery is the query handler for the file and directory hotspots of a GitHub repository
+func (d *Datasource) HandleHotspotsQuery(ctx context.Context, query *models.HotspotsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.HotspotsOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetHotspotsInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
+}
+
+// HandleOwnershipQuery is the query handler for the bus factor and code ownership health of a GitHub repository
+func (d *Datasource) HandleOwnershipQuery(ctx context.Context, query *models.OwnershipQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.OwnershipOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetOwnershipInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
+}
+
+// HandleCodeownersQuery is the query handler for the CODEOWNERS coverage and review routing of a GitHub repository
+func (d *Datasource) HandleCodeownersQuery(ctx context.Context, query *models.CodeownersQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.CodeownersOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetCodeownersReport(ctx, d.client, d.teamMembers, opt, req.TimeRange.From, req.TimeRange.To)
+}
+
+// HandleContributorCohortsQuery is the query handler for the contributor cohorts of a GitHub repository
+func (d *Datasource) HandleContributorCohortsQuery(ctx context.Context, query *models.ContributorCohortsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.ContributorCohortsOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetContributorCohortsInRange(ctx, d.client, opt, req.TimeRange.From, req.TimeRange.To)
+}
+
+// HandleDependenciesQuery is the query handler for listing the dependencies of GitHub repositories from the dependency graph
+func (d *Datasource) HandleDependenciesQuery(ctx context.Context, query *models.DependenciesQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.DependenciesOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetAllDependencies(ctx, d.client, opt)
+}
+
+// HandleForksQuery is the query handler for listing the forks of a GitHub repository
+func (d *Datasource) HandleForksQuery(ctx context.Context, query *models.ForksQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.ForksOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	if opt.Mode == models.ForksModeActive {
+		return GetActiveForks(ctx, d.client, opt, time.Now())
+	}
+	return GetForks(ctx, d.client, d.forks.history(opt.Owner, opt.Repository, time.Now()), opt, req.TimeRange)
+}
+
+// HandleTechStackQuery is the query handler for aggregating the languages and topics of the repositories of a GitHub owner
+func (d *Datasource) HandleTechStackQuery(ctx context.Context, query *models.TechStackQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	opt := models.TechStackOptionsWithRepo(query.Options, query.Owner, query.Repository)
+	return GetTechStack(ctx, d.client, opt)
+}
+
 // CheckHealth is the health check for GitHub
 func (d *Datasource) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
 	_, err := GetAllRepositories(ctx, d.client, models.ListRepositoriesOptions{
@@ -300,7 +507,28 @@ func NewDatasource(ctx context.Context, settings models.Settings) (*Datasource,
 	if err != nil {
 		return nil, err
 	}
-	return &Datasource{client: client}, nil
+	background, stop := context.WithCancel(context.Background())
+	d := &Datasource{
+		client:           client,
+		teamMembers:      newTeamMembersCache(),
+		projectSnapshots: projects.NewStatusSnapshotStore(),
+		packageDownloads: newPackageDownloadsStore(),
+		stargazers:       newRepositoryHistoryStore[Stargazer](),
+		forks:            newRepositoryHistoryStore[Fork](),
+		stop:             stop,
+	}
+
+	go d.projectSnapshots.Run(background, client, projects.StatusSnapshotInterval)
+	go d.packageDownloads.run(background, client, PackageDownloadsRecordInterval)
+
+	return d, nil
+}
+
+// Dispose stops the background recording of the snapshots when the datasource settings change or the datasource is deleted
+func (d *Datasource) Dispose() {
+	if d.stop != nil {
+		d.stop()
+	}
 }
 
 func newHealthResult(status backend.HealthStatus, message string) (*backend.CheckHealthResult, error) {
@@ -0,0 +1,371 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// MaxDependencyRepositories is the number of repositories whose dependencies are read when listing the dependencies of an owner.
+// The dependencies are read with a request per repository, the dependencies of the other repositories are not listed.
+const MaxDependencyRepositories = 100
+
+// DependencyPageLimit is the number of pages of manifests, and of dependencies of a manifest, read for a repository
+const DependencyPageLimit = 10
+
+// QueryListDependencyManifests is the GraphQL query for listing the manifests of the dependency graph of a repository with their dependencies
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    dependencyGraphManifests(first: 10, withDependencies: true) {
+//	      nodes {
+//	        id
+//	        filename
+//	        dependencies(first: 100) {
+//	          nodes {
+//	            packageName
+//	            packageManager
+//	            requirements
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListDependencyManifests struct {
+	Repository struct {
+		DependencyGraphManifests struct {
+			Nodes []struct {
+				ID           string
+				Filename     string
+				Dependencies DependencyGraphDependencies `graphql:"dependencies(first: 100)"`
+			}
+			PageInfo models.PageInfo
+		} `graphql:"dependencyGraphManifests(first: 10, after: $cursor, withDependencies: true)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// QueryListManifestDependencies is the GraphQL query for listing the next dependencies of a manifest with more than 100 dependencies
+type QueryListManifestDependencies struct {
+	Node struct {
+		Manifest struct {
+			Dependencies DependencyGraphDependencies `graphql:"dependencies(first: 100, after: $cursor)"`
+		} `graphql:"... on DependencyGraphManifest"`
+	} `graphql:"node(id: $id)"`
+}
+
+// DependencyGraphDependencies is a page of the dependencies of a manifest
+type DependencyGraphDependencies struct {
+	Nodes []struct {
+		PackageName    string
+		PackageManager string
+		Requirements   string
+	}
+	PageInfo models.PageInfo
+}
+
+// Dependency is a package a repository depends on
+type Dependency struct {
+	Repository string
+	// Manifest is only known for the dependencies read from the manifests
+	Manifest string
+	Package  string
+	// Version is the resolved version of the package from the SBOM, or the version requirements from the manifests
+	Version   string
+	Ecosystem string
+	// License is only known for the dependencies read from the SBOM
+	License string
+}
+
+// Dependencies is a list of repository dependencies
+type Dependencies []Dependency
+
+// Frames converts the list of dependencies to a Grafana DataFrame
+func (d Dependencies) Frames() data.Frames {
+	frame := data.NewFrame(
+		"dependencies",
+		data.NewField("repository", nil, []string{}),
+		data.NewField("manifest", nil, []string{}),
+		data.NewField("package", nil, []string{}),
+		data.NewField("version", nil, []string{}),
+		data.NewField("ecosystem", nil, []string{}),
+		data.NewField("license", nil, []string{}),
+	)
+
+	for _, v := range d {
+		frame.AppendRow(
+			v.Repository,
+			v.Manifest,
+			v.Package,
+			v.Version,
+			v.Ecosystem,
+			v.License,
+		)
+	}
+
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{frame}
+}
+
+// GetAllDependencies lists the dependencies of a repository, or of the first MaxDependencyRepositories repositories of the owner when no repository is set.
+// With a package and a version, it answers which repositories use the package below that version.
+// When listing the dependencies of an owner, a repository whose dependencies can't be read doesn't fail the query, its dependencies are not listed.
+func GetAllDependencies(ctx context.Context, client models.Client, opts models.ListDependenciesOptions) (Dependencies, error) {
+	dependencies := Dependencies{}
+	if opts.Owner == "" {
+		return dependencies, nil
+	}
+
+	repositories, err := listRepositoryNames(ctx, client, opts.Owner, opts.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repositories = firstRepositoryNames(repositories, MaxDependencyRepositories, opts.Owner)
+
+	for _, repository := range repositories {
+		var d Dependencies
+		if opts.Source == models.DependenciesSourceManifests {
+			d, err = getManifestDependencies(ctx, client, opts.Owner, repository)
+		} else {
+			d, err = getSBOMDependencies(ctx, client, opts.Owner, repository, opts.Repository == "")
+		}
+		if err != nil {
+			if opts.Repository != "" {
+				return nil, fmt.Errorf("listing dependencies of %s/%s: %w", opts.Owner, repository, err)
+			}
+			backend.Logger.Warn("could not list the dependencies of the repository", "owner", opts.Owner, "repository", repository, "error", err)
+			// the next requests would fail as well
+			var rateLimitErr *googlegithub.RateLimitError
+			if errors.As(err, &rateLimitErr) {
+				break
+			}
+			continue
+		}
+
+		for _, dependency := range d {
+			if matchDependency(dependency, opts) {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	}
+
+	return dependencies, nil
+}
+
+// getSBOMDependencies reads the dependencies of a repository from its SBOM.
+// When skipMissing is true, a repository without a dependency graph has no dependencies instead of failing the query.
+func getSBOMDependencies(ctx context.Context, client models.Client, owner string, repository string, skipMissing bool) (Dependencies, error) {
+	sbom, resp, err := client.GetSBOM(ctx, owner, repository)
+	if err != nil {
+		if skipMissing && resp != nil && resp.StatusCode == http.StatusNotFound {
+			backend.Logger.Debug("repository has no dependency graph", "owner", owner, "repository", repository)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	info := sbom.GetSBOM()
+	if info == nil {
+		return nil, nil
+	}
+
+	dependencies := Dependencies{}
+	for _, p := range info.Packages {
+		// the SBOM describes the repository itself as a package
+		if containsFold(info.DocumentDescribes, p.GetSPDXID()) {
+			continue
+		}
+
+		ecosystem, name := sbomPackageName(p)
+		license := p.GetLicenseConcluded()
+		if license == "" || license == "NOASSERTION" {
+			license = p.GetLicenseDeclared()
+		}
+		if license == "NOASSERTION" {
+			license = ""
+		}
+
+		dependencies = append(dependencies, Dependency{
+			Repository: owner + "/" + repository,
+			Package:    name,
+			Version:    p.GetVersionInfo(),
+			Ecosystem:  ecosystem,
+			License:    license,
+		})
+	}
+	return dependencies, nil
+}
+
+// purlEcosystems maps the package URL types to the package managers of the dependency graph manifests,
+// so that both sources can be filtered by the same ecosystem
+var purlEcosystems = map[string]string{
+	"pypi":          "pip",
+	"gem":           "rubygems",
+	"golang":        "go",
+	"githubactions": "actions",
+	"cargo":         "rust",
+}
+
+// sbomPackageName returns the ecosystem and the name of a package of the SBOM.
+// The ecosystem is the type of the package URL, the names are prefixed with the package manager of the dependency graph (ex: go:github.com/pkg/errors for pkg:golang).
+func sbomPackageName(p *googlegithub.RepoDependencies) (string, string) {
+	name := p.GetName()
+	ecosystem := ""
+	for _, ref := range p.ExternalRefs {
+		if ref.ReferenceType == "purl" && strings.HasPrefix(ref.ReferenceLocator, "pkg:") {
+			purlType, _, _ := strings.Cut(strings.TrimPrefix(ref.ReferenceLocator, "pkg:"), "/")
+			ecosystem = purlEcosystem(purlType)
+			break
+		}
+	}
+	if prefix, rest, ok := strings.Cut(name, ":"); ok && (ecosystem == "" || purlEcosystem(prefix) == ecosystem) {
+		ecosystem, name = purlEcosystem(prefix), rest
+	}
+	return ecosystem, name
+}
+
+// purlEcosystem returns the package manager of a package URL type, or the package manager itself
+func purlEcosystem(s string) string {
+	s = strings.ToLower(s)
+	if e, ok := purlEcosystems[s]; ok {
+		return e
+	}
+	return s
+}
+
+// getManifestDependencies reads the dependencies of a repository from the manifests of its dependency graph.
+// Up to DependencyPageLimit pages of manifests are read, and of dependencies of every manifest.
+func getManifestDependencies(ctx context.Context, client models.Client, owner string, repository string) (Dependencies, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"owner":  githubv4.String(owner),
+			"name":   githubv4.String(repository),
+		}
+
+		dependencies = Dependencies{}
+	)
+
+	for i := 0; i < DependencyPageLimit; i++ {
+		q := &QueryListDependencyManifests{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, manifest := range q.Repository.DependencyGraphManifests.Nodes {
+			page := manifest.Dependencies
+			for j := 0; j < DependencyPageLimit; j++ {
+				for _, d := range page.Nodes {
+					dependencies = append(dependencies, Dependency{
+						Repository: owner + "/" + repository,
+						Manifest:   manifest.Filename,
+						Package:    d.PackageName,
+						Version:    d.Requirements,
+						Ecosystem:  strings.ToLower(d.PackageManager),
+					})
+				}
+				if !page.PageInfo.HasNextPage || j == DependencyPageLimit-1 {
+					break
+				}
+
+				next := &QueryListManifestDependencies{}
+				if err := client.Query(ctx, next, map[string]interface{}{"id": githubv4.ID(manifest.ID), "cursor": page.PageInfo.EndCursor}); err != nil {
+					return nil, errors.WithStack(err)
+				}
+				page = next.Node.Manifest.Dependencies
+			}
+		}
+
+		if !q.Repository.DependencyGraphManifests.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.DependencyGraphManifests.PageInfo.EndCursor
+	}
+
+	return dependencies, nil
+}
+
+// matchDependency returns true when the dependency matches the package, the ecosystem and the version of the options
+func matchDependency(d Dependency, opts models.ListDependenciesOptions) bool {
+	if opts.Package != "" && !strings.EqualFold(d.Package, opts.Package) {
+		return false
+	}
+	if opts.Ecosystem != "" && !strings.EqualFold(d.Ecosystem, opts.Ecosystem) {
+		return false
+	}
+	if opts.BelowVersion == "" {
+		return true
+	}
+
+	version := dependencyVersion.FindString(d.Version)
+	return version != "" && compareVersions(version, opts.BelowVersion) < 0
+}
+
+// dependencyVersion matches the first version of the version requirements of a manifest, like 1.2.0 in ">= 1.2.0, < 2".
+// It is the lowest version allowed by the requirements, the exact version for the SBOM.
+var dependencyVersion = regexp.MustCompile(`v?\d+(\.[0-9A-Za-z]+)*(-[0-9A-Za-z.-]+)?`)
+
+// compareVersions compares two versions like semantic versions, returning -1, 0 or 1.
+// The numeric parts are compared as numbers, a missing part is 0 and a pre-release is lower than its release.
+func compareVersions(a string, b string) int {
+	a, _, _ = strings.Cut(strings.TrimPrefix(a, "v"), "+")
+	b, _, _ = strings.Cut(strings.TrimPrefix(b, "v"), "+")
+	a, aPre, aHasPre := strings.Cut(a, "-")
+	b, bPre, bHasPre := strings.Cut(b, "-")
+
+	if cmp := compareVersionParts(strings.Split(a, "."), strings.Split(b, ".")); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case aHasPre && !bHasPre:
+		return -1
+	case !aHasPre && bHasPre:
+		return 1
+	}
+	return compareVersionParts(strings.Split(aPre, "."), strings.Split(bPre, "."))
+}
+
+func compareVersionParts(a []string, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		x, y := "0", "0"
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		xn, xErr := strconv.ParseInt(x, 10, 64)
+		yn, yErr := strconv.ParseInt(y, 10, 64)
+		switch {
+		case xErr == nil && yErr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case xErr == nil:
+			// numeric identifiers are lower than alphanumeric ones
+			return -1
+		case yErr == nil:
+			return 1
+		default:
+			if cmp := strings.Compare(x, y); cmp != 0 {
+				return cmp
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,276 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+type sbomMockClient struct {
+	*testutil.TestClient
+	sboms map[string]*googlegithub.SBOM
+	// errs are the errors of the repositories whose SBOM can't be read
+	errs map[string]error
+}
+
+func (m *sbomMockClient) GetSBOM(_ context.Context, owner, repo string) (*googlegithub.SBOM, *googlegithub.Response, error) {
+	if err, ok := m.errs[owner+"/"+repo]; ok {
+		return nil, &googlegithub.Response{Response: &http.Response{StatusCode: http.StatusInternalServerError}}, err
+	}
+	sbom, ok := m.sboms[owner+"/"+repo]
+	if !ok {
+		return nil, &googlegithub.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, errors.New("not found")
+	}
+	return sbom, &googlegithub.Response{}, nil
+}
+
+func testSBOM(repo string, packages ...*googlegithub.RepoDependencies) *googlegithub.SBOM {
+	self := &googlegithub.RepoDependencies{SPDXID: googlegithub.Ptr("SPDXRef-com.github." + repo), Name: googlegithub.Ptr("com.github.grafana/" + repo)}
+	return &googlegithub.SBOM{SBOM: &googlegithub.SBOMInfo{
+		DocumentDescribes: []string{self.GetSPDXID()},
+		Packages:          append([]*googlegithub.RepoDependencies{self}, packages...),
+	}}
+}
+
+// testSBOMPackage creates a package of an SBOM, GitHub prefixes its name with the package manager of the dependency graph
+func testSBOMPackage(manager string, purlType string, name string, version string, license string) *googlegithub.RepoDependencies {
+	return &googlegithub.RepoDependencies{
+		Name:             googlegithub.Ptr(manager + ":" + name),
+		VersionInfo:      googlegithub.Ptr(version),
+		LicenseConcluded: googlegithub.Ptr(license),
+		ExternalRefs: []*googlegithub.PackageExternalRef{
+			{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:" + purlType + "/" + name + "@" + version},
+		},
+	}
+}
+
+func newSBOMMockClient(t *testing.T) *sbomMockClient {
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryListRepositories)
+		require.True(t, ok)
+		for _, name := range []string{"grafana", "loki", "empty"} {
+			repo := struct {
+				Repository Repository `graphql:"... on Repository"`
+			}{}
+			repo.Repository.Name = name
+			query.Search.Nodes = append(query.Search.Nodes, repo)
+		}
+	}
+
+	return &sbomMockClient{
+		TestClient: testutil.NewTestClient(t, nil, testQuery),
+		sboms: map[string]*googlegithub.SBOM{
+			"grafana/grafana": testSBOM("grafana",
+				testSBOMPackage("npm", "npm", "lodash", "4.17.20", "MIT"),
+				testSBOMPackage("go", "golang", "github.com/pkg/errors", "0.9.1", "NOASSERTION"),
+			),
+			"grafana/loki": testSBOM("loki",
+				testSBOMPackage("npm", "npm", "lodash", "4.17.21", "MIT"),
+				testSBOMPackage("pip", "pypi", "requests", "2.31.0", "Apache-2.0"),
+			),
+		},
+	}
+}
+
+func TestGetAllDependencies(t *testing.T) {
+	t.Run("lists the dependencies of a repository from its SBOM", func(t *testing.T) {
+		client := newSBOMMockClient(t)
+		opts := models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{}, "grafana", "grafana")
+		dependencies, err := GetAllDependencies(context.Background(), client, opts)
+		require.NoError(t, err)
+		assert.Equal(t, Dependencies{
+			{Repository: "grafana/grafana", Package: "lodash", Version: "4.17.20", Ecosystem: "npm", License: "MIT"},
+			{Repository: "grafana/grafana", Package: "github.com/pkg/errors", Version: "0.9.1", Ecosystem: "go"},
+		}, dependencies)
+	})
+
+	t.Run("fails when the repository has no dependency graph", func(t *testing.T) {
+		client := newSBOMMockClient(t)
+		opts := models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{}, "grafana", "empty")
+		_, err := GetAllDependencies(context.Background(), client, opts)
+		assert.Error(t, err)
+	})
+
+	t.Run("finds the repositories of the owner using a library below a version", func(t *testing.T) {
+		client := newSBOMMockClient(t)
+		opts := models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{Package: "lodash", BelowVersion: "4.17.21"}, "grafana", "")
+		dependencies, err := GetAllDependencies(context.Background(), client, opts)
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		assert.Equal(t, "grafana/grafana", dependencies[0].Repository)
+	})
+
+	t.Run("finds the repositories of the owner using a Go module or a pip package below a version", func(t *testing.T) {
+		client := newSBOMMockClient(t)
+		opts := models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{Package: "github.com/pkg/errors", BelowVersion: "0.9.2"}, "grafana", "")
+		dependencies, err := GetAllDependencies(context.Background(), client, opts)
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		assert.Equal(t, "grafana/grafana", dependencies[0].Repository)
+
+		opts = models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{Package: "requests", BelowVersion: "2.32.0"}, "grafana", "")
+		dependencies, err = GetAllDependencies(context.Background(), client, opts)
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		assert.Equal(t, "grafana/loki", dependencies[0].Repository)
+	})
+
+	t.Run("skips the repositories of the owner whose dependencies can't be read", func(t *testing.T) {
+		client := newSBOMMockClient(t)
+		client.errs = map[string]error{"grafana/grafana": errors.New("internal error")}
+		opts := models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{Package: "lodash"}, "grafana", "")
+		dependencies, err := GetAllDependencies(context.Background(), client, opts)
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		assert.Equal(t, "grafana/loki", dependencies[0].Repository)
+
+		// a single repository still fails the query
+		opts = models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{}, "grafana", "grafana")
+		_, err = GetAllDependencies(context.Background(), client, opts)
+		assert.Error(t, err)
+	})
+
+	t.Run("filters by ecosystem", func(t *testing.T) {
+		client := newSBOMMockClient(t)
+		opts := models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{Ecosystem: "PIP"}, "grafana", "")
+		dependencies, err := GetAllDependencies(context.Background(), client, opts)
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		assert.Equal(t, "requests", dependencies[0].Package)
+	})
+}
+
+func TestGetManifestDependencies(t *testing.T) {
+	pages := 0
+	testQuery := func(t *testing.T, q interface{}) {
+		dependency := func(name string, requirements string) struct {
+			PackageName    string
+			PackageManager string
+			Requirements   string
+		} {
+			return struct {
+				PackageName    string
+				PackageManager string
+				Requirements   string
+			}{PackageName: name, PackageManager: "NPM", Requirements: requirements}
+		}
+
+		switch query := q.(type) {
+		case *QueryListDependencyManifests:
+			query.Repository.DependencyGraphManifests.Nodes = make([]struct {
+				ID           string
+				Filename     string
+				Dependencies DependencyGraphDependencies `graphql:"dependencies(first: 100)"`
+			}, 1)
+			manifest := &query.Repository.DependencyGraphManifests.Nodes[0]
+			manifest.ID = "DGM_1"
+			manifest.Filename = "package.json"
+			manifest.Dependencies.Nodes = append(manifest.Dependencies.Nodes, dependency("lodash", "^4.17.0"))
+			manifest.Dependencies.PageInfo.HasNextPage = true
+		case *QueryListManifestDependencies:
+			pages++
+			query.Node.Manifest.Dependencies.Nodes = append(query.Node.Manifest.Dependencies.Nodes, dependency("react", ">= 18.2.0, < 19"))
+		default:
+			t.Fatalf("unexpected query %T", q)
+		}
+	}
+
+	client := testutil.NewTestClient(t, nil, testQuery)
+	opts := models.DependenciesOptionsWithRepo(models.ListDependenciesOptions{Source: models.DependenciesSourceManifests, BelowVersion: "18.3"}, "grafana", "grafana")
+	dependencies, err := GetAllDependencies(context.Background(), client, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pages)
+	assert.Equal(t, Dependencies{
+		{Repository: "grafana/grafana", Manifest: "package.json", Package: "lodash", Version: "^4.17.0", Ecosystem: "npm"},
+		{Repository: "grafana/grafana", Manifest: "package.json", Package: "react", Version: ">= 18.2.0, < 19", Ecosystem: "npm"},
+	}, dependencies)
+}
+
+func TestGetManifestDependenciesPageLimit(t *testing.T) {
+	manifestPages, dependencyPages := 0, 0
+	testQuery := func(t *testing.T, q interface{}) {
+		switch query := q.(type) {
+		case *QueryListDependencyManifests:
+			manifestPages++
+			query.Repository.DependencyGraphManifests.Nodes = make([]struct {
+				ID           string
+				Filename     string
+				Dependencies DependencyGraphDependencies `graphql:"dependencies(first: 100)"`
+			}, 1)
+			query.Repository.DependencyGraphManifests.Nodes[0].Dependencies.PageInfo.HasNextPage = true
+			query.Repository.DependencyGraphManifests.PageInfo.HasNextPage = true
+		case *QueryListManifestDependencies:
+			dependencyPages++
+			query.Node.Manifest.Dependencies.PageInfo.HasNextPage = true
+		default:
+			t.Fatalf("unexpected query %T", q)
+		}
+	}
+
+	_, err := getManifestDependencies(context.Background(), testutil.NewTestClient(t, nil, testQuery), "grafana", "grafana")
+	require.NoError(t, err)
+	assert.Equal(t, DependencyPageLimit, manifestPages)
+	// the first page of dependencies comes with the manifest
+	assert.Equal(t, DependencyPageLimit*(DependencyPageLimit-1), dependencyPages)
+}
+
+func TestSBOMPackageName(t *testing.T) {
+	tests := []struct {
+		manager, purlType, name string
+		ecosystem               string
+	}{
+		{"npm", "npm", "@grafana/ui", "npm"},
+		{"go", "golang", "github.com/pkg/errors", "go"},
+		{"pip", "pypi", "requests", "pip"},
+		{"actions", "githubactions", "actions/checkout", "actions"},
+		{"rubygems", "gem", "rails", "rubygems"},
+		{"rust", "cargo", "serde", "rust"},
+		{"maven", "maven", "org.apache.commons:commons-lang3", "maven"},
+	}
+	for _, tt := range tests {
+		ecosystem, name := sbomPackageName(testSBOMPackage(tt.manager, tt.purlType, tt.name, "1.0.0", ""))
+		assert.Equal(t, tt.ecosystem, ecosystem, tt.name)
+		assert.Equal(t, tt.name, name)
+	}
+
+	// without a package URL, the prefix is the package manager
+	ecosystem, name := sbomPackageName(&googlegithub.RepoDependencies{Name: googlegithub.Ptr("go:github.com/pkg/errors")})
+	assert.Equal(t, "go", ecosystem)
+	assert.Equal(t, "github.com/pkg/errors", name)
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-beta.2", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-rc.1", "1.0.0-rc.10", -1},
+		{"1.0.0+build", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, compareVersions(tt.a, tt.b), "%s <=> %s", tt.a, tt.b)
+	}
+}
+
+func TestDependenciesDataFrame(t *testing.T) {
+	client := newSBOMMockClient(t)
+	dependencies, err := GetAllDependencies(context.Background(), client, models.ListDependenciesOptions{Owner: "grafana"})
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "dependencies", dependencies)
+}
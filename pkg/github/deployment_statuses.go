@@ -0,0 +1,246 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryListDeploymentStatuses is the GraphQL query for listing the deployments of a repository along with their status history
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    deployments(first: 100, environments: ["production"], orderBy: {field: CREATED_AT, direction: DESC}) {
+//	      nodes {
+//	        databaseId
+//	        environment
+//	        statuses(first: 100) {
+//	          nodes {
+//	            state
+//	            createdAt
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListDeploymentStatuses struct {
+	Repository struct {
+		Deployments struct {
+			Nodes    []DeploymentWithStatuses
+			PageInfo models.PageInfo
+		} `graphql:"deployments(first: 100, after: $cursor, environments: $environments, orderBy: {field: CREATED_AT, direction: DESC})"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// DeploymentStatus is a single state a deployment went through
+type DeploymentStatus struct {
+	State          githubv4.DeploymentStatusState
+	Description    string
+	EnvironmentURL string `graphql:"environmentUrl"`
+	LogURL         string `graphql:"logUrl"`
+	CreatedAt      githubv4.DateTime
+	Creator        struct {
+		Login string
+	}
+}
+
+// DeploymentWithStatuses is a deployment and the statuses that followed it
+type DeploymentWithStatuses struct {
+	DatabaseID  int64 `graphql:"databaseId"`
+	Environment string
+	Task        string
+	CommitOid   string
+	Ref         *struct {
+		Name string
+	}
+	State     githubv4.DeploymentState
+	CreatedAt githubv4.DateTime
+	Creator   struct {
+		Login string
+	}
+	Statuses struct {
+		Nodes []DeploymentStatus
+	} `graphql:"statuses(first: 100)"`
+}
+
+// refName returns the name of the deployed ref, which is empty when the ref was deleted
+func (d DeploymentWithStatuses) refName() string {
+	if d.Ref == nil {
+		return ""
+	}
+	return d.Ref.Name
+}
+
+// history returns the statuses of the deployment from the oldest to the newest
+func (d DeploymentWithStatuses) history() []DeploymentStatus {
+	statuses := make([]DeploymentStatus, len(d.Statuses.Nodes))
+	copy(statuses, d.Statuses.Nodes)
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].CreatedAt.Before(statuses[j].CreatedAt.Time)
+	})
+	return statuses
+}
+
+// timings returns when the deployment started and finished and how long it waited for an approval.
+// A deployment starts with its first in progress status and finishes with its first success, failure or error status.
+// The approval wait is the time between the first waiting status and the status that followed it.
+func (d DeploymentWithStatuses) timings() (startedAt *time.Time, finishedAt *time.Time, approvalWait *float64) {
+	statuses := d.history()
+
+	for i, s := range statuses {
+		t := s.CreatedAt.Time
+		switch s.State {
+		case githubv4.DeploymentStatusStateWaiting:
+			if approvalWait == nil && i+1 < len(statuses) {
+				wait := statuses[i+1].CreatedAt.Sub(t).Seconds()
+				approvalWait = &wait
+			}
+		case githubv4.DeploymentStatusStateInProgress:
+			if startedAt == nil {
+				startedAt = &t
+			}
+		case githubv4.DeploymentStatusStateSuccess, githubv4.DeploymentStatusStateFailure, githubv4.DeploymentStatusStateError:
+			if finishedAt == nil {
+				finishedAt = &t
+			}
+		}
+	}
+
+	return startedAt, finishedAt, approvalWait
+}
+
+// DeploymentStatuses is a list of deployments along with their status history
+type DeploymentStatuses []DeploymentWithStatuses
+
+// Frames converts the list of deployments to a frame with a row for every status, and a frame with the duration of every deployment
+func (d DeploymentStatuses) Frames() data.Frames {
+	history := data.NewFrame(
+		"deployment_statuses",
+		data.NewField("deployment_id", nil, []int64{}),
+		data.NewField("environment", nil, []string{}),
+		data.NewField("ref", nil, []string{}),
+		data.NewField("sha", nil, []string{}),
+		data.NewField("task", nil, []string{}),
+		data.NewField("state", nil, []string{}),
+		data.NewField("description", nil, []string{}),
+		data.NewField("creator", nil, []string{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("log_url", nil, []string{}),
+		data.NewField("environment_url", nil, []string{}),
+	)
+
+	durations := data.NewFrame(
+		"deployment_durations",
+		data.NewField("deployment_id", nil, []int64{}),
+		data.NewField("environment", nil, []string{}),
+		data.NewField("ref", nil, []string{}),
+		data.NewField("sha", nil, []string{}),
+		data.NewField("state", nil, []string{}),
+		data.NewField("creator", nil, []string{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("started_at", nil, []*time.Time{}),
+		data.NewField("finished_at", nil, []*time.Time{}),
+		data.NewField("deploy_duration", nil, []*float64{}),
+		data.NewField("approval_wait", nil, []*float64{}),
+	)
+
+	for _, deployment := range d {
+		for _, s := range deployment.history() {
+			history.AppendRow(
+				deployment.DatabaseID,
+				deployment.Environment,
+				deployment.refName(),
+				deployment.CommitOid,
+				deployment.Task,
+				string(s.State),
+				s.Description,
+				s.Creator.Login,
+				s.CreatedAt.Time,
+				s.LogURL,
+				s.EnvironmentURL,
+			)
+		}
+
+		startedAt, finishedAt, approvalWait := deployment.timings()
+		var duration *float64
+		if startedAt != nil && finishedAt != nil {
+			seconds := finishedAt.Sub(*startedAt).Seconds()
+			duration = &seconds
+		}
+
+		durations.AppendRow(
+			deployment.DatabaseID,
+			deployment.Environment,
+			deployment.refName(),
+			deployment.CommitOid,
+			string(deployment.State),
+			deployment.Creator.Login,
+			deployment.CreatedAt.Time,
+			startedAt,
+			finishedAt,
+			duration,
+			approvalWait,
+		)
+	}
+
+	for _, f := range []*data.Frame{history, durations} {
+		f.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	}
+
+	return data.Frames{history, durations}
+}
+
+// GetDeploymentStatusesInRange lists the deployments created in the time range along with their status history
+func GetDeploymentStatusesInRange(ctx context.Context, client models.Client, opts models.ListDeploymentStatusesOptions, from time.Time, to time.Time) (DeploymentStatuses, error) {
+	if opts.Owner == "" || opts.Repository == "" {
+		return nil, nil
+	}
+
+	var environments *[]githubv4.String
+	if opts.Environment != "" {
+		environments = &[]githubv4.String{githubv4.String(opts.Environment)}
+	}
+
+	var (
+		variables = map[string]interface{}{
+			"cursor":       (*githubv4.String)(nil),
+			"owner":        githubv4.String(opts.Owner),
+			"name":         githubv4.String(opts.Repository),
+			"environments": environments,
+		}
+
+		deployments = DeploymentStatuses{}
+	)
+
+	for {
+		q := &QueryListDeploymentStatuses{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, v := range q.Repository.Deployments.Nodes {
+			// deployments are ordered from the newest to the oldest, so every remaining one is older than the range
+			if v.CreatedAt.Before(from) {
+				return deployments, nil
+			}
+			if v.CreatedAt.After(to) {
+				continue
+			}
+			deployments = append(deployments, v)
+		}
+
+		if !q.Repository.Deployments.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.Deployments.PageInfo.EndCursor
+	}
+
+	return deployments, nil
+}
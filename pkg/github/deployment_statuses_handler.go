@@ -0,0 +1,24 @@
+package github
+
+import (
+	"context"
+
+	"github.com/grafana/github-datasource/pkg/dfutil"
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func (s *QueryHandler) handleDeploymentStatusesQuery(ctx context.Context, q backend.DataQuery) backend.DataResponse {
+	query := &models.DeploymentStatusesQuery{}
+	if err := UnmarshalQuery(q.JSON, query); err != nil {
+		return *err
+	}
+	return dfutil.FrameResponseWithError(s.Datasource.HandleDeploymentStatusesQuery(ctx, query, q))
+}
+
+// HandleDeploymentStatuses handles the plugin query for github Deployment Statuses
+func (s *QueryHandler) HandleDeploymentStatuses(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return &backend.QueryDataResponse{
+		Responses: processQueries(ctx, req, s.handleDeploymentStatusesQuery),
+	}, nil
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetDeploymentStatusesInRange(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		opts = models.ListDeploymentStatusesOptions{
+			Repository:  "grafana",
+			Owner:       "grafana",
+			Environment: "production",
+		}
+	)
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		testutil.GetTestVariablesFunction("cursor", "owner", "name", "environments")(t, variables)
+		assert.Equal(t, &[]githubv4.String{"production"}, variables["environments"])
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testutil.GetTestQueryFunction(&QueryListDeploymentStatuses{}))
+
+	_, err := GetDeploymentStatusesInRange(ctx, client, opts, time.Now().Add(-30*24*time.Hour), time.Now())
+	require.NoError(t, err)
+}
+
+func deploymentStatus(state githubv4.DeploymentStatusState, createdAt time.Time) DeploymentStatus {
+	return DeploymentStatus{
+		State:     state,
+		CreatedAt: githubv4.DateTime{Time: createdAt},
+	}
+}
+
+func TestDeploymentStatusesDataFrame(t *testing.T) {
+	createdAt, err := time.Parse(time.RFC3339, "2024-05-01T10:00:00Z")
+	require.NoError(t, err)
+
+	approved := DeploymentWithStatuses{
+		DatabaseID:  1,
+		Environment: "production",
+		Task:        "deploy",
+		CommitOid:   "abc123",
+		Ref:         &struct{ Name string }{Name: "main"},
+		State:       githubv4.DeploymentStateActive,
+		CreatedAt:   githubv4.DateTime{Time: createdAt},
+	}
+	approved.Creator.Login = "testUser"
+	// statuses are returned from the newest to the oldest
+	approved.Statuses.Nodes = []DeploymentStatus{
+		deploymentStatus(githubv4.DeploymentStatusStateSuccess, createdAt.Add(20*time.Minute)),
+		deploymentStatus(githubv4.DeploymentStatusStateInProgress, createdAt.Add(15*time.Minute)),
+		deploymentStatus(githubv4.DeploymentStatusStateQueued, createdAt.Add(10*time.Minute)),
+		deploymentStatus(githubv4.DeploymentStatusStateWaiting, createdAt),
+	}
+
+	failed := DeploymentWithStatuses{
+		DatabaseID:  2,
+		Environment: "staging",
+		CommitOid:   "def456",
+		State:       githubv4.DeploymentStateFailure,
+		CreatedAt:   githubv4.DateTime{Time: createdAt.Add(time.Hour)},
+	}
+	failed.Statuses.Nodes = []DeploymentStatus{
+		deploymentStatus(githubv4.DeploymentStatusStateFailure, createdAt.Add(time.Hour+5*time.Minute)),
+	}
+
+	startedAt, finishedAt, approvalWait := approved.timings()
+	require.NotNil(t, startedAt)
+	require.NotNil(t, finishedAt)
+	require.NotNil(t, approvalWait)
+	assert.Equal(t, 5*time.Minute, finishedAt.Sub(*startedAt))
+	assert.Equal(t, float64(600), *approvalWait)
+
+	testutil.CheckGoldenFramer(t, "deployment_statuses", DeploymentStatuses{approved, failed})
+}
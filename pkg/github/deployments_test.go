@@ -60,6 +60,46 @@ func (m *mockDeploymentsClient) ListPullRequestFiles(_ context.Context, _, _ str
 	return nil, nil, nil
 }
 
+func (m *mockDeploymentsClient) CompareCommits(_ context.Context, _, _, _, _ string, _ *googlegithub.ListOptions) (*googlegithub.CommitsComparison, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockDeploymentsClient) GetTree(_ context.Context, _, _, _ string, _ bool) (*googlegithub.Tree, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockDeploymentsClient) ListPackagesForOrg(_ context.Context, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockDeploymentsClient) ListPackagesForUser(_ context.Context, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockDeploymentsClient) ListPackageVersionsForOrg(_ context.Context, _, _, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockDeploymentsClient) ListPackageVersionsForUser(_ context.Context, _, _, _ string, _ *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockDeploymentsClient) GetContainerManifest(_ context.Context, _, _, _ string) (*models.ContainerManifest, error) {
+	return nil, nil
+}
+
+func (m *mockDeploymentsClient) GetSBOM(_ context.Context, _, _ string) (*googlegithub.SBOM, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockDeploymentsClient) GetRepository(_ context.Context, _, _ string) (*googlegithub.Repository, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
+func (m *mockDeploymentsClient) GetCodeScanningDefaultSetup(_ context.Context, _, _ string) (*googlegithub.DefaultSetupConfiguration, *googlegithub.Response, error) {
+	return nil, nil, nil
+}
+
 func TestGetAllDeployments(t *testing.T) {
 	var (
 		ctx  = context.Background()
@@ -0,0 +1,232 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// Discussion represents a GitHub discussion in a repository
+type Discussion struct {
+	Number    int64
+	Title     string
+	URL       string
+	CreatedAt githubv4.DateTime
+	UpdatedAt githubv4.DateTime
+	ClosedAt  githubv4.DateTime
+	Closed    bool
+	Locked    bool
+	Author    *struct {
+		Login string
+	}
+	Category struct {
+		Name         string
+		IsAnswerable bool
+	}
+	IsAnswered     bool
+	AnswerChosenAt githubv4.DateTime
+	Answer         *struct {
+		CreatedAt githubv4.DateTime
+		Author    *struct {
+			Login string
+		}
+	}
+	UpvoteCount int64
+	Comments    struct {
+		TotalCount int64
+		Nodes      []struct {
+			CreatedAt githubv4.DateTime
+		}
+	} `graphql:"comments(first: 1)"`
+	Repository struct {
+		NameWithOwner string
+	}
+}
+
+// Discussions is a slice of GitHub discussions
+type Discussions []Discussion
+
+// Frames converts the list of discussions to a Grafana DataFrame
+func (d Discussions) Frames() data.Frames {
+	frame := data.NewFrame(
+		"discussions",
+		data.NewField("title", nil, []string{}),
+		data.NewField("author", nil, []string{}),
+		data.NewField("repo", nil, []string{}),
+		data.NewField("number", nil, []int64{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("category", nil, []string{}),
+		data.NewField("closed", nil, []bool{}),
+		data.NewField("locked", nil, []bool{}),
+		data.NewField("answered", nil, []bool{}),
+		data.NewField("answered_by", nil, []string{}),
+		data.NewField("upvotes", nil, []int64{}),
+		data.NewField("comments", nil, []int64{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("updated_at", nil, []time.Time{}),
+		data.NewField("closed_at", nil, []*time.Time{}),
+		data.NewField("answer_chosen_at", nil, []*time.Time{}),
+		data.NewField("time_to_first_comment", nil, []*float64{}),
+		data.NewField("time_to_first_answer", nil, []*float64{}),
+	)
+
+	for _, v := range d {
+		var author, answeredBy string
+		if v.Author != nil {
+			author = v.Author.Login
+		}
+
+		var timeToFirstComment *float64
+		if len(v.Comments.Nodes) > 0 {
+			timeToFirstComment = durationSeconds(v.CreatedAt.Time, v.Comments.Nodes[0].CreatedAt.Time)
+		}
+
+		var timeToFirstAnswer *float64
+		if v.Answer != nil {
+			if v.Answer.Author != nil {
+				answeredBy = v.Answer.Author.Login
+			}
+			timeToFirstAnswer = durationSeconds(v.CreatedAt.Time, v.Answer.CreatedAt.Time)
+		}
+
+		frame.AppendRow(
+			v.Title,
+			author,
+			v.Repository.NameWithOwner,
+			v.Number,
+			v.URL,
+			v.Category.Name,
+			v.Closed,
+			v.Locked,
+			v.IsAnswered,
+			answeredBy,
+			v.UpvoteCount,
+			v.Comments.TotalCount,
+			v.CreatedAt.Time,
+			v.UpdatedAt.Time,
+			nullableTime(v.ClosedAt.Time),
+			nullableTime(v.AnswerChosenAt.Time),
+			timeToFirstComment,
+			timeToFirstAnswer,
+		)
+	}
+
+	return data.Frames{frame}
+}
+
+// QuerySearchDiscussions is the object representation of the graphql query for retrieving a paginated list of discussions using the search query
+//
+//	{
+//	  search(query: "repo:grafana/grafana created:2020-08-19..*", type: DISCUSSION, first: 100) {
+//	    nodes {
+//	      ... on Discussion {
+//	        number
+//	        title
+//	      }
+//	  }
+//	}
+type QuerySearchDiscussions struct {
+	Search struct {
+		DiscussionCount int64
+		Nodes           []struct {
+			Discussion Discussion `graphql:"... on Discussion"`
+		}
+		PageInfo models.PageInfo
+	} `graphql:"search(query: $query, type: DISCUSSION, first: 100, after: $cursor)"`
+}
+
+// GetDiscussionsInRange lists discussions in a repository, or in every repository of the owner, given a time range.
+// The search API has no qualifier for the time an answer was chosen, so answered discussions updated since the start of the range are searched and filtered afterwards.
+func GetDiscussionsInRange(ctx context.Context, client models.Client, opts models.ListDiscussionsOptions, from time.Time, to time.Time) (Discussions, error) {
+	filter := fmt.Sprintf("repo:%s/%s", opts.Owner, opts.Repository)
+	if opts.Repository == "" {
+		filter = fmt.Sprintf("owner:%s", opts.Owner)
+	}
+
+	var queryString string
+	if opts.Query != nil {
+		var err error
+		queryString, err = InterPolateMacros(*opts.Query)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	timeField, searchTo := opts.TimeField.String(), to
+	if opts.TimeField == models.DiscussionAnsweredAt {
+		// a discussion is updated when its answer is chosen, so it was updated between the start of the range and now
+		timeField, searchTo = models.DiscussionUpdatedAt.String(), time.Now()
+		filter = strings.Join([]string{filter, "is:answered"}, " ")
+	}
+
+	discussions, err := searchTimeRange(ctx, from, searchTo, func(ctx context.Context, from time.Time, to time.Time, limited bool) ([]Discussion, bool, error) {
+		search := []string{
+			filter,
+			fmt.Sprintf("%s:%s..%s", timeField, from.Format(time.RFC3339), to.Format(time.RFC3339)),
+		}
+		if queryString != "" {
+			search = append(search, queryString)
+		}
+		return searchDiscussions(ctx, client, strings.Join(search, " "), limited)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	discussions = dedupeResults(discussions, func(d Discussion) string {
+		return fmt.Sprintf("%s#%d", d.Repository.NameWithOwner, d.Number)
+	})
+
+	if opts.TimeField != models.DiscussionAnsweredAt {
+		return discussions, nil
+	}
+
+	answered := Discussions{}
+	for _, d := range discussions {
+		if t := d.AnswerChosenAt.Time; !t.Before(from) && !t.After(to) {
+			answered = append(answered, d)
+		}
+	}
+	return answered, nil
+}
+
+// searchDiscussions returns every discussion matching the search query.
+// When limited is true and the search matches more than SearchResultLimit discussions, it stops after the first page and reports the search as truncated.
+func searchDiscussions(ctx context.Context, client models.Client, query string, limited bool) ([]Discussion, bool, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"query":  githubv4.String(query),
+		}
+
+		discussions = []Discussion{}
+	)
+
+	for {
+		q := &QuerySearchDiscussions{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, false, errors.WithStack(err)
+		}
+		if limited && q.Search.DiscussionCount > SearchResultLimit {
+			return nil, true, nil
+		}
+
+		for _, v := range q.Search.Nodes {
+			discussions = append(discussions, v.Discussion)
+		}
+
+		if !q.Search.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Search.PageInfo.EndCursor
+	}
+
+	return discussions, false, nil
+}
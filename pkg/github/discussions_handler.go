@@ -0,0 +1,24 @@
+package github
+
+import (
+	"context"
+
+	"github.com/grafana/github-datasource/pkg/dfutil"
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func (s *QueryHandler) handleDiscussionsQuery(ctx context.Context, q backend.DataQuery) backend.DataResponse {
+	query := &models.DiscussionsQuery{}
+	if err := UnmarshalQuery(q.JSON, query); err != nil {
+		return *err
+	}
+	return dfutil.FrameResponseWithError(s.Datasource.HandleDiscussionsQuery(ctx, query, q))
+}
+
+// HandleDiscussions handles the plugin query for github Discussions
+func (s *QueryHandler) HandleDiscussions(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return &backend.QueryDataResponse{
+		Responses: processQueries(ctx, req, s.handleDiscussionsQuery),
+	}, nil
+}
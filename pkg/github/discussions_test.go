@@ -0,0 +1,117 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestSearchDiscussions(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		opts = models.ListDiscussionsOptions{
+			Repository: "grafana",
+			Owner:      "grafana",
+			TimeField:  models.DiscussionCreatedAt,
+		}
+	)
+
+	testVariables := testutil.GetTestVariablesFunction("query", "cursor")
+
+	client := testutil.NewTestClient(t,
+		testVariables,
+		testutil.GetTestQueryFunction(&QuerySearchDiscussions{}),
+	)
+
+	_, err := GetDiscussionsInRange(ctx, client, opts, time.Now().Add(-30*24*time.Hour), time.Now())
+	require.NoError(t, err)
+}
+
+func TestSearchAnsweredDiscussions(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		from = time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+		to   = time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC)
+		opts = models.ListDiscussionsOptions{
+			Repository: "grafana",
+			Owner:      "grafana",
+			TimeField:  models.DiscussionAnsweredAt,
+		}
+	)
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		query := string(variables["query"].(githubv4.String))
+		assert.Contains(t, query, "repo:grafana/grafana is:answered updated:2024-05-01T00:00:00Z..")
+	}
+
+	// one discussion answered in the range, and one answered after it
+	testQuery := func(t *testing.T, q interface{}) {
+		query := q.(*QuerySearchDiscussions)
+		query.Search.Nodes = make([]struct {
+			Discussion Discussion `graphql:"... on Discussion"`
+		}, 2)
+		query.Search.Nodes[0].Discussion = Discussion{Number: 1, AnswerChosenAt: githubv4.DateTime{Time: from.Add(24 * time.Hour)}}
+		query.Search.Nodes[1].Discussion = Discussion{Number: 2, AnswerChosenAt: githubv4.DateTime{Time: to.Add(24 * time.Hour)}}
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+
+	discussions, err := GetDiscussionsInRange(ctx, client, opts, from, to)
+	require.NoError(t, err)
+	require.Len(t, discussions, 1)
+	assert.Equal(t, int64(1), discussions[0].Number)
+}
+
+func TestDiscussionsDataFrame(t *testing.T) {
+	createdAt, err := time.Parse(time.RFC3339, "2024-05-01T10:00:00Z")
+	require.NoError(t, err)
+
+	answered := Discussion{
+		Number:         1,
+		Title:          "How do I configure the datasource?",
+		URL:            "https://github.com/grafana/grafana/discussions/1",
+		CreatedAt:      githubv4.DateTime{Time: createdAt},
+		UpdatedAt:      githubv4.DateTime{Time: createdAt.Add(3 * time.Hour)},
+		Author:         &struct{ Login string }{Login: "testUser"},
+		IsAnswered:     true,
+		AnswerChosenAt: githubv4.DateTime{Time: createdAt.Add(3 * time.Hour)},
+		Answer: &struct {
+			CreatedAt githubv4.DateTime
+			Author    *struct {
+				Login string
+			}
+		}{
+			CreatedAt: githubv4.DateTime{Time: createdAt.Add(2 * time.Hour)},
+			Author:    &struct{ Login string }{Login: "testUser2"},
+		},
+		UpvoteCount: 5,
+	}
+	answered.Category.Name = "Q&A"
+	answered.Category.IsAnswerable = true
+	answered.Repository.NameWithOwner = "grafana/grafana"
+	answered.Comments.TotalCount = 3
+	answered.Comments.Nodes = []struct {
+		CreatedAt githubv4.DateTime
+	}{{CreatedAt: githubv4.DateTime{Time: createdAt.Add(30 * time.Minute)}}}
+
+	idea := Discussion{
+		Number:    2,
+		Title:     "Support for dark mode",
+		URL:       "https://github.com/grafana/grafana/discussions/2",
+		CreatedAt: githubv4.DateTime{Time: createdAt.Add(time.Hour)},
+		UpdatedAt: githubv4.DateTime{Time: createdAt.Add(time.Hour)},
+		Closed:    true,
+		ClosedAt:  githubv4.DateTime{Time: createdAt.Add(48 * time.Hour)},
+	}
+	idea.Category.Name = "Ideas"
+	idea.Repository.NameWithOwner = "grafana/grafana"
+
+	testutil.CheckGoldenFramer(t, "discussions", Discussions{answered, idea})
+}
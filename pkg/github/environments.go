@@ -0,0 +1,221 @@
+package github
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryListEnvironments is the GraphQL query for listing the deployment environments of a repository and their protection rules
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    environments(first: 100) {
+//	      nodes {
+//	        name
+//	        protectionRules(first: 100) {
+//	          nodes {
+//	            type
+//	            timeout
+//	            reviewers(first: 100) {
+//	              nodes {
+//	                ... on User {
+//	                  login
+//	                }
+//	                ... on Team {
+//	                  combinedSlug
+//	                }
+//	              }
+//	            }
+//	          }
+//	        }
+//	        latestCompletedDeployment {
+//	          commitOid
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListEnvironments struct {
+	Repository struct {
+		Environments struct {
+			Nodes    []Environment
+			PageInfo models.PageInfo
+		} `graphql:"environments(first: 100, after: $cursor)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// DeploymentReviewer is a user or a team that can approve deployments to an environment
+type DeploymentReviewer struct {
+	Typename string `graphql:"__typename"`
+	User     struct {
+		Login string
+	} `graphql:"... on User"`
+	Team struct {
+		CombinedSlug string
+	} `graphql:"... on Team"`
+}
+
+// String returns the login of the user or the slug of the team, with the same prefixes as the bypass actors of a branch
+func (r DeploymentReviewer) String() string {
+	switch r.Typename {
+	case "User":
+		return "user:" + r.User.Login
+	case "Team":
+		return "team:" + r.Team.CombinedSlug
+	}
+	return strings.ToLower(r.Typename)
+}
+
+// DeploymentProtectionRule is a rule that has to pass before a deployment to an environment can proceed
+type DeploymentProtectionRule struct {
+	Type githubv4.DeploymentProtectionRuleType
+	// Timeout is the wait timer of the rule, in minutes
+	Timeout           int64
+	PreventSelfReview bool
+	Reviewers         struct {
+		Nodes []DeploymentReviewer
+	} `graphql:"reviewers(first: 100)"`
+}
+
+// Environment is a deployment environment of a repository
+type Environment struct {
+	Name            string
+	ProtectionRules struct {
+		Nodes []DeploymentProtectionRule
+	} `graphql:"protectionRules(first: 100)"`
+	LatestCompletedDeployment *struct {
+		CommitOid string
+		Ref       *struct {
+			Name string
+		}
+		State     githubv4.DeploymentState
+		CreatedAt githubv4.DateTime
+	}
+}
+
+// Environments is a list of deployment environments
+type Environments []Environment
+
+// Frames converts the list of environments to a frame with the current version deployed to every environment, and a frame with their protection rules
+func (e Environments) Frames() data.Frames {
+	environments := data.NewFrame(
+		"environments",
+		data.NewField("name", nil, []string{}),
+		data.NewField("protection_rules", nil, []int64{}),
+		data.NewField("wait_timer", nil, []int64{}),
+		data.NewField("required_reviewers", nil, []string{}),
+		data.NewField("current_ref", nil, []string{}),
+		data.NewField("current_sha", nil, []string{}),
+		data.NewField("current_state", nil, []string{}),
+		data.NewField("deployed_at", nil, []*time.Time{}),
+	)
+
+	rules := data.NewFrame(
+		"environment_protection_rules",
+		data.NewField("environment", nil, []string{}),
+		data.NewField("type", nil, []string{}),
+		data.NewField("wait_timer", nil, []int64{}),
+		data.NewField("prevent_self_review", nil, []bool{}),
+		data.NewField("reviewers", nil, []string{}),
+	)
+
+	for _, env := range e {
+		var (
+			waitTimer int64
+			required  = []string{}
+		)
+
+		for _, rule := range env.ProtectionRules.Nodes {
+			reviewers := make([]string, len(rule.Reviewers.Nodes))
+			for i, r := range rule.Reviewers.Nodes {
+				reviewers[i] = r.String()
+			}
+
+			switch rule.Type {
+			case githubv4.DeploymentProtectionRuleTypeWaitTimer:
+				waitTimer = rule.Timeout
+			case githubv4.DeploymentProtectionRuleTypeRequiredReviewers:
+				required = append(required, reviewers...)
+			}
+
+			rules.AppendRow(
+				env.Name,
+				string(rule.Type),
+				rule.Timeout,
+				rule.PreventSelfReview,
+				strings.Join(reviewers, ","),
+			)
+		}
+
+		var (
+			ref, sha, state string
+			deployedAt      *time.Time
+		)
+		if d := env.LatestCompletedDeployment; d != nil {
+			sha = d.CommitOid
+			state = string(d.State)
+			if d.Ref != nil {
+				ref = d.Ref.Name
+			}
+			t := d.CreatedAt.Time
+			deployedAt = &t
+		}
+
+		environments.AppendRow(
+			env.Name,
+			int64(len(env.ProtectionRules.Nodes)),
+			waitTimer,
+			strings.Join(required, ","),
+			ref,
+			sha,
+			state,
+			deployedAt,
+		)
+	}
+
+	for _, f := range []*data.Frame{environments, rules} {
+		f.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	}
+
+	return data.Frames{environments, rules}
+}
+
+// GetAllEnvironments lists the deployment environments of a repository
+func GetAllEnvironments(ctx context.Context, client models.Client, opts models.ListEnvironmentsOptions) (Environments, error) {
+	if opts.Owner == "" || opts.Repository == "" {
+		return nil, nil
+	}
+
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"owner":  githubv4.String(opts.Owner),
+			"name":   githubv4.String(opts.Repository),
+		}
+
+		environments = Environments{}
+	)
+
+	for {
+		q := &QueryListEnvironments{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		environments = append(environments, q.Repository.Environments.Nodes...)
+
+		if !q.Repository.Environments.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.Environments.PageInfo.EndCursor
+	}
+
+	return environments, nil
+}
@@ -0,0 +1,24 @@
+package github
+
+import (
+	"context"
+
+	"github.com/grafana/github-datasource/pkg/dfutil"
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func (s *QueryHandler) handleEnvironmentsQuery(ctx context.Context, q backend.DataQuery) backend.DataResponse {
+	query := &models.EnvironmentsQuery{}
+	if err := UnmarshalQuery(q.JSON, query); err != nil {
+		return *err
+	}
+	return dfutil.FrameResponseWithError(s.Datasource.HandleEnvironmentsQuery(ctx, query, q))
+}
+
+// HandleEnvironments handles the plugin query for github Environments
+func (s *QueryHandler) HandleEnvironments(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return &backend.QueryDataResponse{
+		Responses: processQueries(ctx, req, s.handleEnvironmentsQuery),
+	}, nil
+}
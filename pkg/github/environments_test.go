@@ -0,0 +1,66 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetAllEnvironments(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		opts = models.ListEnvironmentsOptions{
+			Repository: "grafana",
+			Owner:      "grafana",
+		}
+	)
+
+	testVariables := testutil.GetTestVariablesFunction("cursor", "owner", "name")
+	client := testutil.NewTestClient(t, testVariables, testutil.GetTestQueryFunction(&QueryListEnvironments{}))
+
+	_, err := GetAllEnvironments(ctx, client, opts)
+	require.NoError(t, err)
+}
+
+func TestEnvironmentsDataFrame(t *testing.T) {
+	deployedAt, err := time.Parse(time.RFC3339, "2024-05-01T10:20:00Z")
+	require.NoError(t, err)
+
+	production := Environment{Name: "production"}
+
+	reviewers := DeploymentProtectionRule{
+		Type:              githubv4.DeploymentProtectionRuleTypeRequiredReviewers,
+		PreventSelfReview: true,
+	}
+	user := DeploymentReviewer{Typename: "User"}
+	user.User.Login = "testUser"
+	team := DeploymentReviewer{Typename: "Team"}
+	team.Team.CombinedSlug = "grafana/release"
+	reviewers.Reviewers.Nodes = []DeploymentReviewer{user, team}
+
+	production.ProtectionRules.Nodes = []DeploymentProtectionRule{
+		reviewers,
+		{Type: githubv4.DeploymentProtectionRuleTypeWaitTimer, Timeout: 30},
+	}
+	production.LatestCompletedDeployment = &struct {
+		CommitOid string
+		Ref       *struct {
+			Name string
+		}
+		State     githubv4.DeploymentState
+		CreatedAt githubv4.DateTime
+	}{
+		CommitOid: "abc123",
+		Ref:       &struct{ Name string }{Name: "main"},
+		State:     githubv4.DeploymentStateActive,
+		CreatedAt: githubv4.DateTime{Time: deployedAt},
+	}
+
+	testutil.CheckGoldenFramer(t, "environments", Environments{production, {Name: "staging"}})
+}
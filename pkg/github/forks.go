@@ -0,0 +1,302 @@
+package github
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryForks is the GraphQL query for retrieving the forks of a repository from the oldest to the newest
+//
+//	{
+//	  repository(owner: "grafana", name: "grafana") {
+//	    forks(first: 100, orderBy: {field: CREATED_AT, direction: ASC}) {
+//	      totalCount
+//	      nodes {
+//	        id
+//	        name
+//	        owner {
+//	          login
+//	        }
+//	        nameWithOwner
+//	        url
+//	        createdAt
+//	      }
+//	      pageInfo {
+//	        hasNextPage
+//	        endCursor
+//	      }
+//	    }
+//	  }
+//	}
+type QueryForks struct {
+	Repository struct {
+		Forks struct {
+			TotalCount int64
+			Nodes      []Fork
+			PageInfo   models.PageInfo
+		} `graphql:"forks(first: 100, orderBy: {field: CREATED_AT, direction: ASC}, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// QueryRecentForks is the GraphQL query for retrieving the forks of a repository from the newest to the oldest, it selects the same fields as QueryForks
+type QueryRecentForks struct {
+	Repository struct {
+		Forks struct {
+			TotalCount int64
+			Nodes      []Fork
+			PageInfo   models.PageInfo
+		} `graphql:"forks(first: 100, orderBy: {field: CREATED_AT, direction: DESC}, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// QueryActiveForks is the GraphQL query for retrieving the forks of a repository from the last pushed to
+//
+//	{
+//	  repository(owner: "grafana", name: "grafana") {
+//	    forks(first: 100, orderBy: {field: PUSHED_AT, direction: DESC}) {
+//	      nodes {
+//	        nameWithOwner
+//	        createdAt
+//	        pushedAt
+//	        stargazerCount
+//	        forkCount
+//	      }
+//	    }
+//	  }
+//	}
+type QueryActiveForks struct {
+	Repository struct {
+		Forks struct {
+			Nodes    []ActiveFork
+			PageInfo models.PageInfo
+		} `graphql:"forks(first: 100, orderBy: {field: PUSHED_AT, direction: DESC}, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// Fork is a fork of a GitHub repository
+type Fork struct {
+	ID    string
+	Name  string
+	Owner struct {
+		Login string
+	}
+	NameWithOwner string
+	URL           string
+	CreatedAt     githubv4.DateTime
+}
+
+// ForkWrapper is a fork with the number of forks of the repository when it was created
+type ForkWrapper struct {
+	Fork
+	ForkCount int64
+}
+
+// ForksWrapper is the fork growth of a repository, from the oldest fork to the newest
+type ForksWrapper []ForkWrapper
+
+// Frames converts the fork growth to a Grafana DataFrame
+func (f ForksWrapper) Frames() data.Frames {
+	frame := data.NewFrame(
+		"forks",
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("fork_count", nil, []int64{}),
+		data.NewField("id", nil, []string{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("owner", nil, []string{}),
+		data.NewField("name_with_owner", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+	)
+
+	for _, v := range f {
+		frame.AppendRow(
+			v.CreatedAt.Time,
+			v.ForkCount,
+			v.ID,
+			v.Name,
+			v.Owner.Login,
+			v.NameWithOwner,
+			v.URL,
+		)
+	}
+
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeGraph}
+	return data.Frames{frame}
+}
+
+// GetForks gets the forks of a GitHub repository created during the time range, with the number of forks when each one was created.
+// The forks are read from the history of the repository, which only fetches the forks created since the previous query.
+// Until the history is backfilled, the forks are listed from the newest until the start of the time range instead.
+// They are listed the same way when the history can't be updated.
+func GetForks(ctx context.Context, client models.Client, history *repositoryHistory[Fork], opts models.ListForksOptions, timeRange backend.TimeRange) (ForksWrapper, error) {
+	err := history.update(ctx, func(ctx context.Context, cursor *githubv4.String) (historyPage[Fork], error) {
+		q := &QueryForks{}
+		if err := client.Query(ctx, q, map[string]interface{}{
+			"cursor": cursor,
+			"owner":  githubv4.String(opts.Owner),
+			"name":   githubv4.String(opts.Repository),
+		}); err != nil {
+			return historyPage[Fork]{}, errors.WithStack(err)
+		}
+		forks := q.Repository.Forks
+		return historyPage[Fork]{Items: forks.Nodes, PageInfo: forks.PageInfo, TotalCount: forks.TotalCount}, nil
+	})
+	if err != nil {
+		backend.Logger.Warn("could not update the history of the forks, listing them from the newest", "owner", opts.Owner, "repository", opts.Repository, "error", err)
+		return getForksInRange(ctx, client, opts, timeRange)
+	}
+
+	all, total, upToDate := history.snapshot()
+	if !upToDate {
+		return getForksInRange(ctx, client, opts, timeRange)
+	}
+
+	// the forks are counted back from the total count since the deleted forks are still in the history
+	forks := ForksWrapper{}
+	for i, fork := range all {
+		createdAt := fork.CreatedAt.Time
+		if createdAt.After(timeRange.To) {
+			break
+		}
+		if !createdAt.Before(timeRange.From) {
+			forks = append(forks, ForkWrapper{Fork: fork, ForkCount: total - int64(len(all)-1-i)})
+		}
+	}
+
+	return forks, nil
+}
+
+// getForksInRange lists the forks of a GitHub repository from the newest until the start of the time range, and returns them from the oldest
+func getForksInRange(ctx context.Context, client models.Client, opts models.ListForksOptions, timeRange backend.TimeRange) (ForksWrapper, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"owner":  githubv4.String(opts.Owner),
+			"name":   githubv4.String(opts.Repository),
+		}
+
+		forks = ForksWrapper{}
+
+		// forkCount is the number of forks when the next fork was created, counted back from the total count of the first page
+		forkCount int64
+	)
+
+	for page := 0; ; page++ {
+		q := &QueryRecentForks{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if page == 0 {
+			forkCount = q.Repository.Forks.TotalCount
+		}
+
+		done := false
+		for _, fork := range q.Repository.Forks.Nodes {
+			if fork.CreatedAt.Before(timeRange.From) {
+				done = true
+				break
+			}
+			if !fork.CreatedAt.After(timeRange.To) {
+				forks = append(forks, ForkWrapper{Fork: fork, ForkCount: forkCount})
+			}
+			forkCount--
+		}
+
+		if done || !q.Repository.Forks.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.Forks.PageInfo.EndCursor
+	}
+
+	slices.Reverse(forks)
+	return forks, nil
+}
+
+// ActiveFork is a fork with its last push
+type ActiveFork struct {
+	Fork
+	PushedAt       *githubv4.DateTime
+	StargazerCount int64
+	ForkCount      int64
+}
+
+// ActiveForks is the list of the active forks of a repository, from the last pushed to
+type ActiveForks []ActiveFork
+
+// Frames converts the list of active forks to a Grafana DataFrame
+func (f ActiveForks) Frames() data.Frames {
+	frame := data.NewFrame(
+		"active_forks",
+		data.NewField("name", nil, []string{}),
+		data.NewField("owner", nil, []string{}),
+		data.NewField("name_with_owner", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("pushed_at", nil, []time.Time{}),
+		data.NewField("stargazer_count", nil, []int64{}),
+		data.NewField("fork_count", nil, []int64{}),
+	)
+
+	for _, v := range f {
+		frame.AppendRow(
+			v.Name,
+			v.Owner.Login,
+			v.NameWithOwner,
+			v.URL,
+			v.CreatedAt.Time,
+			v.PushedAt.Time,
+			v.StargazerCount,
+			v.ForkCount,
+		)
+	}
+
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{frame}
+}
+
+// GetActiveForks lists the forks of a GitHub repository which were pushed to during the active window.
+// A fork starts with the last push of its parent, so only the forks pushed to after they were created are active.
+// The forks are listed from the last pushed to, and the listing stops at the first fork pushed to before the active window or after PageNumberLimit pages.
+func GetActiveForks(ctx context.Context, client models.Client, opts models.ListForksOptions, now time.Time) (ActiveForks, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"owner":  githubv4.String(opts.Owner),
+			"name":   githubv4.String(opts.Repository),
+		}
+
+		since = staleSince(opts.ActiveDays, now)
+		forks = ActiveForks{}
+	)
+
+	for i := 0; i < PageNumberLimit; i++ {
+		q := &QueryActiveForks{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, fork := range q.Repository.Forks.Nodes {
+			if fork.PushedAt == nil || fork.PushedAt.Before(since) {
+				return forks, nil
+			}
+			if fork.PushedAt.After(fork.CreatedAt.Time) {
+				forks = append(forks, fork)
+			}
+		}
+
+		if !q.Repository.Forks.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.Forks.PageInfo.EndCursor
+	}
+
+	return forks, nil
+}
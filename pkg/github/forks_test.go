@@ -0,0 +1,172 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+var forksTestStart = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testFork(owner string, createdAt time.Time) Fork {
+	fork := Fork{
+		ID:            "R_" + owner,
+		Name:          "grafana",
+		NameWithOwner: owner + "/grafana",
+		URL:           "https://github.com/" + owner + "/grafana",
+		CreatedAt:     githubv4.DateTime{Time: createdAt},
+	}
+	fork.Owner.Login = owner
+	return fork
+}
+
+// testForksClient lists three forks, the forks before the total count were deleted
+func testForksClient(t *testing.T, totalCount int64) *testutil.TestClient {
+	return testutil.NewTestClient(t, testutil.GetTestVariablesFunction("name", "owner", "cursor"), func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryForks)
+		require.True(t, ok)
+		for i := 1; i <= 3; i++ {
+			query.Repository.Forks.Nodes = append(query.Repository.Forks.Nodes, testFork(fmt.Sprintf("user%d", i), forksTestStart.AddDate(0, 0, i)))
+		}
+		query.Repository.Forks.PageInfo.EndCursor = "3"
+		query.Repository.Forks.TotalCount = totalCount
+	})
+}
+
+func TestGetForks(t *testing.T) {
+	client := testForksClient(t, 2)
+	opts := models.ListForksOptions{Owner: "grafana", Repository: "grafana"}
+	timeRange := backend.TimeRange{From: forksTestStart.AddDate(0, 0, 2), To: forksTestStart.AddDate(0, 0, 10)}
+
+	forks, err := GetForks(context.Background(), client, &repositoryHistory[Fork]{}, opts, timeRange)
+	require.NoError(t, err)
+	require.Len(t, forks, 2)
+	assert.Equal(t, "user2", forks[0].Owner.Login)
+	assert.Equal(t, int64(1), forks[0].ForkCount)
+	assert.Equal(t, int64(2), forks[1].ForkCount)
+}
+
+func TestGetForksDuringBackfill(t *testing.T) {
+	client := testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		switch query := q.(type) {
+		case *QueryForks:
+			// the history has more pages than a query backfills
+			query.Repository.Forks.Nodes = []Fork{testFork("user0", forksTestStart)}
+			query.Repository.Forks.PageInfo = models.PageInfo{HasNextPage: true, EndCursor: "0"}
+		case *QueryRecentForks:
+			query.Repository.Forks.TotalCount = 500
+			for i := 3; i >= 1; i-- {
+				query.Repository.Forks.Nodes = append(query.Repository.Forks.Nodes, testFork(fmt.Sprintf("user%d", i), forksTestStart.AddDate(0, 0, i)))
+			}
+			query.Repository.Forks.PageInfo.HasNextPage = true
+		default:
+			t.Fatalf("unexpected query %T", q)
+		}
+	})
+	opts := models.ListForksOptions{Owner: "grafana", Repository: "grafana"}
+	timeRange := backend.TimeRange{From: forksTestStart.AddDate(0, 0, 2), To: forksTestStart.AddDate(0, 0, 10)}
+
+	// the forks in the time range are listed from the newest until the history is backfilled
+	forks, err := GetForks(context.Background(), client, &repositoryHistory[Fork]{}, opts, timeRange)
+	require.NoError(t, err)
+	require.Len(t, forks, 2)
+	assert.Equal(t, "user2", forks[0].Owner.Login)
+	assert.Equal(t, int64(499), forks[0].ForkCount)
+	assert.Equal(t, "user3", forks[1].Owner.Login)
+	assert.Equal(t, int64(500), forks[1].ForkCount)
+}
+
+func TestGetForksHistoryError(t *testing.T) {
+	client := &failingHistoryClient{TestClient: testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryRecentForks)
+		require.True(t, ok)
+		query.Repository.Forks.TotalCount = 10
+		query.Repository.Forks.Nodes = []Fork{testFork("user1", forksTestStart.AddDate(0, 0, 1))}
+	})}
+	opts := models.ListForksOptions{Owner: "grafana", Repository: "grafana"}
+	timeRange := backend.TimeRange{From: forksTestStart, To: forksTestStart.AddDate(0, 0, 10)}
+
+	// the forks are listed from the newest when the history can't be updated
+	forks, err := GetForks(context.Background(), client, &repositoryHistory[Fork]{}, opts, timeRange)
+	require.NoError(t, err)
+	require.Len(t, forks, 1)
+	assert.Equal(t, "user1", forks[0].Owner.Login)
+	assert.Equal(t, int64(10), forks[0].ForkCount)
+}
+
+func TestForksDataFrame(t *testing.T) {
+	client := testForksClient(t, 3)
+	opts := models.ListForksOptions{Owner: "grafana", Repository: "grafana"}
+	timeRange := backend.TimeRange{From: forksTestStart, To: forksTestStart.AddDate(0, 0, 10)}
+
+	forks, err := GetForks(context.Background(), client, &repositoryHistory[Fork]{}, opts, timeRange)
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "forks", forks)
+}
+
+func testActiveForksClient(t *testing.T) *testutil.TestClient {
+	return testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryActiveForks)
+		require.True(t, ok)
+
+		fork := func(owner string, createdAt time.Time, pushedAt time.Time) ActiveFork {
+			return ActiveFork{Fork: testFork(owner, createdAt), PushedAt: &githubv4.DateTime{Time: pushedAt}, StargazerCount: 2, ForkCount: 1}
+		}
+		query.Repository.Forks.Nodes = []ActiveFork{
+			fork("pushed", forksTestStart, forksTestStart.AddDate(0, 0, 80)),
+			// forked recently, but never pushed to since
+			fork("forked", forksTestStart.AddDate(0, 0, 70), forksTestStart.AddDate(0, 0, 60)),
+			fork("stale", forksTestStart, forksTestStart.AddDate(0, 0, 5)),
+		}
+		query.Repository.Forks.PageInfo.HasNextPage = true
+	})
+}
+
+func TestGetActiveForks(t *testing.T) {
+	client := testActiveForksClient(t)
+	opts := models.ListForksOptions{Owner: "grafana", Repository: "grafana", Mode: models.ForksModeActive, ActiveDays: 30}
+
+	// the listing stops at the stale fork, without fetching the next page
+	forks, err := GetActiveForks(context.Background(), client, opts, forksTestStart.AddDate(0, 0, 85))
+	require.NoError(t, err)
+	require.Len(t, forks, 1)
+	assert.Equal(t, "pushed/grafana", forks[0].NameWithOwner)
+}
+
+func TestGetActiveForksPageLimit(t *testing.T) {
+	pages := 0
+	client := testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryActiveForks)
+		require.True(t, ok)
+		pages++
+		// every fork of every page is active
+		query.Repository.Forks.Nodes = []ActiveFork{{Fork: testFork(fmt.Sprint(pages), forksTestStart), PushedAt: &githubv4.DateTime{Time: forksTestStart.AddDate(0, 0, 80)}}}
+		query.Repository.Forks.PageInfo.HasNextPage = true
+	})
+	opts := models.ListForksOptions{Owner: "grafana", Repository: "grafana", Mode: models.ForksModeActive, ActiveDays: 30}
+
+	forks, err := GetActiveForks(context.Background(), client, opts, forksTestStart.AddDate(0, 0, 85))
+	require.NoError(t, err)
+	assert.Equal(t, PageNumberLimit, pages)
+	assert.Len(t, forks, PageNumberLimit)
+}
+
+func TestActiveForksDataFrame(t *testing.T) {
+	client := testActiveForksClient(t)
+	opts := models.ListForksOptions{Owner: "grafana", Repository: "grafana", Mode: models.ForksModeActive, ActiveDays: 30}
+
+	forks, err := GetActiveForks(context.Background(), client, opts, forksTestStart.AddDate(0, 0, 85))
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "active_forks", forks)
+}
@@ -0,0 +1,279 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// Hotspot is the activity of a file, or of every file in a directory, over the analyzed commits
+type Hotspot struct {
+	Path        string
+	Commits     int64
+	Additions   int64
+	Deletions   int64
+	Authors     int64
+	LastChanged time.Time
+
+	authors map[string]bool
+	commits map[string]bool
+}
+
+// Churn returns the number of lines added and removed
+func (h Hotspot) Churn() int64 {
+	return h.Additions + h.Deletions
+}
+
+// Score ranks hotspots by how often and how much they change: the number of commits times the log of the churn.
+// Files changed often are riskier than files changed a lot once, so the churn is damped.
+func (h Hotspot) Score() float64 {
+	return float64(h.Commits) * math.Log2(1+float64(h.Churn()))
+}
+
+// add records a change of the hotspot by a commit
+func (h *Hotspot) add(c Commit, additions int64, deletions int64) {
+	if h.authors == nil {
+		h.authors, h.commits = map[string]bool{}, map[string]bool{}
+	}
+
+	h.Additions += additions
+	h.Deletions += deletions
+	if !h.commits[c.OID] {
+		h.commits[c.OID] = true
+		h.Commits++
+	}
+
+	author := commitAuthor(c)
+	if !h.authors[author] {
+		h.authors[author] = true
+		h.Authors++
+	}
+
+	if c.CommittedDate.After(h.LastChanged) {
+		h.LastChanged = c.CommittedDate.Time
+	}
+}
+
+// commitAuthor identifies the author of a commit by their login, or by their email when it is not linked to a GitHub user
+func commitAuthor(c Commit) string {
+	if c.Author.User.Login != "" {
+		return c.Author.User.Login
+	}
+	return c.Author.Email
+}
+
+// Hotspots is the activity of the files and the directories of a repository, from the highest churn score to the lowest
+type Hotspots struct {
+	Files       []Hotspot
+	Directories []Hotspot
+}
+
+func hotspotsFrame(name string, hotspots []Hotspot) *data.Frame {
+	frame := data.NewFrame(
+		name,
+		data.NewField("path", nil, []string{}),
+		data.NewField("commits", nil, []int64{}),
+		data.NewField("additions", nil, []int64{}),
+		data.NewField("deletions", nil, []int64{}),
+		data.NewField("churn", nil, []int64{}),
+		data.NewField("authors", nil, []int64{}),
+		data.NewField("churn_score", nil, []float64{}),
+		data.NewField("last_changed", nil, []time.Time{}),
+	)
+
+	for _, h := range hotspots {
+		frame.AppendRow(
+			h.Path,
+			h.Commits,
+			h.Additions,
+			h.Deletions,
+			h.Churn(),
+			h.Authors,
+			h.Score(),
+			h.LastChanged,
+		)
+	}
+
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return frame
+}
+
+// Frames converts the hotspots to a frame of files and a frame of directories
+func (h Hotspots) Frames() data.Frames {
+	return data.Frames{
+		hotspotsFrame("hotspot_files", h.Files),
+		hotspotsFrame("hotspot_directories", h.Directories),
+	}
+}
+
+// hotspotDirectory returns the directory a file is rolled up to: its first `depth` directories, or "." for the files at the root
+func hotspotDirectory(path string, depth int) string {
+	dirs := strings.Split(path, "/")
+	dirs = dirs[:len(dirs)-1]
+	if len(dirs) == 0 {
+		return "."
+	}
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	return strings.Join(dirs, "/")
+}
+
+// pathFilter keeps the paths matching one of the include globs, if any, and none of the exclude globs
+type pathFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// pathGlobToRegexp converts a path glob to a regexp. `*` and `?` stay within a path segment, `**` crosses them and
+// a leading `**/` also matches no directory at all, so `**/*_test.go` matches `foo_test.go`
+func pathGlobToRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**/") {
+				sb.WriteString("(.*/)?")
+				i += 2
+				continue
+			}
+			if strings.HasPrefix(pattern[i:], "**") {
+				sb.WriteString(".*")
+				i++
+				continue
+			}
+			sb.WriteString("[^/]*")
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+func newPathFilter(include []string, exclude []string) (pathFilter, error) {
+	compile := func(patterns []string) ([]*regexp.Regexp, error) {
+		res := make([]*regexp.Regexp, len(patterns))
+		for i, p := range patterns {
+			re, err := pathGlobToRegexp(p)
+			if err != nil {
+				return nil, backend.DownstreamError(fmt.Errorf("invalid glob %q: %w", p, err))
+			}
+			res[i] = re
+		}
+		return res, nil
+	}
+
+	var (
+		f   pathFilter
+		err error
+	)
+	if f.include, err = compile(include); err != nil {
+		return pathFilter{}, err
+	}
+	if f.exclude, err = compile(exclude); err != nil {
+		return pathFilter{}, err
+	}
+	return f, nil
+}
+
+func (f pathFilter) matches(path string) bool {
+	for _, re := range f.exclude {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHotspots rolls the files changed by commits up into files and directories
+func GetHotspots(commits CommitsWithFiles, opts models.ListHotspotsOptions) (Hotspots, error) {
+	filter, err := newPathFilter(opts.Include, opts.Exclude)
+	if err != nil {
+		return Hotspots{}, err
+	}
+
+	var (
+		files = map[string]*Hotspot{}
+		dirs  = map[string]*Hotspot{}
+	)
+
+	for _, c := range commits {
+		for _, f := range c.Files {
+			path := f.GetFilename()
+			if !filter.matches(path) {
+				continue
+			}
+
+			additions, deletions := int64(f.GetAdditions()), int64(f.GetDeletions())
+			dir := hotspotDirectory(path, opts.PathDepth)
+
+			if files[path] == nil {
+				files[path] = &Hotspot{Path: path}
+			}
+			files[path].add(c.Commit, additions, deletions)
+
+			if dirs[dir] == nil {
+				dirs[dir] = &Hotspot{Path: dir}
+			}
+			dirs[dir].add(c.Commit, additions, deletions)
+		}
+	}
+
+	return Hotspots{
+		Files:       sortedHotspots(files),
+		Directories: sortedHotspots(dirs),
+	}, nil
+}
+
+// sortedHotspots returns the hotspots from the highest churn score to the lowest, then by path
+func sortedHotspots(hotspots map[string]*Hotspot) []Hotspot {
+	list := make([]Hotspot, 0, len(hotspots))
+	for _, h := range hotspots {
+		list = append(list, *h)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score() != list[j].Score() {
+			return list[i].Score() > list[j].Score()
+		}
+		return list[i].Path < list[j].Path
+	})
+
+	return list
+}
+
+// GetHotspotsInRange lists the files changed by every commit in the time range and rolls them up into hotspots.
+// It makes one REST call per commit, like GetCommitsWithFilesInRange.
+func GetHotspotsInRange(ctx context.Context, client models.Client, opts models.ListHotspotsOptions, from time.Time, to time.Time) (Hotspots, error) {
+	commits, err := GetCommitsWithFilesInRange(ctx, client, models.ListCommitsOptions{
+		Owner:      opts.Owner,
+		Repository: opts.Repository,
+		Ref:        opts.Ref,
+	}, from, to)
+	if err != nil {
+		return Hotspots{}, err
+	}
+
+	return GetHotspots(commits, opts)
+}
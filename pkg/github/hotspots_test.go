@@ -0,0 +1,126 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func hotspotsTestCommits(t *testing.T) CommitsWithFiles {
+	t.Helper()
+
+	committedAt, err := time.Parse(time.RFC3339, "2020-08-25T16:21:56+00:00")
+	require.NoError(t, err)
+
+	file := func(name string, additions, deletions int) *googlegithub.CommitFile {
+		return &googlegithub.CommitFile{
+			Filename:  googlegithub.Ptr(name),
+			Additions: googlegithub.Ptr(additions),
+			Deletions: googlegithub.Ptr(deletions),
+		}
+	}
+	commit := func(oid string, login string, email string, days int, files ...*googlegithub.CommitFile) CommitWithFiles {
+		return CommitWithFiles{
+			Commit: Commit{
+				OID:           oid,
+				CommittedDate: githubv4.DateTime{Time: committedAt.AddDate(0, 0, days)},
+				Author:        GitActor{Email: email, User: models.User{Login: login}},
+			},
+			Files: files,
+		}
+	}
+
+	return CommitsWithFiles{
+		commit("aaa", "first", "first@example.com", 0,
+			file("pkg/github/commits.go", 100, 10),
+			file("pkg/github/commits_test.go", 50, 0),
+			file("README.md", 3, 1),
+		),
+		commit("bbb", "second", "second@example.com", 1,
+			file("pkg/github/commits.go", 5, 5),
+			file("pkg/models/commits.go", 20, 2),
+		),
+		commit("ccc", "", "third@example.com", 2,
+			file("pkg/github/commits.go", 1, 1),
+		),
+	}
+}
+
+func TestGetHotspots(t *testing.T) {
+	t.Run("rolls files up to their directories", func(t *testing.T) {
+		hotspots, err := GetHotspots(hotspotsTestCommits(t), models.HotspotsOptionsWithRepo(models.ListHotspotsOptions{}, "grafana", "grafana"))
+		require.NoError(t, err)
+
+		require.Len(t, hotspots.Files, 4)
+		top := hotspots.Files[0]
+		assert.Equal(t, "pkg/github/commits.go", top.Path)
+		assert.Equal(t, int64(3), top.Commits)
+		assert.Equal(t, int64(106), top.Additions)
+		assert.Equal(t, int64(16), top.Deletions)
+		assert.Equal(t, int64(3), top.Authors)
+
+		require.Len(t, hotspots.Directories, 2)
+		assert.Equal(t, "pkg", hotspots.Directories[0].Path)
+		assert.Equal(t, int64(3), hotspots.Directories[0].Commits)
+		assert.Equal(t, int64(194), hotspots.Directories[0].Churn())
+		assert.Equal(t, ".", hotspots.Directories[1].Path)
+	})
+
+	t.Run("uses the path depth", func(t *testing.T) {
+		hotspots, err := GetHotspots(hotspotsTestCommits(t), models.ListHotspotsOptions{PathDepth: 2})
+		require.NoError(t, err)
+
+		paths := []string{}
+		for _, d := range hotspots.Directories {
+			paths = append(paths, d.Path)
+		}
+		assert.ElementsMatch(t, []string{"pkg/github", "pkg/models", "."}, paths)
+	})
+
+	t.Run("filters files with include and exclude globs", func(t *testing.T) {
+		hotspots, err := GetHotspots(hotspotsTestCommits(t), models.ListHotspotsOptions{
+			PathDepth: 2,
+			Include:   []string{"pkg/**"},
+			Exclude:   []string{"**_test.go", "pkg/models/*"},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, hotspots.Files, 1)
+		assert.Equal(t, "pkg/github/commits.go", hotspots.Files[0].Path)
+		require.Len(t, hotspots.Directories, 1)
+		assert.Equal(t, "pkg/github", hotspots.Directories[0].Path)
+	})
+}
+
+func TestPathFilter(t *testing.T) {
+	filter, err := newPathFilter(nil, []string{"**/*_test.go", "docs/**/*.md"})
+	require.NoError(t, err)
+
+	assert.False(t, filter.matches("foo_test.go"))
+	assert.False(t, filter.matches("pkg/github/commits_test.go"))
+	assert.False(t, filter.matches("docs/README.md"))
+	assert.False(t, filter.matches("docs/sources/query-editor.md"))
+	assert.True(t, filter.matches("foo.go"))
+	assert.True(t, filter.matches("README.md"))
+	assert.True(t, filter.matches("pkg/github/commits.go"))
+}
+
+func TestHotspotDirectory(t *testing.T) {
+	assert.Equal(t, ".", hotspotDirectory("README.md", 1))
+	assert.Equal(t, "pkg", hotspotDirectory("pkg/github/commits.go", 1))
+	assert.Equal(t, "pkg/github", hotspotDirectory("pkg/github/commits.go", 3))
+}
+
+func TestHotspotsDataframe(t *testing.T) {
+	hotspots, err := GetHotspots(hotspotsTestCommits(t), models.HotspotsOptionsWithRepo(models.ListHotspotsOptions{}, "grafana", "grafana"))
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "hotspots", hotspots)
+}
@@ -127,7 +127,8 @@ func (c Issues) Frames() data.Frames {
 //	}
 type QuerySearchIssues struct {
 	Search struct {
-		Nodes []struct {
+		IssueCount int64
+		Nodes      []struct {
 			Issue Issue `graphql:"... on Issue"`
 		}
 		PageInfo models.PageInfo
@@ -135,6 +136,7 @@ type QuerySearchIssues struct {
 }
 
 // GetIssuesInRange lists issues in a project given a time range.
+// When the search matches more issues than GitHub returns, the time range is split into smaller ranges.
 func GetIssuesInRange(ctx context.Context, client models.Client, opts models.ListIssuesOptions, from time.Time, to time.Time) (Issues, error) {
 
 	filter := fmt.Sprintf("repo:%s/%s", opts.Owner, opts.Repository)
@@ -142,24 +144,52 @@ func GetIssuesInRange(ctx context.Context, client models.Client, opts models.Lis
 		filter = fmt.Sprintf("owner:%s", opts.Owner)
 	}
 
-	search := []string{
-		"is:issue",
-		filter,
-		fmt.Sprintf("%s:%s..%s", opts.TimeField.String(), from.Format(time.RFC3339), to.Format(time.RFC3339)),
-	}
-
+	var queryString string
 	if opts.Query != nil {
-		queryString, err := InterPolateMacros(*opts.Query)
+		var err error
+		queryString, err = InterPolateMacros(*opts.Query)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
-		search = append(search, queryString)
 	}
 
+	issues, err := searchTimeRange(ctx, from, to, func(ctx context.Context, from time.Time, to time.Time, limited bool) ([]Issue, bool, error) {
+		search := []string{
+			"is:issue",
+			filter,
+			fmt.Sprintf("%s:%s..%s", opts.TimeField.String(), from.Format(time.RFC3339), to.Format(time.RFC3339)),
+		}
+		if queryString != "" {
+			search = append(search, queryString)
+		}
+
+		// A team is expanded into several searches, and an issue can match more than one of them
+		issues := []Issue{}
+		for _, query := range teamSearchQueries(strings.Join(search, " "), opts.TeamSearchOptions) {
+			is, truncated, err := searchIssues(ctx, client, query, limited)
+			if err != nil || truncated {
+				return nil, truncated, err
+			}
+			issues = append(issues, is...)
+		}
+		return issues, false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dedupeResults(issues, func(issue Issue) string {
+		return fmt.Sprintf("%s#%d", issue.Repository.NameWithOwner, issue.Number)
+	}), nil
+}
+
+// searchIssues returns every issue matching the search query.
+// When limited is true and the search matches more than SearchResultLimit issues, it stops after the first page and reports the search as truncated.
+func searchIssues(ctx context.Context, client models.Client, query string, limited bool) ([]Issue, bool, error) {
 	var (
 		variables = map[string]interface{}{
 			"cursor": (*githubv4.String)(nil),
-			"query":  githubv4.String(strings.Join(search, " ")),
+			"query":  githubv4.String(query),
 		}
 
 		issues = []Issue{}
@@ -168,7 +198,10 @@ func GetIssuesInRange(ctx context.Context, client models.Client, opts models.Lis
 	for {
 		q := &QuerySearchIssues{}
 		if err := client.Query(ctx, q, variables); err != nil {
-			return nil, errors.WithStack(err)
+			return nil, false, errors.WithStack(err)
+		}
+		if limited && q.Search.IssueCount > SearchResultLimit {
+			return nil, true, nil
 		}
 		is := make([]Issue, len(q.Search.Nodes))
 
@@ -184,5 +217,5 @@ func GetIssuesInRange(ctx context.Context, client models.Client, opts models.Lis
 		variables["cursor"] = q.Search.PageInfo.EndCursor
 	}
 
-	return issues, nil
+	return issues, false, nil
 }
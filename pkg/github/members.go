@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryListMembers is the GraphQL query for listing the members of an organization along with their role
+//
+//	{
+//	  organization(login: "grafana") {
+//	    membersWithRole(first: 100) {
+//	      edges {
+//	        role
+//	        hasTwoFactorEnabled
+//	        node {
+//	          login
+//	          name
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListMembers struct {
+	Organization struct {
+		MembersWithRole struct {
+			Edges    []Member
+			PageInfo models.PageInfo
+		} `graphql:"membersWithRole(first: 100, after: $cursor)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// Member is a member of a GitHub organization
+type Member struct {
+	Role githubv4.OrganizationMemberRole
+	// HasTwoFactorEnabled is only visible to organization owners, it is nil otherwise
+	HasTwoFactorEnabled *bool
+	Node                models.User
+}
+
+// Members is a list of organization members
+type Members []Member
+
+// Frames converts the list of members to a Grafana DataFrame
+func (m Members) Frames() data.Frames {
+	frame := data.NewFrame(
+		"members",
+		data.NewField("login", nil, []string{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("email", nil, []string{}),
+		data.NewField("company", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("role", nil, []string{}),
+		data.NewField("has_two_factor_enabled", nil, []*bool{}),
+	)
+
+	for _, v := range m {
+		frame.AppendRow(
+			v.Node.Login,
+			v.Node.Name,
+			v.Node.Email,
+			v.Node.Company,
+			v.Node.URL,
+			string(v.Role),
+			v.HasTwoFactorEnabled,
+		)
+	}
+
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{frame}
+}
+
+// GetAllMembers lists every member of an organization
+func GetAllMembers(ctx context.Context, client models.Client, opts models.ListMembersOptions) (Members, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"login":  githubv4.String(opts.Owner),
+		}
+
+		members = Members{}
+	)
+
+	for {
+		q := &QueryListMembers{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		members = append(members, q.Organization.MembersWithRole.Edges...)
+		if !q.Organization.MembersWithRole.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Organization.MembersWithRole.PageInfo.EndCursor
+	}
+
+	return members, nil
+}
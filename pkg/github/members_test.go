@@ -0,0 +1,60 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetAllMembers(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		opts = models.ListMembersOptions{
+			Owner: "grafana",
+		}
+	)
+
+	testVariables := testutil.GetTestVariablesFunction("cursor", "login")
+
+	client := testutil.NewTestClient(t,
+		testVariables,
+		testutil.GetTestQueryFunction(&QueryListMembers{}),
+	)
+
+	_, err := GetAllMembers(ctx, client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMembersDataFrame(t *testing.T) {
+	enabled := true
+
+	members := Members{
+		{
+			Role:                githubv4.OrganizationMemberRoleAdmin,
+			HasTwoFactorEnabled: &enabled,
+			Node: models.User{
+				Login:   "admin",
+				Name:    "Admin User",
+				Company: "ACME Corp",
+				Email:   "admin@example.com",
+				URL:     "https://github.com/admin",
+			},
+		},
+		{
+			Role: githubv4.OrganizationMemberRoleMember,
+			Node: models.User{
+				Login: "member",
+				Name:  "Member User",
+				URL:   "https://github.com/member",
+			},
+		},
+	}
+
+	testutil.CheckGoldenFramer(t, "members", members)
+}
@@ -0,0 +1,453 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryMergeQueue is the GraphQL query for listing the pull requests currently in the merge queue of a branch
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    defaultBranchRef {
+//	      name
+//	    }
+//	    mergeQueue(branch: "main") {
+//	      entries(first: 100) {
+//	        nodes {
+//	          position
+//	          state
+//	          enqueuedAt
+//	          pullRequest {
+//	            number
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryMergeQueue struct {
+	Repository struct {
+		DefaultBranchRef *struct {
+			Name string
+		}
+		MergeQueue *struct {
+			Entries struct {
+				Nodes    []MergeQueueEntry
+				PageInfo models.PageInfo
+			} `graphql:"entries(first: 100, after: $cursor)"`
+		} `graphql:"mergeQueue(branch: $branch)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// QueryMergeQueueHistory is the GraphQL query for searching pull requests along with the times they were added to and removed from a merge queue
+//
+//	{
+//	  search(query: "is:pr repo:grafana/grafana base:main updated:2024-05-01..2024-05-31", type: ISSUE, first: 100) {
+//	    nodes {
+//	      ... on PullRequest {
+//	        number
+//	        timelineItems(first: 100, itemTypes: [ADDED_TO_MERGE_QUEUE_EVENT, REMOVED_FROM_MERGE_QUEUE_EVENT, MERGED_EVENT]) {
+//	          nodes {
+//	            __typename
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryMergeQueueHistory struct {
+	Search struct {
+		IssueCount int64
+		Nodes      []struct {
+			PullRequest MergeQueuePullRequest `graphql:"... on PullRequest"`
+		}
+		PageInfo models.PageInfo
+	} `graphql:"search(query: $query, type: ISSUE, first: 100, after: $cursor)"`
+}
+
+// MergeQueueEntry is a pull request currently in a merge queue
+type MergeQueueEntry struct {
+	Position             int64
+	State                githubv4.MergeQueueEntryState
+	EnqueuedAt           githubv4.DateTime
+	EstimatedTimeToMerge *int64
+	Solo                 bool
+	Jump                 bool
+	Enqueuer             struct {
+		Login string
+	}
+	PullRequest *struct {
+		Number int64
+		Title  string
+		URL    string
+	}
+}
+
+// MergeQueueEvent is a pull request timeline event that adds the pull request to a merge queue, removes it, or merges it
+type MergeQueueEvent struct {
+	Typename string `graphql:"__typename"`
+	Added    struct {
+		CreatedAt githubv4.DateTime
+		Enqueuer  *struct {
+			Login string
+		}
+	} `graphql:"... on AddedToMergeQueueEvent"`
+	Removed struct {
+		CreatedAt githubv4.DateTime
+		Reason    string
+		Actor     *struct {
+			Login string
+		}
+	} `graphql:"... on RemovedFromMergeQueueEvent"`
+	Merged struct {
+		CreatedAt githubv4.DateTime
+	} `graphql:"... on MergedEvent"`
+}
+
+// createdAt returns when the event happened, whatever its type
+func (e MergeQueueEvent) createdAt() time.Time {
+	switch e.Typename {
+	case "AddedToMergeQueueEvent":
+		return e.Added.CreatedAt.Time
+	case "RemovedFromMergeQueueEvent":
+		return e.Removed.CreatedAt.Time
+	}
+	return e.Merged.CreatedAt.Time
+}
+
+// MergeQueuePullRequest is a pull request along with its merge queue events
+type MergeQueuePullRequest struct {
+	Number        int64
+	Title         string
+	URL           string
+	TimelineItems struct {
+		Nodes []MergeQueueEvent
+	} `graphql:"timelineItems(first: 100, itemTypes: [ADDED_TO_MERGE_QUEUE_EVENT, REMOVED_FROM_MERGE_QUEUE_EVENT, MERGED_EVENT])"`
+}
+
+// Merge queue visit outcomes
+const (
+	MergeQueueOutcomeMerged  = "merged"
+	MergeQueueOutcomeEjected = "ejected"
+	MergeQueueOutcomeQueued  = "queued"
+)
+
+// MergeQueueVisit is a single stay of a pull request in a merge queue, from being added until being merged or removed
+type MergeQueueVisit struct {
+	Number     int64
+	Title      string
+	URL        string
+	Enqueuer   string
+	EnqueuedAt time.Time
+	DequeuedAt *time.Time
+	Outcome    string
+	Reason     string
+}
+
+// visits pairs every time the pull request was added to the merge queue with the event that ended that stay
+func (pr MergeQueuePullRequest) visits() []MergeQueueVisit {
+	events := make([]MergeQueueEvent, len(pr.TimelineItems.Nodes))
+	copy(events, pr.TimelineItems.Nodes)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].createdAt().Before(events[j].createdAt())
+	})
+
+	var (
+		visits  = []MergeQueueVisit{}
+		current *MergeQueueVisit
+	)
+
+	for _, e := range events {
+		switch e.Typename {
+		case "AddedToMergeQueueEvent":
+			if current != nil {
+				visits = append(visits, *current)
+			}
+			current = &MergeQueueVisit{
+				Number:     pr.Number,
+				Title:      pr.Title,
+				URL:        pr.URL,
+				EnqueuedAt: e.Added.CreatedAt.Time,
+				Outcome:    MergeQueueOutcomeQueued,
+			}
+			if e.Added.Enqueuer != nil {
+				current.Enqueuer = e.Added.Enqueuer.Login
+			}
+		case "RemovedFromMergeQueueEvent":
+			if current == nil {
+				continue
+			}
+			t := e.Removed.CreatedAt.Time
+			current.DequeuedAt = &t
+			current.Outcome = MergeQueueOutcomeEjected
+			current.Reason = e.Removed.Reason
+			visits = append(visits, *current)
+			current = nil
+		case "MergedEvent":
+			if current == nil {
+				// the queue removes a pull request at the time it merges it; the merge is what ended that stay
+				if last := len(visits) - 1; last >= 0 && visits[last].DequeuedAt != nil && visits[last].DequeuedAt.Equal(e.Merged.CreatedAt.Time) {
+					visits[last].Outcome = MergeQueueOutcomeMerged
+					visits[last].Reason = ""
+				}
+				continue
+			}
+			t := e.Merged.CreatedAt.Time
+			current.DequeuedAt = &t
+			current.Outcome = MergeQueueOutcomeMerged
+			visits = append(visits, *current)
+			current = nil
+		}
+	}
+
+	if current != nil {
+		visits = append(visits, *current)
+	}
+
+	return visits
+}
+
+// MergeQueue is the current content of a merge queue and the history of the pull requests that went through it
+type MergeQueue struct {
+	Branch  string
+	Entries []MergeQueueEntry
+	Visits  []MergeQueueVisit
+}
+
+// depth returns the number of pull requests in the queue every time it changed
+func (m MergeQueue) depth() ([]time.Time, []int64) {
+	type change struct {
+		at    time.Time
+		delta int64
+	}
+
+	changes := []change{}
+	for _, v := range m.Visits {
+		changes = append(changes, change{at: v.EnqueuedAt, delta: 1})
+		if v.DequeuedAt != nil {
+			changes = append(changes, change{at: *v.DequeuedAt, delta: -1})
+		}
+	}
+	// removals are applied first when they happen at the same time as additions, so the depth never spikes
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].at.Equal(changes[j].at) {
+			return changes[i].delta < changes[j].delta
+		}
+		return changes[i].at.Before(changes[j].at)
+	})
+
+	var (
+		times  = []time.Time{}
+		depths = []int64{}
+		depth  int64
+	)
+	for i, c := range changes {
+		depth += c.delta
+		// only the last change at a given time is kept
+		if i+1 < len(changes) && changes[i+1].at.Equal(c.at) {
+			continue
+		}
+		times = append(times, c.at)
+		depths = append(depths, depth)
+	}
+
+	return times, depths
+}
+
+// Frames converts the merge queue to a frame of the pull requests currently queued, a frame of every stay in the queue, and the queue depth over time
+func (m MergeQueue) Frames() data.Frames {
+	entries := data.NewFrame(
+		"merge_queue",
+		data.NewField("branch", nil, []string{}),
+		data.NewField("position", nil, []int64{}),
+		data.NewField("number", nil, []int64{}),
+		data.NewField("title", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("state", nil, []string{}),
+		data.NewField("enqueuer", nil, []string{}),
+		data.NewField("enqueued_at", nil, []time.Time{}),
+		data.NewField("estimated_time_to_merge", nil, []*int64{}),
+		data.NewField("solo", nil, []bool{}),
+		data.NewField("jump", nil, []bool{}),
+	)
+
+	for _, e := range m.Entries {
+		var (
+			number     int64
+			title, url string
+		)
+		if e.PullRequest != nil {
+			number, title, url = e.PullRequest.Number, e.PullRequest.Title, e.PullRequest.URL
+		}
+
+		entries.AppendRow(
+			m.Branch,
+			e.Position,
+			number,
+			title,
+			url,
+			string(e.State),
+			e.Enqueuer.Login,
+			e.EnqueuedAt.Time,
+			e.EstimatedTimeToMerge,
+			e.Solo,
+			e.Jump,
+		)
+	}
+
+	visits := data.NewFrame(
+		"merge_queue_history",
+		data.NewField("number", nil, []int64{}),
+		data.NewField("title", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("enqueuer", nil, []string{}),
+		data.NewField("enqueued_at", nil, []time.Time{}),
+		data.NewField("dequeued_at", nil, []*time.Time{}),
+		data.NewField("outcome", nil, []string{}),
+		data.NewField("reason", nil, []string{}),
+		data.NewField("time_in_queue", nil, []*float64{}),
+	)
+
+	for _, v := range m.Visits {
+		var timeInQueue *float64
+		if v.DequeuedAt != nil {
+			timeInQueue = durationSeconds(v.EnqueuedAt, *v.DequeuedAt)
+		}
+
+		visits.AppendRow(
+			v.Number,
+			v.Title,
+			v.URL,
+			v.Enqueuer,
+			v.EnqueuedAt,
+			v.DequeuedAt,
+			v.Outcome,
+			v.Reason,
+			timeInQueue,
+		)
+	}
+
+	times, depths := m.depth()
+	depth := data.NewFrame(
+		"merge_queue_depth",
+		data.NewField("time", nil, times),
+		data.NewField("depth", nil, depths),
+	)
+
+	for _, f := range []*data.Frame{entries, visits} {
+		f.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	}
+
+	return data.Frames{entries, visits, depth}
+}
+
+// GetMergeQueueInRange lists the pull requests currently in the merge queue, and the history of the pull requests updated in the time range
+func GetMergeQueueInRange(ctx context.Context, client models.Client, opts models.ListMergeQueueOptions, from time.Time, to time.Time) (MergeQueue, error) {
+	var branch *githubv4.String
+	if opts.Branch != "" {
+		branch = githubv4.NewString(githubv4.String(opts.Branch))
+	}
+
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"name":   githubv4.String(opts.Repository),
+			"owner":  githubv4.String(opts.Owner),
+			"branch": branch,
+		}
+
+		queue = MergeQueue{
+			Branch:  opts.Branch,
+			Entries: []MergeQueueEntry{},
+		}
+	)
+
+	for {
+		q := &QueryMergeQueue{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return MergeQueue{}, errors.WithStack(err)
+		}
+		if queue.Branch == "" && q.Repository.DefaultBranchRef != nil {
+			queue.Branch = q.Repository.DefaultBranchRef.Name
+		}
+		if q.Repository.MergeQueue == nil {
+			break
+		}
+
+		queue.Entries = append(queue.Entries, q.Repository.MergeQueue.Entries.Nodes...)
+
+		if !q.Repository.MergeQueue.Entries.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.MergeQueue.Entries.PageInfo.EndCursor
+	}
+
+	prs, err := searchTimeRange(ctx, from, to, func(ctx context.Context, from time.Time, to time.Time, limited bool) ([]MergeQueuePullRequest, bool, error) {
+		search := []string{
+			"is:pr",
+			fmt.Sprintf("repo:%s/%s", opts.Owner, opts.Repository),
+			fmt.Sprintf("updated:%s..%s", from.Format(time.RFC3339), to.Format(time.RFC3339)),
+		}
+		if queue.Branch != "" {
+			search = append(search, fmt.Sprintf("base:%s", queue.Branch))
+		}
+		return searchMergeQueuePullRequests(ctx, client, strings.Join(search, " "), limited)
+	})
+	if err != nil {
+		return MergeQueue{}, err
+	}
+
+	queue.Visits = []MergeQueueVisit{}
+	for _, pr := range dedupeResults(prs, func(pr MergeQueuePullRequest) string { return fmt.Sprint(pr.Number) }) {
+		queue.Visits = append(queue.Visits, pr.visits()...)
+	}
+	sort.SliceStable(queue.Visits, func(i, j int) bool {
+		return queue.Visits[i].EnqueuedAt.Before(queue.Visits[j].EnqueuedAt)
+	})
+
+	return queue, nil
+}
+
+// searchMergeQueuePullRequests returns every pull request matching the search query along with their merge queue events.
+// When limited is true and the search matches more than SearchResultLimit pull requests, it stops after the first page and reports the search as truncated.
+func searchMergeQueuePullRequests(ctx context.Context, client models.Client, query string, limited bool) ([]MergeQueuePullRequest, bool, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"query":  githubv4.String(query),
+		}
+
+		prs = []MergeQueuePullRequest{}
+	)
+
+	for {
+		q := &QueryMergeQueueHistory{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, false, errors.WithStack(err)
+		}
+		if limited && q.Search.IssueCount > SearchResultLimit {
+			return nil, true, nil
+		}
+
+		for _, v := range q.Search.Nodes {
+			prs = append(prs, v.PullRequest)
+		}
+
+		if !q.Search.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Search.PageInfo.EndCursor
+	}
+
+	return prs, false, nil
+}
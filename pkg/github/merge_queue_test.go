@@ -0,0 +1,107 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetMergeQueueInRange(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		opts = models.ListMergeQueueOptions{
+			Repository: "grafana",
+			Owner:      "grafana",
+			Branch:     "main",
+		}
+	)
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		if _, ok := variables["query"]; ok {
+			testutil.GetTestVariablesFunction("cursor", "query")(t, variables)
+			assert.Contains(t, variables["query"], "base:main")
+			return
+		}
+		testutil.GetTestVariablesFunction("cursor", "name", "owner", "branch")(t, variables)
+	}
+	testQuery := func(t *testing.T, q interface{}) {
+		switch q.(type) {
+		case *QueryMergeQueue, *QueryMergeQueueHistory:
+		default:
+			t.Errorf("unexpected query type %T", q)
+		}
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+
+	queue, err := GetMergeQueueInRange(ctx, client, opts, time.Now().Add(-30*24*time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "main", queue.Branch)
+}
+
+func mergeQueueEvent(typename string, createdAt time.Time) MergeQueueEvent {
+	e := MergeQueueEvent{Typename: typename}
+	e.Added.CreatedAt = githubv4.DateTime{Time: createdAt}
+	e.Removed.CreatedAt = githubv4.DateTime{Time: createdAt}
+	e.Merged.CreatedAt = githubv4.DateTime{Time: createdAt}
+	return e
+}
+
+func TestMergeQueueDataFrame(t *testing.T) {
+	enqueuedAt, err := time.Parse(time.RFC3339, "2024-05-01T10:00:00Z")
+	require.NoError(t, err)
+
+	// ejected once because of failing checks, then merged by the queue
+	ejected := mergeQueueEvent("RemovedFromMergeQueueEvent", enqueuedAt.Add(20*time.Minute))
+	ejected.Removed.Reason = "FAILED_CHECKS"
+	retried := MergeQueuePullRequest{Number: 1, Title: "Fix flaky test", URL: "https://github.com/grafana/grafana/pull/1"}
+	retried.TimelineItems.Nodes = []MergeQueueEvent{
+		mergeQueueEvent("MergedEvent", enqueuedAt.Add(time.Hour)),
+		mergeQueueEvent("RemovedFromMergeQueueEvent", enqueuedAt.Add(time.Hour)),
+		mergeQueueEvent("AddedToMergeQueueEvent", enqueuedAt.Add(30*time.Minute)),
+		ejected,
+		mergeQueueEvent("AddedToMergeQueueEvent", enqueuedAt),
+	}
+
+	queued := MergeQueuePullRequest{Number: 2, Title: "Add feature", URL: "https://github.com/grafana/grafana/pull/2"}
+	queued.TimelineItems.Nodes = []MergeQueueEvent{
+		mergeQueueEvent("AddedToMergeQueueEvent", enqueuedAt.Add(10*time.Minute)),
+	}
+
+	visits := append(retried.visits(), queued.visits()...)
+	require.Len(t, visits, 3)
+	assert.Equal(t, MergeQueueOutcomeEjected, visits[0].Outcome)
+	assert.Equal(t, MergeQueueOutcomeMerged, visits[1].Outcome)
+	assert.Equal(t, MergeQueueOutcomeQueued, visits[2].Outcome)
+
+	entry := MergeQueueEntry{
+		Position:   1,
+		State:      githubv4.MergeQueueEntryStateAwaitingChecks,
+		EnqueuedAt: githubv4.DateTime{Time: enqueuedAt.Add(10 * time.Minute)},
+		PullRequest: &struct {
+			Number int64
+			Title  string
+			URL    string
+		}{Number: 2, Title: "Add feature", URL: "https://github.com/grafana/grafana/pull/2"},
+	}
+	entry.Enqueuer.Login = "testUser"
+
+	queue := MergeQueue{
+		Branch:  "main",
+		Entries: []MergeQueueEntry{entry},
+		Visits:  visits,
+	}
+
+	times, depths := queue.depth()
+	assert.Equal(t, []int64{1, 2, 1, 2, 1}, depths)
+	assert.Len(t, times, 5)
+
+	testutil.CheckGoldenFramer(t, "merge_queue", queue)
+}
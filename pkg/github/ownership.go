@@ -0,0 +1,270 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// BusFactorThreshold is the share of the changes of a directory that its bus factor contributors account for
+const BusFactorThreshold = 0.5
+
+// ContributorShare is the part a contributor had in the changes of a directory
+type ContributorShare struct {
+	Contributor string
+	// Changes is the number of file changes, a commit changing two files of the directory counts twice
+	Changes         int64
+	Additions       int64
+	Deletions       int64
+	Share           float64
+	CumulativeShare float64
+	LastChanged     time.Time
+	// Codeowner is true when the contributor is directly listed as an owner of a file of the directory
+	Codeowner bool
+}
+
+// DirectoryOwnership is the concentration of the changes of a directory among its contributors
+type DirectoryOwnership struct {
+	Path string
+	// Contributors are sorted from the one with the most changes to the one with the least
+	Contributors []ContributorShare
+	Changes      int64
+	// BusFactor is the smallest number of contributors that account for more than half of the changes
+	BusFactor int64
+	// Codeowners are the owners of the changed files of the directory
+	Codeowners []string
+}
+
+// InactiveCodeowner is a CODEOWNERS entry owned by a user or a team that doesn't exist or hasn't been active recently
+type InactiveCodeowner struct {
+	Owner   string
+	Kind    string
+	Pattern string
+	Line    int64
+	Reason  string
+}
+
+// Ownership is the ownership health of the directories of a repository
+type Ownership struct {
+	Codeowners     Codeowners
+	Directories    []DirectoryOwnership
+	InactiveOwners []InactiveCodeowner
+}
+
+// Frames converts the ownership to a bus factor frame, an inactive code owners frame and a frame per directory listing its contributors
+func (o Ownership) Frames() data.Frames {
+	busFactor := data.NewFrame(
+		"bus_factor",
+		data.NewField("directory", nil, []string{}),
+		data.NewField("bus_factor", nil, []int64{}),
+		data.NewField("contributors", nil, []int64{}),
+		data.NewField("changes", nil, []int64{}),
+		data.NewField("top_contributor", nil, []string{}),
+		data.NewField("top_contributor_share", nil, []float64{}),
+		data.NewField("codeowners", nil, []string{}),
+	)
+
+	inactive := data.NewFrame(
+		"inactive_codeowners",
+		data.NewField("owner", nil, []string{}),
+		data.NewField("kind", nil, []string{}),
+		data.NewField("pattern", nil, []string{}),
+		data.NewField("line", nil, []int64{}),
+		data.NewField("reason", nil, []string{}),
+	)
+	for _, v := range o.InactiveOwners {
+		inactive.AppendRow(v.Owner, v.Kind, v.Pattern, v.Line, v.Reason)
+	}
+
+	frames := data.Frames{busFactor, inactive}
+	for _, d := range o.Directories {
+		var top ContributorShare
+		if len(d.Contributors) > 0 {
+			top = d.Contributors[0]
+		}
+		busFactor.AppendRow(
+			d.Path,
+			d.BusFactor,
+			int64(len(d.Contributors)),
+			d.Changes,
+			top.Contributor,
+			top.Share,
+			strings.Join(d.Codeowners, " "),
+		)
+
+		frame := data.NewFrame(
+			d.Path,
+			data.NewField("contributor", nil, []string{}),
+			data.NewField("changes", nil, []int64{}),
+			data.NewField("additions", nil, []int64{}),
+			data.NewField("deletions", nil, []int64{}),
+			data.NewField("share", nil, []float64{}),
+			data.NewField("cumulative_share", nil, []float64{}),
+			data.NewField("last_changed", nil, []time.Time{}),
+			data.NewField("codeowner", nil, []bool{}),
+		)
+		for _, c := range d.Contributors {
+			frame.AppendRow(
+				c.Contributor,
+				c.Changes,
+				c.Additions,
+				c.Deletions,
+				c.Share,
+				c.CumulativeShare,
+				c.LastChanged,
+				c.Codeowner,
+			)
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+// GetDirectoryOwnership computes the share of the changes of every contributor of every directory, rolled up to the path depth
+func GetDirectoryOwnership(commits CommitsWithFiles, codeowners Codeowners, pathDepth int) []DirectoryOwnership {
+	type directory struct {
+		contributors map[string]*ContributorShare
+		codeowners   map[string]bool
+		changes      int64
+	}
+
+	dirs := map[string]*directory{}
+	for _, c := range commits {
+		author := commitAuthor(c.Commit)
+		for _, f := range c.Files {
+			path := hotspotDirectory(f.GetFilename(), pathDepth)
+			dir, ok := dirs[path]
+			if !ok {
+				dir = &directory{contributors: map[string]*ContributorShare{}, codeowners: map[string]bool{}}
+				dirs[path] = dir
+			}
+
+			share, ok := dir.contributors[author]
+			if !ok {
+				share = &ContributorShare{Contributor: author}
+				dir.contributors[author] = share
+			}
+			share.Changes++
+			share.Additions += int64(f.GetAdditions())
+			share.Deletions += int64(f.GetDeletions())
+			if c.Commit.CommittedDate.After(share.LastChanged) {
+				share.LastChanged = c.Commit.CommittedDate.Time
+			}
+			dir.changes++
+
+			if rule, ok := codeowners.Match(f.GetFilename()); ok {
+				for _, o := range rule.Owners {
+					dir.codeowners[o] = true
+				}
+			}
+		}
+	}
+
+	ownership := make([]DirectoryOwnership, 0, len(dirs))
+	for path, dir := range dirs {
+		d := DirectoryOwnership{
+			Path:         path,
+			Changes:      dir.changes,
+			Contributors: make([]ContributorShare, 0, len(dir.contributors)),
+			Codeowners:   make([]string, 0, len(dir.codeowners)),
+		}
+		for o := range dir.codeowners {
+			d.Codeowners = append(d.Codeowners, o)
+		}
+		sort.Strings(d.Codeowners)
+
+		for _, c := range dir.contributors {
+			c.Share = float64(c.Changes) / float64(dir.changes)
+			c.Codeowner = dir.codeowners["@"+c.Contributor]
+			d.Contributors = append(d.Contributors, *c)
+		}
+		sort.Slice(d.Contributors, func(i, j int) bool {
+			if d.Contributors[i].Changes != d.Contributors[j].Changes {
+				return d.Contributors[i].Changes > d.Contributors[j].Changes
+			}
+			return d.Contributors[i].Contributor < d.Contributors[j].Contributor
+		})
+
+		var cumulative int64
+		for i := range d.Contributors {
+			cumulative += d.Contributors[i].Changes
+			d.Contributors[i].CumulativeShare = float64(cumulative) / float64(dir.changes)
+			if d.BusFactor == 0 && d.Contributors[i].CumulativeShare > BusFactorThreshold {
+				d.BusFactor = int64(i + 1)
+			}
+		}
+
+		ownership = append(ownership, d)
+	}
+
+	// the directories at risk come first
+	sort.Slice(ownership, func(i, j int) bool {
+		if ownership[i].BusFactor != ownership[j].BusFactor {
+			return ownership[i].BusFactor < ownership[j].BusFactor
+		}
+		if ownership[i].Changes != ownership[j].Changes {
+			return ownership[i].Changes > ownership[j].Changes
+		}
+		return ownership[i].Path < ownership[j].Path
+	})
+
+	return ownership
+}
+
+// getInactiveCodeowners lists every CODEOWNERS entry whose owner doesn't exist or hasn't contributed since the given date
+func getInactiveCodeowners(codeowners Codeowners, statuses map[string]CodeownerStatus, since time.Time) []InactiveCodeowner {
+	inactive := []InactiveCodeowner{}
+	for _, r := range codeowners.Rules {
+		for _, o := range r.Owners {
+			status, ok := statuses[o]
+			if !ok || (status.Exists && status.Active) {
+				continue
+			}
+			inactive = append(inactive, InactiveCodeowner{
+				Owner:   o,
+				Kind:    status.Kind,
+				Pattern: r.Pattern,
+				Line:    r.Line,
+				Reason:  status.Reason(since),
+			})
+		}
+	}
+	return inactive
+}
+
+// GetOwnershipInRange computes the bus factor of every directory from the files changed by the commits in the time range,
+// and checks whether the owners listed in the CODEOWNERS file contributed during the inactivity window.
+// It makes one REST call per commit, like GetCommitsWithFilesInRange, and one GraphQL query per code owner.
+func GetOwnershipInRange(ctx context.Context, client models.Client, opts models.ListOwnershipOptions, from time.Time, to time.Time) (Ownership, error) {
+	commits, err := GetCommitsWithFilesInRange(ctx, client, models.ListCommitsOptions{
+		Owner:      opts.Owner,
+		Repository: opts.Repository,
+		Ref:        opts.Ref,
+	}, from, to)
+	if err != nil {
+		return Ownership{}, err
+	}
+
+	codeowners, err := GetCodeowners(ctx, client, opts.Owner, opts.Repository, opts.Ref)
+	if err != nil {
+		return Ownership{}, err
+	}
+
+	since := time.Now().AddDate(0, 0, -opts.InactiveDays)
+	statuses, err := GetCodeownerStatuses(ctx, client, codeowners, since)
+	if err != nil {
+		return Ownership{}, err
+	}
+
+	return Ownership{
+		Codeowners:     codeowners,
+		Directories:    GetDirectoryOwnership(commits, codeowners, opts.PathDepth),
+		InactiveOwners: getInactiveCodeowners(codeowners, statuses, since),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetDirectoryOwnership(t *testing.T) {
+	codeowners := ParseCodeowners("CODEOWNERS", "* @grafana/backend\n/pkg/models/ @second")
+	ownership := GetDirectoryOwnership(hotspotsTestCommits(t), codeowners, 2)
+
+	require.Len(t, ownership, 3)
+
+	// the directories with the lowest bus factor come first
+	assert.Equal(t, ".", ownership[0].Path)
+	github := ownership[2]
+	assert.Equal(t, "pkg/github", github.Path)
+	assert.Equal(t, int64(4), github.Changes)
+	assert.Equal(t, int64(2), github.BusFactor)
+	require.Len(t, github.Contributors, 3)
+	assert.Equal(t, "first", github.Contributors[0].Contributor)
+	assert.Equal(t, 0.5, github.Contributors[0].Share)
+	assert.Equal(t, 0.75, github.Contributors[1].CumulativeShare)
+	assert.Equal(t, []string{"@grafana/backend"}, github.Codeowners)
+
+	pkgModels := ownership[1]
+	assert.Equal(t, "pkg/models", pkgModels.Path)
+	assert.Equal(t, int64(1), pkgModels.BusFactor)
+	assert.Equal(t, []string{"@second"}, pkgModels.Codeowners)
+	require.Len(t, pkgModels.Contributors, 1)
+	assert.True(t, pkgModels.Contributors[0].Codeowner)
+}
+
+func TestGetInactiveCodeowners(t *testing.T) {
+	since := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	codeowners := ParseCodeowners("CODEOWNERS", "* @grafana/backend docs@grafana.com\n/docs/ @ghost @octocat")
+	statuses := map[string]CodeownerStatus{
+		"@grafana/backend": {Owner: "@grafana/backend", Kind: CodeownerTeam, Exists: true, Members: 2},
+		"docs@grafana.com": {Owner: "docs@grafana.com", Kind: CodeownerEmail, Exists: true, Active: true},
+		"@ghost":           {Owner: "@ghost", Kind: CodeownerUser},
+		"@octocat":         {Owner: "@octocat", Kind: CodeownerUser, Exists: true, Active: true},
+	}
+
+	inactive := getInactiveCodeowners(codeowners, statuses, since)
+	assert.Equal(t, []InactiveCodeowner{
+		{Owner: "@grafana/backend", Kind: CodeownerTeam, Pattern: "*", Line: 1, Reason: "no team member contributed since 2020-08-01"},
+		{Owner: "@ghost", Kind: CodeownerUser, Pattern: "/docs/", Line: 2, Reason: "user not found"},
+	}, inactive)
+}
+
+func TestOwnershipDataframe(t *testing.T) {
+	since := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	codeowners := ParseCodeowners("CODEOWNERS", "* @grafana/backend\n/pkg/models/ @second @ghost")
+	statuses := map[string]CodeownerStatus{
+		"@grafana/backend": {Owner: "@grafana/backend", Kind: CodeownerTeam, Exists: true, Active: true, Members: 2},
+		"@second":          {Owner: "@second", Kind: CodeownerUser, Exists: true, Active: true},
+		"@ghost":           {Owner: "@ghost", Kind: CodeownerUser},
+	}
+
+	ownership := Ownership{
+		Codeowners:     codeowners,
+		Directories:    GetDirectoryOwnership(hotspotsTestCommits(t), codeowners, 1),
+		InactiveOwners: getInactiveCodeowners(codeowners, statuses, since),
+	}
+
+	testutil.CheckGoldenFramer(t, "ownership", ownership)
+}
@@ -0,0 +1,229 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+const (
+	// PackageDownloadsSnapshotInterval is the minimum time between two snapshots of the downloads of the packages of a repository
+	PackageDownloadsSnapshotInterval = time.Minute * 5
+	// MaxPackageDownloadsSnapshots is the number of snapshots kept per repository, the oldest ones are dropped first
+	MaxPackageDownloadsSnapshots = 2000
+	// PackageDownloadsRecordInterval is how often the downloads of the watched packages are recorded
+	PackageDownloadsRecordInterval = time.Hour
+	// PackageDownloadsWatchDuration is how long the packages of a query keep being recorded after they were last queried
+	PackageDownloadsWatchDuration = 7 * 24 * time.Hour
+	// MaxPackageDownloadsQueries is the number of package queries recorded, the least recently queried ones are dropped first
+	MaxPackageDownloadsQueries = 50
+)
+
+// packageVersionKey identifies a version of a package in the snapshots
+type packageVersionKey struct {
+	Name    string
+	Version string
+}
+
+// packageDownloadsSnapshot is the total downloads of every package version of a repository at a given time
+type packageDownloadsSnapshot struct {
+	Time      time.Time
+	Downloads map[packageVersionKey]int64
+}
+
+// packageDownloadsStore records the downloads of the packages every time they are queried,
+// and every PackageDownloadsRecordInterval while they were queried in the last PackageDownloadsWatchDuration.
+// GitHub only returns the total downloads of a version, the snapshots allow charting the downloads over time.
+// The snapshots are kept in memory so they are lost when the plugin restarts.
+type packageDownloadsStore struct {
+	mu        sync.Mutex
+	snapshots map[string][]packageDownloadsSnapshot
+	watched   map[string]watchedPackages
+}
+
+// watchedPackages are the packages of a query recorded periodically until the watch expires
+type watchedPackages struct {
+	opts    models.ListPackagesOptions
+	expires time.Time
+}
+
+func newPackageDownloadsStore() *packageDownloadsStore {
+	return &packageDownloadsStore{
+		snapshots: map[string][]packageDownloadsSnapshot{},
+		watched:   map[string]watchedPackages{},
+	}
+}
+
+// watch records the packages of the query periodically until PackageDownloadsWatchDuration after now.
+// When more than MaxPackageDownloadsQueries are watched, the least recently queried ones are dropped along with their snapshots.
+func (s *packageDownloadsStore) watch(key string, opts models.ListPackagesOptions, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watched[key] = watchedPackages{opts: opts, expires: now.Add(PackageDownloadsWatchDuration)}
+
+	// every watch lasts as long, so the watch expiring first is the least recently queried
+	for len(s.watched) > MaxPackageDownloadsQueries {
+		oldest := ""
+		for k, w := range s.watched {
+			if k != key && (oldest == "" || w.expires.Before(s.watched[oldest].expires)) {
+				oldest = k
+			}
+		}
+		delete(s.watched, oldest)
+		delete(s.snapshots, oldest)
+	}
+}
+
+// watchedPackages returns the queries to record and drops the expired ones along with their snapshots
+func (s *packageDownloadsStore) watchedPackages(now time.Time) map[string]models.ListPackagesOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watched := map[string]models.ListPackagesOptions{}
+	for key, w := range s.watched {
+		if w.expires.Before(now) {
+			delete(s.watched, key)
+			delete(s.snapshots, key)
+			continue
+		}
+		watched[key] = w.opts
+	}
+	return watched
+}
+
+// recordWatched records a snapshot of the downloads of the packages of every watched query
+func (s *packageDownloadsStore) recordWatched(ctx context.Context, client models.Client, now time.Time) {
+	for key, opts := range s.watchedPackages(now) {
+		packages, err := GetAllPackages(ctx, client, opts)
+		if err != nil {
+			backend.Logger.Warn("could not record the package downloads", "packages", key, "error", err)
+			continue
+		}
+		s.record(key, packages, now)
+	}
+}
+
+// run records the watched packages every interval until the context is done
+func (s *packageDownloadsStore) run(ctx context.Context, client models.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.recordWatched(ctx, client, now)
+		}
+	}
+}
+
+// record stores a snapshot of the downloads of the packages of a repository, unless the previous one is too recent,
+// and returns the snapshots of the repository.
+// When more than MaxPackageDownloadsQueries have snapshots, the ones recorded least recently are dropped.
+func (s *packageDownloadsStore) record(repository string, packages Packages, now time.Time) []packageDownloadsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots := s.snapshots[repository]
+	if n := len(snapshots); n > 0 && now.Sub(snapshots[n-1].Time) < PackageDownloadsSnapshotInterval {
+		return snapshots
+	}
+
+	snapshot := packageDownloadsSnapshot{Time: now, Downloads: map[packageVersionKey]int64{}}
+	for _, pkg := range packages {
+		for _, v := range pkg.Versions {
+			snapshot.Downloads[packageVersionKey{Name: pkg.Name, Version: v.Version}] = v.Statistics.DownloadsTotalCount
+		}
+	}
+
+	snapshots = append(snapshots, snapshot)
+	if len(snapshots) > MaxPackageDownloadsSnapshots {
+		snapshots = snapshots[len(snapshots)-MaxPackageDownloadsSnapshots:]
+	}
+	s.snapshots[repository] = snapshots
+
+	for len(s.snapshots) > MaxPackageDownloadsQueries {
+		oldest := ""
+		for k, list := range s.snapshots {
+			if k != repository && (oldest == "" || list[len(list)-1].Time.Before(s.snapshots[oldest][len(s.snapshots[oldest])-1].Time)) {
+				oldest = k
+			}
+		}
+		delete(s.snapshots, oldest)
+	}
+
+	return snapshots
+}
+
+// PackageDownloadsPoint is the total downloads of a package version at the time of a snapshot
+type PackageDownloadsPoint struct {
+	Time      time.Time
+	Name      string
+	Version   string
+	Downloads int64
+	// Rate is the number of downloads per hour since the previous snapshot, it is nil for the first snapshot of a version
+	Rate *float64
+}
+
+// PackageDownloads is the downloads of the package versions of a repository over time
+type PackageDownloads []PackageDownloadsPoint
+
+// Frames converts the downloads to a Grafana data frame, in the long format with a row per version and snapshot
+func (p PackageDownloads) Frames() data.Frames {
+	frame := data.NewFrame(
+		"package_downloads",
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("version", nil, []string{}),
+		data.NewField("downloads", nil, []int64{}),
+		data.NewField("downloads_per_hour", nil, []*float64{}),
+	)
+
+	for _, point := range p {
+		frame.AppendRow(point.Time, point.Name, point.Version, point.Downloads, point.Rate)
+	}
+
+	return data.Frames{frame}
+}
+
+// getPackageDownloads returns the downloads of every version at the time of each snapshot between from and to.
+// The rate is computed from the previous snapshot even when it is before from, so that the first point has a rate.
+func getPackageDownloads(snapshots []packageDownloadsSnapshot, from time.Time, to time.Time) PackageDownloads {
+	downloads := PackageDownloads{}
+	for i, snapshot := range snapshots {
+		if snapshot.Time.Before(from) || snapshot.Time.After(to) {
+			continue
+		}
+
+		keys := make([]packageVersionKey, 0, len(snapshot.Downloads))
+		for key := range snapshot.Downloads {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(a, b int) bool {
+			if keys[a].Name != keys[b].Name {
+				return keys[a].Name < keys[b].Name
+			}
+			return keys[a].Version < keys[b].Version
+		})
+
+		for _, key := range keys {
+			point := PackageDownloadsPoint{Time: snapshot.Time, Name: key.Name, Version: key.Version, Downloads: snapshot.Downloads[key]}
+			if i > 0 {
+				previous := snapshots[i-1]
+				if count, ok := previous.Downloads[key]; ok {
+					rate := float64(point.Downloads-count) / snapshot.Time.Sub(previous.Time).Hours()
+					point.Rate = &rate
+				}
+			}
+			downloads = append(downloads, point)
+		}
+	}
+	return downloads
+}
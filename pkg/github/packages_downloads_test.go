@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func packagesWithDownloads(downloads ...int64) Packages {
+	versions := []PackageVersion{}
+	for i, d := range downloads {
+		versions = append(versions, PackageVersion{Version: []string{"1.0.0", "2.0.0"}[i], Statistics: PackageStatistics{DownloadsTotalCount: d}})
+	}
+	return Packages{{Name: "grafana", PackageType: "DOCKER", Versions: versions}}
+}
+
+func TestPackageDownloadsStore(t *testing.T) {
+	store := newPackageDownloadsStore()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	require.Len(t, store.record("grafana/grafana", packagesWithDownloads(10), start), 1)
+	// too soon after the previous snapshot
+	require.Len(t, store.record("grafana/grafana", packagesWithDownloads(12), start.Add(time.Minute)), 1)
+	snapshots := store.record("grafana/grafana", packagesWithDownloads(20, 4), start.Add(time.Hour))
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, int64(20), snapshots[1].Downloads[packageVersionKey{Name: "grafana", Version: "1.0.0"}])
+
+	require.Len(t, store.record("grafana/loki", packagesWithDownloads(1), start), 1)
+
+	for i := 0; i < MaxPackageDownloadsSnapshots; i++ {
+		snapshots = store.record("grafana/grafana", packagesWithDownloads(30), start.Add(time.Duration(i+2)*time.Hour))
+	}
+	require.Len(t, snapshots, MaxPackageDownloadsSnapshots)
+	assert.Equal(t, start.Add(2*time.Hour), snapshots[0].Time)
+}
+
+func TestPackageDownloadsStoreWatch(t *testing.T) {
+	store := newPackageDownloadsStore()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	opts := models.ListPackagesOptions{Owner: "grafana", Repository: "grafana", PackageType: "DOCKER"}
+	store.watch("grafana/grafana", opts, start)
+	store.record("grafana/grafana", packagesWithDownloads(10), start)
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		testutil.EnsureKeysAreSet(t, variables, "owner", "name", "packageType")
+	}
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryListPackages)
+		require.True(t, ok)
+		nodes := &query.Repository.Packages.Nodes
+		*nodes = make([]struct {
+			Name        string
+			PackageType githubv4.PackageType
+			Statistics  PackageStatistics
+			Versions    struct {
+				Nodes    []PackageVersion
+				PageInfo models.PageInfo
+			} `graphql:"versions(first: 100, after: $versionsCursor)"`
+		}, 1)
+		(*nodes)[0].Name = "grafana"
+		(*nodes)[0].PackageType = "DOCKER"
+		(*nodes)[0].Versions.Nodes = []PackageVersion{{Version: "1.0.0", Statistics: PackageStatistics{DownloadsTotalCount: 25}}}
+	}
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+
+	// the downloads are recorded without the query being run again
+	store.recordWatched(context.Background(), client, start.Add(time.Hour))
+	snapshots := store.record("grafana/grafana", packagesWithDownloads(25), start.Add(time.Hour+time.Minute))
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, start.Add(time.Hour), snapshots[1].Time)
+	assert.Equal(t, int64(25), snapshots[1].Downloads[packageVersionKey{Name: "grafana", Version: "1.0.0"}])
+
+	// once the watch expired, the packages are no longer recorded and their snapshots are dropped
+	store.recordWatched(context.Background(), client, start.Add(PackageDownloadsWatchDuration+time.Hour))
+	assert.Empty(t, store.watched)
+	assert.Empty(t, store.snapshots)
+}
+
+func TestPackageDownloadsStoreLimit(t *testing.T) {
+	store := newPackageDownloadsStore()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i <= MaxPackageDownloadsQueries; i++ {
+		key := fmt.Sprintf("grafana/repo%d", i)
+		now := start.Add(time.Duration(i) * time.Minute)
+		store.watch(key, models.ListPackagesOptions{}, now)
+		store.record(key, packagesWithDownloads(10), now)
+	}
+
+	// the least recently queried packages are dropped along with their snapshots
+	assert.Len(t, store.watched, MaxPackageDownloadsQueries)
+	assert.Len(t, store.snapshots, MaxPackageDownloadsQueries)
+	assert.NotContains(t, store.watched, "grafana/repo0")
+	assert.NotContains(t, store.snapshots, "grafana/repo0")
+
+	// the snapshots are capped even when they are recorded without a watch
+	store.record("grafana/unwatched", packagesWithDownloads(10), start.Add(time.Hour))
+	assert.Len(t, store.snapshots, MaxPackageDownloadsQueries)
+	assert.Contains(t, store.snapshots, "grafana/unwatched")
+	assert.NotContains(t, store.snapshots, "grafana/repo1")
+}
+
+func TestPackageDownloadsDataFrame(t *testing.T) {
+	store := newPackageDownloadsStore()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	store.record("grafana/grafana", packagesWithDownloads(10), start)
+	store.record("grafana/grafana", packagesWithDownloads(20, 4), start.Add(2*time.Hour))
+	snapshots := store.record("grafana/grafana", packagesWithDownloads(50, 10), start.Add(3*time.Hour))
+
+	downloads := getPackageDownloads(snapshots, start.Add(time.Hour), start.Add(4*time.Hour))
+	require.Len(t, downloads, 4)
+	// the rate of the first point is computed from the snapshot before the time range
+	require.NotNil(t, downloads[0].Rate)
+	assert.Equal(t, 5.0, *downloads[0].Rate)
+	assert.Nil(t, downloads[1].Rate)
+	assert.Equal(t, 30.0, *downloads[2].Rate)
+	assert.Equal(t, 6.0, *downloads[3].Rate)
+
+	testutil.CheckGoldenFramer(t, "package_downloads", downloads)
+}
@@ -0,0 +1,251 @@
+package github
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// MaxContainerManifests is the number of versions of a container image, the most recent ones, whose manifest is read from the registry.
+// Every version needs two requests or more, one per platform of a multi-platform image.
+const MaxContainerManifests = 100
+
+const containerPackageType = "container"
+
+// RegistryPackageVersion is a version of a package listed with the rest API
+type RegistryPackageVersion struct {
+	ID        int64
+	Name      string
+	Tags      []string
+	URL       string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// Manifest is only set for the container images when the manifests are included
+	Manifest *models.ContainerManifest
+}
+
+// RegistryPackage is a package of an organization or a user listed with the rest API
+type RegistryPackage struct {
+	Name        string
+	PackageType string
+	Visibility  string
+	Repository  string
+	Versions    []RegistryPackageVersion
+}
+
+// RegistryPackages is a list of packages listed with the rest API
+type RegistryPackages []RegistryPackage
+
+// Frames converts a list of RegistryPackages to Grafana data frames, the versions of the packages and the tags of the container images
+func (p RegistryPackages) Frames() data.Frames {
+	versions := data.NewFrame(
+		"package_versions",
+		data.NewField("name", nil, []string{}),
+		data.NewField("type", nil, []string{}),
+		data.NewField("visibility", nil, []string{}),
+		data.NewField("repository", nil, []string{}),
+		data.NewField("version", nil, []string{}),
+		data.NewField("tags", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("updated_at", nil, []time.Time{}),
+		data.NewField("digest", nil, []*string{}),
+		data.NewField("media_type", nil, []*string{}),
+		data.NewField("platforms", nil, []*string{}),
+		data.NewField("size", nil, []*int64{}),
+	)
+	tags := data.NewFrame(
+		"container_tags",
+		data.NewField("name", nil, []string{}),
+		data.NewField("tag", nil, []string{}),
+		data.NewField("version", nil, []string{}),
+		data.NewField("updated_at", nil, []time.Time{}),
+		data.NewField("size", nil, []*int64{}),
+	)
+
+	for _, pkg := range p {
+		for _, v := range pkg.Versions {
+			var (
+				digest, mediaType, platforms *string
+				size                         *int64
+			)
+			if m := v.Manifest; m != nil {
+				names := make([]string, len(m.Platforms))
+				for i, platform := range m.Platforms {
+					names[i] = platform.Name()
+				}
+				digest, mediaType, size = &m.Digest, &m.MediaType, &m.Size
+				platforms = googlegithub.Ptr(strings.Join(names, ","))
+			}
+
+			versions.AppendRow(
+				pkg.Name,
+				pkg.PackageType,
+				pkg.Visibility,
+				pkg.Repository,
+				v.Name,
+				strings.Join(v.Tags, ","),
+				v.URL,
+				v.CreatedAt,
+				v.UpdatedAt,
+				digest,
+				mediaType,
+				platforms,
+				size,
+			)
+			for _, tag := range v.Tags {
+				tags.AppendRow(pkg.Name, tag, v.Name, v.UpdatedAt, size)
+			}
+		}
+	}
+
+	return data.Frames{versions, tags}
+}
+
+// GetRegistryPackages lists the packages of an organization or a user with the rest API, with their versions.
+// Unlike the GraphQL API, the rest API supports every ecosystem but has no download statistics.
+func GetRegistryPackages(ctx context.Context, client models.Client, opts models.ListRegistryPackagesOptions) (RegistryPackages, error) {
+	packageTypes := []string{opts.PackageType}
+	if opts.PackageType == "" {
+		packageTypes = models.RegistryPackageTypes
+	}
+
+	names := []string{}
+	for _, name := range strings.Split(opts.Names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	packages := RegistryPackages{}
+	for _, packageType := range packageTypes {
+		list, err := listRegistryPackages(ctx, client, opts, packageType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range list {
+			if len(names) > 0 && !containsFold(names, p.GetName()) {
+				continue
+			}
+
+			pkg := RegistryPackage{
+				Name:        p.GetName(),
+				PackageType: p.GetPackageType(),
+				Visibility:  p.GetVisibility(),
+				Repository:  p.GetRepository().GetFullName(),
+			}
+			pkg.Versions, err = listRegistryPackageVersions(ctx, client, opts, pkg)
+			if err != nil {
+				return nil, err
+			}
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages, nil
+}
+
+// listRegistryPackages lists up to PageNumberLimit pages of 100 packages of a type
+func listRegistryPackages(ctx context.Context, client models.Client, opts models.ListRegistryPackagesOptions, packageType string) ([]*googlegithub.Package, error) {
+	listOpts := &googlegithub.PackageListOptions{
+		PackageType: googlegithub.Ptr(packageType),
+		ListOptions: googlegithub.ListOptions{Page: 1, PerPage: 100},
+	}
+	if opts.Visibility != "" {
+		listOpts.Visibility = googlegithub.Ptr(opts.Visibility)
+	}
+
+	packages := []*googlegithub.Package{}
+	for i := 0; i < PageNumberLimit; i++ {
+		var (
+			page []*googlegithub.Package
+			resp *googlegithub.Response
+			err  error
+		)
+		if opts.Kind == models.PackageOwnerUser {
+			page, resp, err = client.ListPackagesForUser(ctx, opts.Owner, listOpts)
+		} else {
+			page, resp, err = client.ListPackagesForOrg(ctx, opts.Owner, listOpts)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		packages = append(packages, page...)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+	return packages, nil
+}
+
+// listRegistryPackageVersions lists up to PageNumberLimit pages of 100 versions of a package, from the most recent one
+func listRegistryPackageVersions(ctx context.Context, client models.Client, opts models.ListRegistryPackagesOptions, pkg RegistryPackage) ([]RegistryPackageVersion, error) {
+	listOpts := &googlegithub.PackageListOptions{
+		ListOptions: googlegithub.ListOptions{Page: 1, PerPage: 100},
+	}
+
+	versions := []RegistryPackageVersion{}
+	for i := 0; i < PageNumberLimit; i++ {
+		var (
+			page []*googlegithub.PackageVersion
+			resp *googlegithub.Response
+			err  error
+		)
+		if opts.Kind == models.PackageOwnerUser {
+			page, resp, err = client.ListPackageVersionsForUser(ctx, opts.Owner, pkg.PackageType, pkg.Name, listOpts)
+		} else {
+			page, resp, err = client.ListPackageVersionsForOrg(ctx, opts.Owner, pkg.PackageType, pkg.Name, listOpts)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range page {
+			version := RegistryPackageVersion{
+				ID:        v.GetID(),
+				Name:      v.GetName(),
+				URL:       v.GetHTMLURL(),
+				CreatedAt: v.GetCreatedAt().Time,
+				UpdatedAt: v.GetUpdatedAt().Time,
+			}
+			if version.URL == "" {
+				version.URL = v.GetPackageHTMLURL()
+			}
+			if metadata, ok := v.GetMetadata(); ok && metadata.Container != nil {
+				version.Tags = metadata.Container.Tags
+			}
+
+			// versions are listed from the most recent one
+			if opts.IncludeManifests && pkg.PackageType == containerPackageType && len(versions) < MaxContainerManifests {
+				version.Manifest, err = client.GetContainerManifest(ctx, opts.Owner, pkg.Name, version.Name)
+				if err != nil {
+					return nil, err
+				}
+			}
+			versions = append(versions, version)
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+	return versions, nil
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+type registryMockClient struct {
+	*testutil.TestClient
+	packages  map[string][]*googlegithub.Package
+	versions  map[string][]*googlegithub.PackageVersion
+	manifests []string
+	user      bool
+}
+
+func (m *registryMockClient) ListPackagesForOrg(_ context.Context, _ string, opts *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	return m.packages[opts.GetPackageType()], &googlegithub.Response{}, nil
+}
+
+func (m *registryMockClient) ListPackagesForUser(ctx context.Context, user string, opts *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	m.user = true
+	return m.ListPackagesForOrg(ctx, user, opts)
+}
+
+func (m *registryMockClient) ListPackageVersionsForOrg(_ context.Context, _, _, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	// the versions are returned on two pages
+	versions := m.versions[packageName]
+	if opts.Page == 1 && len(versions) > 1 {
+		return versions[:1], &googlegithub.Response{NextPage: 2}, nil
+	}
+	if opts.Page == 2 {
+		return versions[1:], &googlegithub.Response{}, nil
+	}
+	return versions, &googlegithub.Response{}, nil
+}
+
+func (m *registryMockClient) ListPackageVersionsForUser(ctx context.Context, user, packageType, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	return m.ListPackageVersionsForOrg(ctx, user, packageType, packageName, opts)
+}
+
+func (m *registryMockClient) GetContainerManifest(_ context.Context, owner, name, reference string) (*models.ContainerManifest, error) {
+	m.manifests = append(m.manifests, owner+"/"+name+"@"+reference)
+	return &models.ContainerManifest{
+		Digest:    reference,
+		MediaType: "application/vnd.oci.image.index.v1+json",
+		Size:      300,
+		Platforms: []models.ContainerPlatform{
+			{Digest: "sha256:amd64", OS: "linux", Architecture: "amd64", Size: 200},
+			{Digest: "sha256:arm64", OS: "linux", Architecture: "arm64", Variant: "v8", Size: 100},
+		},
+	}, nil
+}
+
+func newRegistryMockClient(t *testing.T) *registryMockClient {
+	created := &googlegithub.Timestamp{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	updated := &googlegithub.Timestamp{Time: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)}
+	version := func(id int64, name string, tags ...string) *googlegithub.PackageVersion {
+		v := &googlegithub.PackageVersion{
+			ID:        googlegithub.Ptr(id),
+			Name:      googlegithub.Ptr(name),
+			HTMLURL:   googlegithub.Ptr("https://github.com/orgs/grafana/packages/" + name),
+			CreatedAt: created,
+			UpdatedAt: updated,
+		}
+		if len(tags) > 0 {
+			metadata, err := json.Marshal(googlegithub.PackageMetadata{
+				PackageType: googlegithub.Ptr("container"),
+				Container:   &googlegithub.PackageContainerMetadata{Tags: tags},
+			})
+			require.NoError(t, err)
+			v.Metadata = metadata
+		}
+		return v
+	}
+	pkg := func(name string, packageType string) *googlegithub.Package {
+		return &googlegithub.Package{
+			Name:        googlegithub.Ptr(name),
+			PackageType: googlegithub.Ptr(packageType),
+			Visibility:  googlegithub.Ptr("public"),
+			Repository:  &googlegithub.Repository{FullName: googlegithub.Ptr("grafana/grafana")},
+		}
+	}
+
+	return &registryMockClient{
+		TestClient: testutil.NewTestClient(t, nil, nil),
+		packages: map[string][]*googlegithub.Package{
+			"npm":       {pkg("@grafana/ui", "npm"), pkg("@grafana/data", "npm")},
+			"container": {pkg("grafana-image-renderer", "container")},
+		},
+		versions: map[string][]*googlegithub.PackageVersion{
+			"@grafana/ui":            {version(1, "11.0.0"), version(2, "10.4.2")},
+			"@grafana/data":          {version(3, "11.0.0")},
+			"grafana-image-renderer": {version(4, "sha256:new", "latest", "3.11.0"), version(5, "sha256:old")},
+		},
+	}
+}
+
+func TestGetRegistryPackages(t *testing.T) {
+	t.Run("lists the packages of every type", func(t *testing.T) {
+		client := newRegistryMockClient(t)
+		packages, err := GetRegistryPackages(context.Background(), client, models.ListRegistryPackagesOptions{Owner: "grafana"})
+		require.NoError(t, err)
+
+		require.Len(t, packages, 3)
+		assert.Equal(t, "@grafana/ui", packages[0].Name)
+		require.Len(t, packages[0].Versions, 2)
+		assert.Equal(t, "10.4.2", packages[0].Versions[1].Name)
+		assert.Equal(t, []string{"latest", "3.11.0"}, packages[2].Versions[0].Tags)
+		assert.Nil(t, packages[2].Versions[0].Manifest)
+		assert.Empty(t, client.manifests)
+		assert.False(t, client.user)
+	})
+
+	t.Run("filters the packages by name and gets the manifests of the images", func(t *testing.T) {
+		client := newRegistryMockClient(t)
+		opts := models.ListRegistryPackagesOptions{
+			Owner:            "grafana",
+			Kind:             models.PackageOwnerUser,
+			Names:            "grafana-image-renderer, @grafana/data",
+			IncludeManifests: true,
+		}
+		packages, err := GetRegistryPackages(context.Background(), client, opts)
+		require.NoError(t, err)
+
+		require.Len(t, packages, 2)
+		assert.Equal(t, "@grafana/data", packages[0].Name)
+		assert.Nil(t, packages[0].Versions[0].Manifest)
+		assert.Equal(t, []string{"grafana/grafana-image-renderer@sha256:new", "grafana/grafana-image-renderer@sha256:old"}, client.manifests)
+		assert.True(t, client.user)
+	})
+}
+
+// endlessVersionsMockClient always has another page of versions
+type endlessVersionsMockClient struct {
+	*registryMockClient
+	pages int
+}
+
+func (m *endlessVersionsMockClient) ListPackageVersionsForOrg(_ context.Context, _, _, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	m.pages++
+	return m.versions[packageName][:1], &googlegithub.Response{NextPage: opts.Page + 1}, nil
+}
+
+func TestListRegistryPackageVersionsPageLimit(t *testing.T) {
+	client := &endlessVersionsMockClient{registryMockClient: newRegistryMockClient(t)}
+	versions, err := listRegistryPackageVersions(context.Background(), client, models.ListRegistryPackagesOptions{Owner: "grafana"}, RegistryPackage{Name: "@grafana/ui", PackageType: "npm"})
+	require.NoError(t, err)
+	assert.Equal(t, PageNumberLimit, client.pages)
+	assert.Len(t, versions, PageNumberLimit)
+}
+
+func TestRegistryPackagesDataFrame(t *testing.T) {
+	client := newRegistryMockClient(t)
+	opts := models.ListRegistryPackagesOptions{Owner: "grafana", IncludeManifests: true}
+	packages, err := GetRegistryPackages(context.Background(), client, opts)
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "package_versions", packages)
+}
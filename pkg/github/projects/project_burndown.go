@@ -0,0 +1,267 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// Iteration is an iteration of a project, it lasts from its start date for its duration in days
+type Iteration struct {
+	ID        string
+	Title     string
+	Start     time.Time
+	Duration  int
+	Completed bool
+}
+
+// End returns the first day after the iteration
+func (i Iteration) End() time.Time {
+	return i.Start.AddDate(0, 0, i.Duration)
+}
+
+// BurndownPoint is the work remaining at the start of a day of an iteration.
+// Remaining is nil for the days that haven't started yet.
+type BurndownPoint struct {
+	Day       time.Time
+	Remaining *float64
+	Ideal     float64
+}
+
+// IterationVelocity is the work committed to an iteration and the part of it that was completed before the iteration ended
+type IterationVelocity struct {
+	Iteration      Iteration
+	Committed      float64
+	Completed      float64
+	CommittedItems int64
+	CompletedItems int64
+}
+
+// ProjectBurndown is the burndown of an iteration along with the velocity of the past iterations
+type ProjectBurndown struct {
+	Iteration Iteration
+	Burndown  []BurndownPoint
+	Velocity  []IterationVelocity
+	// Truncated is true when the project has more items than were read
+	Truncated bool
+}
+
+// Frames converts the burndown to the burndown and velocity frames
+func (p ProjectBurndown) Frames() data.Frames {
+	burndown := data.NewFrame(
+		"project_burndown",
+		data.NewField("day", nil, []time.Time{}),
+		data.NewField("remaining", nil, []*float64{}),
+		data.NewField("ideal", nil, []float64{}),
+	)
+	for _, b := range p.Burndown {
+		burndown.AppendRow(b.Day, b.Remaining, b.Ideal)
+	}
+
+	velocity := data.NewFrame(
+		"project_velocity",
+		data.NewField("iteration", nil, []string{}),
+		data.NewField("start_date", nil, []time.Time{}),
+		data.NewField("end_date", nil, []time.Time{}),
+		data.NewField("committed", nil, []float64{}),
+		data.NewField("completed", nil, []float64{}),
+		data.NewField("committed_items", nil, []int64{}),
+		data.NewField("completed_items", nil, []int64{}),
+	)
+	for _, v := range p.Velocity {
+		velocity.AppendRow(v.Iteration.Title, v.Iteration.Start, v.Iteration.End(), v.Committed, v.Completed, v.CommittedItems, v.CompletedItems)
+	}
+
+	if p.Truncated {
+		burndown.AppendNotices(truncatedNotice())
+		velocity.AppendNotices(truncatedNotice())
+	}
+
+	return data.Frames{burndown, velocity}
+}
+
+// iterations returns the iterations of the iteration field with the given name, ordered by start date
+func iterations(fields []Field, name string) ([]Iteration, bool) {
+	for _, f := range fields {
+		if f.Common.Name != name || f.Common.DataType != "ITERATION" {
+			continue
+		}
+
+		list := []Iteration{}
+		add := func(iterations []ProjectV2Iteration, completed bool) {
+			for _, i := range iterations {
+				start, err := time.Parse("2006-01-02", i.StartDate)
+				if err != nil {
+					backend.Logger.Debug("invalid iteration start date", "iteration", i.Title, "startDate", i.StartDate)
+					continue
+				}
+				list = append(list, Iteration{ID: i.ID, Title: i.Title, Start: start, Duration: int(i.Duration), Completed: completed})
+			}
+		}
+		add(f.Iteration.Configuration.CompletedIterations, true)
+		add(f.Iteration.Configuration.Iterations, false)
+
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Start.Before(list[j].Start)
+		})
+		return list, true
+	}
+	return nil, false
+}
+
+// selectIteration returns the iteration with the given title, or the one in progress when the title is empty.
+// Without an iteration in progress, the latest iteration that started is used.
+func selectIteration(iterations []Iteration, title string, now time.Time) (Iteration, bool) {
+	if title != "" {
+		for _, i := range iterations {
+			if i.Title == title {
+				return i, true
+			}
+		}
+		return Iteration{}, false
+	}
+
+	var selected *Iteration
+	for i := range iterations {
+		if iterations[i].Start.After(now) {
+			break
+		}
+		selected = &iterations[i]
+		if now.Before(iterations[i].End()) {
+			break
+		}
+	}
+	if selected == nil {
+		if len(iterations) == 0 {
+			return Iteration{}, false
+		}
+		selected = &iterations[0]
+	}
+	return *selected, true
+}
+
+// burndownItem is the iteration, the estimate and the completion time of a project item
+type burndownItem struct {
+	iteration string
+	estimate  float64
+	closedAt  *time.Time
+}
+
+// completedBefore returns true when the item was closed before the given time
+func (b burndownItem) completedBefore(t time.Time) bool {
+	return b.closedAt != nil && b.closedAt.Before(t)
+}
+
+// burndownItems returns the items assigned to an iteration along with their iteration and estimate.
+// An item counts as 1 when no estimate field is given, and as 0 when it has no estimate.
+func burndownItems(items []ProjectItem, opts models.ProjectOptions) []burndownItem {
+	list := []burndownItem{}
+	for _, item := range items {
+		b := burndownItem{closedAt: closedDate(item.Content)}
+		if opts.EstimateField == "" {
+			b.estimate = 1
+		}
+		for _, fv := range item.FieldValues.Nodes {
+			if fv.IterationValue.Field.Common.Name == opts.IterationField && fv.IterationValue.IterationID != nil {
+				b.iteration = *fv.IterationValue.IterationID
+			}
+			if opts.EstimateField != "" && fv.NumberValue.Field.Common.Name == opts.EstimateField && fv.NumberValue.Number != nil {
+				b.estimate = *fv.NumberValue.Number
+			}
+		}
+		if b.iteration != "" {
+			list = append(list, b)
+		}
+	}
+	return list
+}
+
+// GetIterationBurndown computes the work remaining on every day of the selected iteration and the velocity of the past iterations.
+// An item is completed when its issue or pull request is closed, so draft issues never burn down.
+func GetIterationBurndown(items *ProjectItemsWithFields, opts models.ProjectOptions, now time.Time) (ProjectBurndown, error) {
+	all, ok := iterations(items.Fields, opts.IterationField)
+	if !ok {
+		return ProjectBurndown{}, backend.DownstreamError(fmt.Errorf("iteration field %q not found in the project", opts.IterationField))
+	}
+	iteration, ok := selectIteration(all, opts.Iteration, now)
+	if !ok {
+		return ProjectBurndown{}, backend.DownstreamError(fmt.Errorf("iteration %q not found in the field %q", opts.Iteration, opts.IterationField))
+	}
+
+	list := burndownItems(items.Items, opts)
+	burndown := ProjectBurndown{
+		Iteration: iteration,
+		Burndown:  []BurndownPoint{},
+		Velocity:  []IterationVelocity{},
+		Truncated: items.Truncated,
+	}
+
+	var committed float64
+	for _, item := range list {
+		if item.iteration == iteration.ID {
+			committed += item.estimate
+		}
+	}
+
+	for day := 0; day <= iteration.Duration; day++ {
+		point := BurndownPoint{Day: iteration.Start.AddDate(0, 0, day), Ideal: committed}
+		if iteration.Duration > 0 {
+			point.Ideal = committed * float64(iteration.Duration-day) / float64(iteration.Duration)
+		}
+		if !point.Day.After(now) {
+			remaining := committed
+			for _, item := range list {
+				if item.iteration == iteration.ID && item.completedBefore(point.Day) {
+					remaining -= item.estimate
+				}
+			}
+			point.Remaining = &remaining
+		}
+		burndown.Burndown = append(burndown.Burndown, point)
+	}
+
+	for _, i := range all {
+		if i.End().After(now) {
+			continue
+		}
+		v := IterationVelocity{Iteration: i}
+		for _, item := range list {
+			if item.iteration != i.ID {
+				continue
+			}
+			v.Committed += item.estimate
+			v.CommittedItems++
+			if item.completedBefore(i.End()) {
+				v.Completed += item.estimate
+				v.CompletedItems++
+			}
+		}
+		burndown.Velocity = append(burndown.Velocity, v)
+	}
+
+	return burndown, nil
+}
+
+// GetProjectBurndown lists the items of a project and computes the burndown of an iteration and the velocity of the past iterations.
+// Every item is read rather than the first pages only, since the items of an iteration can be anywhere in the project.
+func GetProjectBurndown(ctx context.Context, client models.Client, opts models.ProjectOptions, now time.Time) (ProjectBurndown, error) {
+	if ProjectNumber(opts.Number) <= 0 {
+		return ProjectBurndown{}, backend.DownstreamError(errors.New("a project number is required for the burndown"))
+	}
+	if opts.IterationField == "" {
+		return ProjectBurndown{}, backend.DownstreamError(errors.New("an iteration field is required for the burndown"))
+	}
+
+	items, err := GetEveryProjectItem(ctx, client, opts)
+	if err != nil {
+		return ProjectBurndown{}, err
+	}
+	return GetIterationBurndown(items, opts, now)
+}
@@ -0,0 +1,130 @@
+package projects
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func burndownTestProject() *ProjectItemsWithFields {
+	sprint := Field{Common: ProjectV2FieldCommon{Name: "Sprint", DataType: "ITERATION"}}
+	sprint.Iteration.Configuration.CompletedIterations = []ProjectV2Iteration{
+		{ID: "it1", Title: "Sprint 1", StartDate: "2024-01-01", Duration: 4},
+	}
+	sprint.Iteration.Configuration.Iterations = []ProjectV2Iteration{
+		{ID: "it3", Title: "Sprint 3", StartDate: "2024-01-09", Duration: 4},
+		{ID: "it2", Title: "Sprint 2", StartDate: "2024-01-05", Duration: 4},
+	}
+
+	item := func(iteration string, estimate float64, closedAt string) ProjectItem {
+		i := ProjectItem{Type: "ISSUE"}
+		if closedAt != "" {
+			closed, _ := time.Parse(time.RFC3339, closedAt)
+			i.Content.Issue.ClosedAt = &githubv4.DateTime{Time: closed}
+		}
+
+		values := []FieldValue{{}, {}}
+		values[0].IterationValue.IterationID = &iteration
+		values[0].IterationValue.Field.Common = ProjectV2FieldCommon{Name: "Sprint", DataType: "ITERATION"}
+		values[1].NumberValue.Number = &estimate
+		values[1].NumberValue.Field.Common = ProjectV2FieldCommon{Name: "Points", DataType: "NUMBER"}
+		i.FieldValues.Nodes = values
+		return i
+	}
+
+	return &ProjectItemsWithFields{
+		Fields: []Field{
+			{Common: ProjectV2FieldCommon{Name: "Points", DataType: "NUMBER"}},
+			sprint,
+		},
+		Items: []ProjectItem{
+			item("it1", 3, "2024-01-02T10:00:00Z"),
+			// closed after the end of its iteration
+			item("it1", 5, "2024-01-06T10:00:00Z"),
+			item("it2", 2, "2024-01-05T10:00:00Z"),
+			item("it2", 3, "2024-01-06T10:00:00Z"),
+			item("it2", 5, ""),
+			item("it3", 8, ""),
+		},
+	}
+}
+
+func TestSelectIteration(t *testing.T) {
+	all, ok := iterations(burndownTestProject().Fields, "Sprint")
+	require.True(t, ok)
+	require.Len(t, all, 3)
+	assert.Equal(t, "Sprint 1", all[0].Title)
+	assert.True(t, all[0].Completed)
+
+	at := func(day int) time.Time {
+		return time.Date(2024, 1, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	i, ok := selectIteration(all, "", at(6))
+	require.True(t, ok)
+	assert.Equal(t, "Sprint 2", i.Title)
+	i, _ = selectIteration(all, "", at(31))
+	assert.Equal(t, "Sprint 3", i.Title)
+	i, _ = selectIteration(all, "Sprint 1", at(6))
+	assert.Equal(t, "it1", i.ID)
+	_, ok = selectIteration(all, "Sprint 4", at(6))
+	assert.False(t, ok)
+
+	_, ok = iterations(burndownTestProject().Fields, "Points")
+	assert.False(t, ok)
+}
+
+func TestGetIterationBurndown(t *testing.T) {
+	now := time.Date(2024, 1, 7, 12, 0, 0, 0, time.UTC)
+	opts := models.ProjectOptions{IterationField: "Sprint", EstimateField: "Points"}
+
+	burndown, err := GetIterationBurndown(burndownTestProject(), opts, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint 2", burndown.Iteration.Title)
+
+	remaining := []*float64{}
+	ideal := []float64{}
+	for _, p := range burndown.Burndown {
+		remaining = append(remaining, p.Remaining)
+		ideal = append(ideal, p.Ideal)
+	}
+	ptr := func(v float64) *float64 { return &v }
+	assert.Equal(t, []*float64{ptr(10), ptr(8), ptr(5), nil, nil}, remaining)
+	assert.Equal(t, []float64{10, 7.5, 5, 2.5, 0}, ideal)
+
+	assert.Equal(t, []IterationVelocity{
+		{Iteration: burndown.Velocity[0].Iteration, Committed: 8, Completed: 3, CommittedItems: 2, CompletedItems: 1},
+	}, burndown.Velocity)
+	assert.Equal(t, "Sprint 1", burndown.Velocity[0].Iteration.Title)
+
+	// without an estimate field every item counts as one
+	burndown, err = GetIterationBurndown(burndownTestProject(), models.ProjectOptions{IterationField: "Sprint"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, *burndown.Burndown[0].Remaining)
+
+	_, err = GetIterationBurndown(burndownTestProject(), models.ProjectOptions{IterationField: "Status"}, now)
+	assert.Error(t, err)
+}
+
+func TestProjectBurndownDataFrame(t *testing.T) {
+	now := time.Date(2024, 1, 7, 12, 0, 0, 0, time.UTC)
+	opts := models.ProjectOptions{IterationField: "Sprint", EstimateField: "Points"}
+
+	burndown, err := GetIterationBurndown(burndownTestProject(), opts, now)
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "project_burndown", burndown)
+
+	burndown.Truncated = true
+	for _, frame := range burndown.Frames() {
+		require.Len(t, frame.Meta.Notices, 1)
+		assert.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+	}
+}
@@ -0,0 +1,591 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// StatusFieldName is the name of the field GitHub records status change events for
+const StatusFieldName = "Status"
+
+// NoStatus is the column of the items without a status
+const NoStatus = "No Status"
+
+// MaxStatusSnapshots is the number of snapshots kept for a project, the oldest ones are dropped first
+const MaxStatusSnapshots = 1000
+
+// StatusSnapshotInterval is how often the statuses of the items of the watched projects are recorded
+const StatusSnapshotInterval = time.Hour
+
+// StatusSnapshotWatchDuration is how long a project keeps being recorded after its cumulative flow was last queried
+const StatusSnapshotWatchDuration = 7 * 24 * time.Hour
+
+// MaxWatchedProjects is the number of projects recorded periodically, the least recently queried ones are dropped first
+const MaxWatchedProjects = 50
+
+// Sources of a status transition
+const (
+	TransitionSourceEvent    = "event"
+	TransitionSourceSnapshot = "snapshot"
+)
+
+// StatusSnapshot is the status of every item of a project, by item ID, at the time it was taken
+type StatusSnapshot struct {
+	Time     time.Time
+	Statuses map[string]string
+}
+
+// StatusSnapshotStore keeps the snapshots of the statuses of the items of projects in memory.
+// A snapshot is only stored when a status changed since the previous one, so the store tells when the changes were first seen.
+// Queries with different filters see different items, so the items missing from a snapshot keep their previous status.
+// Besides the snapshots taken when the cumulative flow is queried, the projects it was queried for are recorded periodically by Run.
+// The snapshots are lost when the plugin restarts.
+type StatusSnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string][]StatusSnapshot
+	watched   map[string]watchedProject
+}
+
+// watchedProject is a project recorded periodically until the watch expires
+type watchedProject struct {
+	opts    models.ProjectOptions
+	expires time.Time
+}
+
+// NewStatusSnapshotStore creates an empty snapshot store
+func NewStatusSnapshotStore() *StatusSnapshotStore {
+	return &StatusSnapshotStore{
+		snapshots: map[string][]StatusSnapshot{},
+		watched:   map[string]watchedProject{},
+	}
+}
+
+// Watch records the project periodically until StatusSnapshotWatchDuration after now.
+// Every item of the project is recorded, whatever the filters of the query.
+// When more than MaxWatchedProjects are watched, the least recently queried ones are dropped along with their snapshots.
+func (s *StatusSnapshotStore) Watch(project string, opts models.ProjectOptions, now time.Time) {
+	opts.Filters, opts.FilterExpression = nil, ""
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watched[project] = watchedProject{opts: opts, expires: now.Add(StatusSnapshotWatchDuration)}
+
+	// every watch lasts as long, so the watch expiring first is the least recently queried
+	for len(s.watched) > MaxWatchedProjects {
+		oldest := ""
+		for p, w := range s.watched {
+			if p != project && (oldest == "" || w.expires.Before(s.watched[oldest].expires)) {
+				oldest = p
+			}
+		}
+		delete(s.watched, oldest)
+		delete(s.snapshots, oldest)
+	}
+}
+
+// watchedProjects returns the projects to record and drops the expired ones along with their snapshots
+func (s *StatusSnapshotStore) watchedProjects(now time.Time) map[string]models.ProjectOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projects := map[string]models.ProjectOptions{}
+	for project, w := range s.watched {
+		if w.expires.Before(now) {
+			delete(s.watched, project)
+			delete(s.snapshots, project)
+			continue
+		}
+		projects[project] = w.opts
+	}
+	return projects
+}
+
+// RecordWatched records a snapshot of the statuses of the items of every watched project
+func (s *StatusSnapshotStore) RecordWatched(ctx context.Context, client models.Client, now time.Time) {
+	for project, opts := range s.watchedProjects(now) {
+		items, err := GetEveryProjectItem(ctx, client, opts)
+		if err != nil {
+			backend.Logger.Warn("could not record the project statuses", "project", project, "error", err)
+			continue
+		}
+		if items.Truncated {
+			backend.Logger.Warn("only the first project items were recorded", "project", project, "items", len(items.Items))
+		}
+		s.Record(project, StatusSnapshotOf(items.Items, now))
+	}
+}
+
+// Run records the watched projects every interval until the context is done
+func (s *StatusSnapshotStore) Run(ctx context.Context, client models.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.RecordWatched(ctx, client, now)
+		}
+	}
+}
+
+// Record stores the snapshot of a project if a status changed since the previous one, and returns the snapshots of the project
+func (s *StatusSnapshotStore) Record(project string, snapshot StatusSnapshot) []StatusSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots := s.snapshots[project]
+	if len(snapshots) > 0 {
+		statuses := map[string]string{}
+		for id, status := range snapshots[len(snapshots)-1].Statuses {
+			statuses[id] = status
+		}
+		for id, status := range snapshot.Statuses {
+			statuses[id] = status
+		}
+		snapshot.Statuses = statuses
+	}
+
+	if len(snapshots) == 0 || !sameStatuses(snapshots[len(snapshots)-1].Statuses, snapshot.Statuses) {
+		snapshots = append(snapshots, snapshot)
+		if len(snapshots) > MaxStatusSnapshots {
+			snapshots = snapshots[len(snapshots)-MaxStatusSnapshots:]
+		}
+		s.snapshots[project] = snapshots
+	}
+
+	return append([]StatusSnapshot{}, snapshots...)
+}
+
+func sameStatuses(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if s, ok := b[k]; !ok || s != v {
+			return false
+		}
+	}
+	return true
+}
+
+// StatusTransition is an item moving from a status to another one. From is empty when the item was added to the project.
+type StatusTransition struct {
+	Time   time.Time
+	From   string
+	To     string
+	Source string
+}
+
+// ItemHistory is the status transitions of a project item, ordered by time
+type ItemHistory struct {
+	ID          string
+	Title       string
+	Status      string
+	AddedAt     time.Time
+	Transitions []StatusTransition
+}
+
+// StatusAt returns the status of the item at the given time, and false if it wasn't in the project yet
+func (h ItemHistory) StatusAt(t time.Time) (string, bool) {
+	status, ok := "", false
+	for _, tr := range h.Transitions {
+		if tr.Time.After(t) {
+			break
+		}
+		status, ok = tr.To, true
+	}
+	return status, ok
+}
+
+// ItemCycleTime is when work started on an item and when it was done.
+// Work starts when the item leaves the first column of the Status field and it is done when it reaches the last one.
+type ItemCycleTime struct {
+	ID        string
+	Title     string
+	Status    string
+	AddedAt   time.Time
+	StartedAt *time.Time
+	DoneAt    *time.Time
+}
+
+// CycleTime returns the number of seconds between the start and the end of the work on the item
+func (c ItemCycleTime) CycleTime() *float64 {
+	if c.StartedAt == nil || c.DoneAt == nil {
+		return nil
+	}
+	v := c.DoneAt.Sub(*c.StartedAt).Seconds()
+	return &v
+}
+
+// FlowPoint is the number of items in every status at a point in time
+type FlowPoint struct {
+	Time   time.Time
+	Counts map[string]int64
+}
+
+// ProjectFlow is the cumulative flow of the items of a project between the columns of the Status field
+type ProjectFlow struct {
+	Statuses  []string
+	Flow      []FlowPoint
+	History   []ItemHistory
+	CycleTime []ItemCycleTime
+	// Truncated is true when the project has more items than were read
+	Truncated bool
+}
+
+// Frames converts the flow to the cumulative flow, cycle time and history frames
+func (p ProjectFlow) Frames() data.Frames {
+	fields := []*data.Field{data.NewField("time", nil, []time.Time{})}
+	for _, s := range p.Statuses {
+		fields = append(fields, data.NewField(s, nil, []int64{}))
+	}
+	flow := data.NewFrame("project_cumulative_flow", fields...)
+	for _, f := range p.Flow {
+		row := []any{f.Time}
+		for _, s := range p.Statuses {
+			row = append(row, f.Counts[s])
+		}
+		flow.AppendRow(row...)
+	}
+
+	cycleTime := data.NewFrame(
+		"project_item_cycle_time",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}),
+		data.NewField("status", nil, []string{}),
+		data.NewField("added_at", nil, []time.Time{}),
+		data.NewField("started_at", nil, []*time.Time{}),
+		data.NewField("done_at", nil, []*time.Time{}),
+		data.NewField("cycle_time", nil, []*float64{}),
+	)
+	cycleTime.Fields[6].Config = &data.FieldConfig{
+		Unit: "s",
+	}
+	for _, c := range p.CycleTime {
+		cycleTime.AppendRow(c.ID, c.Title, c.Status, c.AddedAt, c.StartedAt, c.DoneAt, c.CycleTime())
+	}
+
+	history := data.NewFrame(
+		"project_item_history",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}),
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("from", nil, []string{}),
+		data.NewField("to", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+	)
+	for _, h := range p.History {
+		for _, t := range h.Transitions {
+			history.AppendRow(h.ID, h.Title, t.Time, t.From, t.To, t.Source)
+		}
+	}
+
+	if p.Truncated {
+		flow.AppendNotices(truncatedNotice())
+		cycleTime.AppendNotices(truncatedNotice())
+		history.AppendNotices(truncatedNotice())
+	}
+
+	return data.Frames{flow, cycleTime, history}
+}
+
+// statusEventsNode is an issue or a pull request along with its project status change events
+type statusEventsNode struct {
+	ID            string
+	TimelineItems struct {
+		Nodes []struct {
+			Event ProjectV2ItemStatusChangedEvent `graphql:"... on ProjectV2ItemStatusChangedEvent"`
+		}
+	} `graphql:"timelineItems(first: 100, itemTypes: [PROJECT_V2_ITEM_STATUS_CHANGED_EVENT])"`
+}
+
+// ProjectV2ItemStatusChangedEvent is the status of an issue or a pull request changing in a project
+type ProjectV2ItemStatusChangedEvent struct {
+	CreatedAt      githubv4.DateTime
+	PreviousStatus string
+	Status         string
+	Project        struct {
+		ID string
+	}
+}
+
+// QueryProjectItemStatusEvents is the GraphQL query for retrieving the status changes of a batch of issues and pull requests
+//
+//	{
+//	  nodes(ids: ["I_kwDO"]) {
+//	    ... on Issue {
+//	      id
+//	      timelineItems(first: 100, itemTypes: [PROJECT_V2_ITEM_STATUS_CHANGED_EVENT]) {
+//	        nodes {
+//	          ... on ProjectV2ItemStatusChangedEvent {
+//	            createdAt
+//	            previousStatus
+//	            status
+//	            project {
+//	              id
+//	            }
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryProjectItemStatusEvents struct {
+	Nodes []struct {
+		Issue       statusEventsNode `graphql:"... on Issue"`
+		PullRequest statusEventsNode `graphql:"... on PullRequest"`
+	} `graphql:"nodes(ids: $ids)"`
+}
+
+// GetStatusEvents returns the status change events in a project of its issues and pull requests, by content ID.
+// Only the first 100 events of an issue or a pull request are looked at.
+func GetStatusEvents(ctx context.Context, client models.Client, projectID string, items []ProjectItem) (map[string][]ProjectV2ItemStatusChangedEvent, error) {
+	ids := []githubv4.ID{}
+	for _, item := range items {
+		if id := contentID(item.Content); id != "" {
+			ids = append(ids, githubv4.ID(id))
+		}
+	}
+
+	events := map[string][]ProjectV2ItemStatusChangedEvent{}
+	for start := 0; start < len(ids); start += 100 {
+		end := start + 100
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		q := &QueryProjectItemStatusEvents{}
+		if err := client.Query(ctx, q, map[string]interface{}{"ids": ids[start:end]}); err != nil {
+			return nil, err
+		}
+
+		for _, n := range q.Nodes {
+			for _, node := range []statusEventsNode{n.Issue, n.PullRequest} {
+				for _, e := range node.TimelineItems.Nodes {
+					if e.Event.Project.ID == projectID && !e.Event.CreatedAt.IsZero() {
+						events[node.ID] = append(events[node.ID], e.Event)
+					}
+				}
+			}
+		}
+	}
+	return events, nil
+}
+
+func contentID(content ProjectV2ItemContent) string {
+	if content.Issue.ID != "" {
+		return content.Issue.ID
+	}
+	return content.PullRequest.ID
+}
+
+func itemTitle(content ProjectV2ItemContent) string {
+	for _, title := range []*string{content.Issue.Title, content.PullRequest.Title, content.DraftIssue.Title} {
+		if title != nil {
+			return *title
+		}
+	}
+	return ""
+}
+
+// itemStatus returns the current status of the item, or NoStatus
+func itemStatus(item ProjectItem) string {
+	for _, fv := range item.FieldValues.Nodes {
+		if fv.SelectValue.Field.Common.Name == StatusFieldName && fv.SelectValue.Name != nil {
+			return *fv.SelectValue.Name
+		}
+	}
+	return NoStatus
+}
+
+// StatusSnapshotOf returns the current status of the items
+func StatusSnapshotOf(items []ProjectItem, now time.Time) StatusSnapshot {
+	snapshot := StatusSnapshot{Time: now, Statuses: map[string]string{}}
+	for _, item := range items {
+		snapshot.Statuses[item.ID] = itemStatus(item)
+	}
+	return snapshot
+}
+
+// itemHistory reconstructs the status transitions of an item from its status change events.
+// The snapshots are used for the items without events, like draft issues, and the transitions are dated when the change was first seen.
+func itemHistory(item ProjectItem, events []ProjectV2ItemStatusChangedEvent, snapshots []StatusSnapshot) ItemHistory {
+	h := ItemHistory{
+		ID:          item.ID,
+		Title:       itemTitle(item.Content),
+		Status:      itemStatus(item),
+		AddedAt:     item.CreatedAt.Time,
+		Transitions: []StatusTransition{},
+	}
+
+	if len(events) > 0 {
+		sort.SliceStable(events, func(i, j int) bool {
+			return events[i].CreatedAt.Before(events[j].CreatedAt.Time)
+		})
+		h.Transitions = append(h.Transitions, StatusTransition{Time: h.AddedAt, To: statusOrNone(events[0].PreviousStatus), Source: TransitionSourceEvent})
+		for _, e := range events {
+			h.Transitions = append(h.Transitions, StatusTransition{
+				Time:   e.CreatedAt.Time,
+				From:   statusOrNone(e.PreviousStatus),
+				To:     statusOrNone(e.Status),
+				Source: TransitionSourceEvent,
+			})
+		}
+		return h
+	}
+
+	for _, s := range snapshots {
+		status, ok := s.Statuses[item.ID]
+		if !ok {
+			continue
+		}
+		if len(h.Transitions) == 0 {
+			h.Transitions = append(h.Transitions, StatusTransition{Time: h.AddedAt, To: status, Source: TransitionSourceSnapshot})
+			continue
+		}
+		if from := h.Transitions[len(h.Transitions)-1].To; from != status {
+			h.Transitions = append(h.Transitions, StatusTransition{Time: s.Time, From: from, To: status, Source: TransitionSourceSnapshot})
+		}
+	}
+	if len(h.Transitions) == 0 {
+		h.Transitions = append(h.Transitions, StatusTransition{Time: h.AddedAt, To: h.Status, Source: TransitionSourceSnapshot})
+	}
+	return h
+}
+
+func statusOrNone(status string) string {
+	if status == "" {
+		return NoStatus
+	}
+	return status
+}
+
+// statusColumns returns the options of the Status field in the order of the project
+func statusColumns(fields []Field) []string {
+	for _, f := range fields {
+		if f.Common.Name == StatusFieldName && f.Common.DataType == "SINGLE_SELECT" {
+			columns := []string{}
+			for _, o := range f.SingleSelect.Options {
+				columns = append(columns, o.Name)
+			}
+			return columns
+		}
+	}
+	return []string{}
+}
+
+// cycleTime finds when the work on an item started and ended with the columns of the Status field
+func cycleTime(h ItemHistory, columns []string) ItemCycleTime {
+	c := ItemCycleTime{ID: h.ID, Title: h.Title, Status: h.Status, AddedAt: h.AddedAt}
+	if len(columns) == 0 {
+		return c
+	}
+
+	backlog, done := columns[0], columns[len(columns)-1]
+	for _, t := range h.Transitions {
+		at := t.Time
+		if c.StartedAt == nil && t.To != NoStatus && t.To != backlog {
+			c.StartedAt = &at
+		}
+		if t.To == done && t.From != done {
+			c.DoneAt = &at
+		}
+	}
+	// an item moved back out of the done column isn't done
+	if h.Status != done {
+		c.DoneAt = nil
+	}
+	return c
+}
+
+// GetStatusFlow computes the number of items in every status at the end of every day of the time range, along with their cycle time.
+// The events are the status change events by content ID.
+func GetStatusFlow(items *ProjectItemsWithFields, events map[string][]ProjectV2ItemStatusChangedEvent, snapshots []StatusSnapshot, from time.Time, to time.Time) ProjectFlow {
+	columns := statusColumns(items.Fields)
+	flow := ProjectFlow{
+		Flow:      []FlowPoint{},
+		History:   []ItemHistory{},
+		CycleTime: []ItemCycleTime{},
+		Truncated: items.Truncated,
+	}
+
+	seen := map[string]bool{}
+	for _, c := range columns {
+		seen[c] = true
+	}
+	extra := []string{}
+
+	for _, item := range items.Items {
+		h := itemHistory(item, events[contentID(item.Content)], snapshots)
+		for _, t := range h.Transitions {
+			// statuses that were renamed or removed since
+			if !seen[t.To] && t.To != NoStatus {
+				seen[t.To] = true
+				extra = append(extra, t.To)
+			}
+		}
+		flow.History = append(flow.History, h)
+		flow.CycleTime = append(flow.CycleTime, cycleTime(h, columns))
+	}
+
+	sort.Strings(extra)
+	flow.Statuses = append(append([]string{NoStatus}, columns...), extra...)
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for day := start; day.Before(to); day = day.AddDate(0, 0, 1) {
+		at := day.AddDate(0, 0, 1)
+		if at.After(to) {
+			at = to
+		}
+		point := FlowPoint{Time: day, Counts: map[string]int64{}}
+		for _, h := range flow.History {
+			if status, ok := h.StatusAt(at); ok {
+				point.Counts[status]++
+			}
+		}
+		flow.Flow = append(flow.Flow, point)
+	}
+
+	return flow
+}
+
+// GetProjectFlow lists the items of a project, reconstructs how they moved between the columns of the Status field and records a snapshot of their statuses.
+// The project is then recorded periodically, so that the transitions without events are dated even when nobody queries it.
+// When the status change events can't be retrieved, the transitions are only reconstructed from the snapshots.
+// Every item is read rather than the first pages only, so that the flow counts the whole board.
+func GetProjectFlow(ctx context.Context, client models.Client, store *StatusSnapshotStore, opts models.ProjectOptions, from time.Time, to time.Time) (ProjectFlow, error) {
+	if ProjectNumber(opts.Number) <= 0 {
+		return ProjectFlow{}, backend.DownstreamError(errors.New("a project number is required for the cumulative flow"))
+	}
+
+	items, err := GetEveryProjectItem(ctx, client, opts)
+	if err != nil {
+		return ProjectFlow{}, err
+	}
+
+	events, err := GetStatusEvents(ctx, client, items.ProjectID, items.Items)
+	if err != nil {
+		backend.Logger.Warn("could not get the project status change events, using the snapshots only", "error", err)
+		events = nil
+	}
+
+	var snapshots []StatusSnapshot
+	if store != nil {
+		now := time.Now()
+		store.Watch(items.ProjectID, opts, now)
+		snapshots = store.Record(items.ProjectID, StatusSnapshotOf(items.Items, now))
+	}
+
+	return GetStatusFlow(items, events, snapshots, from, to), nil
+}
@@ -0,0 +1,242 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func flowTestDay(day int, hour int) time.Time {
+	return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func flowTestItem(id string, contentID string, title string, status string, added time.Time) ProjectItem {
+	item := ProjectItem{ID: id, Type: "ISSUE", CreatedAt: githubv4.DateTime{Time: added}}
+	if contentID != "" {
+		item.Content.Issue.ID = contentID
+		item.Content.Issue.Title = &title
+	} else {
+		item.Type = "DRAFT_ISSUE"
+		item.Content.DraftIssue.Title = &title
+	}
+	if status != "" {
+		value := FieldValue{}
+		value.SelectValue.Name = &status
+		value.SelectValue.Field.Common = ProjectV2FieldCommon{Name: "Status", DataType: "SINGLE_SELECT"}
+		item.FieldValues.Nodes = []FieldValue{value}
+	}
+	return item
+}
+
+func flowTestProject() *ProjectItemsWithFields {
+	status := Field{Common: ProjectV2FieldCommon{Name: "Status", DataType: "SINGLE_SELECT"}}
+	for _, o := range []string{"Todo", "In Progress", "Done"} {
+		status.SingleSelect.Options = append(status.SingleSelect.Options, struct{ Name string }{Name: o})
+	}
+
+	return &ProjectItemsWithFields{
+		ProjectID: "PVT_1",
+		Fields:    []Field{status},
+		Items: []ProjectItem{
+			flowTestItem("PVTI_1", "I_1", "done issue", "Done", flowTestDay(1, 9)),
+			flowTestItem("PVTI_2", "I_2", "started issue", "In Progress", flowTestDay(2, 9)),
+			flowTestItem("PVTI_3", "", "draft", "In Progress", flowTestDay(1, 9)),
+		},
+	}
+}
+
+func flowTestEvents() map[string][]ProjectV2ItemStatusChangedEvent {
+	event := func(at time.Time, from string, to string) ProjectV2ItemStatusChangedEvent {
+		e := ProjectV2ItemStatusChangedEvent{CreatedAt: githubv4.DateTime{Time: at}, PreviousStatus: from, Status: to}
+		e.Project.ID = "PVT_1"
+		return e
+	}
+	return map[string][]ProjectV2ItemStatusChangedEvent{
+		"I_1": {
+			event(flowTestDay(3, 12), "In Progress", "Done"),
+			event(flowTestDay(1, 10), "", "Todo"),
+			event(flowTestDay(2, 10), "Todo", "In Progress"),
+		},
+		"I_2": {
+			event(flowTestDay(2, 10), "Todo", "In Progress"),
+		},
+	}
+}
+
+func TestStatusSnapshotStore(t *testing.T) {
+	store := NewStatusSnapshotStore()
+
+	first := store.Record("PVT_1", StatusSnapshot{Time: flowTestDay(1, 0), Statuses: map[string]string{"PVTI_1": "Todo"}})
+	require.Len(t, first, 1)
+
+	// nothing changed, the snapshot is not stored
+	same := store.Record("PVT_1", StatusSnapshot{Time: flowTestDay(2, 0), Statuses: map[string]string{"PVTI_1": "Todo"}})
+	require.Len(t, same, 1)
+	assert.Equal(t, flowTestDay(1, 0), same[0].Time)
+
+	changed := store.Record("PVT_1", StatusSnapshot{Time: flowTestDay(3, 0), Statuses: map[string]string{"PVTI_1": "Done"}})
+	require.Len(t, changed, 2)
+
+	// a query with a filter only sees some of the items, the others keep their status
+	filtered := store.Record("PVT_1", StatusSnapshot{Time: flowTestDay(4, 0), Statuses: map[string]string{"PVTI_2": "Todo"}})
+	require.Len(t, filtered, 3)
+	assert.Equal(t, map[string]string{"PVTI_1": "Done", "PVTI_2": "Todo"}, filtered[2].Statuses)
+
+	assert.Len(t, store.Record("PVT_2", StatusSnapshot{Time: flowTestDay(3, 0), Statuses: map[string]string{}}), 1)
+}
+
+func TestStatusSnapshotStoreWatch(t *testing.T) {
+	store := NewStatusSnapshotStore()
+	opts := models.ProjectOptions{
+		Kind:             models.ProjectKindOrganization,
+		Organization:     "grafana",
+		Number:           "1",
+		FilterExpression: `status = "Done"`,
+	}
+	store.Watch("PVT_1", opts, flowTestDay(1, 0))
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		testutil.EnsureKeysAreSet(t, variables, "login", "number", "cursor")
+	}
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryProject)
+		require.True(t, ok)
+		query.Organization.ProjectV2.ID = "PVT_1"
+		query.Organization.ProjectV2.Items.Nodes = []ProjectItem{
+			flowTestItem("PVTI_1", "I_1", "done issue", "Done", flowTestDay(1, 9)),
+			flowTestItem("PVTI_3", "", "draft", "In Progress", flowTestDay(1, 9)),
+		}
+	}
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+
+	// the periodic snapshot records every item of the project, not only the ones matching the filter of the query
+	store.RecordWatched(context.Background(), client, flowTestDay(2, 0))
+	snapshots := store.Record("PVT_1", StatusSnapshot{Time: flowTestDay(2, 1), Statuses: map[string]string{}})
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, flowTestDay(2, 0), snapshots[0].Time)
+	assert.Equal(t, map[string]string{"PVTI_1": "Done", "PVTI_3": "In Progress"}, snapshots[0].Statuses)
+
+	// once the watch expired, the project is no longer recorded and its snapshots are dropped
+	store.RecordWatched(context.Background(), client, flowTestDay(1, 0).Add(StatusSnapshotWatchDuration+time.Hour))
+	assert.Empty(t, store.watched)
+	assert.Empty(t, store.snapshots)
+}
+
+func TestStatusSnapshotStoreWatchLimit(t *testing.T) {
+	store := NewStatusSnapshotStore()
+	for i := 0; i <= MaxWatchedProjects; i++ {
+		project := fmt.Sprintf("PVT_%d", i)
+		store.Watch(project, models.ProjectOptions{}, flowTestDay(1, 0).Add(time.Duration(i)*time.Minute))
+		store.Record(project, StatusSnapshot{Time: flowTestDay(1, 0), Statuses: map[string]string{}})
+	}
+
+	// the least recently queried project is dropped along with its snapshots
+	assert.Len(t, store.watched, MaxWatchedProjects)
+	assert.NotContains(t, store.watched, "PVT_0")
+	assert.NotContains(t, store.snapshots, "PVT_0")
+	assert.Contains(t, store.watched, fmt.Sprintf("PVT_%d", MaxWatchedProjects))
+
+	// querying a project again keeps it over the others
+	store.Watch("PVT_1", models.ProjectOptions{}, flowTestDay(2, 0))
+	store.Watch("PVT_new", models.ProjectOptions{}, flowTestDay(2, 0))
+	assert.Contains(t, store.watched, "PVT_1")
+	assert.NotContains(t, store.watched, "PVT_2")
+}
+
+func TestItemHistory(t *testing.T) {
+	project := flowTestProject()
+	events := flowTestEvents()
+
+	h := itemHistory(project.Items[0], events["I_1"], nil)
+	assert.Equal(t, []StatusTransition{
+		{Time: flowTestDay(1, 9), To: NoStatus, Source: TransitionSourceEvent},
+		{Time: flowTestDay(1, 10), From: NoStatus, To: "Todo", Source: TransitionSourceEvent},
+		{Time: flowTestDay(2, 10), From: "Todo", To: "In Progress", Source: TransitionSourceEvent},
+		{Time: flowTestDay(3, 12), From: "In Progress", To: "Done", Source: TransitionSourceEvent},
+	}, h.Transitions)
+
+	status, ok := h.StatusAt(flowTestDay(2, 23))
+	assert.True(t, ok)
+	assert.Equal(t, "In Progress", status)
+	_, ok = h.StatusAt(flowTestDay(1, 0))
+	assert.False(t, ok)
+
+	// the draft issue has no events, its transitions are the changes between snapshots
+	snapshots := []StatusSnapshot{
+		{Time: flowTestDay(1, 12), Statuses: map[string]string{"PVTI_3": "Todo"}},
+		{Time: flowTestDay(2, 12), Statuses: map[string]string{"PVTI_3": "Todo", "PVTI_1": "Done"}},
+		{Time: flowTestDay(3, 12), Statuses: map[string]string{"PVTI_3": "In Progress"}},
+	}
+	h = itemHistory(project.Items[2], nil, snapshots)
+	assert.Equal(t, []StatusTransition{
+		{Time: flowTestDay(1, 9), To: "Todo", Source: TransitionSourceSnapshot},
+		{Time: flowTestDay(3, 12), From: "Todo", To: "In Progress", Source: TransitionSourceSnapshot},
+	}, h.Transitions)
+
+	c := cycleTime(itemHistory(project.Items[0], events["I_1"], nil), []string{"Todo", "In Progress", "Done"})
+	require.NotNil(t, c.CycleTime())
+	assert.Equal(t, float64(26*60*60), *c.CycleTime())
+}
+
+func TestGetStatusEvents(t *testing.T) {
+	project := flowTestProject()
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		testutil.EnsureKeysAreSet(t, variables, "ids")
+		// the draft issue has no content to get events for
+		assert.Equal(t, []githubv4.ID{"I_1", "I_2"}, variables["ids"])
+	}
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryProjectItemStatusEvents)
+		require.True(t, ok)
+
+		query.Nodes = make([]struct {
+			Issue       statusEventsNode `graphql:"... on Issue"`
+			PullRequest statusEventsNode `graphql:"... on PullRequest"`
+		}, 1)
+		node := &query.Nodes[0].Issue
+		node.ID = "I_1"
+		node.TimelineItems.Nodes = make([]struct {
+			Event ProjectV2ItemStatusChangedEvent `graphql:"... on ProjectV2ItemStatusChangedEvent"`
+		}, 2)
+		node.TimelineItems.Nodes[0].Event = flowTestEvents()["I_1"][0]
+		// an event of another project
+		node.TimelineItems.Nodes[1].Event = flowTestEvents()["I_1"][1]
+		node.TimelineItems.Nodes[1].Event.Project.ID = "PVT_2"
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+	events, err := GetStatusEvents(context.Background(), client, project.ProjectID, project.Items)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]ProjectV2ItemStatusChangedEvent{"I_1": {flowTestEvents()["I_1"][0]}}, events)
+}
+
+func TestProjectFlowDataFrame(t *testing.T) {
+	snapshots := []StatusSnapshot{
+		{Time: flowTestDay(1, 12), Statuses: map[string]string{"PVTI_3": "Todo"}},
+		{Time: flowTestDay(3, 12), Statuses: map[string]string{"PVTI_3": "In Progress"}},
+	}
+
+	flow := GetStatusFlow(flowTestProject(), flowTestEvents(), snapshots, flowTestDay(1, 0), flowTestDay(4, 0))
+	assert.Equal(t, []string{NoStatus, "Todo", "In Progress", "Done"}, flow.Statuses)
+	require.Len(t, flow.Flow, 3)
+	assert.Equal(t, map[string]int64{"Todo": 2}, flow.Flow[0].Counts)
+	assert.Equal(t, map[string]int64{"Todo": 1, "In Progress": 2}, flow.Flow[1].Counts)
+	assert.Equal(t, map[string]int64{"In Progress": 2, "Done": 1}, flow.Flow[2].Counts)
+
+	testutil.CheckGoldenFramer(t, "project_flow", flow)
+
+	project := flowTestProject()
+	project.Truncated = true
+	for _, frame := range GetStatusFlow(project, flowTestEvents(), snapshots, flowTestDay(1, 0), flowTestDay(4, 0)).Frames() {
+		require.Len(t, frame.Meta.Notices, 1)
+	}
+}
@@ -2,6 +2,7 @@ package projects
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -43,6 +44,16 @@ func (p ProjectItemsWithFields) Frames() data.Frames {
 		fields = append(fields, f)
 	}
 
+	// the fields of the issue or pull request come after the fields of the project, so that the position of the existing columns doesn't change
+	frame.Fields = append(frame.Fields,
+		data.NewField("number", nil, []*int64{}),
+		data.NewField("url", nil, []*string{}),
+		data.NewField("state", nil, []*string{}),
+		data.NewField("labels", nil, []*string{}),
+		data.NewField("review_decision", nil, []*string{}),
+		data.NewField("linked_branches", nil, []*string{}),
+	)
+
 	// get the values for each item and append to the frame
 	for _, v := range p.Items {
 		var vals []any
@@ -54,20 +65,7 @@ func (p ProjectItemsWithFields) Frames() data.Frames {
 		vals = append(vals, v.CreatedAt.Time)
 		vals = append(vals, closedDate(v.Content))
 
-		// create a lookup of field names to values
-		fieldValue := map[string]any{}
-		fieldValue["type"] = v.Type
-		fieldValue["created_at"] = v.CreatedAt.Time
-		fieldValue["closed_at"] = closedDate(v.Content)
-		// populate some field values from content
-		// we could get them from fieldValues but content has explicit types
-		fieldValue["Assignees"] = getAssignees(v.Content)
-		fieldValue["Milestone"] = milestone(v.Content)
-
-		for _, fv := range v.FieldValues.Nodes {
-			name, val := nameValue(fv)
-			fieldValue[name] = val
-		}
+		fieldValue := itemFieldValues(v)
 
 		// add the values to an array that we will append to the frame row
 		for _, f := range fields {
@@ -75,23 +73,40 @@ func (p ProjectItemsWithFields) Frames() data.Frames {
 			vals = append(vals, val)
 		}
 
-		// if there are no filters, just append the values
-		if len(p.Filters) == 0 {
-			frame.AppendRow(vals...)
-			continue
-		}
-
-		// check for a filter match and append to the frame if there is a match
-		match := filter(fieldValue, p.Filters)
-		if match {
-			frame.AppendRow(vals...)
+		for _, name := range contentFields {
+			vals = append(vals, fieldValue[name])
 		}
 
+		frame.AppendRow(vals...)
 	}
 
 	return data.Frames{frame}
 }
 
+// itemFieldValues creates a lookup of field names to values for a project item
+func itemFieldValues(v ProjectItem) map[string]any {
+	fieldValue := map[string]any{}
+	fieldValue["type"] = v.Type
+	fieldValue["created_at"] = v.CreatedAt.Time
+	fieldValue["closed_at"] = closedDate(v.Content)
+	// populate some field values from content
+	// we could get them from fieldValues but content has explicit types
+	fieldValue["Assignees"] = getAssignees(v.Content)
+	fieldValue["Milestone"] = milestone(v.Content)
+	fieldValue["number"] = contentNumber(v.Content)
+	fieldValue["url"] = contentString(v.Content, func(c IssueContent) *string { return c.URL })
+	fieldValue["state"] = contentString(v.Content, func(c IssueContent) *string { return c.State })
+	fieldValue["labels"] = contentLabels(v.Content)
+	fieldValue["review_decision"] = reviewDecision(v.Content)
+	fieldValue["linked_branches"] = linkedBranches(v.Content)
+
+	for _, fv := range v.FieldValues.Nodes {
+		name, val := nameValue(fv)
+		fieldValue[name] = val
+	}
+	return fieldValue
+}
+
 // get the field name and value from the response model
 func nameValue(fv FieldValue) (string, any) {
 	// DateValue, SelectValue, TextValue etc all have the field data type
@@ -117,90 +132,103 @@ func nameValue(fv FieldValue) (string, any) {
 	return fv.DateValue.Field.Common.Name, nil
 }
 
-// GetAllProjectItems uses the graphql endpoint API to list all project items in the repository
-func GetAllProjectItems(ctx context.Context, client models.Client, opts models.ProjectOptions) (*ProjectItemsWithFields, error) {
-	if opts.Kind == 0 {
-		projects, err := getAllProjectItemsByOrg(ctx, client, opts)
-		if err != nil {
-			return nil, err
-		}
-		projects.Filters = opts.Filters
-		return projects, err
-	}
-	projects, err := getAllProjectItemsByUser(ctx, client, opts)
-	if err != nil {
-		return nil, err
-	}
-	projects.Filters = opts.Filters
-	return projects, err
+// projectItemsQuery is a query listing the items of a project of an organization or a user, or linked to a repository or a team
+type projectItemsQuery interface {
+	project() *ProjectV2Items
 }
 
-func getAllProjectItemsByOrg(ctx context.Context, client models.Client, opts models.ProjectOptions) (*ProjectItemsWithFields, error) {
-	var (
-		variables = map[string]interface{}{
-			"cursor": (*githubv4.String)(nil),
-			"login":  githubv4.String(opts.Organization),
-			"number": githubv4.Int(ProjectNumber(opts.Number)),
-		}
+func (q *QueryProject) project() *ProjectV2Items             { return &q.Organization.ProjectV2 }
+func (q *QueryProjectByUser) project() *ProjectV2Items       { return &q.User.ProjectV2 }
+func (q *QueryProjectByRepository) project() *ProjectV2Items { return &q.Repository.ProjectV2 }
+func (q *QueryProjectByTeam) project() *ProjectV2Items       { return &q.Organization.Team.ProjectV2 }
 
-		projectItems = []ProjectItem{}
-	)
-
-	var fields []Field
-	for i := 0; i < PageNumberLimit; i++ {
-		q := &QueryProject{}
-		if err := client.Query(ctx, q, variables); err != nil {
-			return nil, errors.WithStack(err)
-		}
+// GetAllProjectItems uses the graphql endpoint API to list all project items in the repository.
+// The items are filtered while paging, so the page limit applies to the matching items.
+func GetAllProjectItems(ctx context.Context, client models.Client, opts models.ProjectOptions) (*ProjectItemsWithFields, error) {
+	return getProjectItems(ctx, client, opts, PageNumberLimit*pageSize)
+}
 
-		// TODO: run a separate query for fields?  Or only query for fields the first page.
-		fields = q.Organization.ProjectV2.Fields.Nodes
+// GetEveryProjectItem lists the project items matching the filters without the page limit, for the analyses that need the whole project.
+// Up to MaxFilteredPageNumber pages are read, and the result is marked as truncated when the project has more items.
+func GetEveryProjectItem(ctx context.Context, client models.Client, opts models.ProjectOptions) (*ProjectItemsWithFields, error) {
+	return getProjectItems(ctx, client, opts, 0)
+}
 
-		items := make([]ProjectItem, len(q.Organization.ProjectV2.Items.Nodes))
-		copy(items, q.Organization.ProjectV2.Items.Nodes)
-		projectItems = append(projectItems, items...)
+// getProjectItems lists up to maxItems project items matching the filters, or as many as MaxFilteredPageNumber pages hold when maxItems is 0
+func getProjectItems(ctx context.Context, client models.Client, opts models.ProjectOptions, maxItems int) (*ProjectItemsWithFields, error) {
+	f, err := newItemFilter(opts.Filters, opts.FilterExpression, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
-		if !q.Organization.ProjectV2.Items.PageInfo.HasNextPage {
-			break
-		}
-		variables["cursor"] = q.Organization.ProjectV2.Items.PageInfo.EndCursor
+	number := githubv4.Int(ProjectNumber(opts.Number))
+	switch opts.Kind {
+	case models.ProjectKindOrganization:
+		return listProjectItems(ctx, client, f, maxItems, map[string]interface{}{
+			"login":  githubv4.String(opts.Organization),
+			"number": number,
+		}, func() projectItemsQuery { return &QueryProject{} })
+	case models.ProjectKindRepository:
+		return listProjectItems(ctx, client, f, maxItems, map[string]interface{}{
+			"owner":  githubv4.String(opts.RepositoryOwner()),
+			"name":   githubv4.String(opts.Repository),
+			"number": number,
+		}, func() projectItemsQuery { return &QueryProjectByRepository{} })
+	case models.ProjectKindTeam:
+		return listProjectItems(ctx, client, f, maxItems, map[string]interface{}{
+			"login":  githubv4.String(opts.Organization),
+			"slug":   githubv4.String(opts.Team),
+			"number": number,
+		}, func() projectItemsQuery { return &QueryProjectByTeam{} })
 	}
+	return listProjectItems(ctx, client, f, maxItems, map[string]interface{}{
+		"login":  githubv4.String(opts.User),
+		"number": number,
+	}, func() projectItemsQuery { return &QueryProjectByUser{} })
+}
 
-	return &ProjectItemsWithFields{Items: projectItems, Fields: fields}, nil
+// truncatedNotice warns that an analysis only covers the items of the pages which were read
+func truncatedNotice() data.Notice {
+	return data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("The project has more than %d items, only the first ones were analysed", MaxFilteredPageNumber*pageSize),
+	}
 }
 
-func getAllProjectItemsByUser(ctx context.Context, client models.Client, opts models.ProjectOptions) (*ProjectItemsWithFields, error) {
-	var (
-		variables = map[string]interface{}{
-			"cursor": (*githubv4.String)(nil),
-			"login":  githubv4.String(opts.User),
-			"number": githubv4.Int(ProjectNumber(opts.Number)),
-		}
+func listProjectItems(ctx context.Context, client models.Client, f *itemFilter, maxItems int, variables map[string]interface{}, newQuery func() projectItemsQuery) (*ProjectItemsWithFields, error) {
+	variables["cursor"] = (*githubv4.String)(nil)
 
+	var (
 		projectItems = []ProjectItem{}
+		fields       []Field
+		projectID    string
+		truncated    bool
 	)
-
-	var fields []Field
-	for i := 0; i < PageNumberLimit; i++ {
-		q := &QueryProjectByUser{}
+	for i := 0; i < MaxFilteredPageNumber; i++ {
+		q := newQuery()
 		if err := client.Query(ctx, q, variables); err != nil {
 			return nil, errors.WithStack(err)
 		}
 
+		project := q.project()
 		// TODO: run a separate query for fields?  Or only query for fields the first page.
-		fields = q.User.ProjectV2.Fields.Nodes
+		fields = project.Fields.Nodes
+		projectID = project.ID
 
-		items := make([]ProjectItem, len(q.User.ProjectV2.Items.Nodes))
-		copy(items, q.User.ProjectV2.Items.Nodes)
-		projectItems = append(projectItems, items...)
+		for _, item := range project.Items.Nodes {
+			if f.matches(item) {
+				projectItems = append(projectItems, item)
+			}
+		}
 
-		if !q.User.ProjectV2.Items.PageInfo.HasNextPage {
+		truncated = project.Items.PageInfo.HasNextPage
+		if !project.Items.PageInfo.HasNextPage || (maxItems > 0 && len(projectItems) >= maxItems) {
 			break
 		}
-		variables["cursor"] = q.User.ProjectV2.Items.PageInfo.EndCursor
+		variables["cursor"] = project.Items.PageInfo.EndCursor
 	}
 
-	return &ProjectItemsWithFields{Items: projectItems, Fields: fields}, nil
+	return &ProjectItemsWithFields{ProjectID: projectID, Items: projectItems, Fields: fields, Truncated: truncated}, nil
 }
 
 // GetProjectsItemsInRange retrieves every project from the org and then returns the ones that fall within the given time range.
@@ -301,6 +329,69 @@ func milestone(content ProjectV2ItemContent) *string {
 	return nil
 }
 
+// contentFields are the fields of the issue or pull request of an item added to the frame after the fields of the project
+var contentFields = []string{"number", "url", "state", "labels", "review_decision", "linked_branches"}
+
+// get the number of the issue or the pull request of the item
+func contentNumber(content ProjectV2ItemContent) *int64 {
+	if content.Issue.Number != nil {
+		return content.Issue.Number
+	}
+	return content.PullRequest.Number
+}
+
+// get a field of the issue or the pull request of the item
+func contentString(content ProjectV2ItemContent, field func(c IssueContent) *string) *string {
+	if v := field(content.Issue); v != nil {
+		return v
+	}
+	return field(content.PullRequest)
+}
+
+// convert the labels of the issue or the pull request of the item to comma delimited string
+func contentLabels(content ProjectV2ItemContent) *string {
+	l := content.Issue.Labels
+	if l == nil {
+		l = content.PullRequest.Labels
+	}
+	if l == nil {
+		return nil
+	}
+	var names []string
+	for _, v := range l.Nodes {
+		names = append(names, v.Name)
+	}
+	val := strings.Join(names, ",")
+	return &val
+}
+
+// get the review decision of a pull request: APPROVED, CHANGES_REQUESTED or REVIEW_REQUIRED
+func reviewDecision(content ProjectV2ItemContent) *string {
+	if content.PullRequestDetails.ReviewDecision == nil {
+		return nil
+	}
+	val := string(*content.PullRequestDetails.ReviewDecision)
+	return &val
+}
+
+// convert the branches linked to an issue, or the head branch of a pull request, to comma delimited string
+func linkedBranches(content ProjectV2ItemContent) *string {
+	if content.PullRequestDetails.HeadRefName != "" {
+		return &content.PullRequestDetails.HeadRefName
+	}
+	var names []string
+	for _, b := range content.IssueDetails.LinkedBranches.Nodes {
+		if b.Ref != nil {
+			names = append(names, b.Ref.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	val := strings.Join(names, ",")
+	return &val
+}
+
 // convert list of labels to comma delimited string
 func labels(fv FieldValue) *string {
 	if fv.LabelsValue.Nodes != nil {
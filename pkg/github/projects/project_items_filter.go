@@ -1,6 +1,9 @@
 package projects
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,118 +12,348 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 )
 
-// filter checks if the values match the filter criteria
-func filter(fieldValue map[string]any, filters []models.Filter) bool {
-	var conj string
-	multi := len(filters) > 1
-	allMatch := false
-	if multi {
-		conj = filters[0].Conjunction
-		if conj == "and" || conj == "" {
-			allMatch = true
-			conj = "and"
-		}
-	}
-	for _, f := range filters {
-		val := fieldValue[f.Key]
-		match := match(f.Value, val, f.OP)
-		if match && !multi {
-			return true
+// Filter operators, on top of the comparison operators =, !=, >, <, >= and <=
+const (
+	// OpContains matches the values containing the filter value
+	OpContains = "~"
+	// OpMatches matches the values matching the regular expression
+	OpMatches = "=~"
+	// OpNotMatches matches the values not matching the regular expression
+	OpNotMatches = "!~"
+	// OpIn matches the values equal to one of the comma separated filter values
+	OpIn = "in"
+	// OpNotIn matches the values equal to none of the comma separated filter values
+	OpNotIn = "not in"
+	// OpIsEmpty matches the items without a value
+	OpIsEmpty = "is empty"
+	// OpIsNotEmpty matches the items with a value
+	OpIsNotEmpty = "is not empty"
+)
+
+// relativeDate matches the dates relative to the time of the query, like now-7d
+var relativeDate = regexp.MustCompile(`^now(?:([+-])(\d+)([smhdwMy]))?$`)
+
+// itemFilter is a compiled filter of project items. A nil filter matches every item.
+type itemFilter struct {
+	expr filterExpr
+	now  time.Time
+}
+
+// filterExpr is a node of a filter expression
+type filterExpr interface {
+	eval(fieldValue map[string]any, now time.Time) bool
+}
+
+type andExpr struct {
+	left, right filterExpr
+}
+
+func (e andExpr) eval(fieldValue map[string]any, now time.Time) bool {
+	return e.left.eval(fieldValue, now) && e.right.eval(fieldValue, now)
+}
+
+type orExpr struct {
+	left, right filterExpr
+}
+
+func (e orExpr) eval(fieldValue map[string]any, now time.Time) bool {
+	return e.left.eval(fieldValue, now) || e.right.eval(fieldValue, now)
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e notExpr) eval(fieldValue map[string]any, now time.Time) bool {
+	return !e.expr.eval(fieldValue, now)
+}
+
+// comparison compares the value of a field with the filter values
+type comparison struct {
+	key    string
+	op     string
+	values []string
+	re     *regexp.Regexp
+}
+
+// newComparison validates the operator and compiles the regular expression of the comparison
+func newComparison(key string, op string, values []string) (comparison, error) {
+	c := comparison{key: key, op: strings.ToLower(op), values: values}
+	switch c.op {
+	case "=", "!=", ">", "<", ">=", "<=", OpContains, OpIn, OpNotIn:
+		if len(values) == 0 {
+			return c, fmt.Errorf("no value to compare %q with", key)
 		}
-		if match && conj == "or" {
-			return true
+	case OpMatches, OpNotMatches:
+		if len(values) != 1 {
+			return c, fmt.Errorf("one regular expression is needed to match %q", key)
 		}
-		if !match && conj == "and" {
-			return false
+		re, err := regexp.Compile(values[0])
+		if err != nil {
+			return c, fmt.Errorf("invalid regular expression %q: %w", values[0], err)
 		}
+		c.re = re
+	case OpIsEmpty, OpIsNotEmpty:
+	default:
+		return c, fmt.Errorf("unknown operator %q", op)
 	}
-	return allMatch
+	return c, nil
 }
 
-// match based on operator
-func match(v1 string, v2 any, op string) bool {
-	switch op {
+func (c comparison) eval(fieldValue map[string]any, now time.Time) bool {
+	v := toFilterValue(fieldValue[c.key])
+
+	switch c.op {
+	case OpIsEmpty:
+		return v.empty
+	case OpIsNotEmpty:
+		return !v.empty
+	case "!=":
+		return v.empty || !v.equals(c.values[0], now)
+	case OpNotIn:
+		return !c.in(v, now)
+	case OpNotMatches:
+		return v.empty || !c.re.MatchString(v.text)
+	}
+
+	if v.empty {
+		return false
+	}
+	switch c.op {
+	case "=":
+		return v.equals(c.values[0], now)
+	case OpIn:
+		return c.in(v, now)
+	case OpContains:
+		return strings.Contains(v.text, c.values[0])
+	case OpMatches:
+		return c.re.MatchString(v.text)
+	}
+
+	cmp, ok := v.compare(c.values[0], now)
+	if !ok {
+		return false
+	}
+	switch c.op {
 	case ">":
-		return greaterThan(v1, v2)
+		return cmp > 0
 	case "<":
-		return lessThan(v1, v2)
-	case "=":
-		return equals(v1, v2)
-	case "!=":
-		return !equals(v1, v2)
+		return cmp < 0
 	case ">=":
-		return equals(v1, v2) || greaterThan(v1, v2)
+		return cmp >= 0
 	case "<=":
-		return equals(v1, v2) || lessThan(v1, v2)
-	case "~":
-		return contains(v1, v2)
+		return cmp <= 0
 	}
 	return false
 }
 
-func equals(v1 string, v2 any) bool {
-	switch v := v2.(type) {
-	case *string:
-		return v1 == *v
-	case string:
-		return v1 == v
-	case *time.Time:
-		f := func(t time.Time) bool { return v != nil && v.Equal(t) }
-		return checkDate(v1, f)
-	case time.Time:
-		f := func(t time.Time) bool { return v.Equal(t) }
-		return checkDate(v1, f)
+func (c comparison) in(v filterValue, now time.Time) bool {
+	if v.empty {
+		return false
+	}
+	for _, value := range c.values {
+		if v.equals(value, now) {
+			return true
+		}
 	}
 	return false
 }
 
-func greaterThan(v1 string, v2 any) bool {
-	switch v := v2.(type) {
-	case *string:
-		return v1 > *v
+// filterValue is the value of a field of a project item, by kind
+type filterValue struct {
+	empty  bool
+	text   string
+	number *float64
+	date   *time.Time
+}
+
+func toFilterValue(v any) filterValue {
+	switch v := v.(type) {
 	case string:
-		return v1 > v
-	case *time.Time:
-		f := func(t time.Time) bool { return v != nil && v.After(t) }
-		return checkDate(v1, f)
+		return filterValue{empty: v == "", text: v}
+	case *string:
+		if v == nil {
+			return filterValue{empty: true}
+		}
+		return filterValue{empty: *v == "", text: *v}
 	case time.Time:
-		f := func(t time.Time) bool { return v.After(t) }
-		return checkDate(v1, f)
+		return filterValue{text: v.Format(time.RFC3339), date: &v}
+	case *time.Time:
+		if v == nil {
+			return filterValue{empty: true}
+		}
+		return filterValue{text: v.Format(time.RFC3339), date: v}
+	case *float64:
+		if v == nil {
+			return filterValue{empty: true}
+		}
+		return filterValue{text: strconv.FormatFloat(*v, 'f', -1, 64), number: v}
 	}
-	return false
+	return filterValue{empty: true}
 }
 
-func lessThan(v1 string, v2 any) bool {
-	switch v := v2.(type) {
-	case *string:
-		return v1 < *v
-	case string:
-		return v1 < v
-	case *time.Time:
-		f := func(t time.Time) bool { return v != nil && v.Before(t) }
-		return checkDate(v1, f)
-	case time.Time:
-		f := func(t time.Time) bool { return v.Before(t) }
-		return checkDate(v1, f)
+// compare returns -1, 0 or 1 when the value is lower than, equal to or greater than the filter value.
+// Numbers and dates are compared by value, anything else as text. It returns false when the filter value can't be compared.
+func (v filterValue) compare(value string, now time.Time) (int, bool) {
+	switch {
+	case v.number != nil:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false
+		}
+		switch {
+		case *v.number < n:
+			return -1, true
+		case *v.number > n:
+			return 1, true
+		}
+		return 0, true
+	case v.date != nil:
+		t, ok := parseFilterDate(value, now)
+		if !ok {
+			return 0, false
+		}
+		return v.date.Compare(t), true
 	}
-	return false
+	return strings.Compare(v.text, value), true
 }
 
-func contains(v1 string, v2 any) bool {
-	switch v := v2.(type) {
-	case *string:
-		return strings.Contains(*v, v1)
-	case string:
-		return strings.Contains(v, v1)
+func (v filterValue) equals(value string, now time.Time) bool {
+	cmp, ok := v.compare(value, now)
+	return ok && cmp == 0
+}
+
+// parseFilterDate parses an absolute date, or a date relative to now like now-7d
+func parseFilterDate(value string, now time.Time) (time.Time, bool) {
+	if m := relativeDate.FindStringSubmatch(value); m != nil {
+		if m[1] == "" {
+			return now, true
+		}
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+		switch m[3] {
+		case "s":
+			return now.Add(time.Duration(n) * time.Second), true
+		case "m":
+			return now.Add(time.Duration(n) * time.Minute), true
+		case "h":
+			return now.Add(time.Duration(n) * time.Hour), true
+		case "d":
+			return now.AddDate(0, 0, n), true
+		case "w":
+			return now.AddDate(0, 0, 7*n), true
+		case "M":
+			return now.AddDate(0, n, 0), true
+		default:
+			return now.AddDate(n, 0, 0), true
+		}
 	}
-	return false
+
+	t, err := dateparse.ParseAny(value)
+	if err != nil {
+		backend.Logger.Debug("failed to parse date in project filter", "date", value, "error", err)
+		return time.Time{}, false
+	}
+	return t, true
 }
 
-func checkDate(d string, f func(t time.Time) bool) bool {
-	t, err := dateparse.ParseAny(d)
+// filtersExpr combines a list of filters in an expression. Like in the query editor, the conjunction of a filter joins it to the next one,
+// the conjunction of the first filter is used when it has none, and "and" takes precedence over "or".
+func filtersExpr(filters []models.Filter) (filterExpr, error) {
+	var (
+		terms   []filterExpr
+		current filterExpr
+	)
+	conj := "and"
+	if len(filters) > 0 && strings.EqualFold(filters[0].Conjunction, "or") {
+		conj = "or"
+	}
+
+	for i, f := range filters {
+		values := []string{f.Value}
+		if op := strings.ToLower(f.OP); op == OpIn || op == OpNotIn {
+			values = splitFilterList(f.Value)
+		}
+		c, err := newComparison(f.Key, f.OP, values)
+		if err != nil {
+			return nil, err
+		}
+
+		join := conj
+		if i > 0 && filters[i-1].Conjunction != "" {
+			join = strings.ToLower(filters[i-1].Conjunction)
+		}
+		switch {
+		case current == nil:
+			current = c
+		case join == "or":
+			terms = append(terms, current)
+			current = c
+		default:
+			current = andExpr{current, c}
+		}
+	}
+	if current == nil {
+		return nil, nil
+	}
+
+	expr := current
+	for i := len(terms) - 1; i >= 0; i-- {
+		expr = orExpr{terms[i], expr}
+	}
+	return expr, nil
+}
+
+// splitFilterList splits a comma separated list of values
+func splitFilterList(value string) []string {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// newItemFilter compiles the filters and the filter expression, an item has to match both.
+// It returns nil when there is nothing to filter on.
+func newItemFilter(filters []models.Filter, expression string, now time.Time) (*itemFilter, error) {
+	expr, err := filtersExpr(filters)
+	if err != nil {
+		return nil, backend.DownstreamError(fmt.Errorf("invalid project filter: %w", err))
+	}
+
+	if strings.TrimSpace(expression) != "" {
+		parsed, err := parseFilterExpression(expression)
+		if err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("invalid project filter expression: %w", err))
+		}
+		if expr == nil {
+			expr = parsed
+		} else {
+			expr = andExpr{expr, parsed}
+		}
+	}
+
+	if expr == nil {
+		return nil, nil
+	}
+	return &itemFilter{expr: expr, now: now}, nil
+}
+
+// matches returns true when the item matches the filter
+func (f *itemFilter) matches(item ProjectItem) bool {
+	return f == nil || f.expr.eval(itemFieldValues(item), f.now)
+}
+
+// filter checks if the values match the filter criteria
+func filter(fieldValue map[string]any, filters []models.Filter) bool {
+	f, err := newItemFilter(filters, "", time.Now())
 	if err != nil {
-		backend.Logger.Error("Failed to parse date "+d, err)
+		backend.Logger.Debug("invalid project filter", "error", err)
 		return false
 	}
-	return f(t)
+	return f == nil || f.expr.eval(fieldValue, f.now)
 }
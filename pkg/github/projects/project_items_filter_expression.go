@@ -0,0 +1,285 @@
+package projects
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// filterToken is a token of a filter expression: a word, a quoted string, an operator or a punctuation mark
+type filterToken struct {
+	text   string
+	quoted bool
+	pos    int
+}
+
+// is returns true when the token is the unquoted keyword or symbol, keywords are case insensitive
+func (t filterToken) is(s string) bool {
+	return !t.quoted && strings.EqualFold(t.text, s)
+}
+
+var filterOperators = []string{"!=", ">=", "<=", "=~", "!~", "=", ">", "<", "~"}
+
+// tokenizeFilter splits a filter expression into tokens.
+// Strings are quoted with double or single quotes, field names with spaces have to be quoted.
+func tokenizeFilter(s string) ([]filterToken, error) {
+	tokens := []filterToken{}
+	for i := 0; i < len(s); {
+		c, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, filterToken{text: string(c), pos: i})
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for ; end < len(s) && s[end] != s[i]; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			text := s[i+1 : end]
+			if c == '"' {
+				unquoted, err := strconv.Unquote(s[i : end+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid string at %d: %w", i, err)
+				}
+				text = unquoted
+			} else {
+				text = strings.ReplaceAll(text, `\'`, "'")
+			}
+			tokens = append(tokens, filterToken{text: text, quoted: true, pos: i})
+			i = end + 1
+		default:
+			op := ""
+			for _, o := range filterOperators {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op != "" {
+				tokens = append(tokens, filterToken{text: op, pos: i})
+				i += len(op)
+				continue
+			}
+
+			end := i
+			for end < len(s) {
+				r, size := utf8.DecodeRuneInString(s[end:])
+				if unicode.IsSpace(r) || strings.ContainsRune(`(),"'=!<>~`, r) {
+					break
+				}
+				end += size
+			}
+			// a character which starts neither a word nor an operator, like a ! not followed by = or ~
+			if end == i {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+			tokens = append(tokens, filterToken{text: s[i:end], pos: i})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser of filter expressions:
+//
+//	expression = term { "or" term }
+//	term       = factor { "and" factor }
+//	factor     = "not" factor | "(" expression ")" | condition
+//	condition  = key operator value
+//	           | key "is" [ "not" ] "empty"
+//	           | key [ "not" ] "in" "(" value { "," value } ")"
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// parseFilterExpression parses a filter expression like
+//
+//	(Status = "In Progress" or Status = Done) and Estimate >= 3 and closed_at > now-7d and Assignees is not empty
+func parseFilterExpression(s string) (filterExpr, error) {
+	tokens, err := tokenizeFilter(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+	return expr, nil
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) next() (filterToken, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, errors.New("unexpected end of the expression")
+	}
+	p.pos++
+	return t, nil
+}
+
+// accept consumes the next token when it is the keyword or symbol
+func (p *filterParser) accept(s string) bool {
+	if t, ok := p.peek(); ok && t.is(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(s string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if !t.is(s) {
+		return fmt.Errorf("expected %q at %d, got %q", s, t.pos, t.text)
+	}
+	return nil
+}
+
+func (p *filterParser) expression() (filterExpr, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) term() (filterExpr, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) factor() (filterExpr, error) {
+	if p.accept("not") {
+		expr, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	}
+	if p.accept("(") {
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+	return p.condition()
+}
+
+func (p *filterParser) condition() (filterExpr, error) {
+	key, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if !key.quoted && strings.ContainsAny(key.text, "(),") {
+		return nil, fmt.Errorf("expected a field name at %d, got %q", key.pos, key.text)
+	}
+
+	switch {
+	case p.accept("is"):
+		op := OpIsEmpty
+		if p.accept("not") {
+			op = OpIsNotEmpty
+		}
+		if err := p.expect("empty"); err != nil {
+			return nil, err
+		}
+		return newComparison(key.text, op, nil)
+	case p.accept("not"):
+		if err := p.expect("in"); err != nil {
+			return nil, err
+		}
+		return p.list(key.text, OpNotIn)
+	case p.accept("in"):
+		return p.list(key.text, OpIn)
+	}
+
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op.quoted {
+		return nil, fmt.Errorf("expected an operator at %d, got %q", op.pos, op.text)
+	}
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	return newComparison(key.text, op.text, []string{value})
+}
+
+func (p *filterParser) list(key string, op string) (filterExpr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	values := []string{}
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.accept(")") {
+			break
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+	return newComparison(key, op, values)
+}
+
+func (p *filterParser) value() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if !t.quoted && (t.text == "(" || t.text == ")" || t.text == ",") {
+		return "", fmt.Errorf("expected a value at %d, got %q", t.pos, t.text)
+	}
+	return t.text, nil
+}
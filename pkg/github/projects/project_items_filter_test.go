@@ -1,10 +1,16 @@
 package projects
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+	"github.com/shurcooL/githubv4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOrFiltersShouldMatchOne(t *testing.T) {
@@ -46,3 +52,208 @@ func TestAndFiltersShouldMatchAll(t *testing.T) {
 	match := filter(values, []models.Filter{filter1, filter2})
 	assert.False(t, match)
 }
+
+func TestMixedConjunctions(t *testing.T) {
+	values := map[string]any{
+		"Status": "Done",
+		"type":   "ISSUE",
+	}
+	// the query editor stores the conjunction shown after a filter on that filter, and none on the last one:
+	// Status = Todo or Status = Done and type = ISSUE is Status = Todo or (Status = Done and type = ISSUE)
+	filters := []models.Filter{
+		{Key: "Status", Value: "Todo", OP: "=", Conjunction: "or"},
+		{Key: "Status", Value: "Done", OP: "=", Conjunction: "and"},
+		{Key: "type", Value: "ISSUE", OP: "="},
+	}
+	assert.True(t, filter(values, filters))
+
+	values["type"] = "PULL_REQUEST"
+	assert.False(t, filter(values, filters))
+
+	values["Status"] = "Todo"
+	assert.True(t, filter(values, filters))
+
+	values["Status"] = "Done"
+	// Status = Done and type = ISSUE or Status = Todo is (Status = Done and type = ISSUE) or Status = Todo
+	filters = []models.Filter{
+		{Key: "Status", Value: "Done", OP: "=", Conjunction: "and"},
+		{Key: "type", Value: "ISSUE", OP: "=", Conjunction: "or"},
+		{Key: "Status", Value: "Todo", OP: "="},
+	}
+	assert.False(t, filter(values, filters))
+
+	values["Status"] = "Todo"
+	assert.True(t, filter(values, filters))
+}
+
+func filterTestValues() map[string]any {
+	status := "In Progress"
+	estimate := 5.0
+	title := "Fix the login page"
+	closed := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	return map[string]any{
+		"type":      "ISSUE",
+		"Status":    &status,
+		"Estimate":  &estimate,
+		"Title":     &title,
+		"closed_at": &closed,
+		"Assignees": (*string)(nil),
+	}
+}
+
+func TestFilterExpression(t *testing.T) {
+	now := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		match      bool
+	}{
+		{expression: `Status = "In Progress"`, match: true},
+		{expression: `Status != 'In Progress'`, match: false},
+		// numbers are compared by value, not as text
+		{expression: `Estimate > 10`, match: false},
+		{expression: `Estimate >= 5 and Estimate < 5.5`, match: true},
+		{expression: `Status in (Todo, "In Progress")`, match: true},
+		{expression: `Status not in (Todo, Done)`, match: true},
+		{expression: `Title =~ "^Fix (the )?login"`, match: true},
+		{expression: `Title !~ login`, match: false},
+		{expression: `Title ~ page`, match: true},
+		{expression: `Assignees is empty and Status is not empty`, match: true},
+		{expression: `Missing is empty`, match: true},
+		{expression: `closed_at > now-7d`, match: true},
+		{expression: `closed_at > now-1d`, match: false},
+		{expression: `closed_at < 2024-03-11`, match: true},
+		{expression: `(Status = Done or Estimate > 3) and type = ISSUE`, match: true},
+		{expression: `Status = Done or Estimate > 3 and type = PULL_REQUEST`, match: false},
+		{expression: `NOT (Status = Done) AND NOT Assignees IS NOT EMPTY`, match: true},
+		{expression: `"Status" = "In Progress"`, match: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			f, err := newItemFilter(nil, tt.expression, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.match, f.expr.eval(filterTestValues(), now))
+		})
+	}
+}
+
+func TestInvalidFilterExpression(t *testing.T) {
+	for _, expression := range []string{
+		`Status =`,
+		`(Status = Done`,
+		`Status = Done)`,
+		`Status == Done`,
+		`Status in Done`,
+		`Title =~ "("`,
+		`Status is missing`,
+		`Status = "Done`,
+		`!Status`,
+		`Status = !x`,
+		`a!b`,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := newItemFilter(nil, expression, time.Now())
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := newItemFilter([]models.Filter{{Key: "Status", OP: "=~", Value: "["}}, "", time.Now())
+	assert.Error(t, err)
+}
+
+func TestTokenizeFilterUnicode(t *testing.T) {
+	// the UTF-8 encodings of Ѕ and à end with the bytes 0x85 and 0xA0, which are spaces as runes of their own
+	tokens, err := tokenizeFilter(`Status = Ѕà`)
+	require.NoError(t, err)
+	require.Len(t, tokens, 3)
+	assert.Equal(t, "Ѕà", tokens[2].text)
+
+	tokens, err = tokenizeFilter("Status\u00a0=\u00a0Done")
+	require.NoError(t, err)
+	assert.Len(t, tokens, 3)
+}
+
+func TestParseFilterDate(t *testing.T) {
+	now := time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC)
+
+	for value, expected := range map[string]time.Time{
+		"now":        now,
+		"now-7d":     time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+		"now+2h":     time.Date(2024, 3, 12, 12, 0, 0, 0, time.UTC),
+		"now-1w":     time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+		"now-1M":     time.Date(2024, 2, 12, 10, 0, 0, 0, time.UTC),
+		"2024-01-02": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	} {
+		d, ok := parseFilterDate(value, now)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, d, value)
+	}
+
+	_, ok := parseFilterDate("yesterday", now)
+	assert.False(t, ok)
+}
+
+func TestGetAllProjectItemsFiltersWhilePaging(t *testing.T) {
+	var pages int
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryProject)
+		require.True(t, ok)
+		pages++
+
+		// only the items of the last page match the filter
+		itemType := "DRAFT_ISSUE"
+		if pages == 4 {
+			itemType = "ISSUE"
+		}
+		items := &query.Organization.ProjectV2.Items
+		for i := 0; i < pageSize; i++ {
+			items.Nodes = append(items.Nodes, ProjectItem{ID: fmt.Sprintf("PVTI_%d_%d", pages, i), Type: itemType})
+		}
+		items.PageInfo.HasNextPage = pages < 4
+		items.PageInfo.EndCursor = githubv4.String(fmt.Sprintf("cursor%d", pages))
+	}
+
+	client := testutil.NewTestClient(t, nil, testQuery)
+	items, err := GetAllProjectItems(context.Background(), client, models.ProjectOptions{
+		Organization:     "grafana",
+		Number:           1,
+		FilterExpression: "type = ISSUE",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, pages)
+	assert.Len(t, items.Items, pageSize)
+}
+
+func TestGetEveryProjectItem(t *testing.T) {
+	pagedClient := func(t *testing.T, total int) *testutil.TestClient {
+		var pages int
+		return testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+			query, ok := q.(*QueryProject)
+			require.True(t, ok)
+			pages++
+
+			items := &query.Organization.ProjectV2.Items
+			for i := 0; i < pageSize; i++ {
+				items.Nodes = append(items.Nodes, ProjectItem{ID: fmt.Sprintf("PVTI_%d_%d", pages, i), Type: "ISSUE"})
+			}
+			items.PageInfo.HasNextPage = pages < total
+			items.PageInfo.EndCursor = githubv4.String(fmt.Sprintf("cursor%d", pages))
+		})
+	}
+	opts := models.ProjectOptions{Organization: "grafana", Number: 1}
+
+	items, err := GetAllProjectItems(context.Background(), pagedClient(t, 5), opts)
+	require.NoError(t, err)
+	assert.Len(t, items.Items, PageNumberLimit*pageSize)
+	assert.True(t, items.Truncated)
+
+	items, err = GetEveryProjectItem(context.Background(), pagedClient(t, 5), opts)
+	require.NoError(t, err)
+	assert.Len(t, items.Items, 5*pageSize)
+	assert.False(t, items.Truncated)
+
+	items, err = GetEveryProjectItem(context.Background(), pagedClient(t, MaxFilteredPageNumber+1), opts)
+	require.NoError(t, err)
+	assert.Len(t, items.Items, MaxFilteredPageNumber*pageSize)
+	assert.True(t, items.Truncated)
+}
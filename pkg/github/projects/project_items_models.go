@@ -28,41 +28,49 @@ import (
 // }
 type QueryProject struct {
 	Organization struct {
-		ProjectV2 struct {
-			Fields struct {
-				TotalCount int64
-				Nodes      []Field
-				PageInfo   models.PageInfo
-			} `graphql:"fields(first: 100)"`
-			Items struct {
-				// Edges
-				TotalCount int64
-				Nodes      []ProjectItem
-				PageInfo   models.PageInfo
-			} `graphql:"items(first: 100, after: $cursor)"`
-		} `graphql:"projectV2(number: $number)"`
+		ProjectV2 ProjectV2Items `graphql:"projectV2(number: $number)"`
 	} `graphql:"organization(login: $login)"`
 }
 
 // QueryProjectByUser lists GitHub projects by User
 type QueryProjectByUser struct {
 	User struct {
-		ProjectV2 struct {
-			Fields struct {
-				TotalCount int64
-				Nodes      []Field
-				PageInfo   models.PageInfo
-			} `graphql:"fields(first: 100)"`
-			Items struct {
-				// Edges
-				TotalCount int64
-				Nodes      []ProjectItem
-				PageInfo   models.PageInfo
-			} `graphql:"items(first: 100, after: $cursor)"`
-		} `graphql:"projectV2(number: $number)"`
+		ProjectV2 ProjectV2Items `graphql:"projectV2(number: $number)"`
 	} `graphql:"user(login: $login)"`
 }
 
+// QueryProjectByRepository lists project items in a project linked to a repository
+type QueryProjectByRepository struct {
+	Repository struct {
+		ProjectV2 ProjectV2Items `graphql:"projectV2(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// QueryProjectByTeam lists project items in a project linked to a team
+type QueryProjectByTeam struct {
+	Organization struct {
+		Team struct {
+			ProjectV2 ProjectV2Items `graphql:"projectV2(number: $number)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// ProjectV2Items is a project along with its fields and a page of its items
+type ProjectV2Items struct {
+	ID     string
+	Fields struct {
+		TotalCount int64
+		Nodes      []Field
+		PageInfo   models.PageInfo
+	} `graphql:"fields(first: 100)"`
+	Items struct {
+		// Edges
+		TotalCount int64
+		Nodes      []ProjectItem
+		PageInfo   models.PageInfo
+	} `graphql:"items(first: 100, after: $cursor)"`
+}
+
 // ProjectItem is a GitHub project item
 type ProjectItem struct {
 	Content     ProjectV2ItemContent
@@ -76,9 +84,11 @@ type ProjectItem struct {
 
 // ProjectV2ItemContent contains Content for a ProjectItem
 type ProjectV2ItemContent struct {
-	DraftIssue  Content      `graphql:"... on DraftIssue"`
-	Issue       IssueContent `graphql:"... on Issue"`
-	PullRequest IssueContent `graphql:"... on PullRequest"`
+	DraftIssue         Content            `graphql:"... on DraftIssue"`
+	Issue              IssueContent       `graphql:"... on Issue"`
+	PullRequest        IssueContent       `graphql:"... on PullRequest"`
+	IssueDetails       IssueDetails       `graphql:"... on Issue"`
+	PullRequestDetails PullRequestDetails `graphql:"... on PullRequest"`
 }
 
 // Content of the ProjectItem
@@ -91,12 +101,34 @@ type Content struct {
 
 // IssueContent of the ProjectItem
 type IssueContent struct {
+	ID        string
+	Number    *int64
+	URL       *string
+	State     *string
 	Title     *string
 	Body      *string
 	CreatedAt *githubv4.DateTime
 	Assignees *Assignees `graphql:"assignees(first: 10)"`
 	Milestone *models.Milestone
 	ClosedAt  *githubv4.DateTime
+	Labels    *ProjectLabels `graphql:"labels(first: 20)"`
+}
+
+// IssueDetails are the fields of the content of the ProjectItem that only issues have
+type IssueDetails struct {
+	LinkedBranches struct {
+		Nodes []struct {
+			Ref *struct {
+				Name string
+			}
+		}
+	} `graphql:"linkedBranches(first: 10)"`
+}
+
+// PullRequestDetails are the fields of the content of the ProjectItem that only pull requests have
+type PullRequestDetails struct {
+	ReviewDecision *githubv4.PullRequestReviewDecision
+	HeadRefName    string
 }
 
 // Assignees to the ProjectItem
@@ -108,9 +140,11 @@ type Assignees struct {
 
 // ProjectItemsWithFields ...
 type ProjectItemsWithFields struct {
-	Items   []ProjectItem
-	Fields  []Field
-	Filters []models.Filter
+	ProjectID string
+	Items     []ProjectItem
+	Fields    []Field
+	// Truncated is true when the project has more items than were listed
+	Truncated bool
 }
 
 // FieldValues are the values of each Field of a ProjectItem
@@ -122,7 +156,32 @@ type FieldValues struct {
 
 // Field is a field on a ProjectItem
 type Field struct {
-	Common ProjectV2FieldCommon `graphql:"... on ProjectV2FieldCommon"`
+	Common       ProjectV2FieldCommon       `graphql:"... on ProjectV2FieldCommon"`
+	Iteration    ProjectV2IterationField    `graphql:"... on ProjectV2IterationField"`
+	SingleSelect ProjectV2SingleSelectField `graphql:"... on ProjectV2SingleSelectField"`
+}
+
+// ProjectV2SingleSelectField lists the options of a SingleSelect field in the order they are shown in the project
+type ProjectV2SingleSelectField struct {
+	Options []struct {
+		Name string
+	}
+}
+
+// ProjectV2IterationField is the configuration of an Iteration field
+type ProjectV2IterationField struct {
+	Configuration struct {
+		Iterations          []ProjectV2Iteration
+		CompletedIterations []ProjectV2Iteration
+	}
+}
+
+// ProjectV2Iteration is an iteration of an Iteration field
+type ProjectV2Iteration struct {
+	ID        string
+	Title     string
+	StartDate string
+	Duration  int64
 }
 
 // FieldValue is a value for a Field
@@ -183,8 +242,11 @@ type ProjectLabel struct {
 
 // ProjectV2ItemFieldIterationValue is a value for an Iteration field
 type ProjectV2ItemFieldIterationValue struct {
-	Title *string
-	Field CommonField
+	Title       *string
+	IterationID *string `graphql:"iterationId"`
+	StartDate   *string
+	Duration    *int64
+	Field       CommonField
 }
 
 // ProjectV2ItemFieldSingleSelectValue is a value for a SingleSelect field
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/github-datasource/pkg/models"
 	"github.com/grafana/github-datasource/pkg/testutil"
 	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetAllProjectItems(t *testing.T) {
@@ -88,8 +89,60 @@ func TestProjectItemsDataFrame(t *testing.T) {
 				},
 			},
 		},
-		Filters: []models.Filter{},
 	}
 
 	testutil.CheckGoldenFramer(t, "project", project)
 }
+
+func TestGetAllProjectItemsLinkedToRepository(t *testing.T) {
+	opts := models.ProjectOptions{
+		User:       "octocat",
+		Repository: "hello-world",
+		Number:     1,
+		Kind:       models.ProjectKindRepository,
+	}
+
+	client := testutil.NewTestClient(t,
+		testutil.GetTestVariablesFunction("owner", "name", "number"),
+		testutil.GetTestQueryFunction(&QueryProjectByRepository{}),
+	)
+
+	_, err := GetAllProjectItems(context.Background(), client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectItemContentFields(t *testing.T) {
+	var (
+		number   int64 = 12
+		state          = "OPEN"
+		decision       = githubv4.PullRequestReviewDecisionChangesRequested
+	)
+
+	pr := ProjectV2ItemContent{
+		PullRequest: IssueContent{
+			Number: &number,
+			State:  &state,
+			Labels: &ProjectLabels{Nodes: []ProjectLabel{{Name: "bug"}, {Name: "backend"}}},
+		},
+		PullRequestDetails: PullRequestDetails{ReviewDecision: &decision, HeadRefName: "fix-login"},
+	}
+	values := itemFieldValues(ProjectItem{Content: pr})
+	assert.Equal(t, &number, values["number"])
+	assert.Equal(t, "OPEN", *values["state"].(*string))
+	assert.Equal(t, "bug,backend", *values["labels"].(*string))
+	assert.Equal(t, "CHANGES_REQUESTED", *values["review_decision"].(*string))
+	assert.Equal(t, "fix-login", *values["linked_branches"].(*string))
+
+	issue := ProjectV2ItemContent{}
+	issue.IssueDetails.LinkedBranches.Nodes = []struct {
+		Ref *struct {
+			Name string
+		}
+	}{{Ref: &struct{ Name string }{Name: "123-fix"}}, {}}
+	values = itemFieldValues(ProjectItem{Content: issue})
+	assert.Equal(t, "123-fix", *values["linked_branches"].(*string))
+	assert.Nil(t, values["review_decision"])
+	assert.Nil(t, values["labels"])
+}
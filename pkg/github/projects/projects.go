@@ -14,6 +14,12 @@ import (
 // PageNumberLimit is the limit on the number of pages that will be traversed
 const PageNumberLimit = 2
 
+// MaxFilteredPageNumber is the limit on the number of pages of project items that will be traversed to find PageNumberLimit pages of items matching the filters
+const MaxFilteredPageNumber = 20
+
+// pageSize is the number of project items requested per page
+const pageSize = 100
+
 // QueryListProjects lists all projects in a repository
 // organization(login: "grafana") {
 // 	projectsV2(first: 100) {
@@ -26,23 +32,49 @@ const PageNumberLimit = 2
 // }
 type QueryListProjects struct {
 	Organization struct {
-		ProjectsV2 struct {
-			Nodes    []Project
-			PageInfo models.PageInfo
-		} `graphql:"projectsV2(first: 100, after: $cursor)"`
+		ProjectsV2 ProjectsPage `graphql:"projectsV2(first: 100, after: $cursor)"`
 	} `graphql:"organization(login: $login)"`
 }
 
 // QueryListProjectsByUser lists all projects by user
 type QueryListProjectsByUser struct {
 	User struct {
-		ProjectsV2 struct {
-			Nodes    []Project
-			PageInfo models.PageInfo
-		} `graphql:"projectsV2(first: 100, after: $cursor)"`
+		ProjectsV2 ProjectsPage `graphql:"projectsV2(first: 100, after: $cursor)"`
 	} `graphql:"user(login: $login)"`
 }
 
+// QueryListProjectsByRepository lists all projects linked to a repository
+type QueryListProjectsByRepository struct {
+	Repository struct {
+		ProjectsV2 ProjectsPage `graphql:"projectsV2(first: 100, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// QueryListProjectsByTeam lists all projects linked to a team
+type QueryListProjectsByTeam struct {
+	Organization struct {
+		Team struct {
+			ProjectsV2 ProjectsPage `graphql:"projectsV2(first: 100, after: $cursor)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// ProjectsPage is a page of projects
+type ProjectsPage struct {
+	Nodes    []Project
+	PageInfo models.PageInfo
+}
+
+// listProjectsQuery is a query listing the projects of an organization, a user, a repository or a team
+type listProjectsQuery interface {
+	page() *ProjectsPage
+}
+
+func (q *QueryListProjects) page() *ProjectsPage             { return &q.Organization.ProjectsV2 }
+func (q *QueryListProjectsByUser) page() *ProjectsPage       { return &q.User.ProjectsV2 }
+func (q *QueryListProjectsByRepository) page() *ProjectsPage { return &q.Repository.ProjectsV2 }
+func (q *QueryListProjectsByTeam) page() *ProjectsPage       { return &q.Organization.Team.ProjectsV2 }
+
 // Project is a GitHub project
 type Project struct {
 	Number           int64
@@ -104,67 +136,46 @@ func (p Projects) Frames() data.Frames {
 	return data.Frames{frame}
 }
 
-// GetAllProjects uses the graphql endpoint API to list all projects in the repository
+// GetAllProjects uses the graphql endpoint API to list all projects of the organization or the user, or linked to the repository or the team
 func GetAllProjects(ctx context.Context, client models.Client, opts models.ProjectOptions) (Projects, error) {
-	if opts.Kind == 0 {
-		return getAllProjectsByOrg(ctx, client, opts)
+	switch opts.Kind {
+	case models.ProjectKindOrganization:
+		return listProjects(ctx, client, map[string]interface{}{
+			"login": githubv4.String(opts.Organization),
+		}, func() listProjectsQuery { return &QueryListProjects{} })
+	case models.ProjectKindRepository:
+		return listProjects(ctx, client, map[string]interface{}{
+			"owner": githubv4.String(opts.RepositoryOwner()),
+			"name":  githubv4.String(opts.Repository),
+		}, func() listProjectsQuery { return &QueryListProjectsByRepository{} })
+	case models.ProjectKindTeam:
+		return listProjects(ctx, client, map[string]interface{}{
+			"login": githubv4.String(opts.Organization),
+			"slug":  githubv4.String(opts.Team),
+		}, func() listProjectsQuery { return &QueryListProjectsByTeam{} })
 	}
-	return getAllProjectsByUser(ctx, client, opts)
+	return listProjects(ctx, client, map[string]interface{}{
+		"login": githubv4.String(opts.User),
+	}, func() listProjectsQuery { return &QueryListProjectsByUser{} })
 }
 
-func getAllProjectsByOrg(ctx context.Context, client models.Client, opts models.ProjectOptions) (Projects, error) {
-	var (
-		variables = map[string]interface{}{
-			"cursor": (*githubv4.String)(nil),
-			"login":  githubv4.String(opts.Organization),
-		}
-
-		projects = Projects{}
-	)
-
-	for i := 0; i < PageNumberLimit; i++ {
-		q := &QueryListProjects{}
-		if err := client.Query(ctx, q, variables); err != nil {
-			return nil, errors.WithStack(err)
-		}
-
-		projectList := make(Projects, len(q.Organization.ProjectsV2.Nodes))
-		copy(projectList, q.Organization.ProjectsV2.Nodes)
-		projects = append(projects, projectList...)
-
-		if !q.Organization.ProjectsV2.PageInfo.HasNextPage {
-			break
-		}
-		variables["cursor"] = q.Organization.ProjectsV2.PageInfo.EndCursor
-	}
-
-	return projects, nil
-}
-
-func getAllProjectsByUser(ctx context.Context, client models.Client, opts models.ProjectOptions) (Projects, error) {
-	var (
-		variables = map[string]interface{}{
-			"cursor": (*githubv4.String)(nil),
-			"login":  githubv4.String(opts.User),
-		}
-
-		projects = Projects{}
-	)
+func listProjects(ctx context.Context, client models.Client, variables map[string]interface{}, newQuery func() listProjectsQuery) (Projects, error) {
+	variables["cursor"] = (*githubv4.String)(nil)
+	projects := Projects{}
 
 	for i := 0; i < PageNumberLimit; i++ {
-		q := &QueryListProjectsByUser{}
+		q := newQuery()
 		if err := client.Query(ctx, q, variables); err != nil {
 			return nil, errors.WithStack(err)
 		}
 
-		projectList := make(Projects, len(q.User.ProjectsV2.Nodes))
-		copy(projectList, q.User.ProjectsV2.Nodes)
-		projects = append(projects, projectList...)
+		page := q.page()
+		projects = append(projects, page.Nodes...)
 
-		if !q.User.ProjectsV2.PageInfo.HasNextPage {
+		if !page.PageInfo.HasNextPage {
 			break
 		}
-		variables["cursor"] = q.User.ProjectsV2.PageInfo.EndCursor
+		variables["cursor"] = page.PageInfo.EndCursor
 	}
 
 	return projects, nil
@@ -51,3 +51,39 @@ func TestProjectsDataFrame(t *testing.T) {
 
 	testutil.CheckGoldenFramer(t, "projects", projects)
 }
+
+func TestGetAllProjectsLinkedToRepository(t *testing.T) {
+	opts := models.ProjectOptions{
+		Organization: "grafana",
+		Repository:   "grafana",
+		Kind:         models.ProjectKindRepository,
+	}
+
+	client := testutil.NewTestClient(t,
+		testutil.GetTestVariablesFunction("owner", "name"),
+		testutil.GetTestQueryFunction(&QueryListProjectsByRepository{}),
+	)
+
+	_, err := GetAllProjects(context.Background(), client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAllProjectsLinkedToTeam(t *testing.T) {
+	opts := models.ProjectOptions{
+		Organization: "grafana",
+		Team:         "backend",
+		Kind:         models.ProjectKindTeam,
+	}
+
+	client := testutil.NewTestClient(t,
+		testutil.GetTestVariablesFunction("login", "slug"),
+		testutil.GetTestQueryFunction(&QueryListProjectsByTeam{}),
+	)
+
+	_, err := GetAllProjects(context.Background(), client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
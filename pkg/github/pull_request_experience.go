@@ -0,0 +1,356 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// Contributor groups compared by the contributor experience
+const (
+	ContributorExternal = "external"
+	ContributorMember   = "member"
+)
+
+// MaxFirstPullRequestLookups is the number of external authors whose first pull request is searched for, the other ones are reported as unknown
+const MaxFirstPullRequestLookups = 50
+
+// IsMemberAssociation returns true when the author of a pull request is an owner, a member or a collaborator of the repository
+func IsMemberAssociation(association githubv4.CommentAuthorAssociation) bool {
+	switch association {
+	case githubv4.CommentAuthorAssociationOwner, githubv4.CommentAuthorAssociationMember, githubv4.CommentAuthorAssociationCollaborator:
+		return true
+	}
+	return false
+}
+
+// PullRequestExperience is a pull request along with the time someone other than its author first responded to it
+type PullRequestExperience struct {
+	PullRequest
+	// FirstResponseAt is nil when nobody but the author commented on or reviewed the pull request
+	FirstResponseAt *time.Time
+	// FirstTimeContributor is true when this is the earliest pull request of an external author in the repository, and nil when it wasn't looked up.
+	// The author association can't tell, because GitHub computes it when it is queried: once merged, a first pull request is from a contributor.
+	FirstTimeContributor *bool
+}
+
+// TimeToFirstResponse returns the number of seconds between the creation of the pull request and its first response
+func (p PullRequestExperience) TimeToFirstResponse() *float64 {
+	if p.FirstResponseAt == nil {
+		return nil
+	}
+	return durationSeconds(p.CreatedAt.Time, *p.FirstResponseAt)
+}
+
+// ContributorExperience is a list of pull requests along with their first responses
+type ContributorExperience []PullRequestExperience
+
+// ContributorGroupExperience is how fast the pull requests of a group of contributors get a response and how often they get merged
+type ContributorGroupExperience struct {
+	Group                     string
+	PullRequests              int64
+	FirstTimeContributors     int64
+	Merged                    int64
+	ClosedWithoutMerge        int64
+	Responded                 int64
+	MedianTimeToFirstResponse *float64
+	MeanTimeToFirstResponse   *float64
+}
+
+// MergeRate returns the share of the closed pull requests of the group that were merged, or nil if none is closed
+func (g ContributorGroupExperience) MergeRate() *float64 {
+	closed := g.Merged + g.ClosedWithoutMerge
+	if closed == 0 {
+		return nil
+	}
+	rate := float64(g.Merged) / float64(closed)
+	return &rate
+}
+
+// Groups compares the experience of external contributors with the one of members
+func (c ContributorExperience) Groups() []ContributorGroupExperience {
+	groups := []ContributorGroupExperience{}
+	for _, group := range []string{ContributorExternal, ContributorMember} {
+		g := ContributorGroupExperience{Group: group}
+		responses := []float64{}
+
+		for _, pr := range c {
+			if IsMemberAssociation(pr.AuthorAssociation) != (group == ContributorMember) {
+				continue
+			}
+
+			g.PullRequests++
+			if pr.FirstTimeContributor != nil && *pr.FirstTimeContributor {
+				g.FirstTimeContributors++
+			}
+			switch {
+			case pr.Merged:
+				g.Merged++
+			case pr.Closed:
+				g.ClosedWithoutMerge++
+			}
+			if v := pr.TimeToFirstResponse(); v != nil {
+				g.Responded++
+				responses = append(responses, *v)
+			}
+		}
+
+		if len(responses) > 0 {
+			sort.Float64s(responses)
+			median := responses[len(responses)/2]
+			if len(responses)%2 == 0 {
+				median = (responses[len(responses)/2-1] + median) / 2
+			}
+
+			var sum float64
+			for _, v := range responses {
+				sum += v
+			}
+			mean := sum / float64(len(responses))
+
+			g.MedianTimeToFirstResponse, g.MeanTimeToFirstResponse = &median, &mean
+		}
+
+		groups = append(groups, g)
+	}
+
+	return groups
+}
+
+// Frames converts the pull requests to the pull requests frame with the contributor experience fields,
+// followed by the comparison of external contributors with members
+func (c ContributorExperience) Frames() data.Frames {
+	prs := make(PullRequests, len(c))
+	var (
+		associations        = make([]string, len(c))
+		firstTime           = make([]*bool, len(c))
+		timeToFirstResponse = make([]*float64, len(c))
+	)
+	for i, pr := range c {
+		prs[i] = pr.PullRequest
+		associations[i] = string(pr.AuthorAssociation)
+		firstTime[i] = pr.FirstTimeContributor
+		timeToFirstResponse[i] = pr.TimeToFirstResponse()
+	}
+
+	frames := prs.Frames()
+	responseField := data.NewField("time_to_first_response", nil, timeToFirstResponse)
+	responseField.Config = &data.FieldConfig{
+		Unit: "s",
+	}
+	frames[0].Fields = append(frames[0].Fields,
+		data.NewField("author_association", nil, associations),
+		data.NewField("is_first_time_contributor", nil, firstTime),
+		responseField,
+	)
+
+	groups := data.NewFrame(
+		"pull_request_contributor_experience",
+		data.NewField("group", nil, []string{}),
+		data.NewField("pull_requests", nil, []int64{}),
+		data.NewField("first_time_contributors", nil, []int64{}),
+		data.NewField("merged", nil, []int64{}),
+		data.NewField("closed_without_merge", nil, []int64{}),
+		data.NewField("merge_rate", nil, []*float64{}),
+		data.NewField("responded", nil, []int64{}),
+		data.NewField("median_time_to_first_response", nil, []*float64{}),
+		data.NewField("mean_time_to_first_response", nil, []*float64{}),
+	)
+	for _, g := range c.Groups() {
+		groups.AppendRow(
+			g.Group,
+			g.PullRequests,
+			g.FirstTimeContributors,
+			g.Merged,
+			g.ClosedWithoutMerge,
+			g.MergeRate(),
+			g.Responded,
+			g.MedianTimeToFirstResponse,
+			g.MeanTimeToFirstResponse,
+		)
+	}
+
+	return append(frames, groups)
+}
+
+type responseActor struct {
+	Typename string `graphql:"__typename"`
+	Login    string
+}
+
+// QueryPullRequestResponses is the GraphQL query for retrieving the first comments and reviews of a batch of pull requests
+//
+//	{
+//	  nodes(ids: ["PR_kwDO"]) {
+//	    ... on PullRequest {
+//	      id
+//	      timelineItems(first: 20, itemTypes: [ISSUE_COMMENT, PULL_REQUEST_REVIEW]) {
+//	        nodes {
+//	          ... on IssueComment {
+//	            createdAt
+//	            author {
+//	              login
+//	            }
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryPullRequestResponses struct {
+	Nodes []struct {
+		PullRequest struct {
+			ID            string
+			TimelineItems struct {
+				Nodes []struct {
+					IssueComment struct {
+						CreatedAt githubv4.DateTime
+						Author    *responseActor
+					} `graphql:"... on IssueComment"`
+					PullRequestReview struct {
+						SubmittedAt *githubv4.DateTime
+						Author      *responseActor
+					} `graphql:"... on PullRequestReview"`
+				}
+			} `graphql:"timelineItems(first: 20, itemTypes: [ISSUE_COMMENT, PULL_REQUEST_REVIEW])"`
+		} `graphql:"... on PullRequest"`
+	} `graphql:"nodes(ids: $ids)"`
+}
+
+// isResponder returns true when the actor responded to a pull request opened by the author: bots and the author don't count
+func (a *responseActor) isResponder(author string) bool {
+	return a != nil && a.Typename != "Bot" && a.Login != author
+}
+
+// QueryFirstPullRequest is the GraphQL query for finding the earliest pull request of an author in a repository
+//
+//	{
+//	  search(query: "repo:grafana/grafana is:pr author:octocat sort:created-asc", type: ISSUE, first: 1) {
+//	    nodes {
+//	      ... on PullRequest {
+//	        id
+//	      }
+//	    }
+//	  }
+//	}
+type QueryFirstPullRequest struct {
+	Search struct {
+		Nodes []struct {
+			PullRequest struct {
+				ID string
+			} `graphql:"... on PullRequest"`
+		}
+	} `graphql:"search(query: $query, type: ISSUE, first: 1)"`
+}
+
+// firstPullRequestKey returns the author and repository to search the first pull request of, and false when the pull request isn't looked up.
+// The pull requests of members and of apps are not looked up: the author search doesn't match apps, which have no user login.
+func firstPullRequestKey(pr PullRequest) (string, bool) {
+	login, repo := pr.Author.User.Login, pr.Repository.NameWithOwner
+	if login == "" || repo == "" || IsMemberAssociation(pr.AuthorAssociation) {
+		return "", false
+	}
+	return strings.ToLower(repo + "/" + login), true
+}
+
+// getFirstPullRequests returns the IDs of the pull requests that are the earliest pull request of their author in their repository,
+// along with the authors and repositories that were searched.
+// Only external authors are looked up, with a search per author and repository for the first MaxFirstPullRequestLookups of them.
+func getFirstPullRequests(ctx context.Context, client models.Client, prs PullRequests) (map[string]bool, map[string]bool, error) {
+	var (
+		first  = map[string]bool{}
+		looked = map[string]bool{}
+	)
+	for _, pr := range prs {
+		key, ok := firstPullRequestKey(pr)
+		if !ok || looked[key] {
+			continue
+		}
+		if len(looked) == MaxFirstPullRequestLookups {
+			break
+		}
+		looked[key] = true
+
+		q := &QueryFirstPullRequest{}
+		variables := map[string]interface{}{
+			"query": githubv4.String(fmt.Sprintf("repo:%s is:pr author:%s sort:created-asc", pr.Repository.NameWithOwner, pr.Author.User.Login)),
+		}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		for _, n := range q.Search.Nodes {
+			first[n.PullRequest.ID] = true
+		}
+	}
+	return first, looked, nil
+}
+
+// GetContributorExperience finds the first response to every pull request, in batches of 100 pull requests,
+// and whether it is the first pull request of its author in the repository.
+// Only the first 20 comments and reviews of a pull request are looked at.
+func GetContributorExperience(ctx context.Context, client models.Client, prs PullRequests) (ContributorExperience, error) {
+	responses := map[string]time.Time{}
+
+	for start := 0; start < len(prs); start += 100 {
+		end := start + 100
+		if end > len(prs) {
+			end = len(prs)
+		}
+
+		ids := make([]githubv4.ID, 0, end-start)
+		authors := map[string]string{}
+		for _, pr := range prs[start:end] {
+			ids = append(ids, githubv4.ID(pr.ID))
+			authors[pr.ID] = pr.Author.User.Login
+		}
+
+		q := &QueryPullRequestResponses{}
+		if err := client.Query(ctx, q, map[string]interface{}{"ids": ids}); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, node := range q.Nodes {
+			pr := node.PullRequest
+			for _, item := range pr.TimelineItems.Nodes {
+				var at time.Time
+				switch {
+				case item.IssueComment.Author.isResponder(authors[pr.ID]) && !item.IssueComment.CreatedAt.IsZero():
+					at = item.IssueComment.CreatedAt.Time
+				case item.PullRequestReview.Author.isResponder(authors[pr.ID]) && item.PullRequestReview.SubmittedAt != nil:
+					at = item.PullRequestReview.SubmittedAt.Time
+				default:
+					continue
+				}
+				if first, ok := responses[pr.ID]; !ok || at.Before(first) {
+					responses[pr.ID] = at
+				}
+			}
+		}
+	}
+
+	first, looked, err := getFirstPullRequests(ctx, client, prs)
+	if err != nil {
+		return nil, err
+	}
+
+	experience := make(ContributorExperience, len(prs))
+	for i, pr := range prs {
+		experience[i] = PullRequestExperience{PullRequest: pr}
+		if key, ok := firstPullRequestKey(pr); !ok || looked[key] {
+			isFirst := first[pr.ID]
+			experience[i].FirstTimeContributor = &isFirst
+		}
+		if at, ok := responses[pr.ID]; ok {
+			experience[i].FirstResponseAt = &at
+		}
+	}
+	return experience, nil
+}
@@ -0,0 +1,221 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func contributorExperienceTestPullRequests(t *testing.T) PullRequests {
+	t.Helper()
+
+	openedAt, err := time.Parse(time.RFC3339, "2020-08-25T16:21:56+00:00")
+	require.NoError(t, err)
+
+	pr := func(id string, number int64, login string, association githubv4.CommentAuthorAssociation, merged bool) PullRequest {
+		p := PullRequest{
+			ID:                id,
+			Number:            number,
+			Title:             "PullRequest " + id,
+			State:             githubv4.PullRequestStateClosed,
+			Author:            PullRequestAuthor{User: models.User{Login: login}},
+			AuthorAssociation: association,
+			Closed:            true,
+			Merged:            merged,
+			CreatedAt:         githubv4.DateTime{Time: openedAt},
+			ClosedAt:          githubv4.DateTime{Time: openedAt.Add(48 * time.Hour)},
+			UpdatedAt:         githubv4.DateTime{Time: openedAt.Add(48 * time.Hour)},
+			Repository:        Repository{NameWithOwner: "grafana/grafana"},
+		}
+		if merged {
+			p.MergedAt = p.ClosedAt
+		}
+		return p
+	}
+
+	return PullRequests{
+		// the first pull request of the newcomer, merged, so GitHub reports them as a contributor
+		pr("PR_1", 1, "newcomer", githubv4.CommentAuthorAssociationContributor, true),
+		pr("PR_2", 2, "outsider", githubv4.CommentAuthorAssociationContributor, false),
+		pr("PR_3", 3, "maintainer", githubv4.CommentAuthorAssociationMember, true),
+	}
+}
+
+type responseNode = struct {
+	IssueComment struct {
+		CreatedAt githubv4.DateTime
+		Author    *responseActor
+	} `graphql:"... on IssueComment"`
+	PullRequestReview struct {
+		SubmittedAt *githubv4.DateTime
+		Author      *responseActor
+	} `graphql:"... on PullRequestReview"`
+}
+
+func responseComment(login string, typename string, at time.Time) responseNode {
+	n := responseNode{}
+	n.IssueComment.CreatedAt = githubv4.DateTime{Time: at}
+	n.IssueComment.Author = &responseActor{Typename: typename, Login: login}
+	return n
+}
+
+func responseReview(login string, at time.Time) responseNode {
+	n := responseNode{}
+	n.PullRequestReview.SubmittedAt = &githubv4.DateTime{Time: at}
+	n.PullRequestReview.Author = &responseActor{Typename: "User", Login: login}
+	return n
+}
+
+func TestGetContributorExperience(t *testing.T) {
+	prs := contributorExperienceTestPullRequests(t)
+	openedAt := prs[0].CreatedAt.Time
+
+	searches := []string{}
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		if query, ok := variables["query"]; ok {
+			searches = append(searches, string(query.(githubv4.String)))
+			return
+		}
+		testutil.EnsureKeysAreSet(t, variables, "ids")
+		assert.Equal(t, []githubv4.ID{"PR_1", "PR_2", "PR_3"}, variables["ids"])
+	}
+	testQuery := func(t *testing.T, q interface{}) {
+		if search, ok := q.(*QueryFirstPullRequest); ok {
+			// the outsider opened an earlier pull request, the newcomer didn't
+			id := "PR_0"
+			if len(searches) == 1 {
+				id = "PR_1"
+			}
+			search.Search.Nodes = make([]struct {
+				PullRequest struct {
+					ID string
+				} `graphql:"... on PullRequest"`
+			}, 1)
+			search.Search.Nodes[0].PullRequest.ID = id
+			return
+		}
+
+		query, ok := q.(*QueryPullRequestResponses)
+		require.True(t, ok)
+
+		query.Nodes = make([]struct {
+			PullRequest struct {
+				ID            string
+				TimelineItems struct {
+					Nodes []responseNode
+				} `graphql:"timelineItems(first: 20, itemTypes: [ISSUE_COMMENT, PULL_REQUEST_REVIEW])"`
+			} `graphql:"... on PullRequest"`
+		}, 3)
+
+		query.Nodes[0].PullRequest.ID = "PR_1"
+		query.Nodes[0].PullRequest.TimelineItems.Nodes = []responseNode{
+			// the author and bots don't count as a response
+			responseComment("newcomer", "User", openedAt.Add(time.Minute)),
+			responseComment("github-actions", "Bot", openedAt.Add(2*time.Minute)),
+			responseReview("maintainer", openedAt.Add(3*time.Hour)),
+			responseComment("maintainer", "User", openedAt.Add(time.Hour)),
+		}
+		query.Nodes[1].PullRequest.ID = "PR_2"
+		query.Nodes[2].PullRequest.ID = "PR_3"
+		query.Nodes[2].PullRequest.TimelineItems.Nodes = []responseNode{
+			responseReview("reviewer", openedAt.Add(30*time.Minute)),
+		}
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+	experience, err := GetContributorExperience(context.Background(), client, prs)
+	require.NoError(t, err)
+
+	// members are not looked up
+	assert.Equal(t, []string{
+		"repo:grafana/grafana is:pr author:newcomer sort:created-asc",
+		"repo:grafana/grafana is:pr author:outsider sort:created-asc",
+	}, searches)
+
+	require.Len(t, experience, 3)
+	require.NotNil(t, experience[0].FirstTimeContributor)
+	assert.True(t, *experience[0].FirstTimeContributor)
+	require.NotNil(t, experience[1].FirstTimeContributor)
+	assert.False(t, *experience[1].FirstTimeContributor)
+	assert.Equal(t, 3600.0, *experience[0].TimeToFirstResponse())
+	assert.Nil(t, experience[1].TimeToFirstResponse())
+	assert.Equal(t, 1800.0, *experience[2].TimeToFirstResponse())
+
+	groups := experience.Groups()
+	require.Len(t, groups, 2)
+
+	external := groups[0]
+	assert.Equal(t, ContributorExternal, external.Group)
+	assert.Equal(t, int64(2), external.PullRequests)
+	assert.Equal(t, int64(1), external.FirstTimeContributors)
+	assert.Equal(t, 0.5, *external.MergeRate())
+	assert.Equal(t, int64(1), external.Responded)
+	assert.Equal(t, 3600.0, *external.MedianTimeToFirstResponse)
+
+	member := groups[1]
+	assert.Equal(t, ContributorMember, member.Group)
+	assert.Equal(t, int64(1), member.PullRequests)
+	assert.Equal(t, 1.0, *member.MergeRate())
+
+	testutil.CheckGoldenFramer(t, "pull_request_contributor_experience", experience)
+}
+
+func TestGetContributorExperienceLookupLimit(t *testing.T) {
+	prs := PullRequests{}
+	for i := 0; i <= MaxFirstPullRequestLookups; i++ {
+		prs = append(prs, PullRequest{
+			ID:                fmt.Sprintf("PR_%d", i),
+			Author:            PullRequestAuthor{User: models.User{Login: fmt.Sprintf("outsider%d", i)}},
+			AuthorAssociation: githubv4.CommentAuthorAssociationNone,
+			Repository:        Repository{NameWithOwner: "grafana/grafana"},
+		})
+	}
+	// an app has no user login, the author search doesn't match it
+	prs = append(prs, PullRequest{ID: "PR_bot", AuthorAssociation: githubv4.CommentAuthorAssociationNone, Repository: Repository{NameWithOwner: "grafana/grafana"}})
+
+	searches := 0
+	client := testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		if _, ok := q.(*QueryFirstPullRequest); ok {
+			searches++
+		}
+	})
+	experience, err := GetContributorExperience(context.Background(), client, prs)
+	require.NoError(t, err)
+
+	assert.Equal(t, MaxFirstPullRequestLookups, searches)
+	require.NotNil(t, experience[0].FirstTimeContributor)
+	assert.Nil(t, experience[MaxFirstPullRequestLookups].FirstTimeContributor)
+	require.NotNil(t, experience[len(experience)-1].FirstTimeContributor)
+	assert.False(t, *experience[len(experience)-1].FirstTimeContributor)
+}
+
+func TestContributorGroupExperience(t *testing.T) {
+	first := time.Date(2020, 8, 25, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := first.Add(d)
+		return &t
+	}
+	pr := PullRequest{AuthorAssociation: githubv4.CommentAuthorAssociationNone, CreatedAt: githubv4.DateTime{Time: first}}
+
+	groups := ContributorExperience{
+		{PullRequest: pr, FirstResponseAt: at(time.Second)},
+		{PullRequest: pr, FirstResponseAt: at(3 * time.Second)},
+		{PullRequest: pr, FirstResponseAt: at(5 * time.Second)},
+		{PullRequest: pr, FirstResponseAt: at(11 * time.Second)},
+	}.Groups()
+
+	assert.Equal(t, 4.0, *groups[0].MedianTimeToFirstResponse)
+	assert.Equal(t, 5.0, *groups[0].MeanTimeToFirstResponse)
+	// none of the pull requests is closed
+	assert.Nil(t, groups[0].MergeRate())
+	assert.Equal(t, int64(0), groups[1].PullRequests)
+	assert.Nil(t, groups[1].MedianTimeToFirstResponse)
+}
@@ -156,11 +156,29 @@ func (prs PullRequestReviews) Frames() data.Frames {
 // GetAllPullRequestReviews uses the graphql search endpoint API to search all pull requests in the repository
 // and all reviews for those pull requests.
 func GetAllPullRequestReviews(ctx context.Context, client models.Client, opts models.ListPullRequestsOptions) (PullRequestReviews, error) {
+	pullRequestReviews := PullRequestReviews{}
+
+	// A team is expanded into several searches, and a pull request can match more than one of them
+	for _, query := range teamSearchQueries(buildQuery(opts), opts.TeamSearchOptions) {
+		prs, err := searchPullRequestReviews(ctx, client, query)
+		if err != nil {
+			return nil, err
+		}
+		pullRequestReviews = append(pullRequestReviews, prs...)
+	}
+
+	return dedupeResults(pullRequestReviews, func(pr PullRequestWithReviews) string {
+		return fmt.Sprintf("%s#%d", pr.Repository.NameWithOwner, pr.Number)
+	}), nil
+}
+
+// searchPullRequestReviews returns every pull request matching the search query along with their reviews
+func searchPullRequestReviews(ctx context.Context, client models.Client, query string) (PullRequestReviews, error) {
 	var (
 		variables = map[string]interface{}{
 			"prCursor":     (*githubv4.String)(nil),
 			"reviewCursor": (*githubv4.String)(nil),
-			"query":        githubv4.String(buildQuery(opts)),
+			"query":        githubv4.String(query),
 		}
 
 		pullRequestReviews = PullRequestReviews{}
@@ -236,9 +254,10 @@ func GetPullRequestReviewsInRange(ctx context.Context, client models.Client, opt
 	}
 
 	return GetAllPullRequestReviews(ctx, client, models.ListPullRequestsOptions{
-		Repository: opts.Repository,
-		Owner:      opts.Owner,
-		TimeField:  opts.TimeField,
-		Query:      &q,
+		Repository:        opts.Repository,
+		Owner:             opts.Owner,
+		TimeField:         opts.TimeField,
+		Query:             &q,
+		TeamSearchOptions: opts.TeamSearchOptions,
 	})
 }
@@ -26,7 +26,8 @@ import (
 //	}
 type QueryListPullRequests struct {
 	Search struct {
-		Nodes []struct {
+		IssueCount int64
+		Nodes      []struct {
 			PullRequest PullRequest `graphql:"... on PullRequest"`
 		}
 		PageInfo models.PageInfo
@@ -40,6 +41,7 @@ type PullRequestAuthor struct {
 
 // PullRequest is a GitHub pull request
 type PullRequest struct {
+	ID         string
 	Number     int64
 	Title      string
 	URL        string
@@ -63,6 +65,9 @@ type PullRequest struct {
 		}
 	} `graphql:"labels(first: 100)"`
 	Repository Repository
+
+	// AuthorAssociation is the current relationship of the author with the repository
+	AuthorAssociation githubv4.CommentAuthorAssociation
 }
 
 // PullRequests is a list of GitHub Pull Requests
@@ -200,10 +205,37 @@ func (p PullRequests) Frames() data.Frames {
 
 // GetAllPullRequests uses the graphql search endpoint API to search all pull requests in the repository
 func GetAllPullRequests(ctx context.Context, client models.Client, opts models.ListPullRequestsOptions) (PullRequests, error) {
+	pullRequests, _, err := searchTeamPullRequests(ctx, client, opts, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return dedupePullRequests(pullRequests), nil
+}
+
+// searchTeamPullRequests returns every pull request matching the options.
+// A team is expanded into several searches, and a pull request can match more than one of them, so the results have to be deduplicated.
+func searchTeamPullRequests(ctx context.Context, client models.Client, opts models.ListPullRequestsOptions, limited bool) ([]PullRequest, bool, error) {
+	pullRequests := []PullRequest{}
+
+	for _, query := range teamSearchQueries(buildQuery(opts), opts.TeamSearchOptions) {
+		prs, truncated, err := searchPullRequests(ctx, client, query, limited)
+		if err != nil || truncated {
+			return nil, truncated, err
+		}
+		pullRequests = append(pullRequests, prs...)
+	}
+
+	return pullRequests, false, nil
+}
+
+// searchPullRequests returns every pull request matching the search query.
+// When limited is true and the search matches more than SearchResultLimit pull requests, it stops after the first page and reports the search as truncated.
+func searchPullRequests(ctx context.Context, client models.Client, query string, limited bool) ([]PullRequest, bool, error) {
 	var (
 		variables = map[string]interface{}{
 			"cursor": (*githubv4.String)(nil),
-			"query":  githubv4.String(buildQuery(opts)),
+			"query":  githubv4.String(query),
 		}
 
 		pullRequests = []PullRequest{}
@@ -212,7 +244,10 @@ func GetAllPullRequests(ctx context.Context, client models.Client, opts models.L
 	for {
 		q := &QueryListPullRequests{}
 		if err := client.Query(ctx, q, variables); err != nil {
-			return nil, errors.WithStack(err)
+			return nil, false, errors.WithStack(err)
+		}
+		if limited && q.Search.IssueCount > SearchResultLimit {
+			return nil, true, nil
 		}
 		prs := make([]PullRequest, len(q.Search.Nodes))
 
@@ -228,27 +263,52 @@ func GetAllPullRequests(ctx context.Context, client models.Client, opts models.L
 		variables["cursor"] = q.Search.PageInfo.EndCursor
 	}
 
-	return pullRequests, nil
+	return pullRequests, false, nil
+}
+
+// dedupePullRequests removes the pull requests returned by more than one search
+func dedupePullRequests(pullRequests []PullRequest) PullRequests {
+	return dedupeResults(pullRequests, func(pr PullRequest) string {
+		return fmt.Sprintf("%s#%d", pr.Repository.NameWithOwner, pr.Number)
+	})
 }
 
 // GetPullRequestsInRange uses the graphql search endpoint API to find pull requests in the given time range.
+// When the search matches more pull requests than GitHub returns, the time range is split into smaller ranges.
 func GetPullRequestsInRange(ctx context.Context, client models.Client, opts models.ListPullRequestsOptions, from time.Time, to time.Time) (PullRequests, error) {
-	var q string
+	rangeOptions := func(from time.Time, to time.Time) models.ListPullRequestsOptions {
+		var q string
+
+		if opts.TimeField != models.PullRequestNone {
+			q = fmt.Sprintf("%s:%s..%s", opts.TimeField.String(), from.Format(time.RFC3339), to.Format(time.RFC3339))
+		}
 
-	if opts.TimeField != models.PullRequestNone {
-		q = fmt.Sprintf("%s:%s..%s", opts.TimeField.String(), from.Format(time.RFC3339), to.Format(time.RFC3339))
+		if opts.Query != nil {
+			q = fmt.Sprintf("%s %s", *opts.Query, q)
+		}
+
+		return models.ListPullRequestsOptions{
+			Repository:        opts.Repository,
+			Owner:             opts.Owner,
+			TimeField:         opts.TimeField,
+			Query:             &q,
+			TeamSearchOptions: opts.TeamSearchOptions,
+		}
 	}
 
-	if opts.Query != nil {
-		q = fmt.Sprintf("%s %s", *opts.Query, q)
+	// without a time field the search can't be split by time
+	if opts.TimeField == models.PullRequestNone {
+		return GetAllPullRequests(ctx, client, rangeOptions(from, to))
 	}
 
-	return GetAllPullRequests(ctx, client, models.ListPullRequestsOptions{
-		Repository: opts.Repository,
-		Owner:      opts.Owner,
-		TimeField:  opts.TimeField,
-		Query:      &q,
+	pullRequests, err := searchTimeRange(ctx, from, to, func(ctx context.Context, from time.Time, to time.Time, limited bool) ([]PullRequest, bool, error) {
+		return searchTeamPullRequests(ctx, client, rangeOptions(from, to), limited)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dedupePullRequests(pullRequests), nil
 }
 
 // buildQuery builds the "query" field for Pull Request searches
@@ -69,6 +69,23 @@ func GetQueryHandlers(s *QueryHandler) *datasource.QueryTypeMux {
 	register(models.QueryTypeOrganizations, s.HandleOrganizations)
 	register(models.QueryTypeCommitFiles, s.HandleCommitFiles)
 	register(models.QueryTypePullRequestFiles, s.HandlePullRequestFiles)
+	register(models.QueryTypeBranchProtection, s.HandleBranchProtection)
+	register(models.QueryTypeMembers, s.HandleMembers)
+	register(models.QueryTypeTeams, s.HandleTeams)
+	register(models.QueryTypeCollaborators, s.HandleCollaborators)
+	register(models.QueryTypeDeploymentStatuses, s.HandleDeploymentStatuses)
+	register(models.QueryTypeEnvironments, s.HandleEnvironments)
+	register(models.QueryTypeChecks, s.HandleChecks)
+	register(models.QueryTypeMergeQueue, s.HandleMergeQueue)
+	register(models.QueryTypeDiscussions, s.HandleDiscussions)
+	register(models.QueryTypeCompare, s.HandleCompare)
+	register(models.QueryTypeHotspots, s.HandleHotspots)
+	register(models.QueryTypeOwnership, s.HandleOwnership)
+	register(models.QueryTypeCodeowners, s.HandleCodeowners)
+	register(models.QueryTypeContributorCohorts, s.HandleContributorCohorts)
+	register(models.QueryTypeDependencies, s.HandleDependencies)
+	register(models.QueryTypeForks, s.HandleForks)
+	register(models.QueryTypeTechStack, s.HandleTechStack)
 
 	return mux
 }
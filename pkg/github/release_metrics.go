@@ -0,0 +1,225 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// MaxReleaseCadenceComparisons is the number of the most recent releases in the time range that are compared with the release before them
+const MaxReleaseCadenceComparisons = 20
+
+// ReleaseCadence is the time, commits and pull requests between a release and the release published before it
+type ReleaseCadence struct {
+	Tag               string
+	PreviousTag       string
+	PublishedAt       time.Time
+	DaysSincePrevious float64
+	Commits           int64
+	PullRequests      int64
+}
+
+// ReleaseMetrics is a list of releases along with their assets and their cadence
+type ReleaseMetrics struct {
+	Releases Releases
+	// Assets are the assets of every release by tag name, nil when they were not requested
+	Assets map[string][]ReleaseAsset
+	// Cadence is nil when it was not requested
+	Cadence []ReleaseCadence
+	// CadenceTruncated is true when there were more than MaxReleaseCadenceComparisons releases in the time range
+	CadenceTruncated bool
+}
+
+// Frames converts the releases to the releases frame, followed by the release assets and the cadence frames when they were requested
+func (r ReleaseMetrics) Frames() data.Frames {
+	frames := r.Releases.Frames()
+
+	if r.Assets != nil {
+		assets := data.NewFrame(
+			"release_assets",
+			data.NewField("release", nil, []string{}),
+			data.NewField("tag", nil, []string{}),
+			data.NewField("published_at", nil, []*time.Time{}),
+			data.NewField("name", nil, []string{}),
+			data.NewField("content_type", nil, []string{}),
+			data.NewField("size", nil, []int64{}),
+			data.NewField("download_count", nil, []int64{}),
+			data.NewField("url", nil, []string{}),
+			data.NewField("created_at", nil, []time.Time{}),
+			data.NewField("updated_at", nil, []time.Time{}),
+		)
+
+		for _, release := range r.Releases {
+			for _, asset := range r.Assets[release.TagName] {
+				assets.AppendRow(
+					release.Name,
+					release.TagName,
+					nullableTime(release.PublishedAt.Time),
+					asset.Name,
+					asset.ContentType,
+					asset.Size,
+					asset.DownloadCount,
+					asset.DownloadURL,
+					asset.CreatedAt.Time,
+					asset.UpdatedAt.Time,
+				)
+			}
+		}
+
+		frames = append(frames, assets)
+	}
+
+	if r.Cadence != nil {
+		cadence := data.NewFrame(
+			"release_cadence",
+			data.NewField("tag", nil, []string{}),
+			data.NewField("previous_tag", nil, []string{}),
+			data.NewField("published_at", nil, []time.Time{}),
+			data.NewField("days_since_previous", nil, []float64{}),
+			data.NewField("commits", nil, []int64{}),
+			data.NewField("pull_requests", nil, []int64{}),
+		)
+
+		for _, c := range r.Cadence {
+			cadence.AppendRow(
+				c.Tag,
+				c.PreviousTag,
+				c.PublishedAt,
+				c.DaysSincePrevious,
+				c.Commits,
+				c.PullRequests,
+			)
+		}
+		if r.CadenceTruncated {
+			cadence.AppendNotices(data.Notice{
+				Severity: data.NoticeSeverityWarning,
+				Text:     fmt.Sprintf("Only the cadence of the %d most recent releases in the time range is shown", MaxReleaseCadenceComparisons),
+			})
+		}
+
+		frames = append(frames, cadence)
+	}
+
+	return frames
+}
+
+// GetReleaseMetrics retrieves the releases published within the given time range along with their assets and cadence.
+// Every release is compared with the release published before it, even when that one is outside of the time range.
+// Each comparison pages through the commits between the releases, so only the MaxReleaseCadenceComparisons most recent releases are compared.
+// A zero time range keeps every release.
+func GetReleaseMetrics(ctx context.Context, client models.Client, opts models.ListReleasesOptions, from time.Time, to time.Time) (ReleaseMetrics, error) {
+	var (
+		all        Releases
+		withAssets []ReleaseWithAssets
+		err        error
+	)
+
+	if opts.IncludeAssets {
+		withAssets, err = GetAllReleasesWithAssets(ctx, client, opts)
+		for _, r := range withAssets {
+			all = append(all, r.Release)
+		}
+	} else {
+		all, err = GetAllReleases(ctx, client, opts)
+	}
+	if err != nil {
+		return ReleaseMetrics{}, err
+	}
+
+	releases := all
+	if from.Unix() > 0 || to.Unix() > 0 {
+		releases = filterReleasesInRange(all, from, to)
+	}
+
+	metrics := ReleaseMetrics{
+		Releases: releases,
+	}
+
+	if opts.IncludeAssets {
+		inRange := map[string]bool{}
+		for _, r := range releases {
+			inRange[r.TagName] = true
+		}
+
+		metrics.Assets = map[string][]ReleaseAsset{}
+		for _, r := range withAssets {
+			if !inRange[r.TagName] {
+				continue
+			}
+			// the remaining pages of assets are only fetched for the releases in the time range
+			assets, err := GetReleaseAssets(ctx, client, opts, r)
+			if err != nil {
+				return ReleaseMetrics{}, err
+			}
+			metrics.Assets[r.TagName] = assets
+		}
+	}
+
+	if !opts.IncludeCadence {
+		return metrics, nil
+	}
+
+	metrics.Cadence = []ReleaseCadence{}
+	published := publishedReleases(all)
+	inRange := map[string]bool{}
+	for _, r := range releases {
+		inRange[r.TagName] = true
+	}
+
+	// the releases are compared from the newest, and the cadence is listed from the oldest
+	for i := len(published) - 1; i > 0; i-- {
+		previous, current := published[i-1], published[i]
+		if !inRange[current.TagName] {
+			continue
+		}
+		if len(metrics.Cadence) == MaxReleaseCadenceComparisons {
+			metrics.CadenceTruncated = true
+			break
+		}
+
+		comparison, err := GetComparison(ctx, client, models.CompareOptions{
+			Owner:      opts.Owner,
+			Repository: opts.Repository,
+			Base:       previous.TagName,
+			Head:       current.TagName,
+		})
+		if err != nil {
+			return ReleaseMetrics{}, err
+		}
+
+		metrics.Cadence = append(metrics.Cadence, ReleaseCadence{
+			Tag:               current.TagName,
+			PreviousTag:       previous.TagName,
+			PublishedAt:       current.PublishedAt.Time,
+			DaysSincePrevious: current.PublishedAt.Sub(previous.PublishedAt.Time).Hours() / 24,
+			Commits:           comparison.AheadBy,
+			PullRequests:      int64(len(comparison.PullRequests)),
+		})
+	}
+	slices.Reverse(metrics.Cadence)
+
+	return metrics, nil
+}
+
+// publishedReleases returns the releases that are not drafts, from the oldest to the newest
+func publishedReleases(releases Releases) Releases {
+	published := Releases{}
+	for _, r := range releases {
+		if r.IsDraft || r.PublishedAt.IsZero() {
+			continue
+		}
+		published = append(published, r)
+	}
+
+	sort.SliceStable(published, func(i, j int) bool {
+		return published[i].PublishedAt.Before(published[j].PublishedAt.Time)
+	})
+
+	return published
+}
@@ -0,0 +1,176 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func testRelease(tag string, publishedAt time.Time) Release {
+	return Release{
+		Name:        tag,
+		TagName:     tag,
+		URL:         "https://github.com/grafana/grafana/releases/tag/" + tag,
+		CreatedAt:   githubv4.DateTime{Time: publishedAt},
+		PublishedAt: githubv4.DateTime{Time: publishedAt},
+	}
+}
+
+func TestGetReleaseMetrics(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		publishedAt = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		opts        = models.ListReleasesOptions{
+			Repository:     "grafana",
+			Owner:          "grafana",
+			IncludeAssets:  true,
+			IncludeCadence: true,
+		}
+	)
+
+	first := testRelease("v1.0.0", publishedAt)
+	second := ReleaseWithAssets{Release: testRelease("v1.1.0", publishedAt.Add(14*24*time.Hour))}
+	second.ReleaseAssets.Nodes = []ReleaseAsset{
+		{
+			Name:          "grafana-1.1.0.linux-amd64.tar.gz",
+			ContentType:   "application/gzip",
+			Size:          104857600,
+			DownloadCount: 1500,
+			DownloadURL:   "https://github.com/grafana/grafana/releases/download/v1.1.0/grafana-1.1.0.linux-amd64.tar.gz",
+			CreatedAt:     githubv4.DateTime{Time: publishedAt.Add(14 * 24 * time.Hour)},
+			UpdatedAt:     githubv4.DateTime{Time: publishedAt.Add(14 * 24 * time.Hour)},
+		},
+	}
+	draft := testRelease("v1.2.0", time.Time{})
+	draft.IsDraft = true
+
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryListReleasesWithAssets)
+		require.True(t, ok, "unexpected query type %T", q)
+		query.Repository.Releases.Nodes = []ReleaseWithAssets{{Release: draft}, second, {Release: first}}
+	}
+
+	client := &compareMockClient{
+		TestClient: testutil.NewTestClient(t, nil, testQuery),
+		comparisons: map[string]*googlegithub.CommitsComparison{
+			"v1.0.0...v1.1.0": {
+				AheadBy: googlegithub.Ptr(3),
+				Commits: []*googlegithub.RepositoryCommit{
+					newRESTCommit("sha0", "testUser", "Test User", publishedAt),
+					newRESTCommit("sha1", "testUser", "Test User", publishedAt),
+					newRESTCommit("sha2", "testUser", "Test User", publishedAt),
+				},
+			},
+		},
+		pullRequests: map[string]int64{"sha0": 10, "sha1": 10, "sha2": 11},
+	}
+
+	metrics, err := GetReleaseMetrics(ctx, client, opts, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.0.0...v1.1.0"}, client.compared)
+	require.Len(t, metrics.Cadence, 1)
+	assert.Equal(t, float64(14), metrics.Cadence[0].DaysSincePrevious)
+	assert.Equal(t, int64(3), metrics.Cadence[0].Commits)
+	assert.Equal(t, int64(2), metrics.Cadence[0].PullRequests)
+
+	testutil.CheckGoldenFramer(t, "release_metrics", metrics)
+}
+
+func TestGetReleaseMetricsPaginatesAssets(t *testing.T) {
+	var (
+		publishedAt = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		opts        = models.ListReleasesOptions{Repository: "grafana", Owner: "grafana", IncludeAssets: true}
+		pages       = 0
+	)
+
+	old := ReleaseWithAssets{Release: testRelease("v1.0.0", publishedAt)}
+	old.ReleaseAssets.Nodes = []ReleaseAsset{{Name: "old.tar.gz"}}
+	old.ReleaseAssets.PageInfo = models.PageInfo{HasNextPage: true, EndCursor: "old"}
+
+	latest := ReleaseWithAssets{Release: testRelease("v1.1.0", publishedAt.Add(14*24*time.Hour))}
+	latest.ReleaseAssets.Nodes = []ReleaseAsset{{Name: "first.tar.gz"}}
+	latest.ReleaseAssets.PageInfo = models.PageInfo{HasNextPage: true, EndCursor: "1"}
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		if tag, ok := variables["tag"]; ok {
+			// only the release in the time range has its remaining assets fetched
+			assert.Equal(t, githubv4.String("v1.1.0"), tag)
+		}
+	}
+
+	client := testutil.NewTestClient(t, testVariables, func(t *testing.T, q interface{}) {
+		switch query := q.(type) {
+		case *QueryListReleasesWithAssets:
+			query.Repository.Releases.Nodes = []ReleaseWithAssets{latest, old}
+		case *QueryListReleaseAssets:
+			pages++
+			query.Repository.Release = &struct {
+				ReleaseAssets ReleaseAssets `graphql:"releaseAssets(first: 100, after: $cursor)"`
+			}{}
+			query.Repository.Release.ReleaseAssets.Nodes = []ReleaseAsset{{Name: fmt.Sprintf("page-%d.tar.gz", pages)}}
+			query.Repository.Release.ReleaseAssets.PageInfo = models.PageInfo{HasNextPage: pages < 2, EndCursor: githubv4.String(fmt.Sprint(pages + 1))}
+		default:
+			t.Errorf("unexpected query type %T", q)
+		}
+	})
+
+	metrics, err := GetReleaseMetrics(context.Background(), client, opts, publishedAt.Add(24*time.Hour), publishedAt.Add(30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, pages)
+	require.Len(t, metrics.Releases, 1)
+
+	names := []string{}
+	for _, asset := range metrics.Assets["v1.1.0"] {
+		names = append(names, asset.Name)
+	}
+	assert.Equal(t, []string{"first.tar.gz", "page-1.tar.gz", "page-2.tar.gz"}, names)
+	assert.NotContains(t, metrics.Assets, "v1.0.0")
+}
+
+func TestGetReleaseMetricsCadenceLimit(t *testing.T) {
+	var (
+		publishedAt = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		opts        = models.ListReleasesOptions{Repository: "grafana", Owner: "grafana", IncludeCadence: true}
+		releases    = Releases{}
+		comparisons = map[string]*googlegithub.CommitsComparison{}
+	)
+
+	for i := 0; i <= MaxReleaseCadenceComparisons+1; i++ {
+		releases = append(releases, testRelease(fmt.Sprintf("v1.%d.0", i), publishedAt.AddDate(0, 0, i)))
+		if i > 0 {
+			comparisons[fmt.Sprintf("v1.%d.0...v1.%d.0", i-1, i)] = &googlegithub.CommitsComparison{AheadBy: googlegithub.Ptr(1)}
+		}
+	}
+
+	client := &compareMockClient{
+		TestClient: testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+			query, ok := q.(*QueryListReleases)
+			require.True(t, ok, "unexpected query type %T", q)
+			query.Repository.Releases.Nodes = releases
+		}),
+		comparisons: comparisons,
+	}
+
+	metrics, err := GetReleaseMetrics(context.Background(), client, opts, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, metrics.Cadence, MaxReleaseCadenceComparisons)
+	assert.True(t, metrics.CadenceTruncated)
+	// the oldest release in the time range isn't compared
+	assert.Equal(t, "v1.2.0", metrics.Cadence[0].Tag)
+	assert.Equal(t, fmt.Sprintf("v1.%d.0", MaxReleaseCadenceComparisons+1), metrics.Cadence[len(metrics.Cadence)-1].Tag)
+	assert.Len(t, client.compared, MaxReleaseCadenceComparisons)
+
+	frames := metrics.Frames()
+	require.Len(t, frames, 2)
+	require.NotNil(t, frames[1].Meta)
+	assert.Len(t, frames[1].Meta.Notices, 1)
+}
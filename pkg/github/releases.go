@@ -9,6 +9,17 @@ import (
 	"github.com/shurcooL/githubv4"
 )
 
+// ReleaseAsset is a file attached to a GitHub release
+type ReleaseAsset struct {
+	Name          string
+	ContentType   string
+	Size          int64
+	DownloadCount int64
+	DownloadURL   string `graphql:"downloadUrl"`
+	CreatedAt     githubv4.DateTime
+	UpdatedAt     githubv4.DateTime
+}
+
 // Release is a GitHub release. Every release has an associated tag.
 type Release struct {
 	ID           string
@@ -22,6 +33,18 @@ type Release struct {
 	URL          string
 }
 
+// ReleaseAssets is a page of the assets of a release
+type ReleaseAssets struct {
+	Nodes    []ReleaseAsset
+	PageInfo models.PageInfo
+}
+
+// ReleaseWithAssets is a GitHub release along with the first page of its assets
+type ReleaseWithAssets struct {
+	Release
+	ReleaseAssets ReleaseAssets `graphql:"releaseAssets(first: 100)"`
+}
+
 // Releases is a slice of GitHub releases
 type Releases []Release
 
@@ -71,6 +94,25 @@ type QueryListReleases struct {
 	} `graphql:"repository(owner: $owner, name: $name)"`
 }
 
+// QueryListReleasesWithAssets is the GraphQL query for listing GitHub releases in a repository along with the first page of their assets
+type QueryListReleasesWithAssets struct {
+	Repository struct {
+		Releases struct {
+			Nodes    []ReleaseWithAssets
+			PageInfo models.PageInfo
+		} `graphql:"releases(first: 100, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// QueryListReleaseAssets is the GraphQL query for listing the assets of a GitHub release
+type QueryListReleaseAssets struct {
+	Repository struct {
+		Release *struct {
+			ReleaseAssets ReleaseAssets `graphql:"releaseAssets(first: 100, after: $cursor)"`
+		} `graphql:"release(tagName: $tag)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
 // GetAllReleases retrieves every release from a repository
 func GetAllReleases(ctx context.Context, client models.Client, opts models.ListReleasesOptions) (Releases, error) {
 	var (
@@ -98,6 +140,62 @@ func GetAllReleases(ctx context.Context, client models.Client, opts models.ListR
 	return releases, nil
 }
 
+// GetAllReleasesWithAssets retrieves every release from a repository along with the first page of their assets
+func GetAllReleasesWithAssets(ctx context.Context, client models.Client, opts models.ListReleasesOptions) ([]ReleaseWithAssets, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"owner":  githubv4.String(opts.Owner),
+			"name":   githubv4.String(opts.Repository),
+		}
+
+		releases = []ReleaseWithAssets{}
+	)
+
+	for {
+		q := &QueryListReleasesWithAssets{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, err
+		}
+		releases = append(releases, q.Repository.Releases.Nodes...)
+		if !q.Repository.Releases.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.Releases.PageInfo.EndCursor
+	}
+
+	return releases, nil
+}
+
+// GetReleaseAssets retrieves every asset of a release, starting with the first page that was listed along with the release
+func GetReleaseAssets(ctx context.Context, client models.Client, opts models.ListReleasesOptions, release ReleaseWithAssets) ([]ReleaseAsset, error) {
+	var (
+		assets    = release.ReleaseAssets.Nodes
+		pageInfo  = release.ReleaseAssets.PageInfo
+		variables = map[string]interface{}{
+			"owner": githubv4.String(opts.Owner),
+			"name":  githubv4.String(opts.Repository),
+			"tag":   githubv4.String(release.TagName),
+		}
+	)
+
+	for pageInfo.HasNextPage {
+		variables["cursor"] = pageInfo.EndCursor
+
+		q := &QueryListReleaseAssets{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, err
+		}
+		if q.Repository.Release == nil {
+			break
+		}
+		assets = append(assets, q.Repository.Release.ReleaseAssets.Nodes...)
+		pageInfo = q.Repository.Release.ReleaseAssets.PageInfo
+	}
+
+	return assets, nil
+}
+
 // GetReleasesInRange retrieves every release from the repository and then returns the ones that fall within the given time range.
 func GetReleasesInRange(ctx context.Context, client models.Client, opts models.ListReleasesOptions, from time.Time, to time.Time) (Releases, error) {
 	releases, err := GetAllReleases(ctx, client, opts)
@@ -105,6 +203,11 @@ func GetReleasesInRange(ctx context.Context, client models.Client, opts models.L
 		return nil, err
 	}
 
+	return filterReleasesInRange(releases, from, to), nil
+}
+
+// filterReleasesInRange returns the releases published within the given time range
+func filterReleasesInRange(releases Releases, from time.Time, to time.Time) Releases {
 	filtered := []Release{}
 
 	for i, v := range releases {
@@ -113,5 +216,5 @@ func GetReleasesInRange(ctx context.Context, client models.Client, opts models.L
 		}
 	}
 
-	return filtered, nil
+	return filtered
 }
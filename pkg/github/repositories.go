@@ -8,6 +8,7 @@ import (
 
 	googlegithub "github.com/google/go-github/v84/github"
 	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/shurcooL/githubv4"
 )
@@ -24,14 +25,25 @@ import (
 //	  }
 //	}
 type QueryListRepositories struct {
-	Search struct {
-		Nodes []struct {
-			Repository Repository `graphql:"... on Repository"`
-		}
-		PageInfo models.PageInfo
-	} `graphql:"search(query: $query, type: REPOSITORY, first: 100, after: $cursor)"`
+	Search RepositorySearch[Repository] `graphql:"search(query: $query, type: REPOSITORY, first: 100, after: $cursor)"`
 }
 
+// RepositorySearch is a page of the repositories matching a search, with the fields of T
+type RepositorySearch[T any] struct {
+	RepositoryCount int64
+	Nodes           []struct {
+		Repository T `graphql:"... on Repository"`
+	}
+	PageInfo models.PageInfo
+}
+
+// repositorySearchQuery is a search of repositories returning the fields of T
+type repositorySearchQuery[T any] interface {
+	search() *RepositorySearch[T]
+}
+
+func (q *QueryListRepositories) search() *RepositorySearch[Repository] { return &q.Search }
+
 // Repository is a code repository
 type Repository struct {
 	Name  string
@@ -83,42 +95,105 @@ func (r Repositories) Frames() data.Frames {
 
 }
 
-// GetAllRepositories retrieves all available repositories for an organization
+// GetAllRepositories retrieves all available repositories for an organization.
 func GetAllRepositories(ctx context.Context, client models.Client, opts models.ListRepositoriesOptions) (Repositories, error) {
-	query := strings.Join([]string{
-		fmt.Sprintf("org:%s", opts.Owner),
-		opts.Repository,
-	}, " ")
+	return searchAllRepositories(ctx, client, opts, func() repositorySearchQuery[Repository] {
+		return &QueryListRepositories{}
+	}, func(r Repository) string {
+		return r.NameWithOwner
+	})
+}
 
+// searchAllRepositories retrieves all the repositories of an organization matching the repository search, with the fields of T.
+func searchAllRepositories[T any](ctx context.Context, client models.Client, opts models.ListRepositoriesOptions, newQuery func() repositorySearchQuery[T], key func(T) string) ([]T, error) {
+	return searchOwnerRepositories(ctx, client, fmt.Sprintf("org:%s", opts.Owner), opts.Repository, newQuery, key)
+}
+
+// searchOwnerRepositories retrieves all the repositories of the owner qualifier (ex: org:grafana or user:octocat) matching the repository search, with the fields of T.
+// When the owner has more repositories than the search API returns, the search is split by creation date.
+func searchOwnerRepositories[T any](ctx context.Context, client models.Client, owner string, search string, newQuery func() repositorySearchQuery[T], key func(T) string) ([]T, error) {
+	query := strings.Join([]string{owner, search}, " ")
+
+	repos, truncated, err := searchRepositories(ctx, client, query, true, newQuery)
+	if err != nil {
+		return nil, err
+	}
+	if !truncated {
+		return repos, nil
+	}
+
+	repos, err = searchTimeRange(ctx, githubLaunch, time.Now().UTC(), func(ctx context.Context, from time.Time, to time.Time, limited bool) ([]T, bool, error) {
+		created := fmt.Sprintf("created:%s..%s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+		return searchRepositories(ctx, client, strings.Join([]string{query, created}, " "), limited, newQuery)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dedupeResults(repos, key), nil
+}
+
+// searchRepositories returns every repository matching the search query.
+// When limited is true and the search matches more than SearchResultLimit repositories, it stops after the first page and reports the search as truncated.
+func searchRepositories[T any](ctx context.Context, client models.Client, query string, limited bool, newQuery func() repositorySearchQuery[T]) ([]T, bool, error) {
 	var (
 		variables = map[string]interface{}{
 			"cursor": (*githubv4.String)(nil),
 			"query":  githubv4.String(query),
 		}
 
-		repos = []Repository{}
+		repos = []T{}
 	)
 
 	for {
-		q := &QueryListRepositories{}
+		q := newQuery()
 		if err := client.Query(ctx, q, variables); err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		r := make([]Repository, len(q.Search.Nodes))
-
-		for i, v := range q.Search.Nodes {
-			r[i] = v.Repository
+		search := q.search()
+		if limited && search.RepositoryCount > SearchResultLimit {
+			return nil, true, nil
 		}
 
-		repos = append(repos, r...)
+		for _, v := range search.Nodes {
+			repos = append(repos, v.Repository)
+		}
 
-		if !q.Search.PageInfo.HasNextPage {
+		if !search.PageInfo.HasNextPage {
 			break
 		}
-		variables["cursor"] = q.Search.PageInfo.EndCursor
+		variables["cursor"] = search.PageInfo.EndCursor
+	}
+
+	return repos, false, nil
+}
+
+// listRepositoryNames returns the given repository, or the names of every repository of the owner when no repository is set.
+// It is used by the queries that can audit a whole organization at once.
+func listRepositoryNames(ctx context.Context, client models.Client, owner string, repository string) ([]string, error) {
+	if repository != "" {
+		return []string{repository}, nil
+	}
+
+	repos, err := GetAllRepositories(ctx, client, models.ListRepositoriesOptions{Owner: owner})
+	if err != nil {
+		return nil, err
 	}
 
-	return repos, nil
+	names := make([]string, len(repos))
+	for i, r := range repos {
+		names[i] = r.Name
+	}
+	return names, nil
+}
+
+// firstRepositoryNames returns the first max names, so that the queries auditing a whole organization make a bounded number of requests
+func firstRepositoryNames(names []string, max int, owner string) []string {
+	if len(names) <= max {
+		return names
+	}
+	backend.Logger.Warn("only the first repositories of the owner are read", "owner", owner, "repositories", len(names), "max", max)
+	return names[:max]
 }
 
 type OrgRepoResponse struct {
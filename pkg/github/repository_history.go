@@ -0,0 +1,145 @@
+package github
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+const (
+	// RepositoryHistoryPageLimit is the number of pages a query adds at most to the history of a repository, so that a large history is backfilled over several queries
+	RepositoryHistoryPageLimit = 10
+	// RepositoryHistoryExpiry is how long the history of a repository is kept after it was last queried
+	RepositoryHistoryExpiry = 24 * time.Hour
+	// MaxRepositoryHistories is the number of repositories a history store keeps, the least recently queried ones are dropped first
+	MaxRepositoryHistories = 50
+)
+
+// historyPage is a page of the history of a repository along with the total count of the list when it was fetched
+type historyPage[T any] struct {
+	Items      []T
+	PageInfo   models.PageInfo
+	TotalCount int64
+}
+
+// historyPageFunc fetches the page of a history after the cursor, which is nil for the first page
+type historyPageFunc[T any] func(ctx context.Context, cursor *githubv4.String) (historyPage[T], error)
+
+// repositoryHistory is the list of the stargazers or the forks of a repository, from the oldest to the newest.
+// The list is fetched in ascending order and the cursor of its last page is kept, so that every update only fetches what was added since the previous one.
+// Stargazers who remove their star and deleted forks stay in the history, which is kept in memory and lost when the plugin restarts.
+type repositoryHistory[T any] struct {
+	// fetching is held while an update fetches pages, without holding mu so that the other queries can read the history meanwhile
+	fetching sync.Mutex
+
+	mu       sync.Mutex
+	items    []T
+	cursor   githubv4.String
+	total    int64
+	upToDate bool
+
+	// usedAt is when the history was last queried, it is guarded by the mutex of the store
+	usedAt time.Time
+}
+
+// update fetches up to RepositoryHistoryPageLimit pages added to the history since the previous update.
+// It returns right away when another query is already updating the history.
+// The pages fetched before an error are kept, so that a large history is backfilled over several updates when requests fail or time out.
+func (h *repositoryHistory[T]) update(ctx context.Context, fetch historyPageFunc[T]) error {
+	if !h.fetching.TryLock() {
+		return nil
+	}
+	defer h.fetching.Unlock()
+
+	h.mu.Lock()
+	cursor := h.cursor
+	h.mu.Unlock()
+
+	for i := 0; i < RepositoryHistoryPageLimit; i++ {
+		var after *githubv4.String
+		if cursor != "" {
+			after = githubv4.NewString(cursor)
+		}
+
+		page, err := fetch(ctx, after)
+		if err != nil {
+			return err
+		}
+
+		// the end cursor is empty when there is nothing new since the previous update
+		if page.PageInfo.EndCursor != "" {
+			cursor = page.PageInfo.EndCursor
+		}
+
+		h.mu.Lock()
+		h.items = append(h.items, page.Items...)
+		h.cursor = cursor
+		h.total = page.TotalCount
+		h.upToDate = !page.PageInfo.HasNextPage
+		h.mu.Unlock()
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+	}
+
+	return nil
+}
+
+// snapshot returns the history along with the total count of the list when it was last updated,
+// and whether the last update reached the newest page of the list
+func (h *repositoryHistory[T]) snapshot() ([]T, int64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// the history is only appended to, limiting the capacity keeps the callers from writing to it
+	return h.items[:len(h.items):len(h.items)], h.total, h.upToDate
+}
+
+// repositoryHistoryStore keeps a history per repository
+type repositoryHistoryStore[T any] struct {
+	mu        sync.Mutex
+	histories map[string]*repositoryHistory[T]
+}
+
+func newRepositoryHistoryStore[T any]() *repositoryHistoryStore[T] {
+	return &repositoryHistoryStore[T]{histories: map[string]*repositoryHistory[T]{}}
+}
+
+// history returns the history of a repository, creating an empty one the first time it is queried.
+// The histories which expired are dropped, and so are the least recently queried ones when there are more than MaxRepositoryHistories.
+func (s *repositoryHistoryStore[T]) history(owner string, repository string, now time.Time) *repositoryHistory[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the names of the repositories are case insensitive
+	key := strings.ToLower(owner + "/" + repository)
+	h, ok := s.histories[key]
+	if !ok {
+		h = &repositoryHistory[T]{}
+		s.histories[key] = h
+	}
+	h.usedAt = now
+
+	for k, history := range s.histories {
+		if now.Sub(history.usedAt) > RepositoryHistoryExpiry {
+			delete(s.histories, k)
+		}
+	}
+	for len(s.histories) > MaxRepositoryHistories {
+		oldest := ""
+		for k, history := range s.histories {
+			if k != key && (oldest == "" || history.usedAt.Before(s.histories[oldest].usedAt)) {
+				oldest = k
+			}
+		}
+		delete(s.histories, oldest)
+	}
+
+	return h
+}
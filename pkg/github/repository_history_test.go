@@ -0,0 +1,162 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+// failingHistoryClient fails the queries of the history of a repository and runs the other queries with the test client
+type failingHistoryClient struct {
+	*testutil.TestClient
+}
+
+func (c *failingHistoryClient) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	switch q.(type) {
+	case *QueryStargazersHistory, *QueryForks:
+		return errors.New("timeout")
+	}
+	return c.TestClient.Query(ctx, q, variables)
+}
+
+// testHistoryPages serves a list in pages of two items, with the index of the last item as cursor
+type testHistoryPages struct {
+	items    []int
+	cursors  []*githubv4.String
+	failPage int
+}
+
+func (p *testHistoryPages) fetch(_ context.Context, cursor *githubv4.String) (historyPage[int], error) {
+	p.cursors = append(p.cursors, cursor)
+	if len(p.cursors) == p.failPage {
+		return historyPage[int]{}, errors.New("timeout")
+	}
+
+	start := 0
+	if cursor != nil {
+		for i, item := range p.items {
+			if githubv4.String(rune('a'+item)) == *cursor {
+				start = i + 1
+			}
+		}
+	}
+	end := min(start+2, len(p.items))
+
+	page := historyPage[int]{Items: p.items[start:end], TotalCount: int64(len(p.items))}
+	page.PageInfo.HasNextPage = end < len(p.items)
+	if len(page.Items) > 0 {
+		page.PageInfo.EndCursor = githubv4.String(rune('a' + page.Items[len(page.Items)-1]))
+	}
+	return page, nil
+}
+
+func TestRepositoryHistory(t *testing.T) {
+	t.Run("only fetches the items added since the previous update", func(t *testing.T) {
+		history := &repositoryHistory[int]{}
+		pages := &testHistoryPages{items: []int{0, 1, 2}}
+
+		require.NoError(t, history.update(context.Background(), pages.fetch))
+		items, total, upToDate := history.snapshot()
+		assert.Equal(t, []int{0, 1, 2}, items)
+		assert.Equal(t, int64(3), total)
+		assert.True(t, upToDate)
+		assert.Equal(t, []*githubv4.String{nil, githubv4.NewString("b")}, pages.cursors)
+
+		// nothing new
+		pages.cursors = nil
+		require.NoError(t, history.update(context.Background(), pages.fetch))
+		items, _, _ = history.snapshot()
+		assert.Equal(t, []int{0, 1, 2}, items)
+		assert.Equal(t, []*githubv4.String{githubv4.NewString("c")}, pages.cursors)
+
+		pages.cursors = nil
+		pages.items = append(pages.items, 3)
+		require.NoError(t, history.update(context.Background(), pages.fetch))
+		items, total, _ = history.snapshot()
+		assert.Equal(t, []int{0, 1, 2, 3}, items)
+		assert.Equal(t, int64(4), total)
+		assert.Equal(t, []*githubv4.String{githubv4.NewString("c")}, pages.cursors)
+	})
+
+	t.Run("keeps the pages fetched before an error", func(t *testing.T) {
+		history := &repositoryHistory[int]{}
+		pages := &testHistoryPages{items: []int{0, 1, 2, 3, 4}, failPage: 2}
+
+		require.Error(t, history.update(context.Background(), pages.fetch))
+		_, _, upToDate := history.snapshot()
+		assert.False(t, upToDate)
+
+		pages.cursors, pages.failPage = nil, 0
+		require.NoError(t, history.update(context.Background(), pages.fetch))
+		items, _, upToDate := history.snapshot()
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, items)
+		assert.True(t, upToDate)
+		assert.Equal(t, []*githubv4.String{githubv4.NewString("b"), githubv4.NewString("d")}, pages.cursors)
+	})
+
+	t.Run("backfills a large history over several updates", func(t *testing.T) {
+		history := &repositoryHistory[int]{}
+		pages := &testHistoryPages{}
+		for i := 0; i < 2*RepositoryHistoryPageLimit+5; i++ {
+			pages.items = append(pages.items, i)
+		}
+
+		require.NoError(t, history.update(context.Background(), pages.fetch))
+		items, _, upToDate := history.snapshot()
+		assert.Len(t, pages.cursors, RepositoryHistoryPageLimit)
+		assert.Len(t, items, 2*RepositoryHistoryPageLimit)
+		assert.False(t, upToDate)
+
+		require.NoError(t, history.update(context.Background(), pages.fetch))
+		items, _, upToDate = history.snapshot()
+		assert.Equal(t, pages.items, items)
+		assert.True(t, upToDate)
+	})
+
+	t.Run("doesn't wait for another update", func(t *testing.T) {
+		history := &repositoryHistory[int]{}
+		pages := &testHistoryPages{items: []int{0, 1, 2}}
+
+		history.fetching.Lock()
+		require.NoError(t, history.update(context.Background(), pages.fetch))
+		history.fetching.Unlock()
+		assert.Empty(t, pages.cursors)
+	})
+}
+
+func TestRepositoryHistoryStore(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("keeps a history per repository", func(t *testing.T) {
+		store := newRepositoryHistoryStore[int]()
+		assert.Same(t, store.history("grafana", "grafana", now), store.history("Grafana", "Grafana", now))
+		assert.NotSame(t, store.history("grafana", "grafana", now), store.history("grafana", "loki", now))
+	})
+
+	t.Run("drops the histories which expired", func(t *testing.T) {
+		store := newRepositoryHistoryStore[int]()
+		loki := store.history("grafana", "loki", now)
+		store.history("grafana", "grafana", now.Add(RepositoryHistoryExpiry/2))
+		store.history("grafana", "grafana", now.Add(RepositoryHistoryExpiry+time.Minute))
+		assert.Len(t, store.histories, 1)
+		assert.NotSame(t, loki, store.history("grafana", "loki", now.Add(RepositoryHistoryExpiry+time.Minute)))
+	})
+
+	t.Run("drops the least recently queried histories", func(t *testing.T) {
+		store := newRepositoryHistoryStore[int]()
+		for i := 0; i <= MaxRepositoryHistories; i++ {
+			store.history("grafana", fmt.Sprint(i), now.Add(time.Duration(i)*time.Second))
+		}
+		assert.Len(t, store.histories, MaxRepositoryHistories)
+		assert.NotContains(t, store.histories, "grafana/0")
+		assert.Contains(t, store.histories, "grafana/1")
+	})
+}
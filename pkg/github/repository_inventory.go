@@ -0,0 +1,297 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// MaxInventorySecurityRepositories is the number of repositories of the inventory whose security features are read.
+// They are read with two rest API requests per repository, the security features of the other repositories are unknown.
+const MaxInventorySecurityRepositories = 100
+
+// QueryListRepositoryInventory is the GraphQL query for retrieving the repositories of an organization with their metadata
+//
+//	{
+//	  search(query: "org:grafana", type: REPOSITORY, first: 100) {
+//	    nodes {
+//	      ... on Repository {
+//	        nameWithOwner
+//	        licenseInfo {
+//	          spdxId
+//	        }
+//	        repositoryTopics(first: 20) {
+//	          nodes {
+//	            topic {
+//	              name
+//	            }
+//	          }
+//	        }
+//	        languages(first: 20, orderBy: {field: SIZE, direction: DESC}) {
+//	          edges {
+//	            size
+//	            node {
+//	              name
+//	            }
+//	          }
+//	        }
+//	        defaultBranchRef {
+//	          name
+//	        }
+//	        visibility
+//	        isArchived
+//	        pushedAt
+//	        diskUsage
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListRepositoryInventory struct {
+	Search RepositorySearch[RepositoryMetadata] `graphql:"search(query: $query, type: REPOSITORY, first: 100, after: $cursor)"`
+}
+
+func (q *QueryListRepositoryInventory) search() *RepositorySearch[RepositoryMetadata] {
+	return &q.Search
+}
+
+// RepositoryMetadata is a repository with its license, topics, languages and settings
+type RepositoryMetadata struct {
+	Repository
+	LicenseInfo *struct {
+		SpdxID string `graphql:"spdxId"`
+		Name   string
+	}
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string
+			}
+		}
+	} `graphql:"repositoryTopics(first: 20)"`
+	Languages struct {
+		Edges []struct {
+			Size int64
+			Node struct {
+				Name string
+			}
+		}
+	} `graphql:"languages(first: 20, orderBy: {field: SIZE, direction: DESC})"`
+	DefaultBranchRef *struct {
+		Name string
+	}
+	Visibility githubv4.RepositoryVisibility
+	IsArchived bool
+	IsTemplate bool
+	PushedAt   *githubv4.DateTime
+	// DiskUsage is in kilobytes
+	DiskUsage                     *int64
+	HasVulnerabilityAlertsEnabled bool
+}
+
+// License returns the SPDX identifier of the license of the repository, or its name when it has none
+func (r RepositoryMetadata) License() string {
+	if r.LicenseInfo == nil {
+		return ""
+	}
+	if r.LicenseInfo.SpdxID != "" && r.LicenseInfo.SpdxID != "NOASSERTION" {
+		return r.LicenseInfo.SpdxID
+	}
+	return r.LicenseInfo.Name
+}
+
+// Topics returns the names of the topics of the repository
+func (r RepositoryMetadata) Topics() []string {
+	topics := make([]string, len(r.RepositoryTopics.Nodes))
+	for i, t := range r.RepositoryTopics.Nodes {
+		topics[i] = t.Topic.Name
+	}
+	return topics
+}
+
+// RepositorySecurity is the state of the security features of a repository. A nil value is a state that could not be read,
+// the security and analysis settings are only returned to the administrators of the repository.
+type RepositorySecurity struct {
+	SecretScanning               *bool
+	SecretScanningPushProtection *bool
+	DependabotSecurityUpdates    *bool
+	CodeScanningDefaultSetup     *bool
+}
+
+// InventoryRepository is a repository of the inventory
+type InventoryRepository struct {
+	RepositoryMetadata
+	Security RepositorySecurity
+}
+
+// RepositoryInventory is the inventory of the repositories of an organization
+type RepositoryInventory []InventoryRepository
+
+// Frames converts the inventory to Grafana data frames, the repositories and the size of their languages
+func (r RepositoryInventory) Frames() data.Frames {
+	diskUsage := data.NewField("disk_usage", nil, []*int64{})
+	diskUsage.Config = &data.FieldConfig{Unit: "bytes"}
+	repositories := data.NewFrame(
+		"repository_inventory",
+		data.NewField("name", nil, []string{}),
+		data.NewField("owner", nil, []string{}),
+		data.NewField("name_with_owner", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("license", nil, []string{}),
+		data.NewField("topics", nil, []string{}),
+		data.NewField("languages", nil, []string{}),
+		data.NewField("default_branch", nil, []string{}),
+		data.NewField("visibility", nil, []string{}),
+		data.NewField("is_archived", nil, []bool{}),
+		data.NewField("is_template", nil, []bool{}),
+		data.NewField("is_fork", nil, []bool{}),
+		data.NewField("is_mirror", nil, []bool{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("pushed_at", nil, []*time.Time{}),
+		diskUsage,
+		data.NewField("vulnerability_alerts", nil, []bool{}),
+		data.NewField("secret_scanning", nil, []*bool{}),
+		data.NewField("secret_scanning_push_protection", nil, []*bool{}),
+		data.NewField("dependabot_security_updates", nil, []*bool{}),
+		data.NewField("code_scanning_default_setup", nil, []*bool{}),
+	)
+
+	bytes := data.NewField("bytes", nil, []int64{})
+	bytes.Config = &data.FieldConfig{Unit: "bytes"}
+	languages := data.NewFrame(
+		"repository_languages",
+		data.NewField("repository", nil, []string{}),
+		data.NewField("language", nil, []string{}),
+		bytes,
+	)
+
+	for _, v := range r {
+		names := make([]string, len(v.Languages.Edges))
+		for i, l := range v.Languages.Edges {
+			names[i] = l.Node.Name
+			languages.AppendRow(v.NameWithOwner, l.Node.Name, l.Size)
+		}
+
+		var (
+			defaultBranch string
+			pushedAt      *time.Time
+			size          *int64
+		)
+		if v.DefaultBranchRef != nil {
+			defaultBranch = v.DefaultBranchRef.Name
+		}
+		if v.PushedAt != nil {
+			pushedAt = &v.PushedAt.Time
+		}
+		if v.DiskUsage != nil {
+			size = googlegithub.Ptr(*v.DiskUsage * 1024)
+		}
+
+		repositories.AppendRow(
+			v.Name,
+			v.Owner.Login,
+			v.NameWithOwner,
+			v.URL,
+			v.License(),
+			strings.Join(v.Topics(), ","),
+			strings.Join(names, ","),
+			defaultBranch,
+			string(v.Visibility),
+			v.IsArchived,
+			v.IsTemplate,
+			v.IsFork,
+			v.IsMirror,
+			v.CreatedAt.Time,
+			pushedAt,
+			size,
+			v.HasVulnerabilityAlertsEnabled,
+			v.Security.SecretScanning,
+			v.Security.SecretScanningPushProtection,
+			v.Security.DependabotSecurityUpdates,
+			v.Security.CodeScanningDefaultSetup,
+		)
+	}
+
+	repositories.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{repositories, languages}
+}
+
+// GetRepositoryInventory retrieves the repositories of an organization with their metadata and the state of their security features.
+// The security features are read with the rest API, which takes two requests per repository, so they are only read for the first
+// MaxInventorySecurityRepositories repositories. A repository whose security features can't be read doesn't fail the inventory,
+// its security features are unknown.
+func GetRepositoryInventory(ctx context.Context, client models.Client, opts models.ListRepositoriesOptions) (RepositoryInventory, error) {
+	repos, err := searchAllRepositories(ctx, client, opts, func() repositorySearchQuery[RepositoryMetadata] {
+		return &QueryListRepositoryInventory{}
+	}, func(r RepositoryMetadata) string {
+		return r.NameWithOwner
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := make(RepositoryInventory, len(repos))
+	rateLimited := false
+	for i, r := range repos {
+		inventory[i] = InventoryRepository{RepositoryMetadata: r}
+		if i >= MaxInventorySecurityRepositories || rateLimited {
+			continue
+		}
+
+		security, err := getRepositorySecurity(ctx, client, r.Owner.Login, r.Name)
+		if err != nil {
+			backend.Logger.Warn("could not read the security features of the repository", "repository", r.NameWithOwner, "error", err)
+			// the next requests would fail as well
+			var rateLimitErr *googlegithub.RateLimitError
+			rateLimited = errors.As(err, &rateLimitErr)
+			continue
+		}
+		inventory[i].Security = security
+	}
+	return inventory, nil
+}
+
+func getRepositorySecurity(ctx context.Context, client models.Client, owner string, name string) (RepositorySecurity, error) {
+	security := RepositorySecurity{}
+
+	repository, _, err := client.GetRepository(ctx, owner, name)
+	if err != nil {
+		return security, err
+	}
+	if s := repository.GetSecurityAndAnalysis(); s != nil {
+		security.SecretScanning = securityFeatureEnabled(s.GetSecretScanning().GetStatus())
+		security.SecretScanningPushProtection = securityFeatureEnabled(s.GetSecretScanningPushProtection().GetStatus())
+		security.DependabotSecurityUpdates = securityFeatureEnabled(s.GetDependabotSecurityUpdates().GetStatus())
+	}
+
+	setup, resp, err := client.GetCodeScanningDefaultSetup(ctx, owner, name)
+	if err != nil {
+		// code scanning is not available for the repository, or the token is not allowed to read its settings
+		if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) {
+			backend.Logger.Debug("code scanning default setup not available", "owner", owner, "repository", name, "error", err)
+			return security, nil
+		}
+		return security, err
+	}
+	security.CodeScanningDefaultSetup = googlegithub.Ptr(setup.GetState() == "configured")
+	return security, nil
+}
+
+// securityFeatureEnabled converts the status of a security feature, nil when it is unknown
+func securityFeatureEnabled(status string) *bool {
+	switch status {
+	case "enabled":
+		return googlegithub.Ptr(true)
+	case "disabled":
+		return googlegithub.Ptr(false)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	googlegithub "github.com/google/go-github/v84/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+type inventoryMockClient struct {
+	*testutil.TestClient
+	// err is returned when getting a repository
+	err   error
+	calls int
+}
+
+func (m *inventoryMockClient) GetRepository(_ context.Context, _, repo string) (*googlegithub.Repository, *googlegithub.Response, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, &googlegithub.Response{Response: &http.Response{StatusCode: http.StatusForbidden}}, m.err
+	}
+	if repo != "grafana" {
+		// only the administrators get the security and analysis settings
+		return &googlegithub.Repository{}, &googlegithub.Response{}, nil
+	}
+	return &googlegithub.Repository{
+		SecurityAndAnalysis: &googlegithub.SecurityAndAnalysis{
+			SecretScanning:               &googlegithub.SecretScanning{Status: googlegithub.Ptr("enabled")},
+			SecretScanningPushProtection: &googlegithub.SecretScanningPushProtection{Status: googlegithub.Ptr("disabled")},
+			DependabotSecurityUpdates:    &googlegithub.DependabotSecurityUpdates{Status: googlegithub.Ptr("enabled")},
+		},
+	}, &googlegithub.Response{}, nil
+}
+
+func (m *inventoryMockClient) GetCodeScanningDefaultSetup(_ context.Context, _, repo string) (*googlegithub.DefaultSetupConfiguration, *googlegithub.Response, error) {
+	if repo != "grafana" {
+		return nil, &googlegithub.Response{Response: &http.Response{StatusCode: http.StatusForbidden}}, errors.New("forbidden")
+	}
+	return &googlegithub.DefaultSetupConfiguration{State: googlegithub.Ptr("configured")}, &googlegithub.Response{}, nil
+}
+
+func newInventoryMockClient(t *testing.T) *inventoryMockClient {
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryListRepositoryInventory)
+		require.True(t, ok)
+
+		createdAt := time.Date(2020, 8, 25, 16, 21, 56, 0, time.UTC)
+		for _, name := range []string{"grafana", "loki"} {
+			node := struct {
+				Repository RepositoryMetadata `graphql:"... on Repository"`
+			}{}
+			r := &node.Repository
+			r.Name = name
+			r.Owner.Login = "grafana"
+			r.NameWithOwner = "grafana/" + name
+			r.URL = "https://github.com/grafana/" + name
+			r.CreatedAt = githubv4.DateTime{Time: createdAt}
+			r.Visibility = githubv4.RepositoryVisibilityPublic
+			r.DefaultBranchRef = &struct{ Name string }{Name: "main"}
+			r.PushedAt = &githubv4.DateTime{Time: createdAt.AddDate(1, 0, 0)}
+			r.DiskUsage = googlegithub.Ptr(int64(2048))
+			r.HasVulnerabilityAlertsEnabled = true
+			query.Search.Nodes = append(query.Search.Nodes, node)
+		}
+
+		grafana := &query.Search.Nodes[0].Repository
+		grafana.LicenseInfo = &struct {
+			SpdxID string `graphql:"spdxId"`
+			Name   string
+		}{SpdxID: "AGPL-3.0", Name: "GNU Affero General Public License v3.0"}
+		grafana.RepositoryTopics.Nodes = make([]struct{ Topic struct{ Name string } }, 2)
+		grafana.RepositoryTopics.Nodes[0].Topic.Name = "monitoring"
+		grafana.RepositoryTopics.Nodes[1].Topic.Name = "grafana"
+		grafana.Languages.Edges = make([]struct {
+			Size int64
+			Node struct{ Name string }
+		}, 2)
+		grafana.Languages.Edges[0].Size, grafana.Languages.Edges[0].Node.Name = 3000, "TypeScript"
+		grafana.Languages.Edges[1].Size, grafana.Languages.Edges[1].Node.Name = 2000, "Go"
+
+		loki := &query.Search.Nodes[1].Repository
+		loki.IsArchived = true
+		loki.LicenseInfo = &struct {
+			SpdxID string `graphql:"spdxId"`
+			Name   string
+		}{SpdxID: "NOASSERTION", Name: "Other"}
+	}
+
+	return &inventoryMockClient{TestClient: testutil.NewTestClient(t, nil, testQuery)}
+}
+
+func TestGetRepositoryInventory(t *testing.T) {
+	client := newInventoryMockClient(t)
+	inventory, err := GetRepositoryInventory(context.Background(), client, models.ListRepositoriesOptions{Owner: "grafana", Mode: models.RepositoriesModeInventory})
+	require.NoError(t, err)
+	require.Len(t, inventory, 2)
+
+	assert.Equal(t, "AGPL-3.0", inventory[0].License())
+	assert.Equal(t, []string{"monitoring", "grafana"}, inventory[0].Topics())
+	assert.Equal(t, RepositorySecurity{
+		SecretScanning:               googlegithub.Ptr(true),
+		SecretScanningPushProtection: googlegithub.Ptr(false),
+		DependabotSecurityUpdates:    googlegithub.Ptr(true),
+		CodeScanningDefaultSetup:     googlegithub.Ptr(true),
+	}, inventory[0].Security)
+
+	assert.Equal(t, "Other", inventory[1].License())
+	assert.Equal(t, RepositorySecurity{}, inventory[1].Security)
+}
+
+func TestRepositoryInventoryDataFrame(t *testing.T) {
+	client := newInventoryMockClient(t)
+	inventory, err := GetRepositoryInventory(context.Background(), client, models.ListRepositoriesOptions{Owner: "grafana", Mode: models.RepositoriesModeInventory})
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "repository_inventory", inventory)
+}
+
+func TestGetRepositoryInventorySecurityErrors(t *testing.T) {
+	t.Run("a repository whose security features can't be read has unknown security features", func(t *testing.T) {
+		client := newInventoryMockClient(t)
+		client.err = errors.New("forbidden")
+		inventory, err := GetRepositoryInventory(context.Background(), client, models.ListRepositoriesOptions{Owner: "grafana", Mode: models.RepositoriesModeInventory})
+		require.NoError(t, err)
+		require.Len(t, inventory, 2)
+		assert.Equal(t, RepositorySecurity{}, inventory[0].Security)
+		assert.Equal(t, 2, client.calls)
+	})
+
+	t.Run("stops reading the security features once rate limited", func(t *testing.T) {
+		client := newInventoryMockClient(t)
+		request, err := http.NewRequest(http.MethodGet, "https://api.github.com/repos/grafana/grafana", nil)
+		require.NoError(t, err)
+		client.err = &googlegithub.RateLimitError{
+			Response: &http.Response{StatusCode: http.StatusForbidden, Request: request},
+			Message:  "API rate limit exceeded",
+		}
+		inventory, err := GetRepositoryInventory(context.Background(), client, models.ListRepositoriesOptions{Owner: "grafana", Mode: models.RepositoriesModeInventory})
+		require.NoError(t, err)
+		require.Len(t, inventory, 2)
+		assert.Equal(t, 1, client.calls)
+	})
+}
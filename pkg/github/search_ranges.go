@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+// SearchResultLimit is the maximum number of results the GitHub search API returns for a single query.
+// Paging past it silently returns nothing, so searches matching more results are split into smaller time ranges.
+const SearchResultLimit = 1000
+
+// searchMinimumRange is the smallest time range a search is split into.
+// Search qualifiers have a precision of one second, so smaller ranges would not match fewer results.
+const searchMinimumRange = time.Second
+
+// githubLaunch is used as the start of the range when splitting searches that have no time range, like the repositories of an organization
+var githubLaunch = time.Date(2008, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// searchRangeFunc searches a single time range.
+// When limited is true and the search matches more than SearchResultLimit results, it must stop after the first page and report the range as truncated.
+type searchRangeFunc[T any] func(ctx context.Context, from time.Time, to time.Time, limited bool) (results []T, truncated bool, err error)
+
+// searchTimeRange runs the search over the time range, splitting the range in two recursively until every search returns all of its results.
+// Range qualifiers are inclusive, so a result on the boundary of two ranges is returned twice; callers have to deduplicate the results.
+func searchTimeRange[T any](ctx context.Context, from time.Time, to time.Time, search searchRangeFunc[T]) ([]T, error) {
+	limited := to.Sub(from) > searchMinimumRange
+
+	results, truncated, err := search(ctx, from, to, limited)
+	if err != nil {
+		return nil, err
+	}
+	if !truncated {
+		return results, nil
+	}
+
+	middle := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+	if !middle.After(from) {
+		middle = from.Add(searchMinimumRange)
+	}
+
+	before, err := searchTimeRange(ctx, from, middle, search)
+	if err != nil {
+		return nil, err
+	}
+	after, err := searchTimeRange(ctx, middle, to, search)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(before, after...), nil
+}
+
+// dedupeResults removes the results with the same key, keeping the first one
+func dedupeResults[T any](results []T, key func(T) string) []T {
+	var (
+		seen    = map[string]bool{}
+		deduped = make([]T, 0, len(results))
+	)
+
+	for _, v := range results {
+		k := key(v)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		deduped = append(deduped, v)
+	}
+
+	return deduped
+}
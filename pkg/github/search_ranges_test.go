@@ -0,0 +1,115 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestSearchTimeRange(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		from = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to   = from.Add(24 * time.Hour)
+	)
+
+	// one result every minute, so the whole day matches 1441 results
+	search := func(ctx context.Context, from time.Time, to time.Time, limited bool) ([]time.Time, bool, error) {
+		results := []time.Time{}
+		for ts := from; !ts.After(to); ts = ts.Add(time.Minute) {
+			results = append(results, ts)
+		}
+		if limited && len(results) > SearchResultLimit {
+			return nil, true, nil
+		}
+		return results, false, nil
+	}
+
+	results, err := searchTimeRange(ctx, from, to, search)
+	require.NoError(t, err)
+
+	results = dedupeResults(results, func(ts time.Time) string {
+		return ts.String()
+	})
+	assert.Len(t, results, 24*60+1)
+	assert.Equal(t, from, results[0])
+	assert.Equal(t, to, results[len(results)-1])
+}
+
+func TestSearchTimeRangeMinimum(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		from  = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		calls = 0
+	)
+
+	// a search that is always truncated stops being split once the range can't be split anymore
+	search := func(ctx context.Context, from time.Time, to time.Time, limited bool) ([]string, bool, error) {
+		calls++
+		if limited {
+			return nil, true, nil
+		}
+		return []string{fmt.Sprintf("%s..%s", from.Format(time.RFC3339), to.Format(time.RFC3339))}, false, nil
+	}
+
+	results, err := searchTimeRange(ctx, from, from.Add(4*time.Second), search)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"2024-01-01T00:00:00Z..2024-01-01T00:00:01Z",
+		"2024-01-01T00:00:01Z..2024-01-01T00:00:02Z",
+		"2024-01-01T00:00:02Z..2024-01-01T00:00:03Z",
+		"2024-01-01T00:00:03Z..2024-01-01T00:00:04Z",
+	}, results)
+	assert.Equal(t, 7, calls)
+}
+
+func TestGetIssuesInRangeSplitsLargeSearches(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		from    = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to      = from.Add(4 * 24 * time.Hour)
+		rangeRe = regexp.MustCompile(`created:(\S+)\.\.(\S+)`)
+		queries = []string{}
+		query   string
+	)
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		query = fmt.Sprint(variables["query"])
+		queries = append(queries, query)
+	}
+
+	// every day matches 400 issues, so ranges longer than two days have to be split
+	testQuery := func(t *testing.T, q interface{}) {
+		m := rangeRe.FindStringSubmatch(query)
+		require.Len(t, m, 3)
+		start, err := time.Parse(time.RFC3339, m[1])
+		require.NoError(t, err)
+		end, err := time.Parse(time.RFC3339, m[2])
+		require.NoError(t, err)
+
+		q.(*QuerySearchIssues).Search.IssueCount = int64(end.Sub(start).Hours() / 24 * 400)
+	}
+
+	client := testutil.NewTestClient(t, testVariables, testQuery)
+
+	opts := models.ListIssuesOptions{
+		Owner:      "grafana",
+		Repository: "grafana",
+		TimeField:  models.IssueCreatedAt,
+	}
+	_, err := GetIssuesInRange(ctx, client, opts, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"is:issue repo:grafana/grafana created:2024-01-01T00:00:00Z..2024-01-05T00:00:00Z",
+		"is:issue repo:grafana/grafana created:2024-01-01T00:00:00Z..2024-01-03T00:00:00Z",
+		"is:issue repo:grafana/grafana created:2024-01-03T00:00:00Z..2024-01-05T00:00:00Z",
+	}, queries)
+}
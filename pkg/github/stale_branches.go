@@ -0,0 +1,231 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// Branch statuses of the stale branches report
+const (
+	BranchStatusDefault   = "default"
+	BranchStatusMerged    = "merged"
+	BranchStatusAbandoned = "abandoned"
+	BranchStatusActive    = "active"
+)
+
+// QueryDefaultBranch is the GraphQL query for getting the default branch of a repository
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    defaultBranchRef {
+//	      name
+//	    }
+//	  }
+//	}
+type QueryDefaultBranch struct {
+	Repository struct {
+		DefaultBranchRef *struct {
+			Name string
+		}
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// QueryListStaleBranches is the GraphQL query for listing the branches of a repository compared to its default branch
+//
+//	{
+//	  repository(name: "grafana", owner: "grafana") {
+//	    refs(refPrefix: "refs/heads/", first: 50) {
+//	      nodes {
+//	        name
+//	        target {
+//	          ... on Commit {
+//	            oid
+//	            author {
+//	              date
+//	            }
+//	          }
+//	        }
+//	        compare(headRef: "main") {
+//	          aheadBy
+//	          behindBy
+//	        }
+//	        associatedPullRequests(first: 1, orderBy: {field: UPDATED_AT, direction: DESC}) {
+//	          nodes {
+//	            number
+//	            state
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListStaleBranches struct {
+	Repository struct {
+		Refs struct {
+			Nodes []struct {
+				Name   string
+				Target struct {
+					Commit commit `graphql:"... on Commit"`
+				}
+				// the default branch is the head of the comparison, so it is ahead of the branch by the commits the branch is behind
+				Compare *struct {
+					AheadBy  int64
+					BehindBy int64
+				} `graphql:"compare(headRef: $defaultBranch)"`
+				AssociatedPullRequests struct {
+					Nodes []struct {
+						Number int64
+						State  githubv4.PullRequestState
+					}
+				} `graphql:"associatedPullRequests(first: 1, orderBy: {field: UPDATED_AT, direction: DESC})"`
+			}
+			PageInfo models.PageInfo
+		} `graphql:"refs(refPrefix: \"refs/heads/\", first: 50, after: $cursor, query: $query)"`
+	} `graphql:"repository(name: $name, owner: $owner)"`
+}
+
+// StaleBranch is a branch compared to the default branch of its repository
+type StaleBranch struct {
+	branchDTO
+	AheadBy          int64
+	BehindBy         int64
+	PullRequest      *int64
+	PullRequestState githubv4.PullRequestState
+	Status           string
+}
+
+// StaleBranches is the stale branches report of a repository
+type StaleBranches []StaleBranch
+
+// Frames converts the stale branches report to a Grafana DataFrame
+func (b StaleBranches) Frames() data.Frames {
+	frame := data.NewFrame(
+		"stale_branches",
+		data.NewField("name", nil, []string{}),
+		data.NewField("commit_sha", nil, []string{}),
+		data.NewField("author", nil, []string{}),
+		data.NewField("author_login", nil, []string{}),
+		data.NewField("commit_date", nil, []time.Time{}),
+		data.NewField("ahead_by", nil, []int64{}),
+		data.NewField("behind_by", nil, []int64{}),
+		data.NewField("pull_request", nil, []*int64{}),
+		data.NewField("pull_request_state", nil, []string{}),
+		data.NewField("merged", nil, []bool{}),
+		data.NewField("abandoned", nil, []bool{}),
+		data.NewField("status", nil, []string{}),
+	)
+
+	for _, v := range b {
+		frame.AppendRow(
+			v.Name,
+			v.CommitSHA,
+			v.AuthorName,
+			v.AuthorLogin,
+			v.CommitDate,
+			v.AheadBy,
+			v.BehindBy,
+			v.PullRequest,
+			string(v.PullRequestState),
+			v.Status == BranchStatusMerged,
+			v.Status == BranchStatusAbandoned,
+			v.Status,
+		)
+	}
+
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{frame}
+}
+
+// staleSince returns the time before which there was no activity for a branch or a repository to be stale
+func staleSince(days int, now time.Time) time.Time {
+	if days <= 0 {
+		days = models.DefaultStaleDays
+	}
+	return now.AddDate(0, 0, -days)
+}
+
+// GetStaleBranches lists up to PageNumberLimit pages of branches of a repository with the number of commits they are ahead and behind the default branch.
+// A branch whose last pull request was merged is merged, and so is a branch without commits missing from the default branch once it is older than the stale window,
+// so that a branch that was just created isn't merged before any work was pushed.
+// An unmerged branch without commits for the stale window and without an open pull request is abandoned.
+func GetStaleBranches(ctx context.Context, client models.Client, opts models.ListBranchesOptions, now time.Time) (StaleBranches, error) {
+	q := &QueryDefaultBranch{}
+	if err := client.Query(ctx, q, map[string]interface{}{
+		"owner": githubv4.String(opts.Owner),
+		"name":  githubv4.String(opts.Repository),
+	}); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if q.Repository.DefaultBranchRef == nil {
+		return StaleBranches{}, nil
+	}
+	defaultBranch := q.Repository.DefaultBranchRef.Name
+
+	var (
+		variables = map[string]interface{}{
+			"cursor":        (*githubv4.String)(nil),
+			"owner":         githubv4.String(opts.Owner),
+			"name":          githubv4.String(opts.Repository),
+			"query":         githubv4.String(opts.Query),
+			"defaultBranch": githubv4.String(defaultBranch),
+		}
+
+		since    = staleSince(opts.StaleDays, now)
+		branches = StaleBranches{}
+	)
+
+	for i := 0; i < PageNumberLimit; i++ {
+		q := &QueryListStaleBranches{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, node := range q.Repository.Refs.Nodes {
+			branch := StaleBranch{
+				branchDTO: branchDTO{
+					Name:        node.Name,
+					CommitSHA:   node.Target.Commit.OID,
+					AuthorName:  node.Target.Commit.Author.User.Name,
+					AuthorLogin: node.Target.Commit.Author.User.Login,
+					CommitDate:  node.Target.Commit.Author.Date.Time,
+				},
+			}
+			if node.Compare != nil {
+				branch.AheadBy = node.Compare.BehindBy
+				branch.BehindBy = node.Compare.AheadBy
+			}
+			if len(node.AssociatedPullRequests.Nodes) > 0 {
+				pr := node.AssociatedPullRequests.Nodes[0]
+				branch.PullRequest = &pr.Number
+				branch.PullRequestState = pr.State
+			}
+			branch.Status = branchStatus(branch, node.Name == defaultBranch, node.Compare != nil, since)
+			branches = append(branches, branch)
+		}
+
+		if !q.Repository.Refs.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Repository.Refs.PageInfo.EndCursor
+	}
+
+	return branches, nil
+}
+
+func branchStatus(b StaleBranch, isDefault bool, compared bool, since time.Time) string {
+	switch {
+	case isDefault:
+		return BranchStatusDefault
+	case b.PullRequestState == githubv4.PullRequestStateMerged || (compared && b.AheadBy == 0 && b.CommitDate.Before(since)):
+		return BranchStatusMerged
+	case b.PullRequestState != githubv4.PullRequestStateOpen && b.CommitDate.Before(since):
+		return BranchStatusAbandoned
+	}
+	return BranchStatusActive
+}
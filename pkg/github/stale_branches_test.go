@@ -0,0 +1,132 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+var staleTestNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newStaleBranchesTestClient(t *testing.T) *testutil.TestClient {
+	type node = struct {
+		Name   string
+		Target struct {
+			Commit commit `graphql:"... on Commit"`
+		}
+		Compare *struct {
+			AheadBy  int64
+			BehindBy int64
+		} `graphql:"compare(headRef: $defaultBranch)"`
+		AssociatedPullRequests struct {
+			Nodes []struct {
+				Number int64
+				State  githubv4.PullRequestState
+			}
+		} `graphql:"associatedPullRequests(first: 1, orderBy: {field: UPDATED_AT, direction: DESC})"`
+	}
+	branch := func(name string, daysAgo int, ahead int64, behind int64, pr int64, state githubv4.PullRequestState) node {
+		n := node{Name: name}
+		n.Target.Commit.OID = name + "-sha"
+		n.Target.Commit.Author.User.Login = "octocat"
+		n.Target.Commit.Author.Date = githubv4.GitTimestamp{Time: staleTestNow.AddDate(0, 0, -daysAgo)}
+		// the default branch is the head of the comparison
+		n.Compare = &struct {
+			AheadBy  int64
+			BehindBy int64
+		}{AheadBy: behind, BehindBy: ahead}
+		if pr > 0 {
+			n.AssociatedPullRequests.Nodes = append(n.AssociatedPullRequests.Nodes, struct {
+				Number int64
+				State  githubv4.PullRequestState
+			}{Number: pr, State: state})
+		}
+		return n
+	}
+
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		if _, ok := variables["cursor"]; ok {
+			assert.Equal(t, githubv4.String("main"), variables["defaultBranch"])
+		}
+	}
+	testQuery := func(t *testing.T, q interface{}) {
+		switch query := q.(type) {
+		case *QueryDefaultBranch:
+			query.Repository.DefaultBranchRef = &struct{ Name string }{Name: "main"}
+		case *QueryListStaleBranches:
+			query.Repository.Refs.Nodes = []node{
+				branch("main", 1, 0, 0, 0, ""),
+				branch("feature/fast-forward", 200, 0, 20, 0, ""),
+				branch("feature/squashed", 5, 3, 2, 12, githubv4.PullRequestStateMerged),
+				branch("feature/old", 120, 4, 50, 10, githubv4.PullRequestStateClosed),
+				branch("feature/old-open", 120, 4, 50, 11, githubv4.PullRequestStateOpen),
+				branch("feature/recent", 10, 2, 1, 0, ""),
+				// created from the default branch, no commits pushed yet
+				branch("feature/new", 0, 0, 0, 0, ""),
+			}
+		default:
+			t.Fatalf("unexpected query %T", q)
+		}
+	}
+	return testutil.NewTestClient(t, testVariables, testQuery)
+}
+
+func TestGetStaleBranches(t *testing.T) {
+	opts := models.ListBranchesOptions{Owner: "grafana", Repository: "grafana", Mode: models.BranchesModeStale}
+	branches, err := GetStaleBranches(context.Background(), newStaleBranchesTestClient(t), opts, staleTestNow)
+	require.NoError(t, err)
+
+	statuses := map[string]string{}
+	for _, b := range branches {
+		statuses[b.Name] = b.Status
+	}
+	assert.Equal(t, map[string]string{
+		"main":                 BranchStatusDefault,
+		"feature/fast-forward": BranchStatusMerged,
+		"feature/squashed":     BranchStatusMerged,
+		"feature/old":          BranchStatusAbandoned,
+		"feature/old-open":     BranchStatusActive,
+		"feature/recent":       BranchStatusActive,
+		"feature/new":          BranchStatusActive,
+	}, statuses)
+	assert.Equal(t, int64(4), branches[3].AheadBy)
+	assert.Equal(t, int64(50), branches[3].BehindBy)
+
+	// with a longer window the old branch is not abandoned yet
+	opts.StaleDays = 180
+	branches, err = GetStaleBranches(context.Background(), newStaleBranchesTestClient(t), opts, staleTestNow)
+	require.NoError(t, err)
+	assert.Equal(t, BranchStatusActive, branches[3].Status)
+}
+
+func TestGetStaleBranchesPageLimit(t *testing.T) {
+	pages := 0
+	testQuery := func(t *testing.T, q interface{}) {
+		switch query := q.(type) {
+		case *QueryDefaultBranch:
+			query.Repository.DefaultBranchRef = &struct{ Name string }{Name: "main"}
+		case *QueryListStaleBranches:
+			pages++
+			query.Repository.Refs.PageInfo.HasNextPage = true
+		}
+	}
+	opts := models.ListBranchesOptions{Owner: "grafana", Repository: "grafana", Mode: models.BranchesModeStale}
+	_, err := GetStaleBranches(context.Background(), testutil.NewTestClient(t, nil, testQuery), opts, staleTestNow)
+	require.NoError(t, err)
+	assert.Equal(t, PageNumberLimit, pages)
+}
+
+func TestStaleBranchesDataFrame(t *testing.T) {
+	opts := models.ListBranchesOptions{Owner: "grafana", Repository: "grafana", Mode: models.BranchesModeStale}
+	branches, err := GetStaleBranches(context.Background(), newStaleBranchesTestClient(t), opts, staleTestNow)
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "stale_branches", branches)
+}
@@ -0,0 +1,158 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryListRepositoryActivity is the GraphQL query for retrieving the repositories of an organization with their last push, issue and pull request
+//
+//	{
+//	  search(query: "org:grafana", type: REPOSITORY, first: 100) {
+//	    nodes {
+//	      ... on Repository {
+//	        nameWithOwner
+//	        isArchived
+//	        pushedAt
+//	        issues(first: 1, orderBy: {field: UPDATED_AT, direction: DESC}) {
+//	          nodes {
+//	            updatedAt
+//	          }
+//	        }
+//	        pullRequests(first: 1, orderBy: {field: UPDATED_AT, direction: DESC}) {
+//	          nodes {
+//	            updatedAt
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListRepositoryActivity struct {
+	Search RepositorySearch[RepositoryActivity] `graphql:"search(query: $query, type: REPOSITORY, first: 100, after: $cursor)"`
+}
+
+func (q *QueryListRepositoryActivity) search() *RepositorySearch[RepositoryActivity] {
+	return &q.Search
+}
+
+// RepositoryActivity is a repository with its last push and its last updated issue and pull request
+type RepositoryActivity struct {
+	Repository
+	IsArchived bool
+	PushedAt   *githubv4.DateTime
+	Issues     struct {
+		Nodes []struct {
+			UpdatedAt githubv4.DateTime
+		}
+	} `graphql:"issues(first: 1, orderBy: {field: UPDATED_AT, direction: DESC})"`
+	PullRequests struct {
+		Nodes []struct {
+			UpdatedAt githubv4.DateTime
+		}
+	} `graphql:"pullRequests(first: 1, orderBy: {field: UPDATED_AT, direction: DESC})"`
+}
+
+// LastIssueAt returns when the last issue of the repository was updated
+func (r RepositoryActivity) LastIssueAt() *time.Time {
+	if len(r.Issues.Nodes) == 0 {
+		return nil
+	}
+	return &r.Issues.Nodes[0].UpdatedAt.Time
+}
+
+// LastPullRequestAt returns when the last pull request of the repository was updated
+func (r RepositoryActivity) LastPullRequestAt() *time.Time {
+	if len(r.PullRequests.Nodes) == 0 {
+		return nil
+	}
+	return &r.PullRequests.Nodes[0].UpdatedAt.Time
+}
+
+// LastActivityAt returns the time of the last push, issue or pull request of the repository, or its creation when it has none
+func (r RepositoryActivity) LastActivityAt() time.Time {
+	last := r.CreatedAt.Time
+	for _, t := range []*time.Time{r.LastIssueAt(), r.LastPullRequestAt()} {
+		if t != nil && t.After(last) {
+			last = *t
+		}
+	}
+	if r.PushedAt != nil && r.PushedAt.After(last) {
+		last = r.PushedAt.Time
+	}
+	return last
+}
+
+// StaleRepository is a repository of the stale repositories report
+type StaleRepository struct {
+	RepositoryActivity
+	Stale bool
+}
+
+// StaleRepositories is the stale repositories report of an organization
+type StaleRepositories []StaleRepository
+
+// Frames converts the stale repositories report to a Grafana DataFrame
+func (r StaleRepositories) Frames() data.Frames {
+	frame := data.NewFrame(
+		"stale_repositories",
+		data.NewField("name", nil, []string{}),
+		data.NewField("owner", nil, []string{}),
+		data.NewField("name_with_owner", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("is_archived", nil, []bool{}),
+		data.NewField("pushed_at", nil, []*time.Time{}),
+		data.NewField("last_issue_at", nil, []*time.Time{}),
+		data.NewField("last_pull_request_at", nil, []*time.Time{}),
+		data.NewField("last_activity_at", nil, []time.Time{}),
+		data.NewField("stale", nil, []bool{}),
+	)
+
+	for _, v := range r {
+		var pushedAt *time.Time
+		if v.PushedAt != nil {
+			pushedAt = &v.PushedAt.Time
+		}
+
+		frame.AppendRow(
+			v.Name,
+			v.Owner.Login,
+			v.NameWithOwner,
+			v.URL,
+			v.IsArchived,
+			pushedAt,
+			v.LastIssueAt(),
+			v.LastPullRequestAt(),
+			v.LastActivityAt(),
+			v.Stale,
+		)
+	}
+
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{frame}
+}
+
+// GetStaleRepositories lists the repositories of an organization with their last activity.
+// A repository without pushes, issues or pull requests during the stale window is flagged as stale.
+func GetStaleRepositories(ctx context.Context, client models.Client, opts models.ListRepositoriesOptions, now time.Time) (StaleRepositories, error) {
+	repos, err := searchAllRepositories(ctx, client, opts, func() repositorySearchQuery[RepositoryActivity] {
+		return &QueryListRepositoryActivity{}
+	}, func(r RepositoryActivity) string {
+		return r.NameWithOwner
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	since := staleSince(opts.StaleDays, now)
+	stale := make(StaleRepositories, len(repos))
+	for i, r := range repos {
+		stale[i] = StaleRepository{RepositoryActivity: r, Stale: r.LastActivityAt().Before(since)}
+	}
+	return stale, nil
+}
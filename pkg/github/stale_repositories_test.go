@@ -0,0 +1,83 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func newStaleRepositoriesTestClient(t *testing.T) *testutil.TestClient {
+	daysAgo := func(days int) githubv4.DateTime {
+		return githubv4.DateTime{Time: staleTestNow.AddDate(0, 0, -days)}
+	}
+	repository := func(name string, created int, pushed int, issue int, pr int) RepositoryActivity {
+		r := RepositoryActivity{}
+		r.Name = name
+		r.Owner.Login = "grafana"
+		r.NameWithOwner = "grafana/" + name
+		r.URL = "https://github.com/grafana/" + name
+		r.CreatedAt = daysAgo(created)
+		if pushed >= 0 {
+			pushed := daysAgo(pushed)
+			r.PushedAt = &pushed
+		}
+		if issue >= 0 {
+			r.Issues.Nodes = append(r.Issues.Nodes, struct{ UpdatedAt githubv4.DateTime }{UpdatedAt: daysAgo(issue)})
+		}
+		if pr >= 0 {
+			r.PullRequests.Nodes = append(r.PullRequests.Nodes, struct{ UpdatedAt githubv4.DateTime }{UpdatedAt: daysAgo(pr)})
+		}
+		return r
+	}
+
+	testQuery := func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryListRepositoryActivity)
+		require.True(t, ok)
+		for _, r := range []RepositoryActivity{
+			repository("grafana", 1000, 1, 2, 3),
+			// only the issues are active
+			repository("docs", 1000, 400, 10, -1),
+			repository("old", 1000, 200, 300, 150),
+			// never pushed to
+			repository("empty", 30, -1, -1, -1),
+		} {
+			query.Search.Nodes = append(query.Search.Nodes, struct {
+				Repository RepositoryActivity `graphql:"... on Repository"`
+			}{Repository: r})
+		}
+	}
+	return testutil.NewTestClient(t, nil, testQuery)
+}
+
+func TestGetStaleRepositories(t *testing.T) {
+	opts := models.ListRepositoriesOptions{Owner: "grafana", Mode: models.RepositoriesModeStale}
+	repos, err := GetStaleRepositories(context.Background(), newStaleRepositoriesTestClient(t), opts, staleTestNow)
+	require.NoError(t, err)
+
+	stale := map[string]bool{}
+	for _, r := range repos {
+		stale[r.Name] = r.Stale
+	}
+	assert.Equal(t, map[string]bool{"grafana": false, "docs": false, "old": true, "empty": false}, stale)
+	assert.Equal(t, staleTestNow.AddDate(0, 0, -150), repos[2].LastActivityAt())
+
+	opts.StaleDays = 7
+	repos, err = GetStaleRepositories(context.Background(), newStaleRepositoriesTestClient(t), opts, staleTestNow)
+	require.NoError(t, err)
+	assert.True(t, repos[1].Stale)
+	assert.True(t, repos[3].Stale)
+}
+
+func TestStaleRepositoriesDataFrame(t *testing.T) {
+	opts := models.ListRepositoriesOptions{Owner: "grafana", Mode: models.RepositoriesModeStale}
+	repos, err := GetStaleRepositories(context.Background(), newStaleRepositoriesTestClient(t), opts, staleTestNow)
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "stale_repositories", repos)
+}
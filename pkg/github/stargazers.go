@@ -46,6 +46,17 @@ type QueryStargazers struct {
 	} `graphql:"repository(owner: $owner, name: $name)"`
 }
 
+// QueryStargazersHistory is the GraphQL query for retrieving the stargazers of a repository from the oldest to the newest, it selects the same fields as QueryStargazers
+type QueryStargazersHistory struct {
+	Repository struct {
+		Stargazers struct {
+			TotalCount int64
+			PageInfo   models.PageInfo
+			Edges      []Stargazer
+		} `graphql:"stargazers(first: 100, orderBy: {field: STARRED_AT, direction: ASC}, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
 // Stargazer a GitHub user tied to when they starred the repository
 type Stargazer struct {
 	StarredAt githubv4.DateTime
@@ -96,8 +107,51 @@ func (a StargazersWrapper) Frames() data.Frames {
 	return data.Frames{frame}
 }
 
-// GetStargazers gets all stargazers for a GitHub repository
-func GetStargazers(ctx context.Context, client models.Client, opts models.ListStargazersOptions, timeRange backend.TimeRange) (StargazersWrapper, error) {
+// GetStargazers gets the stargazers of a GitHub repository who starred it during the time range.
+// The stargazers are read from the history of the repository, which only fetches the stars added since the previous query.
+// Until the history is backfilled, the stargazers are listed from the newest until the start of the time range instead.
+// They are listed the same way when the history can't be updated.
+func GetStargazers(ctx context.Context, client models.Client, history *repositoryHistory[Stargazer], opts models.ListStargazersOptions, timeRange backend.TimeRange) (StargazersWrapper, error) {
+	err := history.update(ctx, func(ctx context.Context, cursor *githubv4.String) (historyPage[Stargazer], error) {
+		q := &QueryStargazersHistory{}
+		if err := client.Query(ctx, q, map[string]interface{}{
+			"cursor": cursor,
+			"owner":  githubv4.String(opts.Owner),
+			"name":   githubv4.String(opts.Repository),
+		}); err != nil {
+			return historyPage[Stargazer]{}, errors.WithStack(err)
+		}
+		stargazers := q.Repository.Stargazers
+		return historyPage[Stargazer]{Items: stargazers.Edges, PageInfo: stargazers.PageInfo, TotalCount: stargazers.TotalCount}, nil
+	})
+	if err != nil {
+		backend.Logger.Warn("could not update the history of the stargazers, listing them from the newest", "owner", opts.Owner, "repository", opts.Repository, "error", err)
+		return getStargazersInRange(ctx, client, opts, timeRange)
+	}
+
+	all, total, upToDate := history.snapshot()
+	if !upToDate {
+		return getStargazersInRange(ctx, client, opts, timeRange)
+	}
+
+	// the stargazers are listed from the newest to the oldest, and counted back from the total count
+	// since the stargazers who removed their star are still in the history
+	stargazers := StargazersWrapper{}
+	for i := len(all) - 1; i >= 0; i-- {
+		starredAt := all[i].StarredAt.Time
+		if starredAt.Before(timeRange.From) {
+			break
+		}
+		if !starredAt.After(timeRange.To) {
+			stargazers = append(stargazers, StargazerWrapper{Stargazer: all[i], StarCount: total - int64(len(all)-1-i)})
+		}
+	}
+
+	return stargazers, nil
+}
+
+// getStargazersInRange lists the stargazers of a GitHub repository from the newest until the start of the time range
+func getStargazersInRange(ctx context.Context, client models.Client, opts models.ListStargazersOptions, timeRange backend.TimeRange) (StargazersWrapper, error) {
 	var (
 		variables = map[string]interface{}{
 			"cursor": (*githubv4.String)(nil),
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -9,6 +10,8 @@ import (
 	"github.com/grafana/github-datasource/pkg/testutil"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetStargazers(t *testing.T) {
@@ -28,10 +31,10 @@ func TestGetStargazers(t *testing.T) {
 
 	client := testutil.NewTestClient(t,
 		testVariables,
-		testutil.GetTestQueryFunction(&QueryStargazers{}),
+		testutil.GetTestQueryFunction(&QueryStargazersHistory{}),
 	)
 
-	_, err := GetStargazers(ctx, client, opts, timeRange)
+	_, err := GetStargazers(ctx, client, &repositoryHistory[Stargazer]{}, opts, timeRange)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -95,3 +98,101 @@ func TestStargazersDataframe(t *testing.T) {
 
 	testutil.CheckGoldenFramer(t, "stargazers", stargazers)
 }
+
+func TestGetStargazersFromHistory(t *testing.T) {
+	var (
+		start   = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		queries = 0
+	)
+
+	client := testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		queries++
+		query, ok := q.(*QueryStargazersHistory)
+		require.True(t, ok)
+		// every query returns a single new stargazer, starred a day after the previous one
+		query.Repository.Stargazers.Edges = []Stargazer{{StarredAt: githubv4.DateTime{Time: start.AddDate(0, 0, queries)}, Node: models.User{Login: fmt.Sprint(queries)}}}
+		query.Repository.Stargazers.PageInfo.EndCursor = githubv4.String(fmt.Sprint(queries))
+		query.Repository.Stargazers.PageInfo.HasNextPage = queries < 3
+		// the first stargazer removed their star
+		query.Repository.Stargazers.TotalCount = int64(queries - 1)
+	})
+
+	history := &repositoryHistory[Stargazer]{}
+	opts := models.ListStargazersOptions{Owner: "grafana", Repository: "grafana"}
+	timeRange := backend.TimeRange{From: start.AddDate(0, 0, 2), To: start.AddDate(0, 0, 4)}
+
+	stargazers, err := GetStargazers(context.Background(), client, history, opts, timeRange)
+	require.NoError(t, err)
+	assert.Equal(t, 3, queries)
+	require.Len(t, stargazers, 2)
+	assert.Equal(t, "3", stargazers[0].Node.Login)
+	assert.Equal(t, int64(2), stargazers[0].StarCount)
+	assert.Equal(t, "2", stargazers[1].Node.Login)
+	assert.Equal(t, int64(1), stargazers[1].StarCount)
+
+	stargazers, err = GetStargazers(context.Background(), client, history, opts, timeRange)
+	require.NoError(t, err)
+	assert.Equal(t, 4, queries)
+	require.Len(t, stargazers, 3)
+	assert.Equal(t, "4", stargazers[0].Node.Login)
+	assert.Equal(t, int64(3), stargazers[0].StarCount)
+}
+
+func TestGetStargazersDuringBackfill(t *testing.T) {
+	var (
+		start          = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		historyQueries = 0
+		rangeQueries   = 0
+	)
+
+	client := testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		switch query := q.(type) {
+		case *QueryStargazersHistory:
+			// the history has more pages than a query backfills
+			historyQueries++
+			query.Repository.Stargazers.Edges = []Stargazer{{StarredAt: githubv4.DateTime{Time: start}}}
+			query.Repository.Stargazers.PageInfo = models.PageInfo{HasNextPage: true, EndCursor: githubv4.String(fmt.Sprint(historyQueries))}
+		case *QueryStargazers:
+			rangeQueries++
+			query.Repository.Stargazers.TotalCount = 1000
+			query.Repository.Stargazers.Edges = []Stargazer{
+				{StarredAt: githubv4.DateTime{Time: start.AddDate(1, 0, 2)}, Node: models.User{Login: "new"}},
+				{StarredAt: githubv4.DateTime{Time: start.AddDate(1, 0, 0)}, Node: models.User{Login: "old"}},
+			}
+			query.Repository.Stargazers.PageInfo.HasNextPage = true
+		default:
+			t.Fatalf("unexpected query %T", q)
+		}
+	})
+
+	opts := models.ListStargazersOptions{Owner: "grafana", Repository: "grafana"}
+	timeRange := backend.TimeRange{From: start.AddDate(1, 0, 1), To: start.AddDate(1, 0, 3)}
+
+	// the stargazers in the time range are listed from the newest until the history is backfilled
+	stargazers, err := GetStargazers(context.Background(), client, &repositoryHistory[Stargazer]{}, opts, timeRange)
+	require.NoError(t, err)
+	assert.Equal(t, RepositoryHistoryPageLimit, historyQueries)
+	assert.Equal(t, 1, rangeQueries)
+	require.Len(t, stargazers, 1)
+	assert.Equal(t, "new", stargazers[0].Node.Login)
+	assert.Equal(t, int64(1000), stargazers[0].StarCount)
+}
+
+func TestGetStargazersHistoryError(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &failingHistoryClient{TestClient: testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		query, ok := q.(*QueryStargazers)
+		require.True(t, ok)
+		query.Repository.Stargazers.TotalCount = 10
+		query.Repository.Stargazers.Edges = []Stargazer{{StarredAt: githubv4.DateTime{Time: start}, Node: models.User{Login: "new"}}}
+	})}
+	opts := models.ListStargazersOptions{Owner: "grafana", Repository: "grafana"}
+	timeRange := backend.TimeRange{From: start.AddDate(0, 0, -1), To: start.AddDate(0, 0, 1)}
+
+	// the stargazers are listed from the newest when the history can't be updated
+	stargazers, err := GetStargazers(context.Background(), client, &repositoryHistory[Stargazer]{}, opts, timeRange)
+	require.NoError(t, err)
+	require.Len(t, stargazers, 1)
+	assert.Equal(t, "new", stargazers[0].Node.Login)
+	assert.Equal(t, int64(10), stargazers[0].StarCount)
+}
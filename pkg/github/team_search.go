@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// SearchQueryMaxLength is the maximum number of characters GitHub accepts in a search query
+const SearchQueryMaxLength = 256
+
+// TeamMembersCacheDuration is how long the members of a team are kept before they are resolved again
+const TeamMembersCacheDuration = time.Minute * 10
+
+// QueryListTeamMembers is the GraphQL query for listing the logins of the members of a team
+//
+//	{
+//	  organization(login: "grafana") {
+//	    team(slug: "backend") {
+//	      members(first: 100) {
+//	        nodes {
+//	          login
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListTeamMembers struct {
+	Organization struct {
+		Team *struct {
+			Members struct {
+				Nodes []struct {
+					Login string
+				}
+				PageInfo models.PageInfo
+			} `graphql:"members(first: 100, after: $cursor)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// teamNotFoundError is returned when a team doesn't exist or isn't visible to the configured credentials
+type teamNotFoundError struct {
+	org  string
+	slug string
+}
+
+func (e teamNotFoundError) Error() string {
+	return fmt.Sprintf("team %s/%s not found", e.org, e.slug)
+}
+
+// GetTeamMembers lists the logins of every member of a team, including the members of its child teams
+func GetTeamMembers(ctx context.Context, client models.Client, org string, slug string) ([]string, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"login":  githubv4.String(org),
+			"slug":   githubv4.String(slug),
+		}
+
+		members = []string{}
+	)
+
+	for {
+		q := &QueryListTeamMembers{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if q.Organization.Team == nil {
+			return nil, backend.DownstreamError(teamNotFoundError{org: org, slug: slug})
+		}
+		for _, v := range q.Organization.Team.Members.Nodes {
+			members = append(members, v.Login)
+		}
+		if !q.Organization.Team.Members.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Organization.Team.Members.PageInfo.EndCursor
+	}
+
+	return members, nil
+}
+
+type teamMembersCacheEntry struct {
+	members   []string
+	expiresAt time.Time
+}
+
+// teamMembersCache keeps the resolved members of teams so that every panel refresh does not resolve them again
+type teamMembersCache struct {
+	mu      sync.Mutex
+	entries map[string]teamMembersCacheEntry
+}
+
+func newTeamMembersCache() *teamMembersCache {
+	return &teamMembersCache{
+		entries: map[string]teamMembersCacheEntry{},
+	}
+}
+
+// get returns the members of the team, resolving them with the client when they are not cached or expired.
+// A nil cache resolves them every time.
+func (c *teamMembersCache) get(ctx context.Context, client models.Client, org string, slug string) ([]string, error) {
+	if c == nil {
+		return GetTeamMembers(ctx, client, org, slug)
+	}
+
+	key := strings.ToLower(fmt.Sprintf("%s/%s", org, slug))
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && entry.expiresAt.After(time.Now()) {
+		return entry.members, nil
+	}
+
+	members, err := GetTeamMembers(ctx, client, org, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// expired entries are dropped whenever a new one is stored so that the map doesn't grow with old teams
+	for k, v := range c.entries {
+		if v.expiresAt.Before(time.Now()) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = teamMembersCacheEntry{
+		members:   members,
+		expiresAt: time.Now().Add(TeamMembersCacheDuration),
+	}
+
+	return members, nil
+}
+
+// resolveTeam fills the members of the team set in the search options
+func (c *teamMembersCache) resolveTeam(ctx context.Context, client models.Client, owner string, opts *models.TeamSearchOptions) error {
+	if opts.Team == "" {
+		return nil
+	}
+
+	org, slug := opts.TeamOrganization(owner)
+	members, err := c.get(ctx, client, org, slug)
+	if err != nil {
+		return err
+	}
+	opts.TeamMembers = members
+	return nil
+}
+
+// teamSearchQueries expands the members of the team into the base search query, the results of the queries have to be merged.
+// Repeated author terms match any of the authors, so the members are grouped in as few queries as the 256 characters limit of GitHub allows.
+// The other qualifiers match when every term matches, so there is a query per member.
+// No query is returned when a team is set but has no members, because the base query alone would match every issue or pull request.
+func teamSearchQueries(base string, opts models.TeamSearchOptions) []string {
+	if opts.Team == "" {
+		return []string{base}
+	}
+
+	var (
+		queries    = []string{}
+		qualifier  = opts.Qualifier()
+		combinable = qualifier.Combinable()
+		current    = base
+		terms      = 0
+	)
+
+	for _, login := range opts.TeamMembers {
+		term := fmt.Sprintf("%s:%s", qualifier, login)
+		if terms > 0 && (!combinable || len(current)+len(term)+1 > SearchQueryMaxLength) {
+			queries = append(queries, current)
+			current, terms = base, 0
+		}
+		current = strings.TrimSpace(current + " " + term)
+		terms++
+	}
+
+	if terms > 0 {
+		queries = append(queries, current)
+	}
+
+	return queries
+}
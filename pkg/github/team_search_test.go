@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetTeamMembers(t *testing.T) {
+	var (
+		ctx = context.Background()
+	)
+
+	testVariables := testutil.GetTestVariablesFunction("cursor", "login", "slug")
+	client := testutil.NewTestClient(t, testVariables, testutil.GetTestQueryFunction(&QueryListTeamMembers{}))
+
+	// the test client leaves the team empty, which is how GitHub answers for an unknown team
+	_, err := GetTeamMembers(ctx, client, "grafana", "backend")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "team grafana/backend not found")
+}
+
+func TestTeamOrganization(t *testing.T) {
+	org, slug := models.TeamSearchOptions{Team: "backend"}.TeamOrganization("grafana")
+	assert.Equal(t, "grafana", org)
+	assert.Equal(t, "backend", slug)
+
+	org, slug = models.TeamSearchOptions{Team: "loki/backend"}.TeamOrganization("grafana")
+	assert.Equal(t, "loki", org)
+	assert.Equal(t, "backend", slug)
+}
+
+func TestTeamSearchQueries(t *testing.T) {
+	base := "is:pr repo:grafana/grafana"
+
+	t.Run("no team", func(t *testing.T) {
+		assert.Equal(t, []string{base}, teamSearchQueries(base, models.TeamSearchOptions{}))
+	})
+
+	t.Run("team without members", func(t *testing.T) {
+		assert.Empty(t, teamSearchQueries(base, models.TeamSearchOptions{Team: "backend"}))
+	})
+
+	t.Run("authors in a single query", func(t *testing.T) {
+		opts := models.TeamSearchOptions{
+			Team:        "backend",
+			TeamMembers: []string{"alice", "bob"},
+		}
+		assert.Equal(t, []string{base + " author:alice author:bob"}, teamSearchQueries(base, opts))
+	})
+
+	t.Run("a query per member for qualifiers matching every term", func(t *testing.T) {
+		for _, qualifier := range []models.TeamQualifier{models.TeamQualifierAssignee, models.TeamQualifierInvolves, models.TeamQualifierReviewedBy, models.TeamQualifierReviewRequested} {
+			opts := models.TeamSearchOptions{
+				Team:          "backend",
+				TeamQualifier: qualifier,
+				TeamMembers:   []string{"alice", "bob"},
+			}
+			assert.Equal(t, []string{
+				base + " " + string(qualifier) + ":alice",
+				base + " " + string(qualifier) + ":bob",
+			}, teamSearchQueries(base, opts))
+		}
+	})
+
+	t.Run("split queries", func(t *testing.T) {
+		members := []string{}
+		for i := 0; i < 50; i++ {
+			members = append(members, fmt.Sprintf("member-%02d", i))
+		}
+
+		queries := teamSearchQueries(base, models.TeamSearchOptions{Team: "backend", TeamMembers: members})
+		require.Greater(t, len(queries), 1)
+
+		found := []string{}
+		for _, q := range queries {
+			assert.LessOrEqual(t, len(q), SearchQueryMaxLength)
+			assert.True(t, strings.HasPrefix(q, base))
+			for _, term := range strings.Fields(strings.TrimPrefix(q, base)) {
+				found = append(found, strings.TrimPrefix(term, "author:"))
+			}
+		}
+		assert.Equal(t, members, found)
+	})
+}
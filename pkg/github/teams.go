@@ -0,0 +1,240 @@
+package github
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryListTeams is the GraphQL query for listing the teams of an organization, with their parent team and repository permissions
+//
+//	{
+//	  organization(login: "grafana") {
+//	    teams(first: 100, query: "") {
+//	      nodes {
+//	        slug
+//	        parentTeam {
+//	          slug
+//	        }
+//	        repositories(first: 100) {
+//	          edges {
+//	            permission
+//	            node {
+//	              nameWithOwner
+//	            }
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListTeams struct {
+	Organization struct {
+		Teams struct {
+			Nodes    []Team
+			PageInfo models.PageInfo
+		} `graphql:"teams(first: 100, after: $cursor, query: $query)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// QueryListTeamRepositories is the GraphQL query for listing the next pages of the repository permissions of a team
+//
+//	{
+//	  organization(login: "grafana") {
+//	    team(slug: "backend") {
+//	      repositories(first: 100, after: "cursor") {
+//	        edges {
+//	          permission
+//	          node {
+//	            nameWithOwner
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListTeamRepositories struct {
+	Organization struct {
+		Team *struct {
+			Repositories TeamRepositories `graphql:"repositories(first: 100, after: $cursor)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// TeamRepositories is a page of the repositories a team has access to, with the permission of the team
+type TeamRepositories struct {
+	TotalCount int64
+	Edges      []struct {
+		Permission githubv4.RepositoryPermission
+		Node       struct {
+			NameWithOwner string
+		}
+	}
+	PageInfo models.PageInfo
+}
+
+// Team is a team of a GitHub organization
+type Team struct {
+	Name         string
+	Slug         string
+	CombinedSlug string
+	Description  string
+	Privacy      githubv4.TeamPrivacy
+	URL          string
+	ParentTeam   *struct {
+		Slug string
+	}
+	Members struct {
+		TotalCount int64
+	} `graphql:"members(first: 1)"`
+	Repositories TeamRepositories `graphql:"repositories(first: 100)"`
+}
+
+// Teams is a list of organization teams
+type Teams []Team
+
+// teamPath returns the path of a team within the team hierarchy, from the root team down to the team itself (ex: engineering/backend/database)
+func (t Teams) teamPath(slug string) []string {
+	parents := make(map[string]string, len(t))
+	for _, v := range t {
+		if v.ParentTeam != nil {
+			parents[v.Slug] = v.ParentTeam.Slug
+		}
+	}
+
+	path := []string{slug}
+	seen := map[string]bool{slug: true}
+	for {
+		parent, ok := parents[path[0]]
+		// ancestors of a team that was filtered out by the query are unknown, so the path stops at that team
+		if !ok || seen[parent] {
+			break
+		}
+		seen[parent] = true
+		path = append([]string{parent}, path...)
+	}
+	return path
+}
+
+// Frames converts the list of teams to two Grafana DataFrames: the teams with their place in the hierarchy, and the repository permissions of each team
+func (t Teams) Frames() data.Frames {
+	teams := data.NewFrame(
+		"teams",
+		data.NewField("name", nil, []string{}),
+		data.NewField("slug", nil, []string{}),
+		data.NewField("combined_slug", nil, []string{}),
+		data.NewField("description", nil, []string{}),
+		data.NewField("privacy", nil, []string{}),
+		data.NewField("parent", nil, []*string{}),
+		data.NewField("path", nil, []string{}),
+		data.NewField("depth", nil, []int64{}),
+		data.NewField("members", nil, []int64{}),
+		data.NewField("repositories", nil, []int64{}),
+		data.NewField("url", nil, []string{}),
+	)
+
+	permissions := data.NewFrame(
+		"team_repositories",
+		data.NewField("team", nil, []string{}),
+		data.NewField("repository", nil, []string{}),
+		data.NewField("permission", nil, []string{}),
+	)
+
+	for _, v := range t {
+		var parent *string
+		if v.ParentTeam != nil {
+			p := v.ParentTeam.Slug
+			parent = &p
+		}
+
+		path := t.teamPath(v.Slug)
+
+		teams.AppendRow(
+			v.Name,
+			v.Slug,
+			v.CombinedSlug,
+			v.Description,
+			string(v.Privacy),
+			parent,
+			strings.Join(path, "/"),
+			int64(len(path)-1),
+			v.Members.TotalCount,
+			v.Repositories.TotalCount,
+			v.URL,
+		)
+
+		for _, repo := range v.Repositories.Edges {
+			permissions.AppendRow(
+				v.Slug,
+				repo.Node.NameWithOwner,
+				string(repo.Permission),
+			)
+		}
+	}
+
+	teams.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	permissions.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{teams, permissions}
+}
+
+// GetAllTeams lists every team of an organization, filtered by the optional query string
+func GetAllTeams(ctx context.Context, client models.Client, opts models.ListTeamsOptions) (Teams, error) {
+	var (
+		variables = map[string]interface{}{
+			"cursor": (*githubv4.String)(nil),
+			"login":  githubv4.String(opts.Owner),
+			"query":  githubv4.String(opts.Query),
+		}
+
+		teams = Teams{}
+	)
+
+	for {
+		q := &QueryListTeams{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		teams = append(teams, q.Organization.Teams.Nodes...)
+		if !q.Organization.Teams.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = q.Organization.Teams.PageInfo.EndCursor
+	}
+
+	for i := range teams {
+		if err := getTeamRepositories(ctx, client, opts.Owner, &teams[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return teams, nil
+}
+
+// getTeamRepositories fetches the repository permissions of a team beyond the first page returned with the team
+func getTeamRepositories(ctx context.Context, client models.Client, owner string, team *Team) error {
+	variables := map[string]interface{}{
+		"login": githubv4.String(owner),
+		"slug":  githubv4.String(team.Slug),
+	}
+
+	for team.Repositories.PageInfo.HasNextPage {
+		variables["cursor"] = team.Repositories.PageInfo.EndCursor
+		q := &QueryListTeamRepositories{}
+		if err := client.Query(ctx, q, variables); err != nil {
+			return errors.WithStack(err)
+		}
+		if q.Organization.Team == nil {
+			return nil
+		}
+
+		page := q.Organization.Team.Repositories
+		team.Repositories.Edges = append(team.Repositories.Edges, page.Edges...)
+		team.Repositories.PageInfo = page.PageInfo
+	}
+	return nil
+}
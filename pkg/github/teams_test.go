@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+func TestGetAllTeams(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		opts = models.ListTeamsOptions{
+			Owner: "grafana",
+			Query: "backend",
+		}
+	)
+
+	testVariables := testutil.GetTestVariablesFunction("cursor", "login", "query")
+
+	client := testutil.NewTestClient(t,
+		testVariables,
+		testutil.GetTestQueryFunction(&QueryListTeams{}),
+	)
+
+	_, err := GetAllTeams(ctx, client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAllTeamsPaginatesRepositories(t *testing.T) {
+	repository := func(name string) struct {
+		Permission githubv4.RepositoryPermission
+		Node       struct {
+			NameWithOwner string
+		}
+	} {
+		edge := struct {
+			Permission githubv4.RepositoryPermission
+			Node       struct {
+				NameWithOwner string
+			}
+		}{Permission: githubv4.RepositoryPermissionWrite}
+		edge.Node.NameWithOwner = "grafana/" + name
+		return edge
+	}
+
+	pages := 0
+	client := testutil.NewTestClient(t, nil, func(t *testing.T, q interface{}) {
+		switch query := q.(type) {
+		case *QueryListTeams:
+			team := newTestTeam("backend", "")
+			team.Repositories.Edges = append(team.Repositories.Edges, repository("grafana"))
+			team.Repositories.PageInfo = models.PageInfo{HasNextPage: true, EndCursor: "1"}
+			query.Organization.Teams.Nodes = append(query.Organization.Teams.Nodes, team, newTestTeam("frontend", ""))
+		case *QueryListTeamRepositories:
+			pages++
+			query.Organization.Team = &struct {
+				Repositories TeamRepositories `graphql:"repositories(first: 100, after: $cursor)"`
+			}{}
+			query.Organization.Team.Repositories.Edges = append(query.Organization.Team.Repositories.Edges, repository(fmt.Sprintf("repo-%d", pages)))
+			query.Organization.Team.Repositories.PageInfo = models.PageInfo{HasNextPage: pages < 2, EndCursor: githubv4.String(fmt.Sprint(pages + 1))}
+		default:
+			t.Fatalf("unexpected query %T", q)
+		}
+	})
+
+	teams, err := GetAllTeams(context.Background(), client, models.ListTeamsOptions{Owner: "grafana"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, pages)
+	require.Len(t, teams, 2)
+	require.Len(t, teams[0].Repositories.Edges, 3)
+	assert.Equal(t, "grafana/repo-2", teams[0].Repositories.Edges[2].Node.NameWithOwner)
+	assert.Empty(t, teams[1].Repositories.Edges)
+}
+
+func newTestTeam(slug string, parent string) Team {
+	team := Team{
+		Name:         slug,
+		Slug:         slug,
+		CombinedSlug: "grafana/" + slug,
+		Privacy:      githubv4.TeamPrivacyVisible,
+	}
+	if parent != "" {
+		team.ParentTeam = &struct{ Slug string }{Slug: parent}
+	}
+	return team
+}
+
+func TestTeamPath(t *testing.T) {
+	teams := Teams{
+		newTestTeam("engineering", ""),
+		newTestTeam("backend", "engineering"),
+		newTestTeam("database", "backend"),
+		newTestTeam("orphan", "filtered-out"),
+	}
+
+	assert.Equal(t, []string{"engineering"}, teams.teamPath("engineering"))
+	assert.Equal(t, []string{"engineering", "backend", "database"}, teams.teamPath("database"))
+	assert.Equal(t, []string{"filtered-out", "orphan"}, teams.teamPath("orphan"))
+}
+
+func TestTeamsDataFrame(t *testing.T) {
+	engineering := newTestTeam("engineering", "")
+	engineering.Members.TotalCount = 20
+
+	backend := newTestTeam("backend", "engineering")
+	backend.Members.TotalCount = 5
+	backend.Repositories.TotalCount = 2
+	backend.Repositories.Edges = []struct {
+		Permission githubv4.RepositoryPermission
+		Node       struct {
+			NameWithOwner string
+		}
+	}{
+		{Permission: githubv4.RepositoryPermissionAdmin, Node: struct{ NameWithOwner string }{NameWithOwner: "grafana/grafana"}},
+		{Permission: githubv4.RepositoryPermissionRead, Node: struct{ NameWithOwner string }{NameWithOwner: "grafana/loki"}},
+	}
+
+	testutil.CheckGoldenFramer(t, "teams", Teams{engineering, backend})
+}
@@ -0,0 +1,276 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/grafana/github-datasource/pkg/models"
+)
+
+// QueryRepositoryOwnerType is the GraphQL query for finding whether an owner is a user or an organization
+//
+//	{
+//	  repositoryOwner(login: "grafana") {
+//	    __typename
+//	  }
+//	}
+type QueryRepositoryOwnerType struct {
+	RepositoryOwner *struct {
+		Typename string `graphql:"__typename"`
+	} `graphql:"repositoryOwner(login: $login)"`
+}
+
+// QueryListRepositoryLanguages is the GraphQL query for retrieving the repositories of an owner with their languages and topics
+//
+//	{
+//	  search(query: "org:grafana", type: REPOSITORY, first: 100) {
+//	    nodes {
+//	      ... on Repository {
+//	        nameWithOwner
+//	        primaryLanguage {
+//	          name
+//	        }
+//	        languages(first: 100, orderBy: {field: SIZE, direction: DESC}) {
+//	          edges {
+//	            size
+//	            node {
+//	              name
+//	            }
+//	          }
+//	        }
+//	        repositoryTopics(first: 100) {
+//	          nodes {
+//	            topic {
+//	              name
+//	            }
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type QueryListRepositoryLanguages struct {
+	Search RepositorySearch[RepositoryLanguages] `graphql:"search(query: $query, type: REPOSITORY, first: 100, after: $cursor)"`
+}
+
+func (q *QueryListRepositoryLanguages) search() *RepositorySearch[RepositoryLanguages] {
+	return &q.Search
+}
+
+// RepositoryLanguages is a repository with its languages and topics
+type RepositoryLanguages struct {
+	Repository
+	PrimaryLanguage *struct {
+		Name string
+	}
+	Languages struct {
+		Edges []struct {
+			Size int64
+			Node struct {
+				Name string
+			}
+		}
+	} `graphql:"languages(first: 100, orderBy: {field: SIZE, direction: DESC})"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string
+			}
+		}
+	} `graphql:"repositoryTopics(first: 100)"`
+}
+
+// Primary returns the name of the primary language of the repository, or an empty string when it has no code
+func (r RepositoryLanguages) Primary() string {
+	if r.PrimaryLanguage == nil {
+		return ""
+	}
+	return r.PrimaryLanguage.Name
+}
+
+// Bytes returns the size of the code of the repository in every language
+func (r RepositoryLanguages) Bytes() int64 {
+	var bytes int64
+	for _, l := range r.Languages.Edges {
+		bytes += l.Size
+	}
+	return bytes
+}
+
+// LanguageUsage is the size of the code in a language across the repositories of an owner
+type LanguageUsage struct {
+	Language string
+	Bytes    int64
+	// Repositories is the number of repositories with code in the language
+	Repositories int64
+	// PrimaryRepositories is the number of repositories whose primary language is the language
+	PrimaryRepositories int64
+}
+
+// TopicUsage is the number of repositories of an owner with a topic
+type TopicUsage struct {
+	Topic        string
+	Repositories int64
+}
+
+// TechStack is the languages and topics aggregated across the repositories of an owner
+type TechStack struct {
+	Languages    []LanguageUsage
+	Topics       []TopicUsage
+	Repositories []RepositoryLanguages
+}
+
+// Frames converts the tech stack to Grafana data frames, the languages, the topics and the primary language of each repository
+func (s TechStack) Frames() data.Frames {
+	bytes := data.NewField("bytes", nil, []int64{})
+	bytes.Config = &data.FieldConfig{Unit: "bytes"}
+	languages := data.NewFrame(
+		"languages",
+		data.NewField("language", nil, []string{}),
+		bytes,
+		data.NewField("repositories", nil, []int64{}),
+		data.NewField("primary_repositories", nil, []int64{}),
+	)
+	for _, v := range s.Languages {
+		languages.AppendRow(v.Language, v.Bytes, v.Repositories, v.PrimaryRepositories)
+	}
+
+	topics := data.NewFrame(
+		"topics",
+		data.NewField("topic", nil, []string{}),
+		data.NewField("repositories", nil, []int64{}),
+	)
+	for _, v := range s.Topics {
+		topics.AppendRow(v.Topic, v.Repositories)
+	}
+
+	repositoryBytes := data.NewField("bytes", nil, []int64{})
+	repositoryBytes.Config = &data.FieldConfig{Unit: "bytes"}
+	repositories := data.NewFrame(
+		"repositories",
+		data.NewField("name", nil, []string{}),
+		data.NewField("owner", nil, []string{}),
+		data.NewField("name_with_owner", nil, []string{}),
+		data.NewField("url", nil, []string{}),
+		data.NewField("primary_language", nil, []string{}),
+		repositoryBytes,
+		data.NewField("topics", nil, []string{}),
+	)
+	for _, v := range s.Repositories {
+		names := make([]string, len(v.RepositoryTopics.Nodes))
+		for i, t := range v.RepositoryTopics.Nodes {
+			names[i] = t.Topic.Name
+		}
+		repositories.AppendRow(v.Name, v.Owner.Login, v.NameWithOwner, v.URL, v.Primary(), v.Bytes(), strings.Join(names, ","))
+	}
+
+	languages.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	topics.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	repositories.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return data.Frames{languages, topics, repositories}
+}
+
+// ownerSearchQualifier returns the search qualifier of the repositories of an owner, user: for a user and org: for an organization
+func ownerSearchQualifier(ctx context.Context, client models.Client, owner string) (string, error) {
+	q := &QueryRepositoryOwnerType{}
+	if err := client.Query(ctx, q, map[string]interface{}{
+		"login": githubv4.String(owner),
+	}); err != nil {
+		return "", errors.WithStack(err)
+	}
+	if q.RepositoryOwner != nil && q.RepositoryOwner.Typename == "User" {
+		return fmt.Sprintf("user:%s", owner), nil
+	}
+	return fmt.Sprintf("org:%s", owner), nil
+}
+
+// GetTechStack aggregates the languages and topics of every repository of a user or an organization matching the repository search.
+// The languages are sorted by size and the topics by number of repositories, the largest first.
+// Only the 100 largest languages and the first 100 topics of each repository are aggregated.
+func GetTechStack(ctx context.Context, client models.Client, opts models.ListTechStackOptions) (TechStack, error) {
+	owner, err := ownerSearchQualifier(ctx, client, opts.Owner)
+	if err != nil {
+		return TechStack{}, err
+	}
+
+	filters := []string{opts.Repository}
+	if opts.IncludeForks {
+		filters = append(filters, "fork:true")
+	}
+	if !opts.IncludeArchived {
+		filters = append(filters, "archived:false")
+	}
+
+	repos, err := searchOwnerRepositories(ctx, client, owner, strings.TrimSpace(strings.Join(filters, " ")), func() repositorySearchQuery[RepositoryLanguages] {
+		return &QueryListRepositoryLanguages{}
+	}, func(r RepositoryLanguages) string {
+		return r.NameWithOwner
+	})
+	if err != nil {
+		return TechStack{}, err
+	}
+
+	return aggregateTechStack(repos), nil
+}
+
+func aggregateTechStack(repos []RepositoryLanguages) TechStack {
+	var (
+		languages = map[string]*LanguageUsage{}
+		topics    = map[string]*TopicUsage{}
+	)
+
+	for _, r := range repos {
+		for _, l := range r.Languages.Edges {
+			usage, ok := languages[l.Node.Name]
+			if !ok {
+				usage = &LanguageUsage{Language: l.Node.Name}
+				languages[l.Node.Name] = usage
+			}
+			usage.Bytes += l.Size
+			usage.Repositories++
+			if l.Node.Name == r.Primary() {
+				usage.PrimaryRepositories++
+			}
+		}
+
+		for _, t := range r.RepositoryTopics.Nodes {
+			usage, ok := topics[t.Topic.Name]
+			if !ok {
+				usage = &TopicUsage{Topic: t.Topic.Name}
+				topics[t.Topic.Name] = usage
+			}
+			usage.Repositories++
+		}
+	}
+
+	stack := TechStack{Languages: []LanguageUsage{}, Topics: []TopicUsage{}, Repositories: repos}
+	for _, usage := range languages {
+		stack.Languages = append(stack.Languages, *usage)
+	}
+	sort.Slice(stack.Languages, func(i, j int) bool {
+		a, b := stack.Languages[i], stack.Languages[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Language < b.Language
+	})
+
+	for _, usage := range topics {
+		stack.Topics = append(stack.Topics, *usage)
+	}
+	sort.Slice(stack.Topics, func(i, j int) bool {
+		a, b := stack.Topics[i], stack.Topics[j]
+		if a.Repositories != b.Repositories {
+			return a.Repositories > b.Repositories
+		}
+		return a.Topic < b.Topic
+	})
+
+	return stack
+}
@@ -0,0 +1,24 @@
+package github
+
+import (
+	"context"
+
+	"github.com/grafana/github-datasource/pkg/dfutil"
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func (s *QueryHandler) handleTechStackQuery(ctx context.Context, q backend.DataQuery) backend.DataResponse {
+	query := &models.TechStackQuery{}
+	if err := UnmarshalQuery(q.JSON, query); err != nil {
+		return *err
+	}
+	return dfutil.FrameResponseWithError(s.Datasource.HandleTechStackQuery(ctx, query, q))
+}
+
+// HandleTechStack handles the plugin query for the languages and topics of GitHub repositories
+func (s *QueryHandler) HandleTechStack(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return &backend.QueryDataResponse{
+		Responses: processQueries(ctx, req, s.handleTechStackQuery),
+	}, nil
+}
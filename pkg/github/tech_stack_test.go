@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/github-datasource/pkg/models"
+	"github.com/grafana/github-datasource/pkg/testutil"
+)
+
+// newTechStackTestClient serves the repositories of an owner of the given type (User or Organization)
+func newTechStackTestClient(t *testing.T, ownerType string, expectedQuery string) *testutil.TestClient {
+	testVariables := func(t *testing.T, variables map[string]interface{}) {
+		if _, ok := variables["login"]; ok {
+			return
+		}
+		assert.Equal(t, githubv4.String(expectedQuery), variables["query"])
+	}
+
+	testQuery := func(t *testing.T, q interface{}) {
+		if owner, ok := q.(*QueryRepositoryOwnerType); ok {
+			owner.RepositoryOwner = &struct {
+				Typename string `graphql:"__typename"`
+			}{Typename: ownerType}
+			return
+		}
+
+		query, ok := q.(*QueryListRepositoryLanguages)
+		require.True(t, ok)
+
+		type language struct {
+			name string
+			size int64
+		}
+		repos := []struct {
+			name      string
+			primary   string
+			languages []language
+			topics    []string
+		}{
+			{"grafana", "TypeScript", []language{{"TypeScript", 3000}, {"Go", 2000}}, []string{"monitoring", "grafana"}},
+			{"loki", "Go", []language{{"Go", 4000}, {"Shell", 10}}, []string{"monitoring"}},
+			{"empty", "", nil, nil},
+		}
+
+		for _, repo := range repos {
+			node := struct {
+				Repository RepositoryLanguages `graphql:"... on Repository"`
+			}{}
+			r := &node.Repository
+			r.Name = repo.name
+			r.Owner.Login = "grafana"
+			r.NameWithOwner = "grafana/" + repo.name
+			r.URL = "https://github.com/grafana/" + repo.name
+			if repo.primary != "" {
+				r.PrimaryLanguage = &struct{ Name string }{Name: repo.primary}
+			}
+			for _, l := range repo.languages {
+				edge := struct {
+					Size int64
+					Node struct{ Name string }
+				}{Size: l.size}
+				edge.Node.Name = l.name
+				r.Languages.Edges = append(r.Languages.Edges, edge)
+			}
+			for _, topic := range repo.topics {
+				node := struct{ Topic struct{ Name string } }{}
+				node.Topic.Name = topic
+				r.RepositoryTopics.Nodes = append(r.RepositoryTopics.Nodes, node)
+			}
+			query.Search.Nodes = append(query.Search.Nodes, node)
+		}
+	}
+
+	return testutil.NewTestClient(t, testVariables, testQuery)
+}
+
+func TestGetTechStack(t *testing.T) {
+	t.Run("aggregates the languages and topics of the repositories", func(t *testing.T) {
+		client := newTechStackTestClient(t, "Organization", "org:grafana archived:false")
+		stack, err := GetTechStack(context.Background(), client, models.ListTechStackOptions{Owner: "grafana"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []LanguageUsage{
+			{Language: "Go", Bytes: 6000, Repositories: 2, PrimaryRepositories: 1},
+			{Language: "TypeScript", Bytes: 3000, Repositories: 1, PrimaryRepositories: 1},
+			{Language: "Shell", Bytes: 10, Repositories: 1},
+		}, stack.Languages)
+		assert.Equal(t, []TopicUsage{
+			{Topic: "monitoring", Repositories: 2},
+			{Topic: "grafana", Repositories: 1},
+		}, stack.Topics)
+		require.Len(t, stack.Repositories, 3)
+		assert.Equal(t, "", stack.Repositories[2].Primary())
+	})
+
+	t.Run("adds the forks and archived repositories to the search", func(t *testing.T) {
+		client := newTechStackTestClient(t, "Organization", "org:grafana topic:observability fork:true")
+		opts := models.TechStackOptionsWithRepo(models.ListTechStackOptions{IncludeForks: true, IncludeArchived: true}, "grafana", "topic:observability")
+		_, err := GetTechStack(context.Background(), client, opts)
+		require.NoError(t, err)
+	})
+
+	t.Run("searches the repositories of a user", func(t *testing.T) {
+		client := newTechStackTestClient(t, "User", "user:octocat archived:false")
+		_, err := GetTechStack(context.Background(), client, models.ListTechStackOptions{Owner: "octocat"})
+		require.NoError(t, err)
+	})
+}
+
+func TestTechStackDataFrame(t *testing.T) {
+	client := newTechStackTestClient(t, "Organization", "org:grafana archived:false")
+	stack, err := GetTechStack(context.Background(), client, models.ListTechStackOptions{Owner: "grafana"})
+	require.NoError(t, err)
+
+	testutil.CheckGoldenFramer(t, "tech_stack", stack)
+}
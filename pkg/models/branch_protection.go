@@ -0,0 +1,44 @@
+package models
+
+// BranchProtectionPolicy is the policy that the default branch of every repository is checked against
+type BranchProtectionPolicy struct {
+	// RequiredApprovingReviewCount is the minimum number of approving reviews a pull request needs before merging
+	RequiredApprovingReviewCount int64 `json:"requiredApprovingReviewCount"`
+
+	// RequireCodeOwnerReviews requires an approving review from a code owner
+	RequireCodeOwnerReviews bool `json:"requireCodeOwnerReviews"`
+
+	// RequireStatusChecks requires at least one status check to pass before merging
+	RequireStatusChecks bool `json:"requireStatusChecks"`
+
+	// RequireSignedCommits requires commits pushed to the branch to be signed
+	RequireSignedCommits bool `json:"requireSignedCommits"`
+
+	// RequireLinearHistory prevents merge commits from being pushed to the branch
+	RequireLinearHistory bool `json:"requireLinearHistory"`
+
+	// DisallowBypassActors marks a branch as non-compliant when any actor is allowed to bypass its protection
+	DisallowBypassActors bool `json:"disallowBypassActors"`
+}
+
+// ListBranchProtectionOptions are the available options when listing branch protection rules and rulesets
+type ListBranchProtectionOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana).
+	// When empty, every repository of the owner is audited.
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Policy is the policy used to compute the compliance status of the default branch
+	Policy BranchProtectionPolicy `json:"policy"`
+}
+
+// BranchProtectionOptionsWithRepo adds Owner and Repository to a ListBranchProtectionOptions. This is just for convenience
+func BranchProtectionOptionsWithRepo(opt ListBranchProtectionOptions, owner string, repo string) ListBranchProtectionOptions {
+	return ListBranchProtectionOptions{
+		Owner:      owner,
+		Repository: repo,
+		Policy:     opt.Policy,
+	}
+}
@@ -1,5 +1,8 @@
 package models
 
+// DefaultStaleDays is the number of days without activity after which a branch or a repository is considered stale
+const DefaultStaleDays = 90
+
 // ListBranchesOptions are the available options when listing branches
 type ListBranchesOptions struct {
 	// Repository is the name of the repository being queried (ex: grafana)
@@ -10,4 +13,20 @@ type ListBranchesOptions struct {
 
 	// Query filters branches by name prefix/substring (ex: release/)
 	Query string `json:"query"`
+
+	// Mode is the kind of results returned for the branches
+	Mode BranchesMode `json:"mode,omitempty"`
+
+	// StaleDays is the number of days without commits after which an unmerged branch is flagged as abandoned
+	StaleDays int `json:"staleDays,omitempty"`
 }
+
+// BranchesMode is the kind of results returned for branches
+type BranchesMode string
+
+const (
+	// BranchesModeList lists the branches
+	BranchesModeList BranchesMode = ""
+	// BranchesModeStale lists the branches with their ahead and behind counts versus the default branch, flagging the merged and abandoned ones
+	BranchesModeStale BranchesMode = "Branches_Stale"
+)
@@ -0,0 +1,42 @@
+package models
+
+// ChecksSource selects which commits the checks are listed for
+type ChecksSource string
+
+const (
+	// ChecksSourceRef lists the checks of the commit a ref points to
+	ChecksSourceRef ChecksSource = "ref"
+	// ChecksSourcePullRequest lists the checks of the head commit of a pull request
+	ChecksSourcePullRequest ChecksSource = "pullRequest"
+	// ChecksSourceRange lists the checks of every commit of a ref in the time range
+	ChecksSourceRange ChecksSource = "range"
+)
+
+// ListChecksOptions are the available options when listing check suites, check runs and commit statuses
+type ListChecksOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Source selects which commits the checks are listed for. Defaults to ref.
+	Source ChecksSource `json:"source,omitempty"`
+
+	// Ref is the branch, tag or commit SHA used by the ref and range sources (ex: main). Defaults to the default branch.
+	Ref string `json:"gitRef"`
+
+	// PullRequest is the number of the pull request used by the pullRequest source
+	PullRequest int64 `json:"pullRequest,omitempty"`
+}
+
+// ChecksOptionsWithRepo adds Owner and Repository to a ListChecksOptions. This is just for convenience
+func ChecksOptionsWithRepo(opt ListChecksOptions, owner string, repo string) ListChecksOptions {
+	return ListChecksOptions{
+		Owner:       owner,
+		Repository:  repo,
+		Source:      opt.Source,
+		Ref:         opt.Ref,
+		PullRequest: opt.PullRequest,
+	}
+}
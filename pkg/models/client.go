@@ -20,4 +20,14 @@ type Client interface {
 	ListDeployments(ctx context.Context, owner, repo string, opts *googlegithub.DeploymentsListOptions) ([]*googlegithub.Deployment, *googlegithub.Response, error)
 	GetCommitFiles(ctx context.Context, owner, repo, sha string, opts *googlegithub.ListOptions) ([]*googlegithub.CommitFile, *googlegithub.Response, error)
 	ListPullRequestFiles(ctx context.Context, owner, repo string, prNumber int, opts *googlegithub.ListOptions) ([]*googlegithub.CommitFile, *googlegithub.Response, error)
+	CompareCommits(ctx context.Context, owner, repo, base, head string, opts *googlegithub.ListOptions) (*googlegithub.CommitsComparison, *googlegithub.Response, error)
+	GetTree(ctx context.Context, owner, repo, sha string, recursive bool) (*googlegithub.Tree, *googlegithub.Response, error)
+	ListPackagesForOrg(ctx context.Context, org string, opts *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error)
+	ListPackagesForUser(ctx context.Context, user string, opts *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error)
+	ListPackageVersionsForOrg(ctx context.Context, org, packageType, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error)
+	ListPackageVersionsForUser(ctx context.Context, user, packageType, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error)
+	GetContainerManifest(ctx context.Context, owner, name, reference string) (*ContainerManifest, error)
+	GetSBOM(ctx context.Context, owner, repo string) (*googlegithub.SBOM, *googlegithub.Response, error)
+	GetRepository(ctx context.Context, owner, repo string) (*googlegithub.Repository, *googlegithub.Response, error)
+	GetCodeScanningDefaultSetup(ctx context.Context, owner, repo string) (*googlegithub.DefaultSetupConfiguration, *googlegithub.Response, error)
 }
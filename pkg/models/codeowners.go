@@ -0,0 +1,35 @@
+package models
+
+// ListCodeownersOptions are the available options when reporting on the CODEOWNERS file of a repository
+type ListCodeownersOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Ref is the branch or tag whose CODEOWNERS file and files are analyzed (ex: main). The default branch is used when empty.
+	Ref string `json:"gitRef"`
+
+	// PathDepth is the number of directories the coverage of the files is rolled up to
+	PathDepth int `json:"pathDepth,omitempty"`
+
+	// TimeField defines which time field of the pull requests checked for owner approvals is filtered by the time range
+	TimeField PullRequestTimeField `json:"timeField"`
+}
+
+// CodeownersOptionsWithRepo adds Owner and Repository to a ListCodeownersOptions. This is just for convenience
+func CodeownersOptionsWithRepo(opt ListCodeownersOptions, owner string, repo string) ListCodeownersOptions {
+	depth := opt.PathDepth
+	if depth <= 0 {
+		depth = DefaultHotspotsPathDepth
+	}
+
+	return ListCodeownersOptions{
+		Owner:      owner,
+		Repository: repo,
+		Ref:        opt.Ref,
+		PathDepth:  depth,
+		TimeField:  opt.TimeField,
+	}
+}
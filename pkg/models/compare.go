@@ -0,0 +1,26 @@
+package models
+
+// CompareOptions are the available options when comparing two refs of a repository
+type CompareOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Base is the branch, tag or commit SHA the head is compared against (ex: v10.0.0)
+	Base string `json:"base"`
+
+	// Head is the branch, tag or commit SHA that is compared (ex: v10.1.0)
+	Head string `json:"head"`
+}
+
+// CompareOptionsWithRepo adds Owner and Repository to a CompareOptions. This is just for convenience
+func CompareOptionsWithRepo(opt CompareOptions, owner string, repo string) CompareOptions {
+	return CompareOptions{
+		Owner:      owner,
+		Repository: repo,
+		Base:       opt.Base,
+		Head:       opt.Head,
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+// CohortInterval is the length of the periods contributors are grouped by
+type CohortInterval string
+
+const (
+	// CohortWeek groups contributors by week, starting on Monday
+	CohortWeek CohortInterval = "week"
+	// CohortMonth groups contributors by calendar month
+	CohortMonth CohortInterval = "month"
+	// CohortQuarter groups contributors by calendar quarter
+	CohortQuarter CohortInterval = "quarter"
+)
+
+// DefaultCohortLookbackDays is how far before the time range contributions are looked for to tell first-time contributors from returning ones
+const DefaultCohortLookbackDays = 365
+
+// ListContributorCohortsOptions are the available options when grouping the contributors of a repository into cohorts
+type ListContributorCohortsOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Ref is the branch whose commits are counted as contributions (ex: main). The default branch is used when empty.
+	Ref string `json:"gitRef"`
+
+	// Interval is the length of the periods contributors are grouped by
+	Interval CohortInterval `json:"interval"`
+
+	// LookbackDays is how far before the time range contributions are looked for.
+	// A contributor whose first contribution in this window falls in an interval is a first-time contributor of that interval.
+	LookbackDays int `json:"lookbackDays,omitempty"`
+}
+
+// ContributorCohortsOptionsWithRepo adds Owner and Repository to a ListContributorCohortsOptions. This is just for convenience
+func ContributorCohortsOptionsWithRepo(opt ListContributorCohortsOptions, owner string, repo string) ListContributorCohortsOptions {
+	interval := opt.Interval
+	if interval != CohortWeek && interval != CohortQuarter {
+		interval = CohortMonth
+	}
+
+	lookback := opt.LookbackDays
+	if lookback <= 0 {
+		lookback = DefaultCohortLookbackDays
+	}
+
+	return ListContributorCohortsOptions{
+		Owner:        owner,
+		Repository:   repo,
+		Ref:          opt.Ref,
+		Interval:     interval,
+		LookbackDays: lookback,
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+// DependenciesSource is where the dependencies of a repository are read from
+type DependenciesSource string
+
+const (
+	// DependenciesSourceSBOM reads the dependencies from the SPDX software bill of materials exported from the dependency graph.
+	// It has the resolved versions and the licenses of the packages.
+	DependenciesSourceSBOM DependenciesSource = ""
+	// DependenciesSourceManifests reads the dependencies from the manifests of the dependency graph with the GraphQL API.
+	// It has the manifest declaring each dependency, with its version requirements.
+	DependenciesSourceManifests DependenciesSource = "Manifests"
+)
+
+// ListDependenciesOptions provides options when listing the dependencies of repositories
+type ListDependenciesOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana).
+	// When empty, the dependencies of every repository of the owner are listed.
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Source is the dependency graph export the dependencies are read from
+	Source DependenciesSource `json:"source,omitempty"`
+
+	// Package only lists the dependencies on this package (ex: lodash)
+	Package string `json:"package,omitempty"`
+
+	// Ecosystem only lists the dependencies of this ecosystem (ex: npm)
+	Ecosystem string `json:"ecosystem,omitempty"`
+
+	// BelowVersion only lists the dependencies on a version lower than this one, to find the repositories using an outdated library
+	BelowVersion string `json:"belowVersion,omitempty"`
+}
+
+// DependenciesOptionsWithRepo adds Owner and Repository to a ListDependenciesOptions. This is just for convenience
+func DependenciesOptionsWithRepo(opt ListDependenciesOptions, owner string, repo string) ListDependenciesOptions {
+	return ListDependenciesOptions{
+		Owner:        owner,
+		Repository:   repo,
+		Source:       opt.Source,
+		Package:      opt.Package,
+		Ecosystem:    opt.Ecosystem,
+		BelowVersion: opt.BelowVersion,
+	}
+}
@@ -20,3 +20,24 @@ type ListDeploymentsOptions struct {
 	// Environment is the name of the environment (e.g., "production", "staging") to filter by
 	Environment string `json:"environment,omitempty"`
 }
+
+// ListDeploymentStatusesOptions are the available options when listing the status history of deployments
+type ListDeploymentStatusesOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Environment is the name of the environment (e.g., "production", "staging") to filter by
+	Environment string `json:"environment,omitempty"`
+}
+
+// ListEnvironmentsOptions are the available options when listing the deployment environments of a repository
+type ListEnvironmentsOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+}
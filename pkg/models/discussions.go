@@ -0,0 +1,40 @@
+package models
+
+// DiscussionTimeField defines what time field to filter discussions by (created, updated, answered)
+type DiscussionTimeField uint32
+
+const (
+	// DiscussionCreatedAt is used when filtering when a Discussion was opened
+	DiscussionCreatedAt DiscussionTimeField = iota
+	// DiscussionUpdatedAt is used when filtering when a Discussion was updated (last time)
+	DiscussionUpdatedAt
+	// DiscussionAnsweredAt is used when filtering when an answer was chosen for a Discussion
+	DiscussionAnsweredAt
+)
+
+// String returns the search qualifier of the time field, the time field comes from the query so an unknown one falls back to created
+func (d DiscussionTimeField) String() string {
+	fields := [...]string{"created", "updated", "answered"}
+	if int(d) >= len(fields) {
+		return fields[DiscussionCreatedAt]
+	}
+	return fields[d]
+}
+
+// ListDiscussionsOptions provides options when retrieving discussions
+type ListDiscussionsOptions struct {
+	Repository string              `json:"repository"`
+	Owner      string              `json:"owner"`
+	Query      *string             `json:"query,omitempty"`
+	TimeField  DiscussionTimeField `json:"timeField"`
+}
+
+// DiscussionOptionsWithRepo adds the Owner and Repository values to a ListDiscussionsOptions. This is a convenience function because this is a common operation
+func DiscussionOptionsWithRepo(opt ListDiscussionsOptions, owner string, repo string) ListDiscussionsOptions {
+	return ListDiscussionsOptions{
+		Owner:      owner,
+		Repository: repo,
+		Query:      opt.Query,
+		TimeField:  opt.TimeField,
+	}
+}
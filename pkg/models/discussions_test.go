@@ -0,0 +1,15 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscussionTimeFieldString(t *testing.T) {
+	require.Equal(t, "created", DiscussionCreatedAt.String())
+	require.Equal(t, "answered", DiscussionAnsweredAt.String())
+	// the time field comes from the query JSON
+	require.Equal(t, "created", DiscussionTimeField(3).String())
+	require.Equal(t, "created", DiscussionTimeField(42).String())
+}
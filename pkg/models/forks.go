@@ -0,0 +1,36 @@
+package models
+
+// ForksMode is the data returned by a forks query
+type ForksMode string
+
+const (
+	// ForksModeGrowth lists the forks of a repository with the number of forks when each one was created
+	ForksModeGrowth ForksMode = ""
+	// ForksModeActive lists the forks of a repository which were pushed to during the active window
+	ForksModeActive ForksMode = "Forks_Active"
+)
+
+// ListForksOptions is provided when fetching the forks of a repository
+type ListForksOptions struct {
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Mode is the data returned by the query, the fork growth by default
+	Mode ForksMode `json:"mode,omitempty"`
+
+	// ActiveDays is the number of days without pushes after which a fork is no longer active, DefaultStaleDays when empty
+	ActiveDays int `json:"activeDays,omitempty"`
+}
+
+// ForksOptionsWithRepo adds Owner and Repository to a ListForksOptions. This is just for convenience
+func ForksOptionsWithRepo(opt ListForksOptions, owner string, repo string) ListForksOptions {
+	return ListForksOptions{
+		Owner:      owner,
+		Repository: repo,
+		Mode:       opt.Mode,
+		ActiveDays: opt.ActiveDays,
+	}
+}
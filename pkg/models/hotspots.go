@@ -0,0 +1,42 @@
+package models
+
+// DefaultHotspotsPathDepth is the number of directories files are rolled up to when no depth is set
+const DefaultHotspotsPathDepth = 1
+
+// ListHotspotsOptions are the available options when rolling up the files changed by commits into hotspots
+type ListHotspotsOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Ref is the branch or tag whose commits are analyzed (ex: main)
+	Ref string `json:"gitRef"`
+
+	// PathDepth is the number of directories every file is rolled up to (ex: 2 rolls pkg/github/commits.go up to pkg/github)
+	PathDepth int `json:"pathDepth,omitempty"`
+
+	// Include only keeps the files matching one of these globs (ex: pkg/**). `*` doesn't match `/`, `**` does.
+	Include []string `json:"include,omitempty"`
+
+	// Exclude drops the files matching one of these globs (ex: **/*_test.go)
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// HotspotsOptionsWithRepo adds Owner and Repository to a ListHotspotsOptions. This is just for convenience
+func HotspotsOptionsWithRepo(opt ListHotspotsOptions, owner string, repo string) ListHotspotsOptions {
+	depth := opt.PathDepth
+	if depth <= 0 {
+		depth = DefaultHotspotsPathDepth
+	}
+
+	return ListHotspotsOptions{
+		Owner:      owner,
+		Repository: repo,
+		Ref:        opt.Ref,
+		PathDepth:  depth,
+		Include:    opt.Include,
+		Exclude:    opt.Exclude,
+	}
+}
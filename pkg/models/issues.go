@@ -25,6 +25,9 @@ type ListIssuesOptions struct {
 	Filters    *githubv4.IssueFilters `json:"filters"`
 	Query      *string                `json:"query,omitempty"`
 	TimeField  IssueTimeField         `json:"timeField"`
+
+	// TeamSearchOptions scope the search to the members of a team
+	TeamSearchOptions
 }
 
 // IssueOptionsWithRepo adds the Owner and Repository values to a ListIssuesOptions. This is a convenience function because this is a common operation
@@ -35,5 +38,9 @@ func IssueOptionsWithRepo(opt ListIssuesOptions, owner string, repo string) List
 		Filters:    opt.Filters,
 		Query:      opt.Query,
 		TimeField:  opt.TimeField,
+		TeamSearchOptions: TeamSearchOptions{
+			Team:          opt.Team,
+			TeamQualifier: opt.TeamQualifier,
+		},
 	}
 }
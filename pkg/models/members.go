@@ -0,0 +1,55 @@
+package models
+
+// ListMembersOptions are the available options when listing the members of an organization
+type ListMembersOptions struct {
+	// Owner is the login of the organization (ex: grafana)
+	Owner string `json:"owner"`
+}
+
+// ListTeamsOptions are the available options when listing the teams of an organization
+type ListTeamsOptions struct {
+	// Owner is the login of the organization (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Query filters teams by name or slug
+	Query string `json:"query,omitempty"`
+}
+
+// CollaboratorAffiliation filters collaborators by how they are affiliated with a repository
+type CollaboratorAffiliation string
+
+const (
+	// CollaboratorAffiliationOutside lists collaborators who are not members of the organization that owns the repository
+	CollaboratorAffiliationOutside CollaboratorAffiliation = "OUTSIDE"
+	// CollaboratorAffiliationDirect lists collaborators with permissions to the repository, regardless of organization membership
+	CollaboratorAffiliationDirect CollaboratorAffiliation = "DIRECT"
+	// CollaboratorAffiliationAll lists every collaborator the authenticated user can see
+	CollaboratorAffiliationAll CollaboratorAffiliation = "ALL"
+)
+
+// ListCollaboratorsOptions are the available options when listing the collaborators of a repository
+type ListCollaboratorsOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana).
+	// When empty, the collaborators of every repository of the owner are listed.
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Affiliation filters the collaborators. Defaults to outside collaborators.
+	Affiliation CollaboratorAffiliation `json:"affiliation,omitempty"`
+}
+
+// CollaboratorsOptionsWithRepo adds Owner and Repository to a ListCollaboratorsOptions. This is just for convenience
+func CollaboratorsOptionsWithRepo(opt ListCollaboratorsOptions, owner string, repo string) ListCollaboratorsOptions {
+	affiliation := opt.Affiliation
+	if affiliation == "" {
+		affiliation = CollaboratorAffiliationOutside
+	}
+
+	return ListCollaboratorsOptions{
+		Owner:       owner,
+		Repository:  repo,
+		Affiliation: affiliation,
+	}
+}
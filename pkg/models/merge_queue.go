@@ -0,0 +1,22 @@
+package models
+
+// ListMergeQueueOptions are the available options when listing the entries and the history of a merge queue
+type ListMergeQueueOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Branch is the branch the merge queue merges into. Defaults to the default branch of the repository.
+	Branch string `json:"branch,omitempty"`
+}
+
+// MergeQueueOptionsWithRepo adds Owner and Repository to a ListMergeQueueOptions. This is just for convenience
+func MergeQueueOptionsWithRepo(opt ListMergeQueueOptions, owner string, repo string) ListMergeQueueOptions {
+	return ListMergeQueueOptions{
+		Owner:      owner,
+		Repository: repo,
+		Branch:     opt.Branch,
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+// DefaultInactiveOwnerDays is the number of days without contributions after which a code owner is considered inactive
+const DefaultInactiveOwnerDays = 90
+
+// MaxInactiveOwnerDays is the longest window GitHub counts the contributions of a user over
+const MaxInactiveOwnerDays = 365
+
+// ListOwnershipOptions are the available options when computing the ownership concentration of the directories of a repository
+type ListOwnershipOptions struct {
+	// Repository is the name of the repository being queried (ex: grafana)
+	Repository string `json:"repository"`
+
+	// Owner is the owner of the repository (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Ref is the branch or tag whose commits and CODEOWNERS file are analyzed (ex: main)
+	Ref string `json:"gitRef"`
+
+	// PathDepth is the number of directories every file is rolled up to (ex: 2 rolls pkg/github/commits.go up to pkg/github)
+	PathDepth int `json:"pathDepth,omitempty"`
+
+	// InactiveDays is the number of days without any contribution after which a code owner is flagged as inactive
+	InactiveDays int `json:"inactiveDays,omitempty"`
+}
+
+// OwnershipOptionsWithRepo adds Owner and Repository to a ListOwnershipOptions. This is just for convenience
+func OwnershipOptionsWithRepo(opt ListOwnershipOptions, owner string, repo string) ListOwnershipOptions {
+	depth := opt.PathDepth
+	if depth <= 0 {
+		depth = DefaultHotspotsPathDepth
+	}
+
+	days := opt.InactiveDays
+	if days <= 0 {
+		days = DefaultInactiveOwnerDays
+	}
+	if days > MaxInactiveOwnerDays {
+		days = MaxInactiveOwnerDays
+	}
+
+	return ListOwnershipOptions{
+		Owner:        owner,
+		Repository:   repo,
+		Ref:          opt.Ref,
+		PathDepth:    depth,
+		InactiveDays: days,
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/shurcooL/githubv4"
@@ -14,6 +15,12 @@ type ListPackagesOptions struct {
 	Owner       string               `json:"owner"`
 	Names       string               `json:"names"`
 	PackageType githubv4.PackageType `json:"packageType"`
+	Mode        PackagesMode         `json:"mode"`
+	// OwnerKind, RegistryPackageType, Visibility and IncludeManifests are the options of PackagesModeRegistry
+	OwnerKind           PackageOwnerKind `json:"ownerKind"`
+	RegistryPackageType string           `json:"registryPackageType"`
+	Visibility          string           `json:"visibility"`
+	IncludeManifests    bool             `json:"includeManifests"`
 }
 
 // PackagesOptionsWithRepo adds Owner and Repo to a ListPackagesOptions. This is just for convenience
@@ -28,6 +35,7 @@ func PackagesOptionsWithRepo(opt ListPackagesOptions, owner string, repo string)
 		Repository:  repo,
 		Names:       opt.Names,
 		PackageType: opt.PackageType,
+		Mode:        opt.Mode,
 	}, nil
 }
 
@@ -57,3 +65,85 @@ func validatePackageType(packageType githubv4.PackageType) error {
 	}
 	return backend.DownstreamError(fmt.Errorf("invalid package type %q. Valid types are: MAVEN, DOCKER, DEBIAN, PYPI", packageType))
 }
+
+// PackagesMode is the kind of results returned for packages
+type PackagesMode string
+
+const (
+	// PackagesModeRepository lists the packages of a repository with the GraphQL API, with their downloads
+	PackagesModeRepository PackagesMode = ""
+	// PackagesModeRegistry lists the packages of an organization or a user with the rest API, which supports every ecosystem
+	PackagesModeRegistry PackagesMode = "Packages_Registry"
+	// PackagesModeDownloads returns the downloads of the package versions of a repository over time, from the recorded snapshots
+	PackagesModeDownloads PackagesMode = "Packages_Downloads"
+)
+
+// PackageOwnerKind is the kind of the owner of the packages listed with the rest API
+type PackageOwnerKind int
+
+const (
+	// PackageOwnerOrganization lists the packages of an organization
+	PackageOwnerOrganization PackageOwnerKind = 0
+	// PackageOwnerUser lists the packages of a user
+	PackageOwnerUser PackageOwnerKind = 1
+)
+
+// RegistryPackageTypes are the package types of the rest API, which covers the ecosystems the GraphQL API dropped
+var RegistryPackageTypes = []string{"npm", "maven", "rubygems", "docker", "nuget", "container"}
+
+// ListRegistryPackagesOptions provides options when listing the packages of an organization or a user with the rest API
+type ListRegistryPackagesOptions struct {
+	Owner string
+	Kind  PackageOwnerKind
+	Names string
+	// PackageType is one of RegistryPackageTypes, every type is listed when it is empty
+	PackageType string
+	// Visibility is "public", "private" or "internal", every package is listed when it is empty
+	Visibility string
+	// IncludeManifests gets the manifest of the container images from the registry, to get their platforms and sizes
+	IncludeManifests bool
+}
+
+// RegistryPackagesOptions converts the packages query options to the options of the rest API
+func RegistryPackagesOptions(opt ListPackagesOptions, owner string) (ListRegistryPackagesOptions, error) {
+	packageType := strings.ToLower(opt.RegistryPackageType)
+	if packageType != "" && !slices.Contains(RegistryPackageTypes, packageType) {
+		return ListRegistryPackagesOptions{}, backend.DownstreamError(fmt.Errorf("invalid package type %q. Valid types are: %s", opt.RegistryPackageType, strings.Join(RegistryPackageTypes, ", ")))
+	}
+
+	return ListRegistryPackagesOptions{
+		Owner:            owner,
+		Kind:             opt.OwnerKind,
+		Names:            opt.Names,
+		PackageType:      packageType,
+		Visibility:       strings.ToLower(opt.Visibility),
+		IncludeManifests: opt.IncludeManifests,
+	}, nil
+}
+
+// ContainerManifest is the manifest of a container image in the GitHub container registry
+type ContainerManifest struct {
+	Digest    string
+	MediaType string
+	// Size is the size of the config and the layers of the image, or of every platform for a multi-platform image
+	Size      int64
+	Platforms []ContainerPlatform
+}
+
+// ContainerPlatform is an image of a multi-platform container image
+type ContainerPlatform struct {
+	Digest       string
+	OS           string
+	Architecture string
+	Variant      string
+	Size         int64
+}
+
+// Name returns the name of the platform, like linux/arm64/v8
+func (p ContainerPlatform) Name() string {
+	name := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		name += "/" + p.Variant
+	}
+	return name
+}
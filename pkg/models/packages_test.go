@@ -81,3 +81,17 @@ func Test_validatePackageType(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistryPackagesOptions(t *testing.T) {
+	opt, err := RegistryPackagesOptions(ListPackagesOptions{Mode: PackagesModeRegistry, OwnerKind: PackageOwnerUser, RegistryPackageType: "NPM", Visibility: "Public"}, "grafana")
+	require.NoError(t, err)
+	require.Equal(t, ListRegistryPackagesOptions{Owner: "grafana", Kind: PackageOwnerUser, PackageType: "npm", Visibility: "public"}, opt)
+
+	// every type is listed when none is selected
+	opt, err = RegistryPackagesOptions(ListPackagesOptions{}, "grafana")
+	require.NoError(t, err)
+	require.Empty(t, opt.PackageType)
+
+	_, err = RegistryPackagesOptions(ListPackagesOptions{RegistryPackageType: "pypi"}, "grafana")
+	require.ErrorContains(t, err, `invalid package type "pypi"`)
+}
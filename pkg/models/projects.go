@@ -20,20 +20,68 @@ type ProjectOptions struct {
 	Number any `json:"number"`
 	// User is the name of the user who owns the project being queried
 	User string `json:"user"`
-	// Kind is the kind of query - Org vs User
+	// Kind is the kind of query - Org vs User vs Repository vs Team
 	Kind int `json:"kind"`
+	// Repository is the name of the repository the projects are linked to. The repository is owned by the organization, or by the user when there is no organization.
+	Repository string `json:"repository,omitempty"`
+	// Team is the slug of the team of the organization the projects are linked to
+	Team string `json:"team,omitempty"`
 	// Filters allow filtering the results
 	Filters []Filter `json:"filters"`
+	// FilterExpression filters the results with a boolean expression, like (Status = Done or Estimate > 3) and closed_at > now-7d.
+	// Items have to match both the filters and the expression.
+	FilterExpression string `json:"filterExpression,omitempty"`
+	// Mode is what is returned for a project: its items by default
+	Mode ProjectMode `json:"mode,omitempty"`
+	// IterationField is the name of the iteration field the burndown is computed for
+	IterationField string `json:"iterationField,omitempty"`
+	// EstimateField is the name of the number field holding the estimate of an item. Every item counts as 1 when it is empty.
+	EstimateField string `json:"estimateField,omitempty"`
+	// Iteration is the title of the iteration the burndown is computed for. The current iteration is used when it is empty.
+	Iteration string `json:"iteration,omitempty"`
 }
 
+// Kinds of projects, by what they belong or are linked to
+const (
+	// ProjectKindOrganization is for the projects of an organization
+	ProjectKindOrganization = 0
+	// ProjectKindUser is for the projects of a user
+	ProjectKindUser = 1
+	// ProjectKindRepository is for the projects linked to a repository
+	ProjectKindRepository = 2
+	// ProjectKindTeam is for the projects linked to a team
+	ProjectKindTeam = 3
+)
+
+// RepositoryOwner returns the owner of the repository the projects are linked to
+func (o ProjectOptions) RepositoryOwner() string {
+	if o.Organization != "" {
+		return o.Organization
+	}
+	return o.User
+}
+
+// ProjectMode is the kind of results returned for a project
+type ProjectMode string
+
+const (
+	// ProjectModeItems lists the items of the project
+	ProjectModeItems ProjectMode = ""
+	// ProjectModeBurndown returns the remaining work per day of an iteration and the velocity of the past iterations
+	ProjectModeBurndown ProjectMode = "Project_Burndown"
+	// ProjectModeFlow returns the cumulative flow of the items between the Status columns and their cycle time
+	ProjectModeFlow ProjectMode = "Project_Flow"
+)
+
 // Filter allows filtering by Key/Value
 type Filter struct {
 	// Key ...
 	Key string
 	// Value ...
 	Value string
-	// OP ...
+	// OP is the operator: =, !=, >, <, >=, <=, ~ (contains), =~ and !~ (regular expressions), in and not in (comma separated values), is empty or is not empty
 	OP string
-	// Conjunction ...
+	// Conjunction joins the filter to the next one with "and" or "or", "and" takes precedence.
+	// Filters without a conjunction use the one of the first filter.
 	Conjunction string
 }
@@ -32,6 +32,13 @@ type ListPullRequestsOptions struct {
 	TimeField PullRequestTimeField `json:"timeField"`
 
 	Query *string `json:"query,omitempty"`
+
+	// IncludeContributorExperience adds the author association, the first-time contributor flag and the time to first response of every pull request,
+	// and compares the experience of external contributors with the one of members
+	IncludeContributorExperience bool `json:"includeContributorExperience,omitempty"`
+
+	// TeamSearchOptions scope the search to the members of a team
+	TeamSearchOptions
 }
 
 // PullRequestOptionsWithRepo adds the Owner and Repository options to a ListPullRequestsOptions type
@@ -41,5 +48,11 @@ func PullRequestOptionsWithRepo(opt ListPullRequestsOptions, owner string, repo
 		Repository: repo,
 		Query:      opt.Query,
 		TimeField:  opt.TimeField,
+
+		IncludeContributorExperience: opt.IncludeContributorExperience,
+		TeamSearchOptions: TeamSearchOptions{
+			Team:          opt.Team,
+			TeamQualifier: opt.TeamQualifier,
+		},
 	}
 }
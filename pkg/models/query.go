@@ -55,6 +55,40 @@ const (
 	QueryTypePullRequestFiles QueryType = "Pull_Request_Files"
 	// QueryTypeBranches is used when querying branches in a GitHub repository
 	QueryTypeBranches QueryType = "Branches"
+	// QueryTypeBranchProtection is used when auditing branch protection rules and rulesets of a repository or organization
+	QueryTypeBranchProtection QueryType = "Branch_Protection"
+	// QueryTypeMembers is used when querying the members of an organization
+	QueryTypeMembers QueryType = "Members"
+	// QueryTypeTeams is used when querying the teams of an organization
+	QueryTypeTeams QueryType = "Teams"
+	// QueryTypeCollaborators is used when querying the collaborators of a repository
+	QueryTypeCollaborators QueryType = "Collaborators"
+	// QueryTypeDeploymentStatuses is used when querying the status history of the deployments of a repository
+	QueryTypeDeploymentStatuses QueryType = "Deployment_Statuses"
+	// QueryTypeEnvironments is used when querying the deployment environments of a repository
+	QueryTypeEnvironments QueryType = "Environments"
+	// QueryTypeChecks is used when querying the check suites, check runs and commit statuses of commits
+	QueryTypeChecks QueryType = "Checks"
+	// QueryTypeMergeQueue is used when querying the entries and the history of a merge queue
+	QueryTypeMergeQueue QueryType = "Merge_Queue"
+	// QueryTypeDiscussions is used when querying discussions in a repository
+	QueryTypeDiscussions QueryType = "Discussions"
+	// QueryTypeCompare is used when comparing two refs of a repository
+	QueryTypeCompare QueryType = "Compare"
+	// QueryTypeHotspots is used when rolling up the files changed by commits into hotspots
+	QueryTypeHotspots QueryType = "Hotspots"
+	// QueryTypeOwnership is used when computing the bus factor of directories and checking their code owners
+	QueryTypeOwnership QueryType = "Ownership"
+	// QueryTypeCodeowners is used when reporting on the coverage and the review routing of a CODEOWNERS file
+	QueryTypeCodeowners QueryType = "Codeowners"
+	// QueryTypeContributorCohorts is used when grouping contributors into cohorts by the interval of their first contribution
+	QueryTypeContributorCohorts QueryType = "Contributor_Cohorts"
+	// QueryTypeDependencies is used when listing the dependencies of repositories from the dependency graph
+	QueryTypeDependencies QueryType = "Dependencies"
+	// QueryTypeForks is used when querying the forks of a repository
+	QueryTypeForks QueryType = "Forks"
+	// QueryTypeTechStack is used when aggregating the languages and topics of the repositories of an owner
+	QueryTypeTechStack QueryType = "Tech_Stack"
 )
 
 // Query refers to the structure of a query built using the QueryEditor.
@@ -111,6 +145,7 @@ type ContributorsQuery struct {
 // RepositoriesQuery is used when querying for GitHub repositories
 type RepositoriesQuery struct {
 	Query
+	Options ListRepositoriesOptions `json:"options"`
 }
 
 // IssuesQuery is used when querying for GitHub issues
@@ -193,3 +228,103 @@ type BranchesQuery struct {
 	Query
 	Options ListBranchesOptions `json:"options"`
 }
+
+// BranchProtectionQuery is used when auditing branch protection rules and rulesets
+type BranchProtectionQuery struct {
+	Query
+	Options ListBranchProtectionOptions `json:"options"`
+}
+
+// MembersQuery is used when querying the members of a GitHub organization
+type MembersQuery struct {
+	Query
+}
+
+// TeamsQuery is used when querying the teams of a GitHub organization
+type TeamsQuery struct {
+	Query
+	Options ListTeamsOptions `json:"options"`
+}
+
+// CollaboratorsQuery is used when querying the collaborators of a GitHub repository
+type CollaboratorsQuery struct {
+	Query
+	Options ListCollaboratorsOptions `json:"options"`
+}
+
+// DeploymentStatusesQuery is used when querying the status history of the deployments of a GitHub repository
+type DeploymentStatusesQuery struct {
+	Query
+	Options ListDeploymentStatusesOptions `json:"options"`
+}
+
+// EnvironmentsQuery is used when querying the deployment environments of a GitHub repository
+type EnvironmentsQuery struct {
+	Query
+}
+
+// ChecksQuery is used when querying the check suites, check runs and commit statuses of a GitHub repository
+type ChecksQuery struct {
+	Query
+	Options ListChecksOptions `json:"options"`
+}
+
+// MergeQueueQuery is used when querying the merge queue of a GitHub repository
+type MergeQueueQuery struct {
+	Query
+	Options ListMergeQueueOptions `json:"options"`
+}
+
+// DiscussionsQuery is used when querying for GitHub Discussions
+type DiscussionsQuery struct {
+	Query
+	Options ListDiscussionsOptions `json:"options"`
+}
+
+// CompareQuery is used when comparing two refs of a GitHub repository
+type CompareQuery struct {
+	Query
+	Options CompareOptions `json:"options"`
+}
+
+// HotspotsQuery is used when rolling up the files changed by the commits of a GitHub repository into hotspots
+type HotspotsQuery struct {
+	Query
+	Options ListHotspotsOptions `json:"options"`
+}
+
+// OwnershipQuery is used when computing the bus factor and the code ownership health of the directories of a GitHub repository
+type OwnershipQuery struct {
+	Query
+	Options ListOwnershipOptions `json:"options"`
+}
+
+// CodeownersQuery is used when reporting on the coverage and the review routing of the CODEOWNERS file of a GitHub repository
+type CodeownersQuery struct {
+	Query
+	Options ListCodeownersOptions `json:"options"`
+}
+
+// ContributorCohortsQuery is used when grouping the contributors of a GitHub repository into cohorts
+type ContributorCohortsQuery struct {
+	Query
+	Options ListContributorCohortsOptions `json:"options"`
+}
+
+// DependenciesQuery is used when listing the dependencies of a GitHub repository, or of every repository of an owner
+type DependenciesQuery struct {
+	Query
+	Options ListDependenciesOptions `json:"options"`
+}
+
+// ForksQuery is used when querying the fork growth or the active forks of a repository
+type ForksQuery struct {
+	Query
+	Options ListForksOptions `json:"options"`
+}
+
+// TechStackQuery is used when aggregating the languages and topics of every repository of an owner
+type TechStackQuery struct {
+	Query
+	Options ListTechStackOptions `json:"options"`
+}
@@ -7,4 +7,10 @@ type ListReleasesOptions struct {
 
 	// Owner is the owner of the repository (ex: grafana)
 	Owner string `json:"owner"`
+
+	// IncludeAssets adds a frame with the assets of every release, their size and download count
+	IncludeAssets bool `json:"includeAssets,omitempty"`
+
+	// IncludeCadence adds a frame with the days, commits and pull requests between every release and the one before it
+	IncludeCadence bool `json:"includeCadence,omitempty"`
 }
@@ -6,8 +6,23 @@ import "github.com/shurcooL/githubv4"
 type ListRepositoriesOptions struct {
 	Owner      string
 	Repository string
+	Mode       RepositoriesMode `json:"mode"`
+	// StaleDays is the number of days without pushes, issues or pull requests after which a repository is flagged as stale
+	StaleDays int `json:"staleDays,omitempty"`
 }
 
+// RepositoriesMode is the kind of results returned for repositories
+type RepositoriesMode string
+
+const (
+	// RepositoriesModeList lists the repositories
+	RepositoriesModeList RepositoriesMode = ""
+	// RepositoriesModeInventory lists the repositories with their license, topics, languages, settings and security features
+	RepositoriesModeInventory RepositoriesMode = "Repositories_Inventory"
+	// RepositoriesModeStale lists the repositories with their last push, issue and pull request, flagging the stale ones
+	RepositoriesModeStale RepositoriesMode = "Repositories_Stale"
+)
+
 // Repository is a code repository
 type Repository struct {
 	Name  string
@@ -0,0 +1,55 @@
+package models
+
+import "strings"
+
+// TeamQualifier is the search qualifier that the members of a team are expanded into
+type TeamQualifier string
+
+const (
+	// TeamQualifierAuthor matches issues and pull requests created by a member of the team
+	TeamQualifierAuthor TeamQualifier = "author"
+	// TeamQualifierAssignee matches issues and pull requests assigned to a member of the team
+	TeamQualifierAssignee TeamQualifier = "assignee"
+	// TeamQualifierInvolves matches issues and pull requests that involve a member of the team in any way
+	TeamQualifierInvolves TeamQualifier = "involves"
+	// TeamQualifierReviewedBy matches pull requests reviewed by a member of the team
+	TeamQualifierReviewedBy TeamQualifier = "reviewed-by"
+	// TeamQualifierReviewRequested matches pull requests where a review was requested from a member of the team
+	TeamQualifierReviewRequested TeamQualifier = "review-requested"
+)
+
+// Combinable returns true when several terms of the qualifier in one search match any of them.
+// An issue or a pull request has a single author, so GitHub matches repeated author terms with any of the authors,
+// while the other qualifiers have to match every term and each member needs a search of its own.
+func (q TeamQualifier) Combinable() bool {
+	return q == TeamQualifierAuthor
+}
+
+// TeamSearchOptions scope an issue or pull request search to the members of a team
+type TeamSearchOptions struct {
+	// Team is the slug of the team (ex: backend), optionally prefixed with its organization (ex: grafana/backend).
+	// When the organization is omitted, the owner of the query is used.
+	Team string `json:"team,omitempty"`
+
+	// TeamQualifier is the search qualifier every member of the team is expanded into. Defaults to author.
+	TeamQualifier TeamQualifier `json:"teamQualifier,omitempty"`
+
+	// TeamMembers are the logins of the members of the team. They are resolved by the datasource and never sent by the frontend.
+	TeamMembers []string `json:"-"`
+}
+
+// Qualifier returns the search qualifier to expand the team members into
+func (o TeamSearchOptions) Qualifier() TeamQualifier {
+	if o.TeamQualifier == "" {
+		return TeamQualifierAuthor
+	}
+	return o.TeamQualifier
+}
+
+// TeamOrganization splits the Team option into the organization and the team slug, using the owner when no organization is set
+func (o TeamSearchOptions) TeamOrganization(owner string) (string, string) {
+	if org, slug, ok := strings.Cut(o.Team, "/"); ok {
+		return org, slug
+	}
+	return owner, o.Team
+}
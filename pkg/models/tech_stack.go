@@ -0,0 +1,26 @@
+package models
+
+// ListTechStackOptions are the available options when aggregating the languages and topics of the repositories of an owner
+type ListTechStackOptions struct {
+	// Owner is the user or organization whose repositories are aggregated (ex: grafana)
+	Owner string `json:"owner"`
+
+	// Repository filters the repositories with the repository search syntax (ex: "language:go" or "topic:observability")
+	Repository string `json:"repository"`
+
+	// IncludeForks aggregates the forks, which the repository search leaves out by default
+	IncludeForks bool `json:"includeForks,omitempty"`
+
+	// IncludeArchived aggregates the archived repositories
+	IncludeArchived bool `json:"includeArchived,omitempty"`
+}
+
+// TechStackOptionsWithRepo adds Owner and Repository to a ListTechStackOptions. This is just for convenience
+func TechStackOptionsWithRepo(opt ListTechStackOptions, owner string, repo string) ListTechStackOptions {
+	return ListTechStackOptions{
+		Owner:           owner,
+		Repository:      repo,
+		IncludeForks:    opt.IncludeForks,
+		IncludeArchived: opt.IncludeArchived,
+	}
+}
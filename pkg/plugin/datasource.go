@@ -32,6 +32,23 @@ type Datasource interface {
 	HandleWorkflowRunsQuery(context.Context, *models.WorkflowRunsQuery, backend.DataQuery) (dfutil.Framer, error)
 	HandleDeploymentsQuery(context.Context, *models.DeploymentsQuery, backend.DataQuery) (dfutil.Framer, error)
 	HandleOrganizationsQuery(context.Context, *models.OrganizationsQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleBranchProtectionQuery(context.Context, *models.BranchProtectionQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleMembersQuery(context.Context, *models.MembersQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleTeamsQuery(context.Context, *models.TeamsQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleCollaboratorsQuery(context.Context, *models.CollaboratorsQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleDeploymentStatusesQuery(context.Context, *models.DeploymentStatusesQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleEnvironmentsQuery(context.Context, *models.EnvironmentsQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleChecksQuery(context.Context, *models.ChecksQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleMergeQueueQuery(context.Context, *models.MergeQueueQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleDiscussionsQuery(context.Context, *models.DiscussionsQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleCompareQuery(context.Context, *models.CompareQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleHotspotsQuery(context.Context, *models.HotspotsQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleOwnershipQuery(context.Context, *models.OwnershipQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleCodeownersQuery(context.Context, *models.CodeownersQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleContributorCohortsQuery(context.Context, *models.ContributorCohortsQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleDependenciesQuery(context.Context, *models.DependenciesQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleForksQuery(context.Context, *models.ForksQuery, backend.DataQuery) (dfutil.Framer, error)
+	HandleTechStackQuery(context.Context, *models.TechStackQuery, backend.DataQuery) (dfutil.Framer, error)
 	CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error)
 	QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error)
 }
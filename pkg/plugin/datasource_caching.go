@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/pkg/errors"
 
 	"github.com/grafana/github-datasource/pkg/dfutil"
@@ -40,8 +41,9 @@ func newCachedResult(f dfutil.Framer) CachedResult {
 
 // Make sure Datasource implements required interfaces.
 var (
-	_ backend.QueryDataHandler   = (*CachedDatasource)(nil)
-	_ backend.CheckHealthHandler = (*CachedDatasource)(nil)
+	_ backend.QueryDataHandler      = (*CachedDatasource)(nil)
+	_ backend.CheckHealthHandler    = (*CachedDatasource)(nil)
+	_ instancemgmt.InstanceDisposer = (*CachedDatasource)(nil)
 )
 
 // The CachedDatasource wraps the Datasource type and stores an internal map, and responds to queries with cached data.
@@ -302,6 +304,176 @@ func (c *CachedDatasource) HandlePullRequestFilesQuery(ctx context.Context, q *m
 	return c.saveCache(req, f, err)
 }
 
+// HandleBranchProtectionQuery is the cache wrapper for the branch protection query handler
+func (c *CachedDatasource) HandleBranchProtectionQuery(ctx context.Context, q *models.BranchProtectionQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleBranchProtectionQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleMembersQuery is the cache wrapper for the members query handler
+func (c *CachedDatasource) HandleMembersQuery(ctx context.Context, q *models.MembersQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleMembersQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleTeamsQuery is the cache wrapper for the teams query handler
+func (c *CachedDatasource) HandleTeamsQuery(ctx context.Context, q *models.TeamsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleTeamsQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleCollaboratorsQuery is the cache wrapper for the collaborators query handler
+func (c *CachedDatasource) HandleCollaboratorsQuery(ctx context.Context, q *models.CollaboratorsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleCollaboratorsQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleDeploymentStatusesQuery is the cache wrapper for the deployment statuses query handler
+func (c *CachedDatasource) HandleDeploymentStatusesQuery(ctx context.Context, q *models.DeploymentStatusesQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleDeploymentStatusesQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleEnvironmentsQuery is the cache wrapper for the environments query handler
+func (c *CachedDatasource) HandleEnvironmentsQuery(ctx context.Context, q *models.EnvironmentsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleEnvironmentsQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleChecksQuery is the cache wrapper for the checks query handler
+func (c *CachedDatasource) HandleChecksQuery(ctx context.Context, q *models.ChecksQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleChecksQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleMergeQueueQuery is the cache wrapper for the merge queue query handler
+func (c *CachedDatasource) HandleMergeQueueQuery(ctx context.Context, q *models.MergeQueueQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleMergeQueueQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleDiscussionsQuery is the cache wrapper for the discussions query handler
+func (c *CachedDatasource) HandleDiscussionsQuery(ctx context.Context, q *models.DiscussionsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleDiscussionsQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleCompareQuery is the cache wrapper for the comparisons query handler
+func (c *CachedDatasource) HandleCompareQuery(ctx context.Context, q *models.CompareQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleCompareQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleHotspotsQuery is the cache wrapper for the hotspots query handler
+func (c *CachedDatasource) HandleHotspotsQuery(ctx context.Context, q *models.HotspotsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleHotspotsQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleOwnershipQuery is the cache wrapper for the ownership metrics query handler
+func (c *CachedDatasource) HandleOwnershipQuery(ctx context.Context, q *models.OwnershipQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleOwnershipQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleCodeownersQuery is the cache wrapper for the CODEOWNERS reports query handler
+func (c *CachedDatasource) HandleCodeownersQuery(ctx context.Context, q *models.CodeownersQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleCodeownersQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleContributorCohortsQuery is the cache wrapper for the contributor cohorts query handler
+func (c *CachedDatasource) HandleContributorCohortsQuery(ctx context.Context, q *models.ContributorCohortsQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleContributorCohortsQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleDependenciesQuery is the cache wrapper for the dependencies query handler
+func (c *CachedDatasource) HandleDependenciesQuery(ctx context.Context, q *models.DependenciesQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleDependenciesQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleForksQuery is the cache wrapper for the forks query handler
+func (c *CachedDatasource) HandleForksQuery(ctx context.Context, q *models.ForksQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleForksQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
+// HandleTechStackQuery is the cache wrapper for the tech stack query handler
+func (c *CachedDatasource) HandleTechStackQuery(ctx context.Context, q *models.TechStackQuery, req backend.DataQuery) (dfutil.Framer, error) {
+	if value, err := c.getCache(req); err == nil {
+		return value, err
+	}
+
+	f, err := c.datasource.HandleTechStackQuery(ctx, q, req)
+	return c.saveCache(req, f, err)
+}
+
 // CheckHealth forwards the request to the datasource and does not perform any caching
 func (c *CachedDatasource) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
 	return c.datasource.CheckHealth(ctx, req)
@@ -357,6 +529,13 @@ func (c *CachedDatasource) startCleanup() {
 	}
 }
 
+// Dispose forwards the disposal to the wrapped datasource when it holds background work
+func (c *CachedDatasource) Dispose() {
+	if d, ok := c.datasource.(instancemgmt.InstanceDisposer); ok {
+		d.Dispose()
+	}
+}
+
 // WithCaching accepts a Client and returns a CachedClient which wraps the provided Client
 func WithCaching(datasource Datasource) *CachedDatasource {
 	c := &CachedDatasource{
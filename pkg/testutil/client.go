@@ -96,3 +96,53 @@ func (c *TestClient) GetCommitFiles(ctx context.Context, owner, repo, sha string
 func (c *TestClient) ListPullRequestFiles(ctx context.Context, owner, repo string, prNumber int, opts *googlegithub.ListOptions) ([]*googlegithub.CommitFile, *googlegithub.Response, error) {
 	panic("unimplemented")
 }
+
+// CompareCommits is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) CompareCommits(ctx context.Context, owner, repo, base, head string, opts *googlegithub.ListOptions) (*googlegithub.CommitsComparison, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+// GetTree is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) GetTree(ctx context.Context, owner, repo, sha string, recursive bool) (*googlegithub.Tree, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+// ListPackagesForOrg is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) ListPackagesForOrg(ctx context.Context, org string, opts *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+// ListPackagesForUser is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) ListPackagesForUser(ctx context.Context, user string, opts *googlegithub.PackageListOptions) ([]*googlegithub.Package, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+// ListPackageVersionsForOrg is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) ListPackageVersionsForOrg(ctx context.Context, org, packageType, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+// ListPackageVersionsForUser is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) ListPackageVersionsForUser(ctx context.Context, user, packageType, packageName string, opts *googlegithub.PackageListOptions) ([]*googlegithub.PackageVersion, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+// GetContainerManifest is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) GetContainerManifest(ctx context.Context, owner, name, reference string) (*models.ContainerManifest, error) {
+	panic("unimplemented")
+}
+
+// GetSBOM is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) GetSBOM(ctx context.Context, owner, repo string) (*googlegithub.SBOM, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+// GetRepository is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) GetRepository(ctx context.Context, owner, repo string) (*googlegithub.Repository, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
+
+// GetCodeScanningDefaultSetup is not implemented because it is not being used in tests at the moment.
+func (c *TestClient) GetCodeScanningDefaultSetup(ctx context.Context, owner, repo string) (*googlegithub.DefaultSetupConfiguration, *googlegithub.Response, error) {
+	panic("unimplemented")
+}
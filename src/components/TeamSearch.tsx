@@ -0,0 +1,62 @@
+import React, { useState } from 'react';
+import { Combobox, ComboboxOption, Input } from '@grafana/ui';
+import { EditorField } from '@grafana/plugin-ui';
+import { RightColumnWidth } from '../views/QueryEditor';
+import { TeamQualifier } from '../constants';
+import type { TeamSearchOptions } from '../types/query';
+
+interface Props extends TeamSearchOptions {
+  qualifiers: TeamQualifier[];
+  onChange: (value: TeamSearchOptions) => void;
+}
+
+const qualifierOptions: Array<ComboboxOption<TeamQualifier>> = [
+  { label: 'Author', value: TeamQualifier.Author, description: 'Created by a member of the team' },
+  { label: 'Assignee', value: TeamQualifier.Assignee, description: 'Assigned to a member of the team' },
+  { label: 'Involves', value: TeamQualifier.Involves, description: 'Involving a member of the team in any way' },
+  { label: 'Reviewed by', value: TeamQualifier.ReviewedBy, description: 'Reviewed by a member of the team' },
+  {
+    label: 'Review requested',
+    value: TeamQualifier.ReviewRequested,
+    description: 'A review was requested from a member of the team',
+  },
+];
+
+export const TeamSearch = (props: Props) => {
+  const [team, setTeam] = useState<string>(props.team || '');
+  return (
+    <>
+      <EditorField
+        label="Team"
+        tooltip="Slug of the team whose members scope the search, ex: backend or grafana/backend (optional)"
+      >
+        <Input
+          value={team}
+          width={RightColumnWidth}
+          placeholder="backend"
+          onChange={(el) => setTeam(el.currentTarget.value)}
+          onBlur={(el) =>
+            props.onChange({
+              team: el.currentTarget.value,
+              teamQualifier: props.teamQualifier,
+            })
+          }
+        />
+      </EditorField>
+      <EditorField label="Team Qualifier" tooltip="The search qualifier every member of the team is matched with">
+        <Combobox
+          width={RightColumnWidth}
+          options={qualifierOptions.filter((opt) => props.qualifiers.includes(opt.value))}
+          value={props.teamQualifier || TeamQualifier.Author}
+          disabled={!props.team}
+          onChange={(opt) =>
+            props.onChange({
+              team: props.team,
+              teamQualifier: opt.value,
+            })
+          }
+        />
+      </EditorField>
+    </>
+  );
+};
@@ -26,6 +26,23 @@ export const QueryTypes = [
   'Workflow_Usage',
   'Workflow_Runs',
   'Deployments',
+  'Branch_Protection',
+  'Members',
+  'Teams',
+  'Collaborators',
+  'Deployment_Statuses',
+  'Environments',
+  'Checks',
+  'Merge_Queue',
+  'Discussions',
+  'Compare',
+  'Hotspots',
+  'Ownership',
+  'Codeowners',
+  'Contributor_Cohorts',
+  'Dependencies',
+  'Forks',
+  'Tech_Stack',
 ] as const;
 
 
@@ -41,6 +58,40 @@ export enum PackageType {
   PYPI = 'PYPI',
 }
 
+export enum PackagesMode {
+  Repository = '',
+  Registry = 'Packages_Registry',
+  Downloads = 'Packages_Downloads',
+}
+
+export enum PackageOwnerKind {
+  Organization = 0,
+  User = 1,
+}
+
+export const RegistryPackageTypes = ['npm', 'maven', 'rubygems', 'docker', 'nuget', 'container'] as const;
+
+export enum DependenciesSource {
+  SBOM = '',
+  Manifests = 'Manifests',
+}
+
+export enum RepositoriesMode {
+  List = '',
+  Inventory = 'Repositories_Inventory',
+  Stale = 'Repositories_Stale',
+}
+
+export enum BranchesMode {
+  List = '',
+  Stale = 'Branches_Stale',
+}
+
+export enum ForksMode {
+  Growth = '',
+  Active = 'Forks_Active',
+}
+
 export enum PullRequestTimeField {
   ClosedAt,
   CreatedAt,
@@ -55,13 +106,53 @@ export enum IssueTimeField {
   UpdatedAt,
 }
 
+export enum DiscussionTimeField {
+  CreatedAt,
+  UpdatedAt,
+  AnsweredAt,
+}
+
 export enum WorkflowsTimeField {
   None,
   CreatedAt,
   UpdatedAt,
 }
 
+export enum CollaboratorAffiliation {
+  Outside = 'OUTSIDE',
+  Direct = 'DIRECT',
+  All = 'ALL',
+}
+
+export enum CohortInterval {
+  Week = 'week',
+  Month = 'month',
+  Quarter = 'quarter',
+}
+
+export enum TeamQualifier {
+  Author = 'author',
+  Assignee = 'assignee',
+  Involves = 'involves',
+  ReviewedBy = 'reviewed-by',
+  ReviewRequested = 'review-requested',
+}
+
+export enum ChecksSource {
+  Ref = 'ref',
+  PullRequest = 'pullRequest',
+  Range = 'range',
+}
+
 export enum ProjectQueryType {
   ORG = 0,
   USER = 1,
+  REPOSITORY = 2,
+  TEAM = 3,
+}
+
+export enum ProjectMode {
+  Items = '',
+  Burndown = 'Project_Burndown',
+  Flow = 'Project_Flow',
 }
@@ -1,5 +1,24 @@
 import { type DataQuery } from '@grafana/schema';
-import { PullRequestTimeField, IssueTimeField, WorkflowsTimeField, PackageType, ProjectQueryType, QueryTypes } from '../constants';
+import {
+  PullRequestTimeField,
+  IssueTimeField,
+  WorkflowsTimeField,
+  PackageType,
+  PackagesMode,
+  PackageOwnerKind,
+  ProjectQueryType,
+  ProjectMode,
+  QueryTypes,
+  CollaboratorAffiliation,
+  TeamQualifier,
+  ChecksSource,
+  DiscussionTimeField,
+  CohortInterval,
+  DependenciesSource,
+  RepositoriesMode,
+  BranchesMode,
+  ForksMode,
+} from '../constants';
 import type { Filter } from 'components/Filters';
 
 export type QueryType = typeof QueryTypes[number]
@@ -8,6 +27,8 @@ export type Options = { [index: string]: any; }
 
 export type RepositoryOptions = { repository?: string; owner?: string; }
 
+export type TeamSearchOptions = { team?: string; teamQualifier?: TeamQualifier; }
+
 type BaseQuery<T extends QueryType, O extends Options> = { queryType: T; options?: O; } & RepositoryOptions & DataQuery;
 
 //#region Code_Scanning Query
@@ -33,7 +54,7 @@ type Commit_FilesQuery = BaseQuery<'Commit_Files', CommitFilesOptions>
 //#endregion
 
 //#region Issues Query
-export type IssuesOptions = Options & {
+export type IssuesOptions = Options & TeamSearchOptions & {
   timeField?: IssueTimeField;
   query?: string;
 }
@@ -55,19 +76,25 @@ type TagsQuery = BaseQuery<'Tags', TagsOptions>
 //#region Branches Query
 export type BranchesOptions = Options & {
   query?: string;
+  mode?: BranchesMode;
+  staleDays?: number;
 }
 type BranchesQuery = BaseQuery<'Branches', BranchesOptions>
 //#endregion
 
 //#region Releases Query
-export type ReleasesOptions = Options & {}
+export type ReleasesOptions = Options & {
+  includeAssets?: boolean;
+  includeCadence?: boolean;
+}
 type ReleasesQuery = BaseQuery<'Releases', ReleasesOptions>
 //#endregion
 
 //#region Pull_Requests Query
-export type Pull_RequestsOptions = Options & {
+export type Pull_RequestsOptions = Options & TeamSearchOptions & {
   timeField?: PullRequestTimeField;
   query?: string;
+  includeContributorExperience?: boolean;
 }
 type Pull_RequestsQuery = BaseQuery<'Pull_Requests', Pull_RequestsOptions>
 //#endregion
@@ -80,7 +107,7 @@ type Pull_Request_FilesQuery = BaseQuery<'Pull_Request_Files', Pull_Request_File
 //#endregion
 
 //#region Pull_Request_Reviews Query
-export type PullRequestReviewsOptions = Options & {
+export type PullRequestReviewsOptions = Options & TeamSearchOptions & {
   timeField?: PullRequestTimeField;
   query?: string;
 }
@@ -95,7 +122,11 @@ type LabelsQuery = BaseQuery<'Labels', LabelsOptions>
 //#endregion
 
 //#region Repositories Query
-type RepositoriesQuery = BaseQuery<'Repositories', {}>
+export type RepositoriesOptions = Options & {
+  mode?: RepositoriesMode;
+  staleDays?: number;
+}
+type RepositoriesQuery = BaseQuery<'Repositories', RepositoriesOptions>
 //#endregion
 
 //#region Organizations Query
@@ -117,6 +148,11 @@ type MilestonesQuery = BaseQuery<'Milestones', MilestonesOptions>
 export type PackagesOptions = Options & {
   names?: string;
   packageType?: PackageType;
+  mode?: PackagesMode;
+  ownerKind?: PackageOwnerKind;
+  registryPackageType?: string;
+  visibility?: string;
+  includeManifests?: boolean;
 }
 type PackagesQuery = BaseQuery<'Packages', PackagesOptions>
 //#endregion
@@ -131,7 +167,14 @@ export type ProjectsOptions = Options & {
   number?: number | string;
   user?: string;
   kind?: ProjectQueryType;
+  repository?: string;
+  team?: string;
   filters?: Filter[];
+  filterExpression?: string;
+  mode?: ProjectMode;
+  iterationField?: string;
+  estimateField?: string;
+  iteration?: string;
 }
 type ProjectsQuery = BaseQuery<'Projects', ProjectsOptions>
 //#endregion
@@ -177,6 +220,145 @@ export type DeploymentsOptions = Options & {
 type DeploymentsQuery = BaseQuery<'Deployments', DeploymentsOptions>
 //#endregion
 
+//#region Branch_Protection Query
+export type BranchProtectionPolicy = {
+  requiredApprovingReviewCount?: number;
+  requireCodeOwnerReviews?: boolean;
+  requireStatusChecks?: boolean;
+  requireSignedCommits?: boolean;
+  requireLinearHistory?: boolean;
+  disallowBypassActors?: boolean;
+}
+export type BranchProtectionOptions = Options & {
+  policy?: BranchProtectionPolicy;
+}
+type Branch_ProtectionQuery = BaseQuery<'Branch_Protection', BranchProtectionOptions>
+//#endregion
+
+//#region Members Query
+type MembersQuery = BaseQuery<'Members', {}>
+//#endregion
+
+//#region Teams Query
+export type TeamsOptions = Options & {
+  query?: string;
+}
+type TeamsQuery = BaseQuery<'Teams', TeamsOptions>
+//#endregion
+
+//#region Collaborators Query
+export type CollaboratorsOptions = Options & {
+  affiliation?: CollaboratorAffiliation;
+}
+type CollaboratorsQuery = BaseQuery<'Collaborators', CollaboratorsOptions>
+//#endregion
+
+//#region Deployment_Statuses Query
+export type DeploymentStatusesOptions = Options & {
+  environment?: string;
+}
+type Deployment_StatusesQuery = BaseQuery<'Deployment_Statuses', DeploymentStatusesOptions>
+//#endregion
+
+//#region Environments Query
+type EnvironmentsQuery = BaseQuery<'Environments', {}>
+//#endregion
+
+//#region Checks Query
+export type ChecksOptions = Options & {
+  source?: ChecksSource;
+  gitRef?: string;
+  pullRequest?: number;
+}
+type ChecksQuery = BaseQuery<'Checks', ChecksOptions>
+//#endregion
+
+//#region Merge_Queue Query
+export type MergeQueueOptions = Options & {
+  branch?: string;
+}
+type Merge_QueueQuery = BaseQuery<'Merge_Queue', MergeQueueOptions>
+//#endregion
+
+//#region Discussions Query
+export type DiscussionsOptions = Options & {
+  timeField?: DiscussionTimeField;
+  query?: string;
+}
+type DiscussionsQuery = BaseQuery<'Discussions', DiscussionsOptions>
+//#endregion
+
+//#region Compare Query
+export type CompareOptions = Options & {
+  base?: string;
+  head?: string;
+}
+type CompareQuery = BaseQuery<'Compare', CompareOptions>
+//#endregion
+
+//#region Hotspots Query
+export type HotspotsOptions = Options & {
+  gitRef?: string;
+  pathDepth?: number;
+  include?: string[];
+  exclude?: string[];
+}
+type HotspotsQuery = BaseQuery<'Hotspots', HotspotsOptions>
+//#endregion
+
+//#region Ownership Query
+export type OwnershipOptions = Options & {
+  gitRef?: string;
+  pathDepth?: number;
+  inactiveDays?: number;
+}
+type OwnershipQuery = BaseQuery<'Ownership', OwnershipOptions>
+//#endregion
+
+//#region Codeowners Query
+export type CodeownersOptions = Options & {
+  gitRef?: string;
+  pathDepth?: number;
+  timeField?: PullRequestTimeField;
+}
+type CodeownersQuery = BaseQuery<'Codeowners', CodeownersOptions>
+//#endregion
+
+//#region Contributor_Cohorts Query
+export type ContributorCohortsOptions = Options & {
+  gitRef?: string;
+  interval?: CohortInterval;
+  lookbackDays?: number;
+}
+type Contributor_CohortsQuery = BaseQuery<'Contributor_Cohorts', ContributorCohortsOptions>
+//#endregion
+
+//#region Dependencies Query
+export type DependenciesOptions = Options & {
+  source?: DependenciesSource;
+  package?: string;
+  ecosystem?: string;
+  belowVersion?: string;
+}
+type DependenciesQuery = BaseQuery<'Dependencies', DependenciesOptions>
+//#endregion
+
+//#region Forks Query
+export type ForksOptions = Options & {
+  mode?: ForksMode;
+  activeDays?: number;
+}
+type ForksQuery = BaseQuery<'Forks', ForksOptions>
+//#endregion
+
+//#region Tech_Stack Query
+export type TechStackOptions = Options & {
+  includeForks?: boolean;
+  includeArchived?: boolean;
+}
+type Tech_StackQuery = BaseQuery<'Tech_Stack', TechStackOptions>
+//#endregion
+
 export type GitHubQuery =
   Code_ScanningQuery |
   CommitsQuery |
@@ -202,7 +384,24 @@ export type GitHubQuery =
   Workflow_UsageQuery |
   WorkflowsQuery |
   DeploymentsQuery |
-  BranchesQuery
+  BranchesQuery |
+  Branch_ProtectionQuery |
+  MembersQuery |
+  TeamsQuery |
+  CollaboratorsQuery |
+  Deployment_StatusesQuery |
+  EnvironmentsQuery |
+  ChecksQuery |
+  Merge_QueueQuery |
+  DiscussionsQuery |
+  CompareQuery |
+  HotspotsQuery |
+  OwnershipQuery |
+  CodeownersQuery |
+  Contributor_CohortsQuery |
+  DependenciesQuery |
+  ForksQuery |
+  Tech_StackQuery
 
 export type GitHubVariableQuery = { key?: string; field?: string; } & GitHubQuery
 
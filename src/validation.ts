@@ -3,7 +3,17 @@ import { ProjectQueryType } from './constants';
 import type { GitHubQuery } from './types/query';
 
 export const isValid = (query: GitHubQuery): boolean => {
-  if (query.queryType === "Repositories" || query.queryType === "Code_Scanning") {
+  if (
+    query.queryType === "Repositories" ||
+    query.queryType === "Code_Scanning" ||
+    query.queryType === "Branch_Protection" ||
+    query.queryType === "Members" ||
+    query.queryType === "Teams" ||
+    query.queryType === "Collaborators" ||
+    query.queryType === "Discussions" ||
+    query.queryType === "Dependencies" ||
+    query.queryType === "Tech_Stack"
+  ) {
     if (isEmpty(query.owner)) {
       return false;
     }
@@ -18,19 +28,35 @@ export const isValid = (query: GitHubQuery): boolean => {
     query.queryType === "Milestones" ||
     query.queryType === "Vulnerabilities" ||
     query.queryType === "Pull_Request_Files" ||
-    query.queryType === "Stargazers"
+    query.queryType === "Stargazers" ||
+    query.queryType === "Deployment_Statuses" ||
+    query.queryType === "Environments" ||
+    query.queryType === "Checks" ||
+    query.queryType === "Merge_Queue" ||
+    query.queryType === "Compare" ||
+    query.queryType === "Hotspots" ||
+    query.queryType === "Ownership" ||
+    query.queryType === "Codeowners" ||
+    query.queryType === "Contributor_Cohorts" ||
+    query.queryType === "Forks"
   ) {
     if (isEmpty(query.owner) || isEmpty(query.repository)) {
       return false;
     }
   }
   if (query.queryType === "Projects") {
-    if (isEmpty(query.options?.user) && query.options?.kind === ProjectQueryType.USER) {
-      return false;
+    if (query.options?.kind === ProjectQueryType.USER) {
+      return !isEmpty(query.options?.user);
     }
     if (isEmpty(query.options?.organization)) {
       return false;
     }
+    if (isEmpty(query.options?.repository) && query.options?.kind === ProjectQueryType.REPOSITORY) {
+      return false;
+    }
+    if (isEmpty(query.options?.team) && query.options?.kind === ProjectQueryType.TEAM) {
+      return false;
+    }
   }
   return !!query.queryType;
 };
@@ -25,6 +25,23 @@ import { QueryEditorWorkflowRuns } from './QueryEditorWorkflowRuns';
 import { QueryEditorCodeScanning } from './QueryEditorCodeScanning';
 import { QueryEditorDeployments } from './QueryEditorDeployments';
 import { QueryEditorBranches } from './QueryEditorBranches';
+import { QueryEditorBranchProtection } from './QueryEditorBranchProtection';
+import { QueryEditorTeams } from './QueryEditorTeams';
+import { QueryEditorCollaborators } from './QueryEditorCollaborators';
+import { QueryEditorReleases } from './QueryEditorReleases';
+import { QueryEditorDeploymentStatuses } from './QueryEditorDeploymentStatuses';
+import { QueryEditorChecks } from './QueryEditorChecks';
+import { QueryEditorMergeQueue } from './QueryEditorMergeQueue';
+import { QueryEditorDiscussions } from './QueryEditorDiscussions';
+import { QueryEditorCompare } from './QueryEditorCompare';
+import { QueryEditorHotspots } from './QueryEditorHotspots';
+import { QueryEditorOwnership } from './QueryEditorOwnership';
+import { QueryEditorCodeowners } from './QueryEditorCodeowners';
+import { QueryEditorContributorCohorts } from './QueryEditorContributorCohorts';
+import { QueryEditorDependencies } from './QueryEditorDependencies';
+import { QueryEditorRepositories } from './QueryEditorRepositories';
+import { QueryEditorForks } from './QueryEditorForks';
+import { QueryEditorTechStack } from './QueryEditorTechStack';
 
 import { DefaultQueryType, QueryTypes } from '../constants';
 
@@ -38,7 +55,11 @@ export const LeftColumnWidth = 12;
 export const RightColumnWidth = 36;
 
 const queryEditors: Record<QueryType, { component: (props: Props, onChange: (val: any) => void) => ReactNode }> = {
-  ['Repositories']: { component: () => <></> },
+  ['Repositories']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorRepositories {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
   ['GraphQL']: { component: () => <></> },
   ['Organizations']: { component: () => <></> },
   ['ProjectItems']: { component: () => <></> },
@@ -48,7 +69,11 @@ const queryEditors: Record<QueryType, { component: (props: Props, onChange: (val
       <QueryEditorBranches {...(props.query.options || {})} onChange={onChange} />
     ),
   },
-  ['Releases']: { component: () => <></> },
+  ['Releases']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorReleases {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
   ['Vulnerabilities']: { component: () => <></> },
   ['Stargazers']: { component: () => <></> },
   ['Labels']: {
@@ -131,6 +156,83 @@ const queryEditors: Record<QueryType, { component: (props: Props, onChange: (val
       <QueryEditorDeployments {...(props.query.options || {})} onChange={onChange} />
     ),
   },
+  ['Branch_Protection']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorBranchProtection {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Members']: { component: () => <></> },
+  ['Teams']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorTeams {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Collaborators']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorCollaborators {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Deployment_Statuses']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorDeploymentStatuses {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Environments']: { component: () => <></> },
+  ['Checks']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorChecks {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Merge_Queue']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorMergeQueue {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Discussions']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorDiscussions {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Compare']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorCompare {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Hotspots']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorHotspots {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Ownership']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorOwnership {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Codeowners']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorCodeowners {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Contributor_Cohorts']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorContributorCohorts {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Dependencies']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorDependencies {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Forks']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorForks {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
+  ['Tech_Stack']: {
+    component: (props: Props, onChange: (val: any) => void) => (
+      <QueryEditorTechStack {...(props.query.options || {})} onChange={onChange} />
+    ),
+  },
 };
 
 const queryTypeOptions: Array<SelectableValue<QueryType>> = QueryTypes.map((v) => {
@@ -198,7 +300,7 @@ const QueryEditor = (props: Props) => {
               }}
             />
           )}
-          {hasRepo(props.query.queryType) && (
+          {hasRepo(props.query.queryType) && !isOwnerOnly(props.query.queryType) && (
             <QueryEditorRepository
               repository={props.query.repository}
               onChange={(repo) => {
@@ -226,4 +328,11 @@ function hasRepo(qt?: string) {
   return !nonRepoTypes.includes(qt as QueryType);
 }
 
+// Query types that are about an organization, with an owner but no repository
+const ownerOnlyTypes = ['Members', 'Teams'];
+
+function isOwnerOnly(qt?: string) {
+  return ownerOnlyTypes.includes(qt as QueryType);
+}
+
 export default QueryEditor;
@@ -0,0 +1,75 @@
+import React, { useState } from 'react';
+import { Input, InlineSwitch } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import type { BranchProtectionOptions, BranchProtectionPolicy } from '../types/query';
+
+interface Props extends BranchProtectionOptions {
+  onChange: (value: BranchProtectionOptions) => void;
+}
+
+export const QueryEditorBranchProtection = (props: Props) => {
+  const policy = props.policy || {};
+  const [reviews, setReviews] = useState<string>(policy.requiredApprovingReviewCount?.toString() || '');
+
+  const onPolicyChange = (value: BranchProtectionPolicy) => {
+    props.onChange({
+      ...props,
+      policy: { ...policy, ...value },
+    });
+  };
+
+  return (
+    <EditorRow>
+      <EditorField
+        label="Required Reviews"
+        tooltip="The minimum number of approving reviews the default branch must require to be compliant"
+      >
+        <Input
+          type="number"
+          min={0}
+          value={reviews}
+          width={RightColumnWidth / 2}
+          onChange={(el) => setReviews(el.currentTarget.value)}
+          onBlur={(el) => {
+            const count = parseInt(el.currentTarget.value, 10);
+            onPolicyChange({ requiredApprovingReviewCount: isNaN(count) ? undefined : count });
+          }}
+        />
+      </EditorField>
+      <EditorField label="Code Owner Reviews" tooltip="Require an approving review from a code owner">
+        <InlineSwitch
+          value={policy.requireCodeOwnerReviews || false}
+          onChange={(el) => onPolicyChange({ requireCodeOwnerReviews: el.currentTarget.checked })}
+        />
+      </EditorField>
+      <EditorField label="Status Checks" tooltip="Require status checks to pass before merging">
+        <InlineSwitch
+          value={policy.requireStatusChecks || false}
+          onChange={(el) => onPolicyChange({ requireStatusChecks: el.currentTarget.checked })}
+        />
+      </EditorField>
+      <EditorField label="Signed Commits" tooltip="Require the commits pushed to the default branch to be signed">
+        <InlineSwitch
+          value={policy.requireSignedCommits || false}
+          onChange={(el) => onPolicyChange({ requireSignedCommits: el.currentTarget.checked })}
+        />
+      </EditorField>
+      <EditorField label="Linear History" tooltip="Prevent merge commits from being pushed to the default branch">
+        <InlineSwitch
+          value={policy.requireLinearHistory || false}
+          onChange={(el) => onPolicyChange({ requireLinearHistory: el.currentTarget.checked })}
+        />
+      </EditorField>
+      <EditorField
+        label="No Bypass Actors"
+        tooltip="Mark the default branch as non compliant when any user, team or app is allowed to bypass its protection"
+      >
+        <InlineSwitch
+          value={policy.disallowBypassActors || false}
+          onChange={(el) => onPolicyChange({ disallowBypassActors: el.currentTarget.checked })}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
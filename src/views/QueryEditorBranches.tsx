@@ -1,15 +1,26 @@
 import React, { useState } from 'react';
-import { Input } from '@grafana/ui';
+import { Combobox, ComboboxOption, Input } from '@grafana/ui';
 import { EditorField, EditorRow } from '@grafana/plugin-ui';
 import { BranchesOptions } from '../types/query';
+import { BranchesMode } from '../constants';
 import { LeftColumnWidth, RightColumnWidth } from './QueryEditor';
 
 interface Props extends BranchesOptions {
   onChange: (value: BranchesOptions) => void;
 }
 
-export const QueryEditorBranches = ({ query = '', onChange }: Props) => {
-  const [filter, setFilter] = useState<string>(query);
+const modes: Array<ComboboxOption<BranchesMode>> = [
+  { label: 'List', value: BranchesMode.List, description: 'The branches of the repository' },
+  {
+    label: 'Stale',
+    value: BranchesMode.Stale,
+    description: 'The branches compared to the default branch, flagging the merged and abandoned ones',
+  },
+];
+
+export const QueryEditorBranches = (props: Props) => {
+  const [filter, setFilter] = useState<string>(props.query || '');
+  const [staleDays, setStaleDays] = useState<string>(props.staleDays !== undefined ? String(props.staleDays) : '');
 
   return (
     <EditorRow>
@@ -22,10 +33,36 @@ export const QueryEditorBranches = ({ query = '', onChange }: Props) => {
           aria-label="Branch filter"
           value={filter}
           onChange={(e) => setFilter(e.currentTarget.value)}
-          onBlur={() => onChange({ query: filter })}
+          onBlur={() => props.onChange({ ...props, query: filter })}
+          width={RightColumnWidth}
+        />
+      </EditorField>
+      <EditorField label="Mode" tooltip="What is returned for the branches">
+        <Combobox
           width={RightColumnWidth}
+          options={modes}
+          value={props.mode || BranchesMode.List}
+          onChange={(opt) => props.onChange({ ...props, mode: opt.value })}
         />
       </EditorField>
+      {props.mode === BranchesMode.Stale && (
+        <EditorField
+          label="Stale Days"
+          tooltip="The number of days without commits after which an unmerged branch without an open pull request is abandoned"
+        >
+          <Input
+            width={RightColumnWidth}
+            type="number"
+            value={staleDays}
+            placeholder="90"
+            onChange={(el) => setStaleDays(el.currentTarget.value)}
+            onBlur={(el) => {
+              const parsed = parseInt(el.currentTarget.value, 10);
+              props.onChange({ ...props, staleDays: isNaN(parsed) ? undefined : parsed });
+            }}
+          />
+        </EditorField>
+      )}
     </EditorRow>
   );
 };
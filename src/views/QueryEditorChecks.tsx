@@ -0,0 +1,66 @@
+import React, { useState } from 'react';
+import { Combobox, ComboboxOption, Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import { ChecksSource } from '../constants';
+import type { ChecksOptions } from '../types/query';
+
+interface Props extends ChecksOptions {
+  onChange: (value: ChecksOptions) => void;
+}
+
+const sourceOptions: Array<ComboboxOption<ChecksSource>> = [
+  { label: 'Ref', value: ChecksSource.Ref, description: 'The commit a branch, tag or SHA points to' },
+  { label: 'Pull request', value: ChecksSource.PullRequest, description: 'The head commit of a pull request' },
+  { label: 'Range', value: ChecksSource.Range, description: 'Every commit of a ref in the time range' },
+];
+
+export const QueryEditorChecks = (props: Props) => {
+  const [gitRef, setGitRef] = useState<string>(props.gitRef || '');
+  const [pullRequest, setPullRequest] = useState<string>(
+    props.pullRequest !== undefined ? String(props.pullRequest) : ''
+  );
+  const source = props.source || ChecksSource.Ref;
+
+  return (
+    <EditorRow>
+      <EditorField label="Source" tooltip="Which commits to list the checks of">
+        <Combobox
+          width={RightColumnWidth}
+          options={sourceOptions}
+          value={source}
+          onChange={(opt) =>
+            props.onChange({
+              ...props,
+              source: opt.value,
+            })
+          }
+        />
+      </EditorField>
+      {source === ChecksSource.PullRequest ? (
+        <EditorField label="Pull Request Number" tooltip="The pull request to list the checks of its head commit">
+          <Input
+            width={RightColumnWidth}
+            type="number"
+            value={pullRequest}
+            placeholder="e.g. 42"
+            onChange={(el) => setPullRequest(el.currentTarget.value)}
+            onBlur={(el) => {
+              const parsed = parseInt(el.currentTarget.value, 10);
+              props.onChange({ ...props, pullRequest: isNaN(parsed) ? undefined : parsed });
+            }}
+          />
+        </EditorField>
+      ) : (
+        <EditorField label="Ref (Branch / Tag)" tooltip="Defaults to the default branch of the repository (optional)">
+          <Input
+            width={RightColumnWidth}
+            value={gitRef}
+            onChange={(el) => setGitRef(el.currentTarget.value)}
+            onBlur={(el) => props.onChange({ ...props, gitRef: el.currentTarget.value })}
+          />
+        </EditorField>
+      )}
+    </EditorRow>
+  );
+};
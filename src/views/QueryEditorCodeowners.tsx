@@ -0,0 +1,66 @@
+import React, { useState } from 'react';
+import { Input, Combobox, ComboboxOption } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import { PullRequestTimeField } from '../constants';
+import type { CodeownersOptions } from '../types/query';
+
+interface Props extends CodeownersOptions {
+  onChange: (value: CodeownersOptions) => void;
+}
+
+const timeFieldOptions: Array<ComboboxOption<PullRequestTimeField>> = Object.keys(PullRequestTimeField)
+  .filter((_, i) => PullRequestTimeField[i] !== undefined)
+  .map((_, i) => {
+    return {
+      label: `${PullRequestTimeField[i]}`,
+      value: i as PullRequestTimeField,
+    };
+  });
+
+const defaultTimeField = timeFieldOptions[0].value;
+
+export const QueryEditorCodeowners = (props: Props) => {
+  const [ref, setRef] = useState<string>(props.gitRef || '');
+  const [pathDepth, setPathDepth] = useState<string>(props.pathDepth !== undefined ? String(props.pathDepth) : '');
+  return (
+    <EditorRow>
+      <EditorField
+        label="Ref (Branch / Tag)"
+        tooltip="The branch or tag whose CODEOWNERS file and files are analyzed, the default branch when empty (optional)"
+      >
+        <Input
+          width={RightColumnWidth}
+          value={ref}
+          placeholder="main"
+          onChange={(el) => setRef(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, gitRef: el.currentTarget.value })}
+        />
+      </EditorField>
+      <EditorField label="Path Depth" tooltip="The number of directories the coverage of the files is rolled up to">
+        <Input
+          width={RightColumnWidth}
+          type="number"
+          value={pathDepth}
+          placeholder="1"
+          onChange={(el) => setPathDepth(el.currentTarget.value)}
+          onBlur={(el) => {
+            const parsed = parseInt(el.currentTarget.value, 10);
+            props.onChange({ ...props, pathDepth: isNaN(parsed) ? undefined : parsed });
+          }}
+        />
+      </EditorField>
+      <EditorField
+        label="Time Field"
+        tooltip="The time field of the pull requests checked for owner approvals that is filtered on the time range"
+      >
+        <Combobox
+          width={RightColumnWidth}
+          options={timeFieldOptions}
+          value={props.timeField || defaultTimeField}
+          onChange={(opt) => props.onChange({ ...props, timeField: opt.value })}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
@@ -0,0 +1,47 @@
+import React from 'react';
+import { Combobox, ComboboxOption } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import { CollaboratorAffiliation } from '../constants';
+import type { CollaboratorsOptions } from '../types/query';
+
+interface Props extends CollaboratorsOptions {
+  onChange: (value: CollaboratorsOptions) => void;
+}
+
+const affiliationOptions: Array<ComboboxOption<CollaboratorAffiliation>> = [
+  {
+    label: 'Outside',
+    value: CollaboratorAffiliation.Outside,
+    description: 'Collaborators who are not members of the organization',
+  },
+  {
+    label: 'Direct',
+    value: CollaboratorAffiliation.Direct,
+    description: 'Collaborators with a direct permission to the repository',
+  },
+  { label: 'All', value: CollaboratorAffiliation.All, description: 'Every collaborator, including through teams' },
+];
+
+export const QueryEditorCollaborators = (props: Props) => {
+  return (
+    <EditorRow>
+      <EditorField
+        label="Affiliation"
+        tooltip="Filter the collaborators by how they are affiliated with the repository"
+      >
+        <Combobox
+          width={RightColumnWidth}
+          options={affiliationOptions}
+          value={props.affiliation || CollaboratorAffiliation.Outside}
+          onChange={(opt) =>
+            props.onChange({
+              ...props,
+              affiliation: opt.value,
+            })
+          }
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
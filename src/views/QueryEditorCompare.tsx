@@ -0,0 +1,42 @@
+import React, { useState } from 'react';
+import { Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import type { CompareOptions } from '../types/query';
+
+interface Props extends CompareOptions {
+  onChange: (value: CompareOptions) => void;
+}
+
+export const QueryEditorCompare = (props: Props) => {
+  const [base, setBase] = useState<string>(props.base || '');
+  const [head, setHead] = useState<string>(props.head || '');
+  return (
+    <EditorRow>
+      <EditorField
+        label="Base"
+        tooltip="The branch, tag or commit SHA the head is compared against, e.g. the previous tag"
+      >
+        <Input
+          width={RightColumnWidth}
+          value={base}
+          placeholder="v10.0.0"
+          onChange={(el) => setBase(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, base: el.currentTarget.value })}
+        />
+      </EditorField>
+      <EditorField
+        label="Head"
+        tooltip="The branch, tag or commit SHA that is compared, e.g. the latest tag or deployed SHA"
+      >
+        <Input
+          width={RightColumnWidth}
+          value={head}
+          placeholder="v10.1.0"
+          onChange={(el) => setHead(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, head: el.currentTarget.value })}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
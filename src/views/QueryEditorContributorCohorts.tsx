@@ -0,0 +1,63 @@
+import React, { useState } from 'react';
+import { Input, Combobox, ComboboxOption } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import { CohortInterval } from '../constants';
+import type { ContributorCohortsOptions } from '../types/query';
+
+interface Props extends ContributorCohortsOptions {
+  onChange: (value: ContributorCohortsOptions) => void;
+}
+
+const intervalOptions: Array<ComboboxOption<CohortInterval>> = [
+  { label: 'Week', value: CohortInterval.Week, description: 'Weeks starting on Monday' },
+  { label: 'Month', value: CohortInterval.Month },
+  { label: 'Quarter', value: CohortInterval.Quarter },
+];
+
+export const QueryEditorContributorCohorts = (props: Props) => {
+  const [ref, setRef] = useState<string>(props.gitRef || '');
+  const [lookbackDays, setLookbackDays] = useState<string>(
+    props.lookbackDays !== undefined ? String(props.lookbackDays) : ''
+  );
+  return (
+    <EditorRow>
+      <EditorField
+        label="Ref (Branch)"
+        tooltip="The branch whose commits are counted as contributions, the default branch when empty (optional)"
+      >
+        <Input
+          width={RightColumnWidth}
+          value={ref}
+          placeholder="main"
+          onChange={(el) => setRef(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, gitRef: el.currentTarget.value })}
+        />
+      </EditorField>
+      <EditorField label="Interval" tooltip="The length of the periods contributors are grouped by">
+        <Combobox
+          width={RightColumnWidth}
+          options={intervalOptions}
+          value={props.interval || CohortInterval.Month}
+          onChange={(opt) => props.onChange({ ...props, interval: opt.value })}
+        />
+      </EditorField>
+      <EditorField
+        label="Lookback Days"
+        tooltip="How far before the time range contributions are looked for, to tell first-time contributors from returning ones"
+      >
+        <Input
+          width={RightColumnWidth}
+          type="number"
+          value={lookbackDays}
+          placeholder="365"
+          onChange={(el) => setLookbackDays(el.currentTarget.value)}
+          onBlur={(el) => {
+            const parsed = parseInt(el.currentTarget.value, 10);
+            props.onChange({ ...props, lookbackDays: isNaN(parsed) ? undefined : parsed });
+          }}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
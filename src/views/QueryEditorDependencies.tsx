@@ -0,0 +1,74 @@
+import React, { useState } from 'react';
+import { Input, Combobox, ComboboxOption } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import { DependenciesSource } from '../constants';
+import type { DependenciesOptions } from '../types/query';
+
+interface Props extends DependenciesOptions {
+  onChange: (value: DependenciesOptions) => void;
+}
+
+const sourceOptions: Array<ComboboxOption<DependenciesSource>> = [
+  {
+    label: 'SBOM',
+    value: DependenciesSource.SBOM,
+    description: 'The resolved versions and the licenses, from the software bill of materials',
+  },
+  {
+    label: 'Manifests',
+    value: DependenciesSource.Manifests,
+    description: 'The version requirements, with the manifest declaring each dependency',
+  },
+];
+
+export const QueryEditorDependencies = (props: Props) => {
+  const [pkg, setPkg] = useState<string>(props.package || '');
+  const [ecosystem, setEcosystem] = useState<string>(props.ecosystem || '');
+  const [belowVersion, setBelowVersion] = useState<string>(props.belowVersion || '');
+  return (
+    <EditorRow>
+      <EditorField label="Source" tooltip="Where the dependencies of the dependency graph are read from">
+        <Combobox
+          width={RightColumnWidth}
+          options={sourceOptions}
+          value={props.source || DependenciesSource.SBOM}
+          onChange={(opt) => props.onChange({ ...props, source: opt.value })}
+        />
+      </EditorField>
+      <EditorField label="Package" tooltip="Only list the dependencies on this package (optional)">
+        <Input
+          width={RightColumnWidth}
+          value={pkg}
+          placeholder="lodash"
+          onChange={(el) => setPkg(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, package: el.currentTarget.value })}
+        />
+      </EditorField>
+      <EditorField
+        label="Ecosystem"
+        tooltip="Only list the dependencies of this package manager, like npm, pip or go (optional)"
+      >
+        <Input
+          width={RightColumnWidth}
+          value={ecosystem}
+          placeholder="npm"
+          onChange={(el) => setEcosystem(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, ecosystem: el.currentTarget.value })}
+        />
+      </EditorField>
+      <EditorField
+        label="Below Version"
+        tooltip="Only list the dependencies on a version lower than this one, to find the repositories using an outdated library (optional)"
+      >
+        <Input
+          width={RightColumnWidth}
+          value={belowVersion}
+          placeholder="4.17.21"
+          onChange={(el) => setBelowVersion(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, belowVersion: el.currentTarget.value })}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
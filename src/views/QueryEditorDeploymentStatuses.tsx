@@ -0,0 +1,34 @@
+import React, { useState } from 'react';
+import { Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import type { DeploymentStatusesOptions } from 'types/query';
+
+interface Props extends DeploymentStatusesOptions {
+  onChange: (value: DeploymentStatusesOptions) => void;
+}
+
+export const QueryEditorDeploymentStatuses = (props: Props) => {
+  const [environment, setEnvironment] = useState<string | undefined>(props.environment);
+
+  return (
+    <EditorRow>
+      <EditorField
+        label="Environment"
+        tooltip="Filter by environment name (e.g., 'production', 'staging', 'qa') (optional)"
+      >
+        <Input
+          value={environment}
+          width={RightColumnWidth}
+          onChange={(el) => setEnvironment(el.currentTarget.value)}
+          onBlur={(el) =>
+            props.onChange({
+              ...props,
+              environment: el.currentTarget.value || undefined,
+            })
+          }
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
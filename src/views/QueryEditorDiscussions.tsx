@@ -0,0 +1,69 @@
+import React, { useState } from 'react';
+import { Combobox, ComboboxOption, Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import { DiscussionTimeField } from '../constants';
+import type { DiscussionsOptions } from '../types/query';
+
+interface Props extends DiscussionsOptions {
+  onChange: (value: DiscussionsOptions) => void;
+}
+
+const timeFieldOptions: Array<ComboboxOption<DiscussionTimeField>> = Object.keys(DiscussionTimeField)
+  .filter((_, i) => DiscussionTimeField[i] !== undefined)
+  .map((_, i) => {
+    return {
+      label: `${DiscussionTimeField[i]}`,
+      value: i as DiscussionTimeField,
+    };
+  });
+
+const defaultTimeField = 0 as DiscussionTimeField;
+
+export const QueryEditorDiscussions = (props: Props) => {
+  const [query, setQuery] = useState<string>(props.query || '');
+  return (
+    <EditorRow>
+      <EditorField
+        label="Query"
+        tooltip={() => (
+          <>
+            For more information, visit&nbsp;
+            <a
+              href="https://docs.github.com/en/search-github/searching-on-github/searching-discussions"
+              target="_blank"
+              rel="noreferrer"
+            >
+              https://docs.github.com/en/search-github/searching-on-github/searching-discussions
+            </a>
+          </>
+        )}
+      >
+        <Input
+          value={query}
+          width={RightColumnWidth * 2 + 2}
+          onChange={(el) => setQuery(el.currentTarget.value)}
+          onBlur={(el) =>
+            props.onChange({
+              ...props,
+              query: el.currentTarget.value,
+            })
+          }
+        />
+      </EditorField>
+      <EditorField label="Time Field" tooltip="The time field to filter on the time range">
+        <Combobox
+          width={RightColumnWidth}
+          options={timeFieldOptions}
+          value={props.timeField || defaultTimeField}
+          onChange={(opt) =>
+            props.onChange({
+              ...props,
+              timeField: opt.value,
+            })
+          }
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
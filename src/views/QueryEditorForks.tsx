@@ -0,0 +1,53 @@
+import React, { useState } from 'react';
+import { Combobox, ComboboxOption, Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import { ForksMode } from '../constants';
+import type { ForksOptions } from '../types/query';
+
+interface Props extends ForksOptions {
+  onChange: (value: ForksOptions) => void;
+}
+
+const modes: Array<ComboboxOption<ForksMode>> = [
+  {
+    label: 'Growth',
+    value: ForksMode.Growth,
+    description: 'The forks created in the time range, with the number of forks when each one was created',
+  },
+  { label: 'Active', value: ForksMode.Active, description: 'The forks pushed to since they were created' },
+];
+
+export const QueryEditorForks = (props: Props) => {
+  const [activeDays, setActiveDays] = useState<string>(props.activeDays !== undefined ? String(props.activeDays) : '');
+  return (
+    <EditorRow>
+      <EditorField label="Mode" tooltip="What is returned for the forks">
+        <Combobox
+          width={RightColumnWidth}
+          options={modes}
+          value={props.mode || ForksMode.Growth}
+          onChange={(opt) => props.onChange({ ...props, mode: opt.value })}
+        />
+      </EditorField>
+      {props.mode === ForksMode.Active && (
+        <EditorField
+          label="Active Days"
+          tooltip="The number of days without pushes after which a fork is no longer active"
+        >
+          <Input
+            width={RightColumnWidth}
+            type="number"
+            value={activeDays}
+            placeholder="90"
+            onChange={(el) => setActiveDays(el.currentTarget.value)}
+            onBlur={(el) => {
+              const parsed = parseInt(el.currentTarget.value, 10);
+              props.onChange({ ...props, activeDays: isNaN(parsed) ? undefined : parsed });
+            }}
+          />
+        </EditorField>
+      )}
+    </EditorRow>
+  );
+};
@@ -0,0 +1,66 @@
+import React, { useState } from 'react';
+import { Input, TagsInput } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import type { HotspotsOptions } from '../types/query';
+
+interface Props extends HotspotsOptions {
+  onChange: (value: HotspotsOptions) => void;
+}
+
+export const QueryEditorHotspots = (props: Props) => {
+  const [ref, setRef] = useState<string>(props.gitRef || '');
+  const [pathDepth, setPathDepth] = useState<string>(props.pathDepth !== undefined ? String(props.pathDepth) : '');
+  return (
+    <>
+      <EditorRow>
+        <EditorField label="Ref (Branch / Tag)" tooltip="The branch or tag whose commits are analyzed">
+          <Input
+            width={RightColumnWidth}
+            value={ref}
+            placeholder="main"
+            onChange={(el) => setRef(el.currentTarget.value)}
+            onBlur={(el) => props.onChange({ ...props, gitRef: el.currentTarget.value })}
+          />
+        </EditorField>
+        <EditorField
+          label="Path Depth"
+          tooltip="The number of directories files are rolled up to, ex: 2 rolls pkg/github/commits.go up to pkg/github"
+        >
+          <Input
+            width={RightColumnWidth}
+            type="number"
+            value={pathDepth}
+            placeholder="1"
+            onChange={(el) => setPathDepth(el.currentTarget.value)}
+            onBlur={(el) => {
+              const parsed = parseInt(el.currentTarget.value, 10);
+              props.onChange({ ...props, pathDepth: isNaN(parsed) ? undefined : parsed });
+            }}
+          />
+        </EditorField>
+      </EditorRow>
+      <EditorRow>
+        <EditorField
+          label="Include"
+          tooltip="Only keep the files matching one of these globs, ex: pkg/**. * doesn't match /, ** does (optional)"
+        >
+          <TagsInput
+            width={RightColumnWidth * 2}
+            tags={props.include}
+            placeholder="pkg/**"
+            onChange={(include) => props.onChange({ ...props, include })}
+          />
+        </EditorField>
+        <EditorField label="Exclude" tooltip="Drop the files matching one of these globs, ex: **/*_test.go (optional)">
+          <TagsInput
+            width={RightColumnWidth * 2}
+            tags={props.exclude}
+            placeholder="**/*_test.go"
+            onChange={(exclude) => props.onChange({ ...props, exclude })}
+          />
+        </EditorField>
+      </EditorRow>
+    </>
+  );
+};
@@ -2,8 +2,9 @@ import React, { useState } from 'react';
 import { Combobox, ComboboxOption, Input } from '@grafana/ui';
 import { EditorField, EditorRow } from '@grafana/plugin-ui';
 import { RightColumnWidth } from './QueryEditor';
+import { TeamSearch } from '../components/TeamSearch';
 import { components } from 'components/selectors';
-import { IssueTimeField } from '../constants';
+import { IssueTimeField, TeamQualifier } from '../constants';
 import type { IssuesOptions } from '../types/query';
 
 interface Props extends IssuesOptions {
@@ -66,6 +67,12 @@ export const QueryEditorIssues = (props: Props) => {
           }
         />
       </EditorField>
+      <TeamSearch
+        team={props.team}
+        teamQualifier={props.teamQualifier}
+        qualifiers={[TeamQualifier.Author, TeamQualifier.Assignee, TeamQualifier.Involves]}
+        onChange={(value) => props.onChange({ ...props, ...value })}
+      />
     </EditorRow>
   );
 };
@@ -0,0 +1,29 @@
+import React, { useState } from 'react';
+import { Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import type { MergeQueueOptions } from '../types/query';
+
+interface Props extends MergeQueueOptions {
+  onChange: (value: MergeQueueOptions) => void;
+}
+
+export const QueryEditorMergeQueue = (props: Props) => {
+  const [branch, setBranch] = useState<string>(props.branch || '');
+  return (
+    <EditorRow>
+      <EditorField
+        label="Branch"
+        tooltip="The branch the merge queue merges into. Defaults to the default branch of the repository (optional)"
+      >
+        <Input
+          width={RightColumnWidth}
+          value={branch}
+          placeholder="main"
+          onChange={(el) => setBranch(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, branch: el.currentTarget.value || undefined })}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
@@ -0,0 +1,64 @@
+import React, { useState } from 'react';
+import { Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import type { OwnershipOptions } from '../types/query';
+
+interface Props extends OwnershipOptions {
+  onChange: (value: OwnershipOptions) => void;
+}
+
+const toNumber = (value: string): number | undefined => {
+  const parsed = parseInt(value, 10);
+  return isNaN(parsed) ? undefined : parsed;
+};
+
+export const QueryEditorOwnership = (props: Props) => {
+  const [ref, setRef] = useState<string>(props.gitRef || '');
+  const [pathDepth, setPathDepth] = useState<string>(props.pathDepth !== undefined ? String(props.pathDepth) : '');
+  const [inactiveDays, setInactiveDays] = useState<string>(
+    props.inactiveDays !== undefined ? String(props.inactiveDays) : ''
+  );
+  return (
+    <EditorRow>
+      <EditorField
+        label="Ref (Branch / Tag)"
+        tooltip="The branch or tag whose commits and CODEOWNERS file are analyzed"
+      >
+        <Input
+          width={RightColumnWidth}
+          value={ref}
+          placeholder="main"
+          onChange={(el) => setRef(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, gitRef: el.currentTarget.value })}
+        />
+      </EditorField>
+      <EditorField
+        label="Path Depth"
+        tooltip="The number of directories files are rolled up to, ex: 2 rolls pkg/github/commits.go up to pkg/github"
+      >
+        <Input
+          width={RightColumnWidth}
+          type="number"
+          value={pathDepth}
+          placeholder="1"
+          onChange={(el) => setPathDepth(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, pathDepth: toNumber(el.currentTarget.value) })}
+        />
+      </EditorField>
+      <EditorField
+        label="Inactive Days"
+        tooltip="The number of days without contributions after which a code owner is flagged as inactive, at most 365"
+      >
+        <Input
+          width={RightColumnWidth}
+          type="number"
+          value={inactiveDays}
+          placeholder="90"
+          onChange={(el) => setInactiveDays(el.currentTarget.value)}
+          onBlur={(el) => props.onChange({ ...props, inactiveDays: toNumber(el.currentTarget.value) })}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
@@ -1,8 +1,8 @@
 import React, { useEffect, useMemo, useState } from 'react';
-import { Input, Combobox, ComboboxOption } from '@grafana/ui';
-import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { Input, Combobox, ComboboxOption, InlineSwitch, RadioButtonGroup } from '@grafana/ui';
+import { EditorField, EditorRow, EditorRows } from '@grafana/plugin-ui';
 import { RightColumnWidth, LeftColumnWidth } from './QueryEditor';
-import { PackageType } from '../constants';
+import { PackageOwnerKind, PackageType, PackagesMode, RegistryPackageTypes } from '../constants';
 import type { PackagesOptions } from '../types/query';
 
 interface Props extends PackagesOptions {
@@ -11,6 +11,41 @@ interface Props extends PackagesOptions {
 
 export const DefaultPackageType = PackageType.DOCKER;
 
+const modes: Array<ComboboxOption<PackagesMode>> = [
+  {
+    label: 'Repository',
+    value: PackagesMode.Repository,
+    description: 'The packages of the repository with their downloads',
+  },
+  {
+    label: 'Registry',
+    value: PackagesMode.Registry,
+    description: 'The packages of the owner in every ecosystem with their versions, without downloads',
+  },
+  {
+    label: 'Downloads',
+    value: PackagesMode.Downloads,
+    description: 'The downloads of the package versions of the repository over time',
+  },
+];
+
+const ownerKinds = [
+  { label: 'Organization', value: PackageOwnerKind.Organization },
+  { label: 'User', value: PackageOwnerKind.User },
+];
+
+const registryPackageTypes: Array<ComboboxOption<string>> = [
+  { label: 'All', value: '' },
+  ...RegistryPackageTypes.map((t) => ({ label: t, value: t })),
+];
+
+const visibilities: Array<ComboboxOption<string>> = [
+  { label: 'All', value: '' },
+  { label: 'Public', value: 'public' },
+  { label: 'Private', value: 'private' },
+  { label: 'Internal', value: 'internal' },
+];
+
 export const QueryEditorPackages = (props: Props) => {
   const [names, setNames] = useState<string>(props.names || '');
 
@@ -57,34 +92,90 @@ export const QueryEditorPackages = (props: Props) => {
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, []);
 
+  const registry = props.mode === PackagesMode.Registry;
+
   return (
-    <EditorRow>
-      <EditorField label="Package type">
-        <Combobox
-          options={packageTypeOptions}
-          value={props.packageType || DefaultPackageType}
-          width={RightColumnWidth}
-          onChange={(opt) =>
-            props.onChange({
-              ...props,
-              packageType: opt.value as PackageType,
-            })
-          }
-        />
-      </EditorField>
-      <EditorField label="Names" tooltip="Search for packages using a comma delimited list of names">
-        <Input
-          value={names}
-          width={RightColumnWidth * 2 + LeftColumnWidth}
-          onChange={(el) => setNames(el.currentTarget.value)}
-          onBlur={(el) =>
-            props.onChange({
-              ...props.query,
-              names: el.currentTarget.value,
-            })
-          }
-        />
-      </EditorField>
-    </EditorRow>
+    <EditorRows>
+      <EditorRow>
+        <EditorField label="Mode" tooltip="What is returned for the packages">
+          <Combobox
+            width={RightColumnWidth}
+            options={modes}
+            value={props.mode || PackagesMode.Repository}
+            onChange={(opt) => props.onChange({ ...props, mode: opt.value })}
+          />
+        </EditorField>
+        {registry && (
+          <>
+            <EditorField label="Owner Type" tooltip="Whether the owner is an organization or a user">
+              <RadioButtonGroup<PackageOwnerKind>
+                options={ownerKinds}
+                value={props.ownerKind || PackageOwnerKind.Organization}
+                onChange={(v) => props.onChange({ ...props, ownerKind: v })}
+                size={'md'}
+              />
+            </EditorField>
+            <EditorField label="Visibility" tooltip="The visibility of the packages (optional)">
+              <Combobox
+                width={RightColumnWidth}
+                options={visibilities}
+                value={props.visibility || ''}
+                onChange={(opt) => props.onChange({ ...props, visibility: opt.value })}
+              />
+            </EditorField>
+          </>
+        )}
+      </EditorRow>
+      <EditorRow>
+        {registry ? (
+          <EditorField label="Package type" tooltip="The ecosystem of the packages, every ecosystem when empty">
+            <Combobox
+              options={registryPackageTypes}
+              value={props.registryPackageType || ''}
+              width={RightColumnWidth}
+              onChange={(opt) => props.onChange({ ...props, registryPackageType: opt.value })}
+            />
+          </EditorField>
+        ) : (
+          <EditorField label="Package type">
+            <Combobox
+              options={packageTypeOptions}
+              value={props.packageType || DefaultPackageType}
+              width={RightColumnWidth}
+              onChange={(opt) =>
+                props.onChange({
+                  ...props,
+                  packageType: opt.value as PackageType,
+                })
+              }
+            />
+          </EditorField>
+        )}
+        <EditorField label="Names" tooltip="Search for packages using a comma delimited list of names">
+          <Input
+            value={names}
+            width={RightColumnWidth * 2 + LeftColumnWidth}
+            onChange={(el) => setNames(el.currentTarget.value)}
+            onBlur={(el) =>
+              props.onChange({
+                ...props.query,
+                names: el.currentTarget.value,
+              })
+            }
+          />
+        </EditorField>
+        {registry && (
+          <EditorField
+            label="Include Manifests"
+            tooltip="Gets the platforms and the size of the 100 most recent versions of the container images from the registry. Makes a request per version."
+          >
+            <InlineSwitch
+              value={props.includeManifests || false}
+              onChange={(el) => props.onChange({ ...props, includeManifests: el.currentTarget.checked })}
+            />
+          </EditorField>
+        )}
+      </EditorRow>
+    </EditorRows>
   );
 };
@@ -1,11 +1,11 @@
 import React, { useState } from 'react';
-import { Input, RadioButtonGroup } from '@grafana/ui';
+import { Combobox, ComboboxOption, Input, RadioButtonGroup } from '@grafana/ui';
 import { EditorRows, EditorRow, EditorField } from '@grafana/plugin-ui';
 import { RightColumnWidth } from './QueryEditor';
 import { components } from '../components/selectors';
 import { SelectableValue } from '@grafana/data';
 import { Filter, Filters } from 'components/Filters';
-import { ProjectQueryType } from './../constants';
+import { ProjectMode, ProjectQueryType } from './../constants';
 import type { ProjectsOptions } from 'types/query';
 
 interface Props extends ProjectsOptions {
@@ -15,6 +15,22 @@ interface Props extends ProjectsOptions {
 const queryTypes = [
   { label: 'Organization', value: ProjectQueryType.ORG },
   { label: 'User', value: ProjectQueryType.USER },
+  { label: 'Repository', value: ProjectQueryType.REPOSITORY },
+  { label: 'Team', value: ProjectQueryType.TEAM },
+];
+
+const modes: Array<ComboboxOption<ProjectMode>> = [
+  { label: 'Items', value: ProjectMode.Items, description: 'The items of the project' },
+  {
+    label: 'Burndown',
+    value: ProjectMode.Burndown,
+    description: 'The remaining work of an iteration and the velocity of the past iterations',
+  },
+  {
+    label: 'Flow',
+    value: ProjectMode.Flow,
+    description: 'The cumulative flow of the items between the Status columns and their cycle time',
+  },
 ];
 
 const filters: Array<SelectableValue<string>> = [
@@ -37,6 +53,12 @@ const ops: Array<SelectableValue<string>> = [
   { label: 'Less Than or Equal', value: '<=' },
   { label: 'Greater Than or Equal', value: '>=' },
   { label: 'Contains', value: '~' },
+  { label: 'Matches Regex', value: '=~' },
+  { label: 'Does Not Match Regex', value: '!~' },
+  { label: 'In', value: 'in' },
+  { label: 'Not In', value: 'not in' },
+  { label: 'Is Empty', value: 'is empty' },
+  { label: 'Is Not Empty', value: 'is not empty' },
 ];
 
 const fetchFilters = async (key?: string) => (key ? [] : filters);
@@ -44,14 +66,23 @@ const fetchFilters = async (key?: string) => (key ? [] : filters);
 export const QueryEditorProjects = (props: Props) => {
   const [org, setOrg] = useState<string>(props.organization || '');
   const [user, setUser] = useState<string>(props.user || '');
+  const [repository, setRepository] = useState<string>(props.repository || '');
+  const [team, setTeam] = useState<string>(props.team || '');
   const [number, setNumber] = useState<number | string | undefined>(props.number);
   const [kind, setKind] = useState<ProjectQueryType>(props.kind || ProjectQueryType.ORG);
   const [filters, setFilters] = useState<Filter[]>(props.filters || []);
-  const label = kind === ProjectQueryType.ORG ? 'Organization' : 'User';
+  const [iterationField, setIterationField] = useState<string>(props.iterationField || '');
+  const [estimateField, setEstimateField] = useState<string>(props.estimateField || '');
+  const [iteration, setIteration] = useState<string>(props.iteration || '');
+  const [filterExpression, setFilterExpression] = useState<string>(props.filterExpression || '');
+  const label =
+    kind === ProjectQueryType.USER ? 'User' : kind === ProjectQueryType.REPOSITORY ? 'Owner' : 'Organization';
   const tooltip =
-    kind === ProjectQueryType.ORG
-      ? "The organization for the GitHub project (example: 'grafana')"
-      : 'The user who owns the GitHub project';
+    kind === ProjectQueryType.USER
+      ? 'The user who owns the GitHub project'
+      : kind === ProjectQueryType.REPOSITORY
+        ? "The organization or user who owns the repository (example: 'grafana')"
+        : "The organization for the GitHub project (example: 'grafana')";
 
   return (
     <EditorRows>
@@ -72,7 +103,7 @@ export const QueryEditorProjects = (props: Props) => {
         </EditorField>
         <EditorField label={label} tooltip={tooltip}>
           <>
-            {kind === ProjectQueryType.ORG && (
+            {kind !== ProjectQueryType.USER && (
               <Input
                 aria-label={components.QueryEditor.Owner.input}
                 width={RightColumnWidth}
@@ -104,6 +135,30 @@ export const QueryEditorProjects = (props: Props) => {
             )}
           </>
         </EditorField>
+        {kind === ProjectQueryType.REPOSITORY && (
+          <EditorField label="Repository" tooltip="The repository the GitHub projects are linked to">
+            <Input
+              aria-label={components.QueryEditor.Repository.input}
+              width={RightColumnWidth}
+              value={repository}
+              onChange={(el) => setRepository(el.currentTarget.value)}
+              onBlur={(el) => props.onChange({ ...props, repository: el.currentTarget.value, kind })}
+            />
+          </EditorField>
+        )}
+        {kind === ProjectQueryType.TEAM && (
+          <EditorField
+            label="Team"
+            tooltip="The slug of the team of the organization the GitHub projects are linked to (example: 'backend')"
+          >
+            <Input
+              width={RightColumnWidth}
+              value={team}
+              onChange={(el) => setTeam(el.currentTarget.value)}
+              onBlur={(el) => props.onChange({ ...props, team: el.currentTarget.value, kind })}
+            />
+          </EditorField>
+        )}
         <EditorField label="Project Number" tooltip="The project number for the GitHub project (example: 123).">
           <Input
             aria-label={components.QueryEditor.Number.input}
@@ -119,6 +174,58 @@ export const QueryEditorProjects = (props: Props) => {
           />
         </EditorField>
       </EditorRow>
+      {number && (
+        <EditorRow>
+          <EditorField label="Mode" tooltip="What is returned for the project">
+            <Combobox
+              width={RightColumnWidth}
+              options={modes}
+              value={props.mode || ProjectMode.Items}
+              onChange={(opt) => props.onChange({ ...props, mode: opt.value })}
+            />
+          </EditorField>
+          {props.mode === ProjectMode.Burndown && (
+            <>
+              <EditorField
+                label="Iteration Field"
+                tooltip="The name of the iteration field the burndown is computed for"
+              >
+                <Input
+                  width={RightColumnWidth}
+                  value={iterationField}
+                  placeholder="Sprint"
+                  onChange={(el) => setIterationField(el.currentTarget.value)}
+                  onBlur={(el) => props.onChange({ ...props, iterationField: el.currentTarget.value })}
+                />
+              </EditorField>
+              <EditorField
+                label="Estimate Field"
+                tooltip="The name of the number field holding the estimate of an item. Every item counts as 1 when empty (optional)"
+              >
+                <Input
+                  width={RightColumnWidth}
+                  value={estimateField}
+                  placeholder="Estimate"
+                  onChange={(el) => setEstimateField(el.currentTarget.value)}
+                  onBlur={(el) => props.onChange({ ...props, estimateField: el.currentTarget.value })}
+                />
+              </EditorField>
+              <EditorField
+                label="Iteration"
+                tooltip="The title of the iteration the burndown is computed for, the current iteration when empty (optional)"
+              >
+                <Input
+                  width={RightColumnWidth}
+                  value={iteration}
+                  placeholder="Sprint 12"
+                  onChange={(el) => setIteration(el.currentTarget.value)}
+                  onBlur={(el) => props.onChange({ ...props, iteration: el.currentTarget.value })}
+                />
+              </EditorField>
+            </>
+          )}
+        </EditorRow>
+      )}
       {/* Filters currently only apply to Project Items */}
       {number && (
         <EditorRow>
@@ -138,6 +245,22 @@ export const QueryEditorProjects = (props: Props) => {
           </EditorField>
         </EditorRow>
       )}
+      {number && (
+        <EditorRow>
+          <EditorField
+            label="Filter Expression"
+            tooltip="A boolean expression the items have to match on top of the filters, like (Status = Done or Estimate > 3) and closed_at > now-7d (optional)"
+          >
+            <Input
+              width={RightColumnWidth * 3}
+              value={filterExpression}
+              placeholder='Status in ("Todo", "In Progress") and Assignees is not empty'
+              onChange={(el) => setFilterExpression(el.currentTarget.value)}
+              onBlur={(el) => props.onChange({ ...props, filterExpression: el.currentTarget.value })}
+            />
+          </EditorField>
+        </EditorRow>
+      )}
     </EditorRows>
   );
 };
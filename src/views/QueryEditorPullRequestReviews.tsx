@@ -1,7 +1,8 @@
 import React, { useState } from 'react';
 import { Input, Combobox, ComboboxOption } from '@grafana/ui';
 import { RightColumnWidth } from './QueryEditor';
-import { PullRequestTimeField } from '../constants';
+import { TeamSearch } from '../components/TeamSearch';
+import { PullRequestTimeField, TeamQualifier } from '../constants';
 import type { PullRequestReviewsOptions } from '../types/query';
 import { EditorField, EditorRow } from '@grafana/plugin-ui';
 
@@ -67,6 +68,12 @@ export const QueryEditorPullRequestReviews = (props: Props) => {
           }
         />
       </EditorField>
+      <TeamSearch
+        team={props.team}
+        teamQualifier={props.teamQualifier}
+        qualifiers={Object.values(TeamQualifier)}
+        onChange={(value) => props.onChange({ ...props, ...value })}
+      />
     </EditorRow>
   );
 };
@@ -1,8 +1,9 @@
 import React, { useState } from 'react';
-import { Input, Combobox, ComboboxOption } from '@grafana/ui';
+import { Input, Combobox, ComboboxOption, InlineSwitch } from '@grafana/ui';
 import { EditorField, EditorRow } from '@grafana/plugin-ui';
 import { RightColumnWidth } from './QueryEditor';
-import { PullRequestTimeField } from '../constants';
+import { TeamSearch } from '../components/TeamSearch';
+import { PullRequestTimeField, TeamQualifier } from '../constants';
 import type { Pull_RequestsOptions } from '../types/query';
 
 interface Props extends Pull_RequestsOptions {
@@ -67,6 +68,21 @@ export const QueryEditorPullRequests = (props: Props) => {
           }
         />
       </EditorField>
+      <TeamSearch
+        team={props.team}
+        teamQualifier={props.teamQualifier}
+        qualifiers={Object.values(TeamQualifier)}
+        onChange={(value) => props.onChange({ ...props, ...value })}
+      />
+      <EditorField
+        label="Contributor Experience"
+        tooltip="Adds the author association, the first-time contributor flag and the time to first response of every pull request, and a frame comparing external contributors with members. Makes additional API calls."
+      >
+        <InlineSwitch
+          value={props.includeContributorExperience || false}
+          onChange={(el) => props.onChange({ ...props, includeContributorExperience: el.currentTarget.checked })}
+        />
+      </EditorField>
     </EditorRow>
   );
 };
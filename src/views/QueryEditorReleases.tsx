@@ -0,0 +1,33 @@
+import React from 'react';
+import { InlineSwitch } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import type { ReleasesOptions } from '../types/query';
+
+interface Props extends ReleasesOptions {
+  onChange: (value: ReleasesOptions) => void;
+}
+
+export const QueryEditorReleases = (props: Props) => {
+  return (
+    <EditorRow>
+      <EditorField
+        label="Include Assets"
+        tooltip="Adds a frame with the assets of every release, their size and download count"
+      >
+        <InlineSwitch
+          value={props.includeAssets || false}
+          onChange={(el) => props.onChange({ ...props, includeAssets: el.currentTarget.checked })}
+        />
+      </EditorField>
+      <EditorField
+        label="Include Cadence"
+        tooltip="Adds a frame with the days, commits and pull requests between every release and the one before it. Makes one additional API call per release."
+      >
+        <InlineSwitch
+          value={props.includeCadence || false}
+          onChange={(el) => props.onChange({ ...props, includeCadence: el.currentTarget.checked })}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
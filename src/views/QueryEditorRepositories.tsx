@@ -0,0 +1,58 @@
+import React, { useState } from 'react';
+import { Combobox, ComboboxOption, Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import { RepositoriesMode } from '../constants';
+import type { RepositoriesOptions } from '../types/query';
+
+interface Props extends RepositoriesOptions {
+  onChange: (value: RepositoriesOptions) => void;
+}
+
+const modes: Array<ComboboxOption<RepositoriesMode>> = [
+  { label: 'List', value: RepositoriesMode.List, description: 'The repositories of the owner' },
+  {
+    label: 'Inventory',
+    value: RepositoriesMode.Inventory,
+    description: 'The repositories with their license, topics, languages, settings and security features',
+  },
+  {
+    label: 'Stale',
+    value: RepositoriesMode.Stale,
+    description: 'The repositories with their last push, issue and pull request, flagging the stale ones',
+  },
+];
+
+export const QueryEditorRepositories = (props: Props) => {
+  const [staleDays, setStaleDays] = useState<string>(props.staleDays !== undefined ? String(props.staleDays) : '');
+  return (
+    <EditorRow>
+      <EditorField label="Mode" tooltip="What is returned for the repositories">
+        <Combobox
+          width={RightColumnWidth}
+          options={modes}
+          value={props.mode || RepositoriesMode.List}
+          onChange={(opt) => props.onChange({ ...props, mode: opt.value })}
+        />
+      </EditorField>
+      {props.mode === RepositoriesMode.Stale && (
+        <EditorField
+          label="Stale Days"
+          tooltip="The number of days without pushes, issues or pull requests after which a repository is stale"
+        >
+          <Input
+            width={RightColumnWidth}
+            type="number"
+            value={staleDays}
+            placeholder="90"
+            onChange={(el) => setStaleDays(el.currentTarget.value)}
+            onBlur={(el) => {
+              const parsed = parseInt(el.currentTarget.value, 10);
+              props.onChange({ ...props, staleDays: isNaN(parsed) ? undefined : parsed });
+            }}
+          />
+        </EditorField>
+      )}
+    </EditorRow>
+  );
+};
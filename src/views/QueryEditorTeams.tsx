@@ -0,0 +1,31 @@
+import React, { useState } from 'react';
+import { Input } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import { RightColumnWidth } from './QueryEditor';
+import type { TeamsOptions } from '../types/query';
+
+interface Props extends TeamsOptions {
+  onChange: (value: TeamsOptions) => void;
+}
+
+export const QueryEditorTeams = (props: Props) => {
+  const [query, setQuery] = useState<string>(props.query || '');
+
+  return (
+    <EditorRow>
+      <EditorField label="Query" tooltip="Filter the teams by name or slug. Leave empty to list every team.">
+        <Input
+          value={query}
+          width={RightColumnWidth}
+          onChange={(el) => setQuery(el.currentTarget.value)}
+          onBlur={(el) =>
+            props.onChange({
+              ...props,
+              query: el.currentTarget.value,
+            })
+          }
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};
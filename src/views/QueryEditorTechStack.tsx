@@ -0,0 +1,30 @@
+import React from 'react';
+import { InlineSwitch } from '@grafana/ui';
+import { EditorField, EditorRow } from '@grafana/plugin-ui';
+import type { TechStackOptions } from '../types/query';
+
+interface Props extends TechStackOptions {
+  onChange: (value: TechStackOptions) => void;
+}
+
+export const QueryEditorTechStack = (props: Props) => {
+  return (
+    <EditorRow>
+      <EditorField label="Include Forks" tooltip="Aggregate the forks, which are left out by default">
+        <InlineSwitch
+          value={props.includeForks || false}
+          onChange={(el) => props.onChange({ ...props, includeForks: el.currentTarget.checked })}
+        />
+      </EditorField>
+      <EditorField
+        label="Include Archived"
+        tooltip="Aggregate the archived repositories, which are left out by default"
+      >
+        <InlineSwitch
+          value={props.includeArchived || false}
+          onChange={(el) => props.onChange({ ...props, includeArchived: el.currentTarget.checked })}
+        />
+      </EditorField>
+    </EditorRow>
+  );
+};